@@ -7,5 +7,6 @@ import (
 
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: mysql.Provider})
+		GRPCProviderFunc: mysql.ProviderServer})
+	mysql.FlushMetrics()
 }
@@ -0,0 +1,326 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	accountFlavorMySQL   = "mysql"
+	accountFlavorMariaDB = "mariadb"
+)
+
+// accountGrant is a grant a tool needs. Flavor limits it to MySQL or MariaDB,
+// MinVersion and MaxVersion (exclusive) to versions of the flavor. With
+// RequireTable, the grant is only made when the table exists, e.g. for tables
+// of optional schemas.
+type accountGrant struct {
+	Privileges   []string
+	Database     string
+	Table        string
+	Flavor       string
+	MinVersion   string
+	MaxVersion   string
+	RequireTable bool
+}
+
+func (g accountGrant) String() string {
+	return fmt.Sprintf("%s ON %s.%s", strings.Join(g.Privileges, ", "), g.Database, g.Table)
+}
+
+func (g accountGrant) tablePrivilegeGrant(user, host string) *TablePrivilegeGrant {
+	return &TablePrivilegeGrant{
+		Database:   g.Database,
+		Table:      g.Table,
+		Privileges: g.Privileges,
+		UserOrRole: UserOrRole{Name: user, Host: host},
+	}
+}
+
+// accountGrantsFunc returns the grants of an account profile, from the
+// arguments of the resource.
+type accountGrantsFunc func(d interface{ Get(string) interface{} }) []accountGrant
+
+// accountProfileResource returns a resource creating an account with the
+// grants a tool needs. Each plan checks which grants the server needs, so
+// grants missing after an upgrade or a manual revoke are made by the next
+// apply.
+func accountProfileResource(grantsFor accountGrantsFunc, extraSchema map[string]*schema.Schema) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"user": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"host": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+			Default:  "%",
+		},
+		"plaintext_password": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+		"grants": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Grants of the account, as needed on the server",
+		},
+	}
+	for k, v := range extraSchema {
+		s[k] = v
+	}
+
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return createAccountProfile(ctx, d, meta, grantsFor(d))
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return updateAccountProfile(ctx, d, meta, grantsFor(d))
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return readAccountProfile(ctx, d, meta, grantsFor(d))
+		},
+		DeleteContext: deleteAccountProfile,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return checkAccountProfileGrants(ctx, d, meta, grantsFor(d))
+		},
+		Schema: s,
+	}
+}
+
+// expectedAccountGrants returns the grants needed on the server.
+func expectedAccountGrants(ctx context.Context, db *sql.DB, meta interface{}, grants []accountGrant) ([]accountGrant, error) {
+	mariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return nil, err
+	}
+	flavor := accountFlavorMySQL
+	if mariaDB {
+		flavor = accountFlavorMariaDB
+	}
+	currentVersion := getVersionFromMeta(ctx, meta)
+
+	var expected []accountGrant
+	for _, g := range grants {
+		if g.Flavor != "" && g.Flavor != flavor {
+			continue
+		}
+		if g.MinVersion != "" && currentVersion.LessThan(version.Must(version.NewVersion(g.MinVersion))) {
+			continue
+		}
+		if g.MaxVersion != "" && !currentVersion.LessThan(version.Must(version.NewVersion(g.MaxVersion))) {
+			continue
+		}
+		if g.RequireTable {
+			exists, err := queryHasRows(ctx, db, "SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", g.Database, g.Table)
+			if err != nil {
+				return nil, fmt.Errorf("failed checking table %s.%s: %v", g.Database, g.Table, err)
+			}
+			if !exists {
+				continue
+			}
+		}
+		expected = append(expected, g)
+	}
+	return expected, nil
+}
+
+func accountGrantStrings(grants []accountGrant) []string {
+	result := make([]string, 0, len(grants))
+	for _, g := range grants {
+		result = append(result, g.String())
+	}
+	return result
+}
+
+func checkAccountProfileGrants(ctx context.Context, d *schema.ResourceDiff, meta interface{}, grants []accountGrant) error {
+	if d.Id() == "" {
+		return d.SetNewComputed("grants")
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not read grants of account: %v", err)
+		return nil
+	}
+	expected, err := expectedAccountGrants(ctx, db, meta, grants)
+	if err != nil {
+		log.Printf("[WARN] Could not read grants of account: %v", err)
+		return nil
+	}
+	var current []string
+	for _, g := range d.Get("grants").([]interface{}) {
+		current = append(current, g.(string))
+	}
+	if !slices.Equal(current, accountGrantStrings(expected)) {
+		return d.SetNew("grants", accountGrantStrings(expected))
+	}
+	return nil
+}
+
+func grantAccountPrivileges(ctx context.Context, db *sql.DB, grants []accountGrant, user, host string) error {
+	for _, g := range grants {
+		stmtSQL := g.tablePrivilegeGrant(user, host).SQLGrantStatement()
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed granting %s: %v", g, err)
+		}
+	}
+	return nil
+}
+
+func createAccountProfile(ctx context.Context, d *schema.ResourceData, meta interface{}, grants []accountGrant) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	expected, err := expectedAccountGrants(ctx, db, meta, grants)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", formatUserIdentifier(user, host), quoteString(d.Get("plaintext_password").(string)))
+	log.Println("[DEBUG] Executing statement: CREATE USER", formatUserIdentifier(user, host))
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed creating user: %v", err)
+	}
+	d.SetId(fmt.Sprintf("%s@%s", user, host))
+
+	if err := grantAccountPrivileges(ctx, db, expected, user, host); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return readAccountProfile(ctx, d, meta, grants)
+}
+
+func updateAccountProfile(ctx context.Context, d *schema.ResourceData, meta interface{}, grants []accountGrant) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	if d.HasChange("plaintext_password") {
+		stmtSQL := fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", formatUserIdentifier(user, host), quoteString(d.Get("plaintext_password").(string)))
+		log.Println("[DEBUG] Executing statement: ALTER USER", formatUserIdentifier(user, host))
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed changing password: %v", err)
+		}
+	}
+
+	// Grants are additive: granting again what the user has is a no-op.
+	expected, err := expectedAccountGrants(ctx, db, meta, grants)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := grantAccountPrivileges(ctx, db, expected, user, host); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return readAccountProfile(ctx, d, meta, grants)
+}
+
+// readAccountProfile sets grants to the needed grants the account has, so a
+// missing grant shows as a change.
+func readAccountProfile(ctx context.Context, d *schema.ResourceData, meta interface{}, grants []accountGrant) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	found, err := userExists(ctx, db, meta, user, host)
+	if err != nil {
+		return diag.Errorf("failed reading user: %v", err)
+	}
+	if !found {
+		log.Printf("[WARN] User %s@%s not found, removing it from state", user, host)
+		d.SetId("")
+		return nil
+	}
+
+	expected, err := expectedAccountGrants(ctx, db, meta, grants)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userGrants, err := showUserGrants(ctx, db, UserOrRole{Name: user, Host: host})
+	if err != nil {
+		return diag.Errorf("failed reading grants: %v", err)
+	}
+
+	var present []accountGrant
+	for _, g := range expected {
+		if hasAccountGrant(userGrants, g.tablePrivilegeGrant(user, host)) {
+			present = append(present, g)
+		}
+	}
+	if err := d.Set("grants", accountGrantStrings(present)); err != nil {
+		return diag.Errorf("failed setting grants field: %v", err)
+	}
+	return nil
+}
+
+// privilegeAliases maps privileges to the names SHOW GRANTS may show them as.
+// MariaDB 10.5 renamed REPLICATION CLIENT, and both accept REPLICA for SLAVE.
+var privilegeAliases = map[string]string{
+	"REPLICATION CLIENT":  "BINLOG MONITOR",
+	"REPLICATION REPLICA": "REPLICATION SLAVE",
+	"REPLICA MONITOR":     "SLAVE MONITOR",
+}
+
+func canonicalPrivilege(priv string) string {
+	if alias, ok := privilegeAliases[priv]; ok {
+		return alias
+	}
+	return priv
+}
+
+func hasAccountGrant(userGrants []MySQLGrant, want *TablePrivilegeGrant) bool {
+	var privileges []string
+	for _, g := range userGrants {
+		if g, ok := g.(MySQLGrantWithPrivileges); ok && want.ConflictsWithGrant(g) {
+			for _, p := range normalizePerms(g.GetPrivileges()) {
+				privileges = append(privileges, canonicalPrivilege(p))
+			}
+		}
+	}
+	if slices.Contains(privileges, "ALL PRIVILEGES") {
+		return true
+	}
+	for _, p := range want.Privileges {
+		if !slices.Contains(privileges, canonicalPrivilege(p)) {
+			return false
+		}
+	}
+	return true
+}
+
+func deleteAccountProfile(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "DROP USER IF EXISTS " + formatUserIdentifier(d.Get("user").(string), d.Get("host").(string))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed dropping user: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestHasAccountGrant(t *testing.T) {
+	grants := []MySQLGrant{
+		&TablePrivilegeGrant{Database: "mysql_innodb_cluster_metadata", Table: "*", Privileges: []string{"SELECT"}},
+		&TablePrivilegeGrant{Database: "mysql_innodb_cluster_metadata", Table: "*", Privileges: []string{"EXECUTE"}},
+		&TablePrivilegeGrant{Database: "performance_schema", Table: "*", Privileges: []string{"ALL PRIVILEGES"}},
+	}
+	tests := []struct {
+		grant accountGrant
+		want  bool
+	}{
+		{routerAccountGrants[0], true},
+		{routerAccountGrants[1], false},
+		{accountGrant{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "*"}, true},
+		{accountGrant{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "global_variables"}, false},
+	}
+	for _, tt := range tests {
+		if got := hasAccountGrant(grants, tt.grant.tablePrivilegeGrant("router", "%")); got != tt.want {
+			t.Errorf("hasAccountGrant(%s) = %t, want %t", tt.grant, got, tt.want)
+		}
+	}
+
+	mariaDBGrants := []MySQLGrant{
+		&TablePrivilegeGrant{Database: "*", Table: "*", Privileges: []string{"PROCESS", "BINLOG MONITOR"}},
+	}
+	if !hasAccountGrant(mariaDBGrants, toolAccountGrants["datadog"][0].tablePrivilegeGrant("datadog", "%")) {
+		t.Errorf("BINLOG MONITOR should satisfy REPLICATION CLIENT")
+	}
+}
@@ -0,0 +1,188 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// auditedResources are the account resources whose changes are written to
+// the audit_log table.
+var auditedResources = map[string]bool{
+	"mysql_user":            true,
+	"mysql_users":           true,
+	"mysql_user_password":   true,
+	"mysql_user_replica":    true,
+	"mysql_role":            true,
+	"mysql_grant":           true,
+	"mysql_default_roles":   true,
+	"mysql_group_role_sync": true,
+}
+
+// auditLogConfig is the audit_log block of the provider.
+type auditLogConfig struct {
+	Table       string
+	Workspace   string
+	RunID       string
+	CreateTable bool
+}
+
+var (
+	auditTablesMtx     sync.Mutex
+	auditTablesCreated = map[string]bool{}
+)
+
+func auditLogSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Write a row to a table after every change of an account, role or grant",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"table": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Audit table as database.table",
+				},
+				"workspace": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Workspace recorded in the rows. Defaults to TF_WORKSPACE or TFC_WORKSPACE_NAME.",
+				},
+				"run_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Run ID recorded in the rows. Defaults to TFC_RUN_ID.",
+				},
+				"create_table": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Create the table if it doesn't exist",
+				},
+			},
+		},
+	}
+}
+
+func buildAuditLogConfig(block []interface{}, getenv func(string) string) (*auditLogConfig, error) {
+	if len(block) == 0 || block[0] == nil {
+		return nil, nil
+	}
+	m := block[0].(map[string]interface{})
+
+	table := m["table"].(string)
+	if database, name, ok := strings.Cut(table, "."); !ok || database == "" || name == "" {
+		return nil, fmt.Errorf("audit_log table %q must be given as database.table", table)
+	}
+
+	conf := &auditLogConfig{
+		Table:       table,
+		Workspace:   m["workspace"].(string),
+		RunID:       m["run_id"].(string),
+		CreateTable: m["create_table"].(bool),
+	}
+	if conf.Workspace == "" {
+		conf.Workspace = getenv("TF_WORKSPACE")
+	}
+	if conf.Workspace == "" {
+		conf.Workspace = getenv("TFC_WORKSPACE_NAME")
+	}
+	if conf.RunID == "" {
+		conf.RunID = getenv("TFC_RUN_ID")
+	}
+	return conf, nil
+}
+
+func (c *auditLogConfig) quotedTable() string {
+	database, name, _ := strings.Cut(c.Table, ".")
+	return quoteIdentifier(database) + "." + quoteIdentifier(name)
+}
+
+// auditResource writes a row to the audit table after every successful
+// create, update and delete of an account resource.
+func auditResource(typeName string, r *schema.Resource) {
+	if !auditedResources[typeName] {
+		return
+	}
+	if r.CreateContext != nil {
+		r.CreateContext = auditOperation(typeName, "create", r.CreateContext)
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = auditOperation(typeName, "update", r.UpdateContext)
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = auditOperation(typeName, "delete", r.DeleteContext)
+	}
+}
+
+func auditOperation(typeName, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		// Deletes clear the ID.
+		target := d.Id()
+		diags := f(ctx, d, meta)
+		if diags.HasError() {
+			return diags
+		}
+		if target == "" {
+			target = d.Id()
+		}
+
+		conf, ok := meta.(*MySQLConfiguration)
+		if !ok || conf.AuditLog == nil {
+			return diags
+		}
+		// The change is done, so failing to record it is only a warning.
+		if err := writeAuditRow(ctx, meta, conf.AuditLog, typeName, operation, target); err != nil {
+			return append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Failed writing audit row",
+				Detail:   fmt.Sprintf("%s %s of %s was applied but not recorded in %s: %v", typeName, operation, target, conf.AuditLog.Table, err),
+			})
+		}
+		return diags
+	}
+}
+
+func writeAuditRow(ctx context.Context, meta interface{}, conf *auditLogConfig, typeName, operation, target string) error {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	if conf.CreateTable {
+		auditTablesMtx.Lock()
+		if !auditTablesCreated[conf.Table] {
+			stmtSQL := "CREATE TABLE IF NOT EXISTS " + conf.quotedTable() + ` (
+  id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  changed_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  changed_by VARCHAR(288) NOT NULL,
+  workspace VARCHAR(255) NOT NULL,
+  run_id VARCHAR(255) NOT NULL,
+  operation VARCHAR(16) NOT NULL,
+  resource_type VARCHAR(64) NOT NULL,
+  target VARCHAR(1024) NOT NULL,
+  KEY changed_at (changed_at)
+)`
+			log.Println("[DEBUG] Executing statement:", stmtSQL)
+			if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+				auditTablesMtx.Unlock()
+				return err
+			}
+			auditTablesCreated[conf.Table] = true
+		}
+		auditTablesMtx.Unlock()
+	}
+
+	stmtSQL := "INSERT INTO " + conf.quotedTable() +
+		" (changed_by, workspace, run_id, operation, resource_type, target) VALUES (CURRENT_USER(), ?, ?, ?, ?, ?)"
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	_, err = db.ExecContext(ctx, stmtSQL, conf.Workspace, conf.RunID, operation, typeName, target)
+	return err
+}
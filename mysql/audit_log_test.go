@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestBuildAuditLogConfig(t *testing.T) {
+	env := map[string]string{"TFC_WORKSPACE_NAME": "prod", "TFC_RUN_ID": "run-123"}
+	getenv := func(key string) string { return env[key] }
+
+	conf, err := buildAuditLogConfig([]interface{}{map[string]interface{}{
+		"table":        "ops.terraform_audit",
+		"workspace":    "",
+		"run_id":       "",
+		"create_table": true,
+	}}, getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Workspace != "prod" || conf.RunID != "run-123" {
+		t.Errorf("got workspace %q and run ID %q from the environment", conf.Workspace, conf.RunID)
+	}
+	if got := conf.quotedTable(); got != "`ops`.`terraform_audit`" {
+		t.Errorf("got table %s", got)
+	}
+
+	if conf, err := buildAuditLogConfig(nil, getenv); conf != nil || err != nil {
+		t.Errorf("got %v, %v without a block", conf, err)
+	}
+	if _, err := buildAuditLogConfig([]interface{}{map[string]interface{}{
+		"table": "terraform_audit", "workspace": "", "run_id": "", "create_table": true,
+	}}, getenv); err == nil {
+		t.Error("no error for a table without database")
+	}
+}
@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// auroraPrivilegeRoles maps the privileges Aurora MySQL 2 adds for its AWS
+// integrations to the roles that replace them in Aurora MySQL 3.
+var auroraPrivilegeRoles = map[string]string{
+	"LOAD FROM S3":      "AWS_LOAD_S3_ACCESS",
+	"SELECT INTO S3":    "AWS_SELECT_S3_ACCESS",
+	"INVOKE LAMBDA":     "AWS_LAMBDA_ACCESS",
+	"INVOKE SAGEMAKER":  "AWS_SAGEMAKER_ACCESS",
+	"INVOKE COMPREHEND": "AWS_COMPREHEND_ACCESS",
+}
+
+// normalizeAuroraPrivilege returns the spelling SHOW GRANTS uses for an
+// Aurora privilege, or an empty string for other privileges.
+func normalizeAuroraPrivilege(privilege string) string {
+	normalized := strings.ToUpper(strings.Join(strings.Fields(privilege), " "))
+	if _, ok := auroraPrivilegeRoles[normalized]; ok {
+		return normalized
+	}
+	return ""
+}
+
+// serverAuroraVersion returns the Aurora MySQL version, e.g. 2.11.2 or
+// 3.05.2, or an empty string for other servers.
+func serverAuroraVersion(db *sql.DB) (string, error) {
+	var auroraVersion string
+	err := db.QueryRow("SELECT @@aurora_version").Scan(&auroraVersion)
+	if mysqlErrorNumber(err) == unknownSystemVariableErrCode {
+		return "", nil
+	}
+	return auroraVersion, err
+}
+
+// checkAuroraPrivileges fails the plan of grants of Aurora privileges that
+// aren't global or are granted to a server other than Aurora MySQL 2.
+func checkAuroraPrivileges(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	var privileges []string
+	for _, privilege := range setToArray(d.Get("privileges")) {
+		if normalized := normalizeAuroraPrivilege(privilege); normalized != "" {
+			privileges = append(privileges, normalized)
+		}
+	}
+	if len(privileges) == 0 {
+		return nil
+	}
+
+	if d.NewValueKnown("database") && (d.Get("database").(string) != "*" || d.Get("table").(string) != "*") {
+		return fmt.Errorf("%s can only be granted globally, set database and table to \"*\"", strings.Join(privileges, ", "))
+	}
+
+	if _, ok := meta.(*MySQLConfiguration); !ok {
+		return nil
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not check Aurora privileges: %v", err)
+		return nil
+	}
+	auroraVersion, err := serverAuroraVersion(db)
+	if err != nil {
+		log.Printf("[WARN] Could not check Aurora privileges: %v", err)
+		return nil
+	}
+
+	switch {
+	case auroraVersion == "":
+		return fmt.Errorf("%s only exist in Aurora MySQL", strings.Join(privileges, ", "))
+	case !strings.HasPrefix(auroraVersion, "2."):
+		roles := make([]string, len(privileges))
+		for i, privilege := range privileges {
+			roles[i] = auroraPrivilegeRoles[privilege]
+		}
+		return fmt.Errorf("Aurora MySQL %s replaced %s with the roles %s, grant them with roles instead",
+			auroraVersion, strings.Join(privileges, ", "), strings.Join(roles, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// authModeSkipGrantTables is a server started with --skip-grant-tables,
+	// which doesn't check privileges and refuses account management until
+	// the grant tables are loaded.
+	authModeSkipGrantTables = "skip_grant_tables"
+	// authModeInsecureRoot is a server with a root account without a
+	// password, e.g. initialized with --initialize-insecure.
+	authModeInsecureRoot = "insecure_root"
+
+	degradedAuthRefuse = "refuse"
+	degradedAuthWarn   = "warn"
+)
+
+// accountResources manage accounts or privileges, which a server with
+// skip-grant-tables can't show or change reliably.
+var accountResources = []string{
+	"mysql_user",
+	"mysql_users",
+	"mysql_user_password",
+	"mysql_user_replica",
+	"mysql_grant",
+	"mysql_temporary_grant",
+	"mysql_role",
+	"mysql_default_roles",
+	"mysql_group_role_sync",
+	"mysql_router_account",
+	"mysql_tool_account",
+	"mysql_backup_account",
+	"mysql_secure_installation",
+	"mysql_tenant",
+}
+
+// skipGrantsUser reports whether currentUser is the placeholder account
+// CURRENT_USER() returns with skip-grant-tables, e.g. "skip-grants user@".
+func skipGrantsUser(currentUser string) bool {
+	return strings.HasPrefix(currentUser, "skip-grants user@")
+}
+
+// serverAuthMode detects degraded authentication modes, returning an empty
+// string for servers that check privileges normally.
+func serverAuthMode(ctx context.Context, db *sql.DB, systemTablesReadable bool) string {
+	var currentUser string
+	if err := db.QueryRowContext(ctx, "SELECT CURRENT_USER()").Scan(&currentUser); err != nil {
+		log.Printf("[WARN] Could not read the current user: %v", err)
+		return ""
+	}
+	if skipGrantsUser(currentUser) {
+		return authModeSkipGrantTables
+	}
+
+	if !systemTablesReadable {
+		return ""
+	}
+	stmtSQL := "SELECT 1 FROM mysql.user WHERE User = 'root' AND authentication_string = '' AND plugin NOT IN ('auth_socket', 'unix_socket')"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	insecure, err := queryHasRows(ctx, db, stmtSQL)
+	if err != nil {
+		log.Printf("[WARN] Could not check for a root account without a password: %v", err)
+		return ""
+	}
+	if insecure {
+		return authModeInsecureRoot
+	}
+	return ""
+}
+
+// authModeState is the authentication mode of the server a provider manages.
+// It's detected once, on the first use of an account resource, and its
+// warnings are shown once, not for each resource.
+type authModeState struct {
+	mu       sync.Mutex
+	detected bool
+	mode     string
+	warned   atomic.Bool
+}
+
+func newAuthModeState() *authModeState {
+	return &authModeState{}
+}
+
+// get returns the authentication mode, detecting it unless it was already.
+// Failing connections are retried by the next call.
+func (s *authModeState) get(ctx context.Context, conf *MySQLConfiguration) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.detected {
+		return s.mode
+	}
+	oneConnection, err := connectToMySQLInternal(ctx, conf)
+	if err != nil {
+		log.Printf("[WARN] Could not determine the authentication mode: %v", err)
+		return ""
+	}
+	s.mode, s.detected = oneConnection.AuthMode, true
+	if s.mode != "" {
+		log.Printf("[WARN] The server runs in the degraded authentication mode %s", s.mode)
+	}
+	return s.mode
+}
+
+// warnOnce reports whether the warning of the mode is still to be shown.
+func (s *authModeState) warnOnce() bool {
+	return s.warned.CompareAndSwap(false, true)
+}
+
+func authModeFromMeta(ctx context.Context, meta interface{}) (*authModeState, string, string) {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok || conf.ProxySQL || conf.AuthMode == nil {
+		return nil, "", ""
+	}
+	return conf.AuthMode, conf.AuthMode.get(ctx, conf), conf.DegradedAuth
+}
+
+// guardDegradedAuth keeps account resources from creating state that can't
+// be reconciled once the server restarts normally. With skip-grant-tables,
+// they fail, or with degraded_auth = "warn" keep their state unread and only
+// fail changes. A root account without a password only adds a warning.
+func guardDegradedAuth(name string, r *schema.Resource) {
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if _, mode, behavior := authModeFromMeta(ctx, meta); mode == authModeSkipGrantTables && behavior != degradedAuthWarn {
+			return skipGrantTablesError(name)
+		}
+		if customizeDiff != nil {
+			return customizeDiff(ctx, d, meta)
+		}
+		return nil
+	}
+
+	if r.ReadContext != nil {
+		read := r.ReadContext
+		r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			state, mode, behavior := authModeFromMeta(ctx, meta)
+			switch {
+			case mode == authModeSkipGrantTables && behavior == degradedAuthWarn:
+				log.Printf("[WARN] %s %s not read: the server runs with --skip-grant-tables", name, d.Id())
+				if !state.warnOnce() {
+					return nil
+				}
+				return diag.Diagnostics{{
+					Severity: diag.Warning,
+					Summary:  "Account resources not read: the server runs with --skip-grant-tables",
+					Detail:   "Their state is kept as it was. Restart the server without --skip-grant-tables to manage accounts.",
+				}}
+			case mode == authModeSkipGrantTables:
+				return diag.FromErr(skipGrantTablesError(name))
+			case mode == authModeInsecureRoot:
+				diags := read(ctx, d, meta)
+				if !state.warnOnce() {
+					return diags
+				}
+				return append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "The server has a root account without a password",
+					Detail:   "It was probably initialized with --initialize-insecure. Set a password for root, e.g. with mysql_secure_installation.",
+				})
+			}
+			return read(ctx, d, meta)
+		}
+	}
+
+	guardChange := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if _, mode, _ := authModeFromMeta(ctx, meta); mode == authModeSkipGrantTables {
+				return diag.FromErr(skipGrantTablesError(name))
+			}
+			return f(ctx, d, meta)
+		}
+	}
+	if r.CreateContext != nil {
+		r.CreateContext = guardChange(r.CreateContext)
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = guardChange(r.UpdateContext)
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = guardChange(r.DeleteContext)
+	}
+}
+
+func skipGrantTablesError(name string) error {
+	return fmt.Errorf("%s can't be managed while the server runs with --skip-grant-tables: accounts and privileges it shows don't match the grant tables, restart it normally first", name)
+}
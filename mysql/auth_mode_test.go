@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSkipGrantsUser(t *testing.T) {
+	tests := []struct {
+		currentUser string
+		want        bool
+	}{
+		{"skip-grants user@", true},
+		{"skip-grants user@skip-grants host", true},
+		{"root@localhost", false},
+		{"skip-grants@%", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := skipGrantsUser(tt.currentUser); got != tt.want {
+			t.Errorf("skipGrantsUser(%q) = %v, want %v", tt.currentUser, got, tt.want)
+		}
+	}
+}
+
+func TestGuardDegradedAuthWarnsOnce(t *testing.T) {
+	reads := 0
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Optional: true}},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			reads++
+			return nil
+		},
+	}
+	guardDegradedAuth("mysql_user", r)
+
+	// A detected mode is used without connecting again.
+	conf := &MySQLConfiguration{AuthMode: &authModeState{detected: true, mode: authModeInsecureRoot}}
+	var warnings int
+	for i := 0; i < 3; i++ {
+		d := r.TestResourceData()
+		for _, diagnostic := range r.ReadContext(context.Background(), d, conf) {
+			if diagnostic.Severity == diag.Warning {
+				warnings++
+			}
+		}
+	}
+	if reads != 3 || warnings != 1 {
+		t.Errorf("expected 3 reads and 1 warning, got %d reads and %d warnings", reads, warnings)
+	}
+}
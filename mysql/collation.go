@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// equivalentCollations maps collations of one server family to the closest
+// ones of the other, e.g. the UCA 9.0.0 collations of MySQL 8 to the UCA
+// 14.0.0 ones of MariaDB 10.10. They're suggested when a collation doesn't
+// exist on the server, in order of preference.
+var equivalentCollations = map[string][]string{
+	"utf8mb4_0900_ai_ci":     {"utf8mb4_uca1400_ai_ci", "utf8mb4_unicode_520_ci", "utf8mb4_unicode_ci"},
+	"utf8mb4_0900_as_ci":     {"utf8mb4_uca1400_as_ci", "utf8mb4_unicode_520_ci", "utf8mb4_unicode_ci"},
+	"utf8mb4_0900_as_cs":     {"utf8mb4_uca1400_as_cs", "utf8mb4_bin"},
+	"utf8mb4_0900_bin":       {"utf8mb4_bin"},
+	"utf8mb4_uca1400_ai_ci":  {"utf8mb4_0900_ai_ci", "utf8mb4_unicode_520_ci", "utf8mb4_unicode_ci"},
+	"utf8mb4_uca1400_as_ci":  {"utf8mb4_0900_as_ci", "utf8mb4_unicode_520_ci", "utf8mb4_unicode_ci"},
+	"utf8mb4_uca1400_as_cs":  {"utf8mb4_0900_as_cs", "utf8mb4_bin"},
+	"utf8mb4_uca1400_ai_cs":  {"utf8mb4_0900_as_cs", "utf8mb4_bin"},
+	"utf8mb4_unicode_520_ci": {"utf8mb4_unicode_ci"},
+}
+
+// serverCollations are the character sets and collations of a server.
+type serverCollations struct {
+	// collations maps each collation to its character set.
+	collations map[string]string
+	// charsets maps each character set to its default collation.
+	charsets map[string]string
+}
+
+func readServerCollations(ctx context.Context, db *sql.DB) (*serverCollations, error) {
+	sc := &serverCollations{collations: map[string]string{}, charsets: map[string]string{}}
+
+	rows, err := db.QueryContext(ctx, "SELECT COLLATION_NAME, CHARACTER_SET_NAME FROM information_schema.COLLATIONS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var collation, charset string
+		if err := rows.Scan(&collation, &charset); err != nil {
+			return nil, err
+		}
+		sc.collations[strings.ToLower(collation)] = strings.ToLower(charset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, "SELECT CHARACTER_SET_NAME, DEFAULT_COLLATE_NAME FROM information_schema.CHARACTER_SETS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var charset, collation string
+		if err := rows.Scan(&charset, &collation); err != nil {
+			return nil, err
+		}
+		sc.charsets[strings.ToLower(charset)] = strings.ToLower(collation)
+	}
+	return sc, rows.Err()
+}
+
+// resolveCharset returns the name the server knows charset by. utf8 is an
+// alias of utf8mb3, which newer servers report instead.
+func (sc *serverCollations) resolveCharset(charset string) (string, bool) {
+	if _, ok := sc.charsets[charset]; ok {
+		return charset, true
+	}
+	if charset == "utf8" {
+		if _, ok := sc.charsets["utf8mb3"]; ok {
+			return "utf8mb3", true
+		}
+	}
+	return charset, false
+}
+
+// resolveCollation is resolveCharset for collations, e.g. utf8_general_ci
+// is reported as utf8mb3_general_ci.
+func (sc *serverCollations) resolveCollation(collation string) (string, bool) {
+	if _, ok := sc.collations[collation]; ok {
+		return collation, true
+	}
+	if rest, ok := strings.CutPrefix(collation, "utf8_"); ok {
+		if _, ok := sc.collations["utf8mb3_"+rest]; ok {
+			return "utf8mb3_" + rest, true
+		}
+	}
+	return collation, false
+}
+
+// collationSuggestions returns collations of charset on the server to use
+// instead of collation: known equivalents first, then the ones with the
+// same name in charset and the default collation of charset.
+func (sc *serverCollations) collationSuggestions(charset, collation string) []string {
+	var suggestions []string
+	add := func(c string) {
+		if cs, ok := sc.collations[c]; !ok || (charset != "" && cs != charset) {
+			return
+		}
+		for _, s := range suggestions {
+			if s == c {
+				return
+			}
+		}
+		suggestions = append(suggestions, c)
+	}
+
+	for _, c := range equivalentCollations[collation] {
+		add(c)
+	}
+	if charset != "" {
+		if i := strings.Index(collation, "_"); i > 0 {
+			add(charset + collation[i:])
+		}
+		add(sc.charsets[charset])
+	}
+	return suggestions
+}
+
+// check returns an error describing why the pair of charset and collation
+// can't be used on the server, with suggestions. Either may be empty.
+func (sc *serverCollations) check(charset, collation string) error {
+	charset = strings.ToLower(charset)
+	collation = strings.ToLower(collation)
+
+	if charset != "" {
+		resolved, ok := sc.resolveCharset(charset)
+		if !ok {
+			available := make([]string, 0, len(sc.charsets))
+			for cs := range sc.charsets {
+				available = append(available, cs)
+			}
+			sort.Strings(available)
+			return fmt.Errorf("character set %s is not available on the server, it has %s", charset, strings.Join(available, ", "))
+		}
+		charset = resolved
+	}
+	if collation == "" {
+		return nil
+	}
+
+	resolved, ok := sc.resolveCollation(collation)
+	if !ok {
+		msg := fmt.Sprintf("collation %s is not available on the server", collation)
+		if suggestions := sc.collationSuggestions(charset, collation); len(suggestions) > 0 {
+			msg += fmt.Sprintf("; use %s instead", strings.Join(suggestions, " or "))
+		}
+		return errors.New(msg)
+	}
+	if collationCharset := sc.collations[resolved]; charset != "" && collationCharset != charset {
+		msg := fmt.Sprintf("collation %s belongs to character set %s, not %s", collation, collationCharset, charset)
+		if suggestions := sc.collationSuggestions(charset, resolved); len(suggestions) > 0 {
+			msg += fmt.Sprintf("; use %s", strings.Join(suggestions, " or "))
+		}
+		return fmt.Errorf("%s; or set the character set to %s", msg, collationCharset)
+	}
+	return nil
+}
+
+// checkDatabaseCollation fails the plan of a database whose character set
+// and collation the server doesn't support. The check is skipped when the
+// server can't be reached yet.
+func checkDatabaseCollation(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("default_character_set") && !d.HasChange("default_collation") {
+		return nil
+	}
+	if !d.NewValueKnown("default_character_set") || !d.NewValueKnown("default_collation") {
+		return nil
+	}
+	if _, ok := meta.(*MySQLConfiguration); !ok {
+		return nil
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not validate the character set and collation: %v", err)
+		return nil
+	}
+	sc, err := readServerCollations(ctx, db)
+	if err != nil {
+		log.Printf("[WARN] Could not read the collations of the server: %v", err)
+		return nil
+	}
+	return sc.check(d.Get("default_character_set").(string), d.Get("default_collation").(string))
+}
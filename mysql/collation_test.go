@@ -0,0 +1,69 @@
+package mysql
+
+import "testing"
+
+func TestCheckCollation(t *testing.T) {
+	mariadb := &serverCollations{
+		collations: map[string]string{
+			"utf8mb4_general_ci":     "utf8mb4",
+			"utf8mb4_bin":            "utf8mb4",
+			"utf8mb4_unicode_ci":     "utf8mb4",
+			"utf8mb4_unicode_520_ci": "utf8mb4",
+			"utf8mb4_uca1400_ai_ci":  "utf8mb4",
+			"utf8mb4_uca1400_as_cs":  "utf8mb4",
+			"utf8mb3_general_ci":     "utf8mb3",
+			"latin1_swedish_ci":      "latin1",
+		},
+		charsets: map[string]string{
+			"utf8mb4": "utf8mb4_general_ci",
+			"utf8mb3": "utf8mb3_general_ci",
+			"latin1":  "latin1_swedish_ci",
+		},
+	}
+	mysql := &serverCollations{
+		collations: map[string]string{
+			"utf8mb4_0900_ai_ci": "utf8mb4",
+			"utf8mb4_general_ci": "utf8mb4",
+			"latin1_swedish_ci":  "latin1",
+		},
+		charsets: map[string]string{
+			"utf8mb4": "utf8mb4_0900_ai_ci",
+			"latin1":  "latin1_swedish_ci",
+		},
+	}
+
+	tests := []struct {
+		server    *serverCollations
+		charset   string
+		collation string
+		want      string
+	}{
+		{mariadb, "utf8mb4", "utf8mb4_general_ci", ""},
+		{mariadb, "UTF8MB4", "utf8mb4_UCA1400_AI_CI", ""},
+		{mariadb, "utf8", "utf8_general_ci", ""},
+		{mariadb, "", "latin1_swedish_ci", ""},
+		{mariadb, "utf8mb4", "", ""},
+		{mariadb, "utf8mb4", "utf8mb4_0900_ai_ci",
+			"collation utf8mb4_0900_ai_ci is not available on the server; use utf8mb4_uca1400_ai_ci or utf8mb4_unicode_520_ci or utf8mb4_unicode_ci or utf8mb4_general_ci instead"},
+		{mariadb, "utf8mb4", "utf8mb4_0900_as_cs",
+			"collation utf8mb4_0900_as_cs is not available on the server; use utf8mb4_uca1400_as_cs or utf8mb4_bin or utf8mb4_general_ci instead"},
+		{mariadb, "utf8mb4", "utf8mb3_general_ci",
+			"collation utf8mb3_general_ci belongs to character set utf8mb3, not utf8mb4; use utf8mb4_general_ci; or set the character set to utf8mb3"},
+		{mariadb, "utf16", "utf16_general_ci",
+			"character set utf16 is not available on the server, it has latin1, utf8mb3, utf8mb4"},
+		{mysql, "utf8mb4", "utf8mb4_uca1400_ai_ci",
+			"collation utf8mb4_uca1400_ai_ci is not available on the server; use utf8mb4_0900_ai_ci instead"},
+		{mysql, "latin1", "latin1_german1_ci",
+			"collation latin1_german1_ci is not available on the server; use latin1_swedish_ci instead"},
+	}
+	for _, tt := range tests {
+		err := tt.server.check(tt.charset, tt.collation)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("check(%q, %q) = %q, want %q", tt.charset, tt.collation, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// complianceCheck is a single CIS benchmark check. The query must return one
+// string column per offending object; an empty result means the check passed.
+type complianceCheck struct {
+	ID          string
+	Description string
+	Query       string
+}
+
+var complianceChecks = []complianceCheck{
+	{
+		ID:          "anonymous_users",
+		Description: "Ensure no anonymous accounts exist",
+		Query:       "SELECT CONCAT(user, '@', host) FROM mysql.user WHERE user = ''",
+	},
+	{
+		ID:          "test_database",
+		Description: "Ensure the test database is removed",
+		Query:       "SELECT SCHEMA_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = 'test' OR SCHEMA_NAME LIKE 'test\\_%'",
+	},
+	{
+		ID:          "wildcard_hosts",
+		Description: "Ensure no accounts use a wildcard hostname",
+		Query:       "SELECT CONCAT(user, '@', host) FROM mysql.user WHERE host = '%'",
+	},
+	{
+		ID:          "empty_passwords",
+		Description: "Ensure all password-authenticated accounts have a password",
+		Query: "SELECT CONCAT(user, '@', host) FROM mysql.user WHERE authentication_string = '' " +
+			"AND plugin IN ('', 'mysql_native_password', 'caching_sha2_password', 'sha256_password')",
+	},
+	{
+		ID:          "file_privilege",
+		Description: "Ensure FILE is not granted to non-administrative accounts",
+		Query:       "SELECT CONCAT(user, '@', host) FROM mysql.user WHERE File_priv = 'Y' AND user NOT IN ('root', 'mysql.session', 'mysql.sys', 'mysql.infoschema')",
+	},
+}
+
+func complianceCheckIDs() []string {
+	ids := make([]string, 0, len(complianceChecks))
+	for _, check := range complianceChecks {
+		ids = append(ids, check.ID)
+	}
+	return ids
+}
+
+func dataSourceComplianceReport() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadComplianceReport,
+		Schema: map[string]*schema.Schema{
+			"checks": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(complianceCheckIDs(), false),
+				},
+				Set:         schema.HashString,
+				Description: "Checks to evaluate. Defaults to all supported checks.",
+			},
+			"passed": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"results": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"passed": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"findings": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadComplianceReport(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getReadDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	selected := setToArray(d.Get("checks"))
+
+	allPassed := true
+	results := make([]map[string]interface{}, 0, len(complianceChecks))
+	for _, check := range complianceChecks {
+		if len(selected) > 0 && !slices.Contains(selected, check.ID) {
+			continue
+		}
+
+		findings, err := runComplianceCheck(ctx, db, check)
+		if err != nil {
+			return diag.Errorf("failed running compliance check %s: %v", check.ID, err)
+		}
+
+		passed := len(findings) == 0
+		allPassed = allPassed && passed
+		results = append(results, map[string]interface{}{
+			"id":          check.ID,
+			"description": check.Description,
+			"passed":      passed,
+			"findings":    findings,
+		})
+	}
+
+	if err := d.Set("results", results); err != nil {
+		return diag.Errorf("failed setting results field: %v", err)
+	}
+	d.Set("passed", allPassed)
+
+	d.SetId(id.UniqueId())
+
+	return nil
+}
+
+func runComplianceCheck(ctx context.Context, db *sql.DB, check complianceCheck) ([]string, error) {
+	log.Printf("[DEBUG] SQL: %s", check.Query)
+
+	rows, err := db.QueryContext(ctx, check.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	findings := []string{}
+	for rows.Next() {
+		var finding string
+		if err := rows.Scan(&finding); err != nil {
+			return nil, fmt.Errorf("failed scanning MySQL rows: %v", err)
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings, rows.Err()
+}
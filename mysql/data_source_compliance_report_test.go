@@ -0,0 +1,39 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceComplianceReport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComplianceReportConfigBasic(`["anonymous_users", "file_privilege"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_compliance_report.test", "results.#", "2"),
+					resource.TestCheckResourceAttr("data.mysql_compliance_report.test", "results.0.id", "anonymous_users"),
+					resource.TestCheckResourceAttr("data.mysql_compliance_report.test", "results.1.id", "file_privilege"),
+					resource.TestCheckResourceAttrSet("data.mysql_compliance_report.test", "passed"),
+				),
+			},
+			{
+				Config: testAccComplianceReportConfigBasic("null"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_compliance_report.test", "results.#", fmt.Sprint(len(complianceChecks))),
+				),
+			},
+		},
+	})
+}
+
+func testAccComplianceReportConfigBasic(checks string) string {
+	return fmt.Sprintf(`
+data "mysql_compliance_report" "test" {
+  checks = %s
+}`, checks)
+}
@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceDatabaseSize() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadDatabaseSize,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"max_size_bytes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Quota of the database, compared with total_bytes in within_quota",
+			},
+			"data_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"index_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"total_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"within_quota": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "False when total_bytes exceeds max_size_bytes, for use in preconditions",
+			},
+			"tables": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rows": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Estimated number of rows",
+						},
+						"data_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"index_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"total_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadDatabaseSize(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getReadDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	if database == "" {
+		database = defaultDatabaseFromMeta(meta)
+		if database == "" {
+			return diag.Errorf("database must be set when the provider has no default_database")
+		}
+		d.Set("database", database)
+	}
+
+	exists, err := queryHasRows(ctx, db, "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?", database)
+	if err != nil {
+		return diag.Errorf("failed checking database %s: %v", database, err)
+	}
+	if !exists {
+		return diag.Errorf("database %s doesn't exist", database)
+	}
+
+	// The sizes are estimates maintained by the storage engine; views have
+	// no sizes.
+	stmtSQL := "SELECT TABLE_NAME, TABLE_ROWS, DATA_LENGTH, INDEX_LENGTH FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, database)
+	if err != nil {
+		return diag.Errorf("failed querying for table sizes: %v", err)
+	}
+	defer rows.Close()
+
+	tables := make([]map[string]interface{}, 0)
+	var dataBytes, indexBytes int64
+	for rows.Next() {
+		var name string
+		var tableRows, tableData, tableIndex sql.NullInt64
+		if err := rows.Scan(&name, &tableRows, &tableData, &tableIndex); err != nil {
+			return diag.Errorf("failed scanning MySQL rows: %v", err)
+		}
+		dataBytes += tableData.Int64
+		indexBytes += tableIndex.Int64
+		tables = append(tables, map[string]interface{}{
+			"name":        name,
+			"rows":        tableRows.Int64,
+			"data_bytes":  tableData.Int64,
+			"index_bytes": tableIndex.Int64,
+			"total_bytes": tableData.Int64 + tableIndex.Int64,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed querying for table sizes: %v", err)
+	}
+
+	if err := d.Set("tables", tables); err != nil {
+		return diag.Errorf("failed setting tables field: %v", err)
+	}
+	total := dataBytes + indexBytes
+	d.Set("data_bytes", dataBytes)
+	d.Set("index_bytes", indexBytes)
+	d.Set("total_bytes", total)
+	maxSize := int64(d.Get("max_size_bytes").(int))
+	d.Set("within_quota", maxSize == 0 || total <= maxSize)
+
+	d.SetId(database)
+
+	return nil
+}
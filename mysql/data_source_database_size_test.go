@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceDatabaseSize(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "mysql_database_size" "test" {
+  database       = "mysql"
+  max_size_bytes = 1
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.mysql_database_size.test", "total_bytes"),
+					resource.TestCheckResourceAttrSet("data.mysql_database_size.test", "tables.0.name"),
+					resource.TestCheckResourceAttr("data.mysql_database_size.test", "within_quota", "false"),
+				),
+			},
+		},
+	})
+}
@@ -28,7 +28,7 @@ func dataSourceDatabases() *schema.Resource {
 }
 
 func ShowDatabases(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	db, err := getDatabaseFromMeta(ctx, meta)
+	db, err := getReadDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -0,0 +1,306 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// systemDatabases are never exported by mysql_generated_config.
+var systemDatabases = map[string]bool{
+	"information_schema": true,
+	"mysql":              true,
+	"performance_schema": true,
+	"sys":                true,
+}
+
+// passwordAuthPlugins are left out of generated users, their passwords can't
+// be exported.
+var passwordAuthPlugins = map[string]bool{
+	"":                      true,
+	"mysql_native_password": true,
+	"caching_sha2_password": true,
+	"sha256_password":       true,
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func dataSourceGeneratedConfig() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadGeneratedConfig,
+		Schema: map[string]*schema.Schema{
+			"database_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "LIKE pattern of the databases to export, all but system databases by default",
+			},
+			"user_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "LIKE pattern of the users to export, all but reserved accounts by default",
+			},
+			"include_grants": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"hcl": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Resources and import blocks of the exported objects",
+			},
+			"resource_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// generatedConfig collects the resources and import blocks of the export.
+type generatedConfig struct {
+	names     map[string]bool
+	resources []string
+	imports   []string
+}
+
+// add appends a resource with the given attributes (already formatted HCL
+// lines) and its import block.
+func (c *generatedConfig) add(resourceType, name string, attributes []string, importID string) {
+	name = c.uniqueName(name)
+	address := resourceType + "." + name
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "resource %q %q {\n", resourceType, name)
+	for _, attribute := range attributes {
+		fmt.Fprintf(&b, "  %s\n", attribute)
+	}
+	b.WriteString("}\n")
+	c.resources = append(c.resources, b.String())
+	c.imports = append(c.imports, fmt.Sprintf("import {\n  to = %s\n  id = %s\n}\n", address, hclString(importID)))
+}
+
+// uniqueName turns the parts of an object's name into a resource name not
+// used yet.
+func (c *generatedConfig) uniqueName(name string) string {
+	name = strings.Trim(nonIdentifierChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	unique := name
+	for i := 2; c.names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	c.names[unique] = true
+	return unique
+}
+
+func (c *generatedConfig) hcl() string {
+	return strings.Join(append(c.resources, c.imports...), "\n")
+}
+
+// hclString quotes a string for HCL, which unlike Go also interpolates ${
+// and %{.
+func hclString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(s)
+	s = strings.ReplaceAll(s, "${", "$${")
+	s = strings.ReplaceAll(s, "%{", "%%{")
+	return `"` + s + `"`
+}
+
+func hclStringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = hclString(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func ReadGeneratedConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getReadDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	config := &generatedConfig{names: map[string]bool{}}
+
+	if err := generateDatabases(ctx, db, config, d.Get("database_pattern").(string)); err != nil {
+		return diag.Errorf("failed exporting databases: %v", err)
+	}
+	if err := generateUsers(ctx, db, config, d.Get("user_pattern").(string), d.Get("include_grants").(bool)); err != nil {
+		return diag.Errorf("failed exporting users: %v", err)
+	}
+
+	d.Set("hcl", config.hcl())
+	d.Set("resource_count", len(config.resources))
+	d.SetId(id.UniqueId())
+
+	return nil
+}
+
+func generateDatabases(ctx context.Context, db *sql.DB, config *generatedConfig, pattern string) error {
+	stmtSQL := "SELECT SCHEMA_NAME, DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM information_schema.SCHEMATA"
+	var args []interface{}
+	if pattern != "" {
+		stmtSQL += " WHERE SCHEMA_NAME LIKE ?"
+		args = append(args, pattern)
+	}
+	stmtSQL += " ORDER BY SCHEMA_NAME"
+
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, charset, collation string
+		if err := rows.Scan(&name, &charset, &collation); err != nil {
+			return err
+		}
+		if systemDatabases[strings.ToLower(name)] {
+			continue
+		}
+		config.add("mysql_database", name, []string{
+			"name                  = " + hclString(name),
+			"default_character_set = " + hclString(charset),
+			"default_collation     = " + hclString(collation),
+		}, name)
+	}
+	return rows.Err()
+}
+
+type generatedAccount struct {
+	user, host, plugin string
+}
+
+func generateUsers(ctx context.Context, db *sql.DB, config *generatedConfig, pattern string, includeGrants bool) error {
+	stmtSQL := "SELECT User, Host, plugin FROM mysql.user WHERE User <> ''"
+	var args []interface{}
+	if pattern != "" {
+		stmtSQL += " AND User LIKE ?"
+		args = append(args, pattern)
+	}
+	stmtSQL += " ORDER BY User, Host"
+
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return err
+	}
+	var accounts []generatedAccount
+	for rows.Next() {
+		var account generatedAccount
+		var plugin sql.NullString
+		if err := rows.Scan(&account.user, &account.host, &plugin); err != nil {
+			rows.Close()
+			return err
+		}
+		account.plugin = plugin.String
+		if !reservedAccounts[account.user] {
+			accounts = append(accounts, account)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		attributes := []string{
+			"user = " + hclString(account.user),
+			"host = " + hclString(account.host),
+		}
+		if !passwordAuthPlugins[account.plugin] {
+			attributes = append(attributes, "auth_plugin = "+hclString(account.plugin))
+		}
+		config.add("mysql_user", account.user+"_"+account.host, attributes, fmt.Sprintf("%s@%s", account.user, account.host))
+
+		if !includeGrants {
+			continue
+		}
+		grants, err := showUserGrants(ctx, db, UserOrRole{Name: account.user, Host: account.host})
+		if err != nil {
+			return fmt.Errorf("failed reading grants of %s: %v", formatUserIdentifier(account.user, account.host), err)
+		}
+		generateGrants(config, account, grants)
+	}
+	return nil
+}
+
+// generateGrants adds the grants of an account. Privileges on the same
+// object (e.g. static and dynamic global privileges listed separately by
+// MySQL 8) are merged, as mysql_grant manages them together.
+func generateGrants(config *generatedConfig, account generatedAccount, grants []MySQLGrant) {
+	type tableKey struct {
+		database, table string
+		grant           bool
+	}
+	privileges := map[tableKey][]string{}
+	var keys []tableKey
+
+	prefix := account.user + "_" + account.host
+	for _, grant := range grants {
+		switch g := grant.(type) {
+		case *TablePrivilegeGrant:
+			key := tableKey{database: g.Database, table: g.Table, grant: g.Grant}
+			if key.table == "" {
+				key.table = "*"
+			}
+			if _, ok := privileges[key]; !ok {
+				keys = append(keys, key)
+			}
+			privileges[key] = append(privileges[key], g.Privileges...)
+		case *RoleGrant:
+			roles := append([]string{}, g.Roles...)
+			sort.Strings(roles)
+			attributes := []string{
+				"user  = " + hclString(account.user),
+				"host  = " + hclString(account.host),
+				"roles = " + hclStringList(roles),
+			}
+			importID := fmt.Sprintf("%s@%s@@", account.user, account.host)
+			if g.Grant {
+				attributes = append(attributes, "grant = true")
+				importID += "@"
+			}
+			config.add("mysql_grant", prefix+"_roles", attributes, importID+";r")
+		default:
+			log.Printf("[WARN] Not exporting grant %s, it can't be imported", grant.SQLGrantStatement())
+		}
+	}
+
+	for _, key := range keys {
+		privs := privileges[key]
+		sort.Strings(privs)
+		attributes := []string{
+			"user       = " + hclString(account.user),
+			"host       = " + hclString(account.host),
+			"database   = " + hclString(key.database),
+			"table      = " + hclString(key.table),
+			"privileges = " + hclStringList(privs),
+		}
+		importID := fmt.Sprintf("%s@%s@%s@%s", account.user, account.host, key.database, key.table)
+		if key.grant {
+			attributes = append(attributes, "grant      = true")
+			importID += "@"
+		}
+		name := prefix
+		if key.database != "*" {
+			name += "_" + key.database
+		}
+		if key.table != "*" {
+			name += "_" + key.table
+		}
+		config.add("mysql_grant", name+"_grant", attributes, importID)
+	}
+}
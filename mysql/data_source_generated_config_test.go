@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHCLString(t *testing.T) {
+	cases := map[string]string{
+		"app":             `"app"`,
+		`quo"te\`:         `"quo\"te\\"`,
+		"${var} and %{if": `"$${var} and %%{if"`,
+	}
+	for in, want := range cases {
+		if got := hclString(in); got != want {
+			t.Errorf("hclString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestGenerateGrants(t *testing.T) {
+	account := generatedAccount{user: "app", host: "10.0.%"}
+	userOrRole := UserOrRole{Name: "app", Host: "10.0.%"}
+	config := &generatedConfig{names: map[string]bool{}}
+	generateGrants(config, account, []MySQLGrant{
+		&TablePrivilegeGrant{Database: "*", Table: "*", Privileges: []string{"PROCESS"}, UserOrRole: userOrRole},
+		&TablePrivilegeGrant{Database: "*", Table: "*", Privileges: []string{"BACKUP_ADMIN"}, UserOrRole: userOrRole},
+		&TablePrivilegeGrant{Database: "shop", Table: "*", Privileges: []string{"SELECT"}, Grant: true, UserOrRole: userOrRole},
+		&RoleGrant{Roles: []string{"writer", "reader"}, UserOrRole: userOrRole},
+	})
+
+	hcl := config.hcl()
+	for _, want := range []string{
+		`resource "mysql_grant" "app_10_0_grant" {`,
+		`privileges = ["BACKUP_ADMIN", "PROCESS"]`,
+		`resource "mysql_grant" "app_10_0_shop_grant" {`,
+		`id = "app@10.0.%@shop@*@"`,
+		`roles = ["reader", "writer"]`,
+		`id = "app@10.0.%@@;r"`,
+	} {
+		if !strings.Contains(hcl, want) {
+			t.Errorf("generated config lacks %s:\n%s", want, hcl)
+		}
+	}
+	if len(config.resources) != 3 {
+		t.Errorf("got %d resources, want 3:\n%s", len(config.resources), hcl)
+	}
+}
+
+func TestGeneratedConfigUniqueName(t *testing.T) {
+	config := &generatedConfig{names: map[string]bool{}}
+	cases := []struct{ name, want string }{
+		{"App-DB", "app_db"},
+		{"app.db", "app_db_2"},
+		{"1st", "_1st"},
+	}
+	for _, c := range cases {
+		if got := config.uniqueName(c.name); got != c.want {
+			t.Errorf("uniqueName(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAccDataSourceGeneratedConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_database" "test" {
+  name = "tf_generated_config"
+}
+
+data "mysql_generated_config" "test" {
+  database_pattern = mysql_database.test.name
+  user_pattern     = "nobody_matches_this"
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_generated_config.test", "resource_count", "1"),
+					resource.TestMatchResourceAttr("data.mysql_generated_config.test", "hcl", regexp.MustCompile(`id = "tf_generated_config"`)),
+				),
+			},
+		},
+	})
+}
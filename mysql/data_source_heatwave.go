@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceHeatwave() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadHeatwave,
+		Schema: map[string]*schema.Schema{
+			"attached": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether a HeatWave cluster is attached to the DB system and online",
+			},
+			"variables": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Global rapid_* variables",
+			},
+			"status": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Global rapid_* status variables",
+			},
+		},
+	}
+}
+
+func ReadHeatwave(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	attached, err := heatwaveAttached(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed checking HeatWave status: %v", err)
+	}
+
+	variables := map[string]string{}
+	status := map[string]string{}
+	for stmtSQL, values := range map[string]map[string]string{
+		"SHOW GLOBAL VARIABLES LIKE 'rapid\\_%'": variables,
+		"SHOW GLOBAL STATUS LIKE 'rapid\\_%'":    status,
+	} {
+		log.Printf("[DEBUG] SQL: %s", stmtSQL)
+		rows, err := db.QueryContext(ctx, stmtSQL)
+		if err != nil {
+			return diag.Errorf("failed reading HeatWave variables: %v", err)
+		}
+		for rows.Next() {
+			var name, value string
+			if err := rows.Scan(&name, &value); err != nil {
+				rows.Close()
+				return diag.Errorf("failed scanning MySQL rows: %v", err)
+			}
+			values[strings.ToLower(name)] = value
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return diag.Errorf("failed reading HeatWave variables: %v", err)
+		}
+	}
+
+	d.Set("attached", attached)
+	d.Set("variables", variables)
+	d.Set("status", status)
+
+	d.SetId(id.UniqueId())
+
+	return nil
+}
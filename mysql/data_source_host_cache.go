@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceHostCache() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadHostCache,
+		Schema: map[string]*schema.Schema{
+			"max_connect_errors": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"skip_name_resolve": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"hosts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"connect_errors": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Connection errors counted towards max_connect_errors",
+						},
+						"blocked": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"blocked_attempts": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Connections refused because the host was blocked",
+						},
+						"name_resolution_errors": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"blocked_hosts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"name_resolution_errors": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"recommend_skip_name_resolve": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "True when hosts fail reverse DNS lookups while skip_name_resolve is off",
+			},
+		},
+	}
+}
+
+func ReadHostCache(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var maxConnectErrors int64
+	var skipNameResolve bool
+	stmtSQL := "SELECT @@GLOBAL.max_connect_errors, @@GLOBAL.skip_name_resolve"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	if err := db.QueryRowContext(ctx, stmtSQL).Scan(&maxConnectErrors, &skipNameResolve); err != nil {
+		return diag.Errorf("failed reading host cache variables: %v", err)
+	}
+
+	stmtSQL = "SELECT IP, HOST, SUM_CONNECT_ERRORS, COUNT_HOST_BLOCKED_ERRORS, COUNT_NAMEINFO_TRANSIENT_ERRORS + COUNT_NAMEINFO_PERMANENT_ERRORS FROM performance_schema.host_cache ORDER BY IP"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed reading host cache: %v", err)
+	}
+	defer rows.Close()
+
+	hosts := make([]map[string]interface{}, 0)
+	blockedHosts := make([]string, 0)
+	var nameResolutionErrors int64
+	for rows.Next() {
+		var ip string
+		var host sql.NullString
+		var connectErrors, blockedAttempts, resolutionErrors int64
+		if err := rows.Scan(&ip, &host, &connectErrors, &blockedAttempts, &resolutionErrors); err != nil {
+			return diag.Errorf("failed scanning MySQL rows: %v", err)
+		}
+		blocked := connectErrors >= maxConnectErrors
+		if blocked {
+			blockedHosts = append(blockedHosts, ip)
+		}
+		nameResolutionErrors += resolutionErrors
+		hosts = append(hosts, map[string]interface{}{
+			"ip":                     ip,
+			"host":                   host.String,
+			"connect_errors":         connectErrors,
+			"blocked":                blocked,
+			"blocked_attempts":       blockedAttempts,
+			"name_resolution_errors": resolutionErrors,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed reading host cache: %v", err)
+	}
+
+	d.Set("max_connect_errors", maxConnectErrors)
+	d.Set("skip_name_resolve", skipNameResolve)
+	if err := d.Set("hosts", hosts); err != nil {
+		return diag.Errorf("failed setting hosts field: %v", err)
+	}
+	d.Set("blocked_hosts", blockedHosts)
+	d.Set("name_resolution_errors", nameResolutionErrors)
+	d.Set("recommend_skip_name_resolve", !skipNameResolve && nameResolutionErrors > 0)
+
+	d.SetId(id.UniqueId())
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceHostCache(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_host_cache_flush" "test" {
+  triggers = {
+    reason = "test"
+  }
+}
+
+data "mysql_host_cache" "test" {
+  depends_on = [mysql_host_cache_flush.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("mysql_host_cache_flush.test", "id"),
+					resource.TestCheckResourceAttr("data.mysql_host_cache.test", "blocked_hosts.#", "0"),
+					resource.TestCheckResourceAttrSet("data.mysql_host_cache.test", "max_connect_errors"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceInnoDBCluster() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadInnoDBCluster,
+		Schema: map[string]*schema.Schema{
+			"group_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UUID of the replication group, empty when Group Replication isn't configured",
+			},
+			"single_primary_mode": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"members": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"member_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"primary_host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Host of the primary in single-primary mode",
+			},
+			"primary_port": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"online_members": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"online_secondaries": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+type groupReplicationMember struct {
+	memberId string
+	host     string
+	port     int
+	state    string
+	role     string
+	version  string
+}
+
+func ReadInnoDBCluster(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var groupName sql.NullString
+	var singlePrimary bool
+	stmtSQL := "SELECT @@GLOBAL.group_replication_group_name, @@GLOBAL.group_replication_single_primary_mode"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	if err := db.QueryRowContext(ctx, stmtSQL).Scan(&groupName, &singlePrimary); err != nil {
+		if mysqlErrorNumber(err) != unknownSystemVariableErrCode {
+			return diag.Errorf("failed reading Group Replication settings: %v", err)
+		}
+		// The group_replication plugin isn't installed.
+		log.Printf("[DEBUG] Group Replication is not available: %v", err)
+	}
+
+	// MEMBER_ROLE and MEMBER_VERSION were added in 8.0.2; older servers only
+	// report the primary in a status variable.
+	var members []groupReplicationMember
+	if getVersionFromMeta(ctx, meta).GreaterThanOrEqual(version.Must(version.NewVersion("8.0.2"))) {
+		members, err = readGroupReplicationMembers(ctx, db, "SELECT MEMBER_ID, MEMBER_HOST, MEMBER_PORT, MEMBER_STATE, MEMBER_ROLE, MEMBER_VERSION FROM performance_schema.replication_group_members")
+	} else {
+		members, err = readGroupReplicationMembers(ctx, db, "SELECT MEMBER_ID, MEMBER_HOST, MEMBER_PORT, MEMBER_STATE, IF(MEMBER_ID = (SELECT VARIABLE_VALUE FROM performance_schema.global_status WHERE VARIABLE_NAME = 'group_replication_primary_member'), 'PRIMARY', 'SECONDARY'), '' FROM performance_schema.replication_group_members")
+	}
+	if err != nil {
+		return diag.Errorf("failed reading Group Replication members: %v", err)
+	}
+
+	var primaryHost string
+	var primaryPort, onlineMembers, onlineSecondaries int
+	memberList := make([]map[string]interface{}, 0, len(members))
+	for _, member := range members {
+		if member.state == "ONLINE" {
+			onlineMembers++
+			if member.role == "SECONDARY" {
+				onlineSecondaries++
+			}
+		}
+		if member.role == "PRIMARY" && singlePrimary {
+			primaryHost, primaryPort = member.host, member.port
+		}
+		memberList = append(memberList, map[string]interface{}{
+			"member_id": member.memberId,
+			"host":      member.host,
+			"port":      member.port,
+			"state":     member.state,
+			"role":      member.role,
+			"version":   member.version,
+		})
+	}
+
+	d.Set("group_name", groupName.String)
+	d.Set("single_primary_mode", singlePrimary)
+	if err := d.Set("members", memberList); err != nil {
+		return diag.Errorf("failed setting members field: %v", err)
+	}
+	d.Set("primary_host", primaryHost)
+	d.Set("primary_port", primaryPort)
+	d.Set("online_members", onlineMembers)
+	d.Set("online_secondaries", onlineSecondaries)
+
+	d.SetId(id.UniqueId())
+
+	return nil
+}
+
+func readGroupReplicationMembers(ctx context.Context, db *sql.DB, stmtSQL string) ([]groupReplicationMember, error) {
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []groupReplicationMember
+	for rows.Next() {
+		var memberId, host, state, role, memberVersion sql.NullString
+		var port sql.NullInt64
+		if err := rows.Scan(&memberId, &host, &port, &state, &role, &memberVersion); err != nil {
+			return nil, err
+		}
+		// A server that isn't in a group shows as a single OFFLINE row without
+		// an ID.
+		if memberId.String == "" {
+			continue
+		}
+		members = append(members, groupReplicationMember{
+			memberId: memberId.String,
+			host:     host.String,
+			port:     int(port.Int64),
+			state:    state.String,
+			role:     role.String,
+			version:  memberVersion.String,
+		})
+	}
+	return members, rows.Err()
+}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceInnoDBCluster(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The test server isn't part of a group.
+				Config: `data "mysql_innodb_cluster" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_innodb_cluster.test", "members.#", "0"),
+					resource.TestCheckResourceAttr("data.mysql_innodb_cluster.test", "online_members", "0"),
+					resource.TestCheckResourceAttr("data.mysql_innodb_cluster.test", "primary_host", ""),
+				),
+			},
+		},
+	})
+}
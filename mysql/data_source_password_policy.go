@@ -0,0 +1,196 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePasswordPolicy() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadPasswordPolicy,
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "What validates passwords: validate_password, simple_password_check or none",
+			},
+			"policy": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dictionary_file": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"check_user_name": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether passwords may not contain the user name",
+			},
+			"min_length": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Effective minimum length, at least the sum of the required characters",
+			},
+			"min_lower": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"min_upper": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"min_numeric": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"min_special": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"password_history": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of previous passwords that can't be reused",
+			},
+			"password_reuse_interval": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Days before a previous password can be reused",
+			},
+		},
+	}
+}
+
+// passwordPolicy is the effective policy of validate_password or MariaDB's
+// simple_password_check.
+type passwordPolicy struct {
+	source         string
+	policy         string
+	dictionaryFile string
+	checkUserName  bool
+	minLength      int
+	minLower       int
+	minUpper       int
+	minNumeric     int
+	minSpecial     int
+}
+
+// validatePasswordPolicy returns the requirements validate_password enforces
+// with the given settings. LOW only checks the length, MEDIUM and STRONG also
+// the character counts. The length is never less than the characters
+// required.
+func validatePasswordPolicy(variables map[string]string) passwordPolicy {
+	policy := passwordPolicy{
+		source:         "validate_password",
+		policy:         strings.ToUpper(variables["policy"]),
+		dictionaryFile: variables["dictionary_file"],
+		checkUserName:  variables["check_user_name"] == "ON",
+		minLength:      atoiOrZero(variables["length"]),
+	}
+	if policy.policy != "LOW" && policy.policy != "0" {
+		mixedCase := atoiOrZero(variables["mixed_case_count"])
+		policy.minLower = mixedCase
+		policy.minUpper = mixedCase
+		policy.minNumeric = atoiOrZero(variables["number_count"])
+		policy.minSpecial = atoiOrZero(variables["special_char_count"])
+	}
+	policy.minLength = max(policy.minLength, policy.minLower+policy.minUpper+policy.minNumeric+policy.minSpecial)
+	return policy
+}
+
+// simplePasswordCheckPolicy returns the requirements of MariaDB's
+// simple_password_check plugin.
+func simplePasswordCheckPolicy(variables map[string]string) passwordPolicy {
+	sameCase := atoiOrZero(variables["letters_same_case"])
+	policy := passwordPolicy{
+		source:     "simple_password_check",
+		minLength:  atoiOrZero(variables["minimal_length"]),
+		minLower:   sameCase,
+		minUpper:   sameCase,
+		minNumeric: atoiOrZero(variables["digits"]),
+		minSpecial: atoiOrZero(variables["other_characters"]),
+	}
+	policy.minLength = max(policy.minLength, policy.minLower+policy.minUpper+policy.minNumeric+policy.minSpecial)
+	return policy
+}
+
+func atoiOrZero(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+// readVariablesWithPrefix returns the global variables starting with prefix,
+// keyed by the rest of their name.
+func readVariablesWithPrefix(ctx context.Context, db *sql.DB, prefix string) (map[string]string, error) {
+	stmtSQL := "SHOW GLOBAL VARIABLES LIKE " + quoteString(strings.ReplaceAll(prefix, "_", "\\_")+"%")
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variables := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		variables[strings.TrimPrefix(name, prefix)] = value
+	}
+	return variables, rows.Err()
+}
+
+func ReadPasswordPolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	validatePassword, err := readValidatePasswordVariables(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed reading validate_password variables: %v", err)
+	}
+	simplePasswordCheck, err := readVariablesWithPrefix(ctx, db, "simple_password_check_")
+	if err != nil {
+		return diag.Errorf("failed reading simple_password_check variables: %v", err)
+	}
+
+	policy := passwordPolicy{source: "none"}
+	switch {
+	case len(validatePassword) > 0:
+		policy = validatePasswordPolicy(validatePassword)
+	case len(simplePasswordCheck) > 0:
+		policy = simplePasswordCheckPolicy(simplePasswordCheck)
+	}
+
+	// MySQL 8.0 only; other servers don't restrict reusing passwords.
+	reuse, err := readVariablesWithPrefix(ctx, db, "password_")
+	if err != nil {
+		return diag.Errorf("failed reading password variables: %v", err)
+	}
+
+	d.Set("source", policy.source)
+	d.Set("policy", policy.policy)
+	d.Set("dictionary_file", policy.dictionaryFile)
+	d.Set("check_user_name", policy.checkUserName)
+	d.Set("min_length", policy.minLength)
+	d.Set("min_lower", policy.minLower)
+	d.Set("min_upper", policy.minUpper)
+	d.Set("min_numeric", policy.minNumeric)
+	d.Set("min_special", policy.minSpecial)
+	d.Set("password_history", atoiOrZero(reuse["history"]))
+	d.Set("password_reuse_interval", atoiOrZero(reuse["reuse_interval"]))
+	d.SetId(id.UniqueId())
+
+	return nil
+}
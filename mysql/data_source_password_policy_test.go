@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	variables := map[string]string{
+		"policy":             "MEDIUM",
+		"length":             "8",
+		"mixed_case_count":   "2",
+		"number_count":       "3",
+		"special_char_count": "2",
+		"check_user_name":    "ON",
+	}
+	want := passwordPolicy{
+		source:        "validate_password",
+		policy:        "MEDIUM",
+		checkUserName: true,
+		minLength:     9,
+		minLower:      2,
+		minUpper:      2,
+		minNumeric:    3,
+		minSpecial:    2,
+	}
+	if got := validatePasswordPolicy(variables); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	variables["policy"] = "LOW"
+	want = passwordPolicy{source: "validate_password", policy: "LOW", checkUserName: true, minLength: 8}
+	if got := validatePasswordPolicy(variables); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSimplePasswordCheckPolicy(t *testing.T) {
+	got := simplePasswordCheckPolicy(map[string]string{
+		"minimal_length":    "12",
+		"digits":            "1",
+		"letters_same_case": "1",
+		"other_characters":  "1",
+	})
+	want := passwordPolicy{source: "simple_password_check", minLength: 12, minLower: 1, minUpper: 1, minNumeric: 1, minSpecial: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
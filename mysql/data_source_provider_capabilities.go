@@ -0,0 +1,241 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourcePrivilegeRequirements lists the global privileges resources need.
+// Each inner list names alternatives, any of which satisfies the
+// requirement, e.g. SUPER before MySQL 8 and SYSTEM_VARIABLES_ADMIN since.
+// Resources without fixed requirements, like mysql_sql, aren't listed.
+var resourcePrivilegeRequirements = map[string][][]string{
+	"mysql_database":              {{"CREATE"}, {"DROP"}, {"ALTER"}},
+	"mysql_user":                  {{"CREATE USER"}},
+	"mysql_users":                 {{"CREATE USER"}},
+	"mysql_user_password":         {{"CREATE USER"}},
+	"mysql_default_roles":         {{"CREATE USER"}},
+	"mysql_role":                  {{"CREATE ROLE", "CREATE USER"}, {"DROP ROLE", "CREATE USER"}},
+	"mysql_grant":                 {{"GRANT OPTION"}},
+	"mysql_temporary_grant":       {{"GRANT OPTION"}},
+	"mysql_global_variable":       {{"SYSTEM_VARIABLES_ADMIN", "SUPER"}},
+	"mysql_global_variables":      {{"SYSTEM_VARIABLES_ADMIN", "SUPER"}},
+	"mysql_host_cache_flush":      {{"RELOAD", "DROP"}},
+	"mysql_user_defined_function": {{"INSERT"}, {"DELETE"}},
+	"mysql_secure_installation":   {{"CREATE USER"}, {"DROP"}},
+	"mysql_backup_account":        {{"CREATE USER"}, {"GRANT OPTION"}},
+	"mysql_router_account":        {{"CREATE USER"}, {"GRANT OPTION"}},
+	"mysql_tool_account":          {{"CREATE USER"}, {"GRANT OPTION"}},
+	"mysql_dump":                  {{"SELECT"}, {"SHOW VIEW"}},
+	"mysql_restore":               {{"CREATE"}, {"INSERT"}},
+	"mysql_load_data":             {{"INSERT"}},
+}
+
+func dataSourceProviderCapabilities() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadProviderCapabilities,
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The account the provider is authenticated as",
+			},
+			"grants": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Output of SHOW GRANTS for the account, with the privileges of its granted roles on MySQL 8",
+			},
+			"global_privileges": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"resources": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"usable": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"missing_privileges": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Missing global privileges; alternatives are joined with ' or '",
+						},
+					},
+				},
+			},
+			"usable_resources": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func ReadProviderCapabilities(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var currentUser string
+	if err := db.QueryRowContext(ctx, "SELECT CURRENT_USER()").Scan(&currentUser); err != nil {
+		return diag.Errorf("failed reading current user: %v", err)
+	}
+
+	grants, err := showCurrentUserGrants(ctx, db, "SHOW GRANTS FOR CURRENT_USER()")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// MySQL 8 shows the privileges of roles with USING. MariaDB doesn't
+	// support it.
+	if roles := grantedRoles(grants); len(roles) > 0 {
+		if isMariaDB, err := serverMariaDB(db); err == nil && !isMariaDB {
+			grants, err = showCurrentUserGrants(ctx, db, "SHOW GRANTS FOR CURRENT_USER() USING "+strings.Join(roles, ", "))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	privileges := globalPrivileges(grants)
+	resources := make([]map[string]interface{}, 0, len(resourcePrivilegeRequirements))
+	usable := make([]string, 0)
+	for _, name := range sortedRequirementResources() {
+		missing := missingPrivileges(privileges, resourcePrivilegeRequirements[name])
+		resources = append(resources, map[string]interface{}{
+			"type":               name,
+			"usable":             len(missing) == 0,
+			"missing_privileges": missing,
+		})
+		if len(missing) == 0 {
+			usable = append(usable, name)
+		}
+	}
+
+	rawGrants := make([]string, 0, len(grants))
+	for _, g := range grants {
+		rawGrants = append(rawGrants, g.raw)
+	}
+	globalList := make([]string, 0, len(privileges))
+	for p := range privileges {
+		globalList = append(globalList, p)
+	}
+	sort.Strings(globalList)
+
+	d.Set("user", currentUser)
+	d.Set("grants", rawGrants)
+	d.Set("global_privileges", globalList)
+	if err := d.Set("resources", resources); err != nil {
+		return diag.Errorf("failed setting resources field: %v", err)
+	}
+	d.Set("usable_resources", usable)
+	d.SetId(currentUser)
+	return nil
+}
+
+// currentUserGrant is a line of SHOW GRANTS and its parsed grant, nil for
+// lines the provider doesn't parse, e.g. PROXY grants.
+type currentUserGrant struct {
+	raw    string
+	parsed MySQLGrant
+}
+
+func showCurrentUserGrants(ctx context.Context, db *sql.DB, stmtSQL string) ([]currentUserGrant, error) {
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed showing grants: %v", err)
+	}
+	defer rows.Close()
+
+	var grants []currentUserGrant
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		parsed, err := parseGrantFromRow(raw)
+		if err != nil {
+			log.Printf("[DEBUG] Not parsing grant %s: %v", raw, err)
+		}
+		grants = append(grants, currentUserGrant{raw: raw, parsed: parsed})
+	}
+	return grants, rows.Err()
+}
+
+func grantedRoles(grants []currentUserGrant) []string {
+	var roles []string
+	for _, g := range grants {
+		if roleGrant, ok := g.parsed.(*RoleGrant); ok {
+			for _, role := range roleGrant.Roles {
+				roles = append(roles, quoteString(role))
+			}
+		}
+	}
+	return roles
+}
+
+// globalPrivileges returns the privileges granted on *.*, with GRANT OPTION
+// when it's granted.
+func globalPrivileges(grants []currentUserGrant) map[string]bool {
+	privileges := map[string]bool{}
+	for _, g := range grants {
+		tableGrant, ok := g.parsed.(*TablePrivilegeGrant)
+		if !ok || tableGrant.Database != "*" {
+			continue
+		}
+		for _, p := range tableGrant.Privileges {
+			privileges[normalizeVerifiedName(p)] = true
+		}
+		if tableGrant.Grant {
+			privileges["GRANT OPTION"] = true
+		}
+	}
+	return privileges
+}
+
+// missingPrivileges returns the requirements not met by privileges. ALL
+// PRIVILEGES meets all of them but GRANT OPTION.
+func missingPrivileges(privileges map[string]bool, requirements [][]string) []string {
+	missing := make([]string, 0)
+	for _, alternatives := range requirements {
+		met := false
+		for _, p := range alternatives {
+			if privileges[normalizeVerifiedName(p)] || (p != "GRANT OPTION" && privileges["ALL PRIVILEGES"]) {
+				met = true
+				break
+			}
+		}
+		if !met {
+			missing = append(missing, strings.Join(alternatives, " or "))
+		}
+	}
+	return missing
+}
+
+func sortedRequirementResources() []string {
+	names := make([]string, 0, len(resourcePrivilegeRequirements))
+	for name := range resourcePrivilegeRequirements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourcePrivilegeRequirements(t *testing.T) {
+	resources := Provider().ResourcesMap
+	for name := range resourcePrivilegeRequirements {
+		if _, ok := resources[name]; !ok {
+			t.Errorf("requirements of unknown resource %s", name)
+		}
+	}
+}
+
+func TestMissingPrivileges(t *testing.T) {
+	var grants []currentUserGrant
+	for _, raw := range []string{
+		"GRANT SELECT, INSERT, CREATE USER ON *.* TO `terraform`@`%`",
+		"GRANT SYSTEM_VARIABLES_ADMIN ON *.* TO `terraform`@`%` WITH GRANT OPTION",
+		"GRANT ALL PRIVILEGES ON `app`.* TO `terraform`@`%`",
+	} {
+		parsed, err := parseGrantFromRow(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		grants = append(grants, currentUserGrant{raw: raw, parsed: parsed})
+	}
+	privileges := globalPrivileges(grants)
+
+	tests := []struct {
+		resource string
+		want     []string
+	}{
+		{"mysql_user", []string{}},
+		{"mysql_grant", []string{}},
+		{"mysql_global_variable", []string{}},
+		{"mysql_database", []string{"CREATE", "DROP", "ALTER"}},
+		{"mysql_role", []string{}},
+		{"mysql_dump", []string{"SHOW VIEW"}},
+	}
+	for _, tt := range tests {
+		if got := missingPrivileges(privileges, resourcePrivilegeRequirements[tt.resource]); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("missing privileges of %s = %v, want %v", tt.resource, got, tt.want)
+		}
+	}
+
+	all := map[string]bool{"ALL PRIVILEGES": true}
+	if got := missingPrivileges(all, resourcePrivilegeRequirements["mysql_grant"]); !reflect.DeepEqual(got, []string{"GRANT OPTION"}) {
+		t.Errorf("missing privileges with ALL PRIVILEGES = %v, want GRANT OPTION", got)
+	}
+}
+
+func TestAccDataSourceProviderCapabilities(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "mysql_provider_capabilities" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.mysql_provider_capabilities.test", "user"),
+					resource.TestCheckTypeSetElemAttr("data.mysql_provider_capabilities.test", "usable_resources.*", "mysql_user"),
+				),
+			},
+		},
+	})
+}
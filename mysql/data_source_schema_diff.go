@@ -0,0 +1,215 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// schemaInventory maps object types (table, column, index, routine) to
+// object names and a definition used for comparisons.
+type schemaInventory map[string]map[string]string
+
+var schemaInventoryQueries = []struct {
+	objectType string
+	stmtSQL    string
+}{
+	{"table", "SELECT TABLE_NAME, CONCAT_WS(' ', TABLE_TYPE, ENGINE) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?"},
+	{"column", "SELECT CONCAT(TABLE_NAME, '.', COLUMN_NAME), CONCAT_WS(' ', COLUMN_TYPE, IF(IS_NULLABLE = 'YES', 'NULL', 'NOT NULL'), CONCAT('DEFAULT ', QUOTE(COLUMN_DEFAULT)), NULLIF(EXTRA, '')) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ?"},
+	{"index", "SELECT CONCAT(TABLE_NAME, '.', INDEX_NAME), CONCAT(IF(NON_UNIQUE = 0, 'UNIQUE ', ''), INDEX_TYPE, ' (', GROUP_CONCAT(COLUMN_NAME ORDER BY SEQ_IN_INDEX SEPARATOR ', '), ')') FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = ? GROUP BY TABLE_NAME, INDEX_NAME, NON_UNIQUE, INDEX_TYPE"},
+	{"routine", "SELECT CONCAT(ROUTINE_TYPE, ' ', ROUTINE_NAME), CONCAT_WS(' ', DTD_IDENTIFIER, ROUTINE_DEFINITION) FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ?"},
+}
+
+func dataSourceSchemaDiff() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadSchemaDiff,
+		Schema: map[string]*schema.Schema{
+			"source_database": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"target_database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Database on the same server to compare with",
+			},
+			"target_inventory": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "inventory of another mysql_schema_diff, e.g. read through another provider, to compare with",
+			},
+			"inventory": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON inventory of the source database",
+			},
+			"in_sync": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"differences": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"change": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "missing_in_target, missing_in_source or different",
+						},
+						"source_definition": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"target_definition": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadSchemaDiff(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getReadDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	source, err := readSchemaInventory(ctx, db, d.Get("source_database").(string))
+	if err != nil {
+		return diag.Errorf("failed reading source_database: %v", err)
+	}
+	inventory, err := json.Marshal(source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("inventory", string(inventory))
+
+	var target schemaInventory
+	targetDatabase := d.Get("target_database").(string)
+	targetInventory := d.Get("target_inventory").(string)
+	switch {
+	case targetDatabase != "" && targetInventory != "":
+		return diag.Errorf("only one of target_database and target_inventory can be set")
+	case targetDatabase != "":
+		target, err = readSchemaInventory(ctx, db, targetDatabase)
+		if err != nil {
+			return diag.Errorf("failed reading target_database: %v", err)
+		}
+	case targetInventory != "":
+		if err := json.Unmarshal([]byte(targetInventory), &target); err != nil {
+			return diag.Errorf("failed parsing target_inventory: %v", err)
+		}
+	}
+
+	// Without a target, only the inventory is exported.
+	var differences []map[string]interface{}
+	if target != nil {
+		differences = diffSchemaInventories(source, target)
+	}
+	if err := d.Set("differences", differences); err != nil {
+		return diag.Errorf("failed setting differences field: %v", err)
+	}
+	d.Set("in_sync", len(differences) == 0)
+
+	d.SetId(id.UniqueId())
+
+	return nil
+}
+
+func readSchemaInventory(ctx context.Context, db *sql.DB, database string) (schemaInventory, error) {
+	exists, err := queryHasRows(ctx, db, "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?", database)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("database %s doesn't exist", database)
+	}
+
+	inventory := schemaInventory{}
+	for _, q := range schemaInventoryQueries {
+		objects := map[string]string{}
+		log.Printf("[DEBUG] SQL: %s", q.stmtSQL)
+		rows, err := db.QueryContext(ctx, q.stmtSQL, database)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var name string
+			var definition sql.NullString
+			if err := rows.Scan(&name, &definition); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			objects[name] = definition.String
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		inventory[q.objectType] = objects
+	}
+	return inventory, nil
+}
+
+// diffSchemaInventories returns the differences ordered by object type and
+// name.
+func diffSchemaInventories(source, target schemaInventory) []map[string]interface{} {
+	var differences []map[string]interface{}
+	for _, q := range schemaInventoryQueries {
+		names := map[string]bool{}
+		for name := range source[q.objectType] {
+			names[name] = true
+		}
+		for name := range target[q.objectType] {
+			names[name] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+
+		for _, name := range sorted {
+			sourceDefinition, inSource := source[q.objectType][name]
+			targetDefinition, inTarget := target[q.objectType][name]
+			var change string
+			switch {
+			case !inTarget:
+				change = "missing_in_target"
+			case !inSource:
+				change = "missing_in_source"
+			case sourceDefinition != targetDefinition:
+				change = "different"
+			default:
+				continue
+			}
+			differences = append(differences, map[string]interface{}{
+				"object_type":       q.objectType,
+				"name":              name,
+				"change":            change,
+				"source_definition": sourceDefinition,
+				"target_definition": targetDefinition,
+			})
+		}
+	}
+	return differences
+}
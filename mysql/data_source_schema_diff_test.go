@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceSchemaDiff(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaDiffConfig("mysql", "mysql"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_schema_diff.test", "in_sync", "true"),
+					resource.TestCheckResourceAttr("data.mysql_schema_diff.test", "differences.#", "0"),
+					resource.TestCheckResourceAttrSet("data.mysql_schema_diff.test", "inventory"),
+				),
+			},
+			{
+				Config: testAccSchemaDiffConfig("mysql", "information_schema"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_schema_diff.test", "in_sync", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestDiffSchemaInventories(t *testing.T) {
+	source := schemaInventory{
+		"table":  {"orders": "BASE TABLE InnoDB", "users": "BASE TABLE InnoDB"},
+		"column": {"orders.id": "int NOT NULL", "orders.total": "decimal(10,2) NULL DEFAULT NULL"},
+	}
+	target := schemaInventory{
+		"table":  {"orders": "BASE TABLE InnoDB"},
+		"column": {"orders.id": "bigint NOT NULL", "orders.total": "decimal(10,2) NULL DEFAULT NULL", "orders.note": "text NULL"},
+	}
+
+	var got []string
+	for _, difference := range diffSchemaInventories(source, target) {
+		got = append(got, fmt.Sprintf("%s %s %s", difference["object_type"], difference["name"], difference["change"]))
+	}
+	expected := []string{
+		"table users missing_in_target",
+		"column orders.id different",
+		"column orders.note missing_in_source",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected differences %q", got)
+	}
+
+	if differences := diffSchemaInventories(source, source); len(differences) != 0 {
+		t.Errorf("expected no differences, got %v", differences)
+	}
+}
+
+func testAccSchemaDiffConfig(source, target string) string {
+	return fmt.Sprintf(`
+data "mysql_schema_diff" "test" {
+  source_database = "%s"
+  target_database = "%s"
+}`, source, target)
+}
@@ -0,0 +1,273 @@
+package mysql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	checksumMethodTable = "checksum_table"
+	checksumMethodCRC   = "crc"
+)
+
+func dataSourceTableChecksums() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadTableChecksums,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"tables": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"method": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      checksumMethodCRC,
+				ValidateFunc: validation.StringInSlice([]string{checksumMethodCRC, checksumMethodTable}, false),
+				Description:  "crc for checksums of the row values that match across versions and flavors, or checksum_table for CHECKSUM TABLE",
+			},
+			"chunk_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10000,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Rows checksummed per query with crc, in primary key order",
+			},
+			"checksums": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"table": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rows": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"checksum": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"combined_checksum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 of the row counts and checksums of all tables, for comparing two servers in one condition",
+			},
+		},
+	}
+}
+
+func ReadTableChecksums(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getReadDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	if database == "" {
+		database = defaultDatabaseFromMeta(meta)
+		if database == "" {
+			return diag.Errorf("database must be set when the provider has no default_database")
+		}
+		d.Set("database", database)
+	}
+	method := d.Get("method").(string)
+
+	checksums := make([]map[string]interface{}, 0)
+	combined := sha256.New()
+	for _, table := range toStringList(d.Get("tables")) {
+		var rows int64
+		var checksum string
+		if method == checksumMethodTable {
+			rows, checksum, err = checksumTable(ctx, db, database, table)
+		} else {
+			rows, checksum, err = crcChecksumTable(ctx, db, database, table, d.Get("chunk_size").(int))
+		}
+		if err != nil {
+			return diag.Errorf("failed checksumming %s.%s: %v", database, table, err)
+		}
+		fmt.Fprintf(combined, "%s:%d:%s\n", table, rows, checksum)
+		checksums = append(checksums, map[string]interface{}{
+			"table":    table,
+			"rows":     rows,
+			"checksum": checksum,
+		})
+	}
+
+	if err := d.Set("checksums", checksums); err != nil {
+		return diag.Errorf("failed setting checksums field: %v", err)
+	}
+	d.Set("combined_checksum", fmt.Sprintf("%x", combined.Sum(nil)))
+	d.SetId(fmt.Sprintf("%s:%s", database, method))
+	return nil
+}
+
+func countTableRows(ctx context.Context, db *sql.DB, name string) (int64, error) {
+	var rows int64
+	stmtSQL := "SELECT COUNT(*) FROM " + name
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	err := db.QueryRowContext(ctx, stmtSQL).Scan(&rows)
+	return rows, err
+}
+
+// checksumTable uses CHECKSUM TABLE, which depends on the row format, so it
+// only matches between servers of the same version.
+func checksumTable(ctx context.Context, db *sql.DB, database, table string) (int64, string, error) {
+	name := quoteIdentifier(database) + "." + quoteIdentifier(table)
+	rows, err := countTableRows(ctx, db, name)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var tableName string
+	var checksum sql.NullInt64
+	stmtSQL := "CHECKSUM TABLE " + name
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	if err := db.QueryRowContext(ctx, stmtSQL).Scan(&tableName, &checksum); err != nil {
+		return 0, "", err
+	}
+	if !checksum.Valid {
+		return 0, "", fmt.Errorf("table doesn't exist")
+	}
+	return rows, strconv.FormatInt(checksum.Int64, 10), nil
+}
+
+// crcRowExpression returns the CRC32 of the values of a row, as
+// pt-table-checksum computes it. The ISNULL flags tell NULL from empty
+// values, which CONCAT_WS skips.
+func crcRowExpression(columns []string) string {
+	quoted := make([]string, len(columns))
+	nulls := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdentifier(column)
+		nulls[i] = "ISNULL(" + quoteIdentifier(column) + ")"
+	}
+	return fmt.Sprintf("CRC32(CONCAT_WS('#', %s, CONCAT(%s)))", strings.Join(quoted, ", "), strings.Join(nulls, ", "))
+}
+
+// crcChecksumTable XORs the CRC32 of all rows, which doesn't depend on their
+// order. Tables with a primary key are read in chunks of it, so no single
+// query scans the whole table; the XOR of the chunks is the same.
+func crcChecksumTable(ctx context.Context, db *sql.DB, database, table string, chunkSize int) (int64, string, error) {
+	name := quoteIdentifier(database) + "." + quoteIdentifier(table)
+	columns, err := queryStrings(ctx, db, "SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION", database, table)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(columns) == 0 {
+		return 0, "", fmt.Errorf("table doesn't exist")
+	}
+	keys, err := queryStrings(ctx, db, "SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY' ORDER BY ORDINAL_POSITION", database, table)
+	if err != nil {
+		return 0, "", err
+	}
+
+	aggregateSQL := fmt.Sprintf("SELECT COUNT(*), COALESCE(BIT_XOR(CAST(%s AS UNSIGNED)), 0) FROM %s", crcRowExpression(columns), name)
+	var rows int64
+	var crc uint64
+	aggregate := func(where string, args ...interface{}) error {
+		var chunkRows int64
+		var chunkCRC uint64
+		stmtSQL := aggregateSQL + where
+		log.Printf("[DEBUG] SQL: %s", stmtSQL)
+		if err := db.QueryRowContext(ctx, stmtSQL, args...).Scan(&chunkRows, &chunkCRC); err != nil {
+			return err
+		}
+		rows += chunkRows
+		crc ^= chunkCRC
+		return nil
+	}
+
+	if len(keys) == 0 {
+		if err := aggregate(""); err != nil {
+			return 0, "", err
+		}
+		return rows, fmt.Sprintf("%08x", crc), nil
+	}
+
+	quotedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		quotedKeys[i] = quoteIdentifier(key)
+	}
+	keyList := "(" + strings.Join(quotedKeys, ", ") + ")"
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ") + ")"
+
+	var lower []interface{}
+	for {
+		where := ""
+		if lower != nil {
+			where = fmt.Sprintf(" WHERE %s > %s", keyList, placeholders)
+		}
+		// The key of the last row of the chunk is its upper bound.
+		stmtSQL := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT 1 OFFSET %d", strings.Join(quotedKeys, ", "), name, where, strings.Join(quotedKeys, ", "), chunkSize-1)
+		log.Printf("[DEBUG] SQL: %s", stmtSQL)
+		values := make([]sql.NullString, len(keys))
+		dest := make([]interface{}, len(keys))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		err := db.QueryRowContext(ctx, stmtSQL, lower...).Scan(dest...)
+		if err == sql.ErrNoRows {
+			if err := aggregate(where, lower...); err != nil {
+				return 0, "", err
+			}
+			return rows, fmt.Sprintf("%08x", crc), nil
+		}
+		if err != nil {
+			return 0, "", err
+		}
+
+		upper := make([]interface{}, len(keys))
+		for i, v := range values {
+			upper[i] = v.String
+		}
+		if lower == nil {
+			err = aggregate(fmt.Sprintf(" WHERE %s <= %s", keyList, placeholders), upper...)
+		} else {
+			err = aggregate(fmt.Sprintf(" WHERE %s > %s AND %s <= %s", keyList, placeholders, keyList, placeholders), append(append([]interface{}{}, lower...), upper...)...)
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		lower = upper
+	}
+}
+
+func queryStrings(ctx context.Context, db *sql.DB, stmtSQL string, args ...interface{}) ([]string, error) {
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestCRCRowExpression(t *testing.T) {
+	got := crcRowExpression([]string{"id", "name"})
+	want := "CRC32(CONCAT_WS('#', `id`, `name`, CONCAT(ISNULL(`id`), ISNULL(`name`))))"
+	if got != want {
+		t.Errorf("crcRowExpression = %s, want %s", got, want)
+	}
+}
+
+func TestAccDataSourceTableChecksums(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipTiDB(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "mysql_table_checksums" "crc" {
+  database   = "mysql"
+  tables     = ["db"]
+  chunk_size = 1
+}
+
+data "mysql_table_checksums" "whole" {
+  database = "mysql"
+  tables   = ["db"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.mysql_table_checksums.crc", "checksums.0.checksum"),
+					resource.TestCheckResourceAttrPair("data.mysql_table_checksums.crc", "combined_checksum", "data.mysql_table_checksums.whole", "combined_checksum"),
+				),
+			},
+		},
+	})
+}
@@ -16,7 +16,8 @@ func dataSourceTables() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"database": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"pattern": {
 				Type:     schema.TypeString,
@@ -32,12 +33,19 @@ func dataSourceTables() *schema.Resource {
 }
 
 func ShowTables(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	db, err := getDatabaseFromMeta(ctx, meta)
+	db, err := getReadDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	database := d.Get("database").(string)
+	if database == "" {
+		database = defaultDatabaseFromMeta(meta)
+		if database == "" {
+			return diag.Errorf("database must be set when the provider has no default_database")
+		}
+		d.Set("database", database)
+	}
 	pattern := d.Get("pattern").(string)
 
 	sql := fmt.Sprintf("SHOW TABLES FROM %s", quoteIdentifier(database))
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceUserDefinition() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadUserDefinition,
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "localhost",
+			},
+			"auth_plugin": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"auth_string_hex": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"grants": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func ReadUserDefinition(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getReadDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	authPlugin, authStringHex, err := readUserAuthString(ctx, db, user, host)
+	if err != nil {
+		return diag.Errorf("failed reading user %s: %v", formatUserIdentifier(user, host), err)
+	}
+
+	grants, err := showGrantStatements(ctx, db, user, host)
+	if err != nil {
+		return diag.Errorf("failed reading grants of %s: %v", formatUserIdentifier(user, host), err)
+	}
+
+	d.Set("auth_plugin", authPlugin)
+	if err := setEncryptedStateValue(ctx, d, meta, "auth_string_hex", authStringHex); err != nil {
+		return diag.Errorf("failed setting auth_string_hex: %v", err)
+	}
+	if err := d.Set("grants", grants); err != nil {
+		return diag.Errorf("failed setting grants field: %v", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s@%s", user, host))
+
+	return nil
+}
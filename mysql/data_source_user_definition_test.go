@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceUserDefinition(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQL8(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserDefinitionConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_user_definition.test", "auth_plugin", "caching_sha2_password"),
+					resource.TestCheckResourceAttrSet("data.mysql_user_definition.test", "auth_string_hex"),
+					resource.TestCheckResourceAttr("data.mysql_user_definition.test", "grants.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+const testAccUserDefinitionConfig = `
+resource "mysql_user" "test" {
+  user               = "jdoe-definition"
+  host               = "example.com"
+  plaintext_password = "password"
+  auth_plugin        = "caching_sha2_password"
+}
+
+resource "mysql_grant" "test" {
+  user       = mysql_user.test.user
+  host       = mysql_user.test.host
+  database   = "mysql"
+  privileges = ["SELECT"]
+}
+
+data "mysql_user_definition" "test" {
+  user = mysql_user.test.user
+  host = mysql_user.test.host
+
+  depends_on = [mysql_grant.test]
+}
+`
@@ -0,0 +1,389 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// userProfile is a named bundle of account settings mysql_user applies
+// through its profile attribute. Unset fields aren't managed.
+type userProfile struct {
+	Name                  string   `json:"name"`
+	TLSOption             string   `json:"tls_option,omitempty"`
+	MaxUserConnections    *int     `json:"max_user_connections,omitempty"`
+	MaxQueriesPerHour     *int     `json:"max_queries_per_hour,omitempty"`
+	MaxUpdatesPerHour     *int     `json:"max_updates_per_hour,omitempty"`
+	MaxConnectionsPerHour *int     `json:"max_connections_per_hour,omitempty"`
+	PasswordExpireDays    *int     `json:"password_expire_days,omitempty"`
+	PasswordHistory       *int     `json:"password_history,omitempty"`
+	PasswordReuseDays     *int     `json:"password_reuse_days,omitempty"`
+	FailedLoginAttempts   *int     `json:"failed_login_attempts,omitempty"`
+	PasswordLockDays      *int     `json:"password_lock_days,omitempty"`
+	DefaultRoles          []string `json:"default_roles,omitempty"`
+}
+
+var userProfileIntAttributes = []string{
+	"max_user_connections",
+	"max_queries_per_hour",
+	"max_updates_per_hour",
+	"max_connections_per_hour",
+	"password_expire_days",
+	"password_history",
+	"password_reuse_days",
+	"failed_login_attempts",
+	"password_lock_days",
+}
+
+func dataSourceUserProfile() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"tls_option": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateTLSOption,
+		},
+		"default_roles": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"definition": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "JSON encoded profile to set as profile of mysql_user",
+		},
+	}
+	for _, name := range userProfileIntAttributes {
+		s[name] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		}
+	}
+	s["password_lock_days"].ValidateFunc = validation.IntAtLeast(-1)
+	s["password_expire_days"].Description = "Days until passwords expire, 0 means never"
+	s["password_lock_days"].Description = "Days accounts stay locked after failed_login_attempts, -1 means until unlocked"
+
+	return &schema.Resource{
+		ReadContext: ReadUserProfile,
+		Schema:      s,
+	}
+}
+
+func ReadUserProfile(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	profile := userProfile{
+		Name:      d.Get("name").(string),
+		TLSOption: d.Get("tls_option").(string),
+	}
+	if roles, ok := d.GetOk("default_roles"); ok {
+		profile.DefaultRoles = setToArray(roles)
+	}
+
+	// Zero values are meaningful, so unset attributes are found in the raw
+	// config.
+	raw := d.GetRawConfig()
+	for name, field := range map[string]**int{
+		"max_user_connections":     &profile.MaxUserConnections,
+		"max_queries_per_hour":     &profile.MaxQueriesPerHour,
+		"max_updates_per_hour":     &profile.MaxUpdatesPerHour,
+		"max_connections_per_hour": &profile.MaxConnectionsPerHour,
+		"password_expire_days":     &profile.PasswordExpireDays,
+		"password_history":         &profile.PasswordHistory,
+		"password_reuse_days":      &profile.PasswordReuseDays,
+		"failed_login_attempts":    &profile.FailedLoginAttempts,
+		"password_lock_days":       &profile.PasswordLockDays,
+	} {
+		if raw.IsNull() || raw.GetAttr(name).IsNull() {
+			continue
+		}
+		value := d.Get(name).(int)
+		*field = &value
+	}
+
+	definition, err := json.Marshal(profile)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("definition", string(definition))
+	d.SetId(profile.Name)
+
+	return nil
+}
+
+func parseUserProfile(definition string) (*userProfile, error) {
+	if definition == "" {
+		return nil, nil
+	}
+	var profile userProfile
+	if err := json.Unmarshal([]byte(definition), &profile); err != nil {
+		return nil, fmt.Errorf("failed parsing profile: %v", err)
+	}
+	if _, errs := validateTLSOption(profile.TLSOption, "tls_option"); len(errs) > 0 {
+		return nil, fmt.Errorf("profile %s: %v", profile.Name, errs[0])
+	}
+	return &profile, nil
+}
+
+func validateUserProfile(v interface{}, k string) (ws []string, es []error) {
+	if _, err := parseUserProfile(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s must be the definition of a mysql_user_profile: %v", k, err))
+	}
+	return
+}
+
+// userProfileStatements returns the ALTER USER statements moving an account
+// from the old to the new profile. Settings the new profile no longer has
+// are reset to server defaults. Settings set on the user itself are skipped.
+func userProfileStatements(account string, old, new *userProfile, skipTLS, skipMaxUserConnections bool) []string {
+	if old == nil {
+		old = &userProfile{}
+	}
+	if new == nil {
+		new = &userProfile{}
+	}
+
+	var stmts []string
+	if !skipTLS && (new.TLSOption != "" || old.TLSOption != "") {
+		tlsOption := new.TLSOption
+		if tlsOption == "" {
+			tlsOption = "NONE"
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER USER %s REQUIRE %s", account, tlsOption))
+	}
+
+	var limits []string
+	for _, limit := range []struct {
+		name     string
+		old, new *int
+		skip     bool
+	}{
+		{"MAX_QUERIES_PER_HOUR", old.MaxQueriesPerHour, new.MaxQueriesPerHour, false},
+		{"MAX_UPDATES_PER_HOUR", old.MaxUpdatesPerHour, new.MaxUpdatesPerHour, false},
+		{"MAX_CONNECTIONS_PER_HOUR", old.MaxConnectionsPerHour, new.MaxConnectionsPerHour, false},
+		{"MAX_USER_CONNECTIONS", old.MaxUserConnections, new.MaxUserConnections, skipMaxUserConnections},
+	} {
+		if limit.skip || (limit.new == nil && limit.old == nil) {
+			continue
+		}
+		value := 0
+		if limit.new != nil {
+			value = *limit.new
+		}
+		limits = append(limits, fmt.Sprintf("%s %d", limit.name, value))
+	}
+	if len(limits) > 0 {
+		stmts = append(stmts, fmt.Sprintf("ALTER USER %s WITH %s", account, strings.Join(limits, " ")))
+	}
+
+	var policy []string
+	if new.PasswordExpireDays != nil {
+		if *new.PasswordExpireDays == 0 {
+			policy = append(policy, "PASSWORD EXPIRE NEVER")
+		} else {
+			policy = append(policy, fmt.Sprintf("PASSWORD EXPIRE INTERVAL %d DAY", *new.PasswordExpireDays))
+		}
+	} else if old.PasswordExpireDays != nil {
+		policy = append(policy, "PASSWORD EXPIRE DEFAULT")
+	}
+	if new.PasswordHistory != nil {
+		policy = append(policy, fmt.Sprintf("PASSWORD HISTORY %d", *new.PasswordHistory))
+	} else if old.PasswordHistory != nil {
+		policy = append(policy, "PASSWORD HISTORY DEFAULT")
+	}
+	if new.PasswordReuseDays != nil {
+		policy = append(policy, fmt.Sprintf("PASSWORD REUSE INTERVAL %d DAY", *new.PasswordReuseDays))
+	} else if old.PasswordReuseDays != nil {
+		policy = append(policy, "PASSWORD REUSE INTERVAL DEFAULT")
+	}
+	if new.FailedLoginAttempts != nil {
+		policy = append(policy, fmt.Sprintf("FAILED_LOGIN_ATTEMPTS %d", *new.FailedLoginAttempts))
+	} else if old.FailedLoginAttempts != nil {
+		policy = append(policy, "FAILED_LOGIN_ATTEMPTS 0")
+	}
+	if new.PasswordLockDays != nil {
+		if *new.PasswordLockDays < 0 {
+			policy = append(policy, "PASSWORD_LOCK_TIME UNBOUNDED")
+		} else {
+			policy = append(policy, fmt.Sprintf("PASSWORD_LOCK_TIME %d", *new.PasswordLockDays))
+		}
+	} else if old.PasswordLockDays != nil {
+		policy = append(policy, "PASSWORD_LOCK_TIME 0")
+	}
+	if len(policy) > 0 {
+		stmts = append(stmts, fmt.Sprintf("ALTER USER %s %s", account, strings.Join(policy, " ")))
+	}
+
+	return stmts
+}
+
+// applyUserProfile moves the account from the old to the new profile.
+func applyUserProfile(ctx context.Context, db *sql.DB, d *schema.ResourceData, user, host string, old, new *userProfile) error {
+	skipTLS := normalizeTLSOption(d.Get("tls_option").(string)) != "NONE"
+	_, skipMaxUserConnections := d.GetOk("max_user_connections")
+
+	for _, stmtSQL := range userProfileStatements(formatUserIdentifier(user, host), old, new, skipTLS, skipMaxUserConnections) {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed applying profile: %v", err)
+		}
+	}
+
+	var oldRoles, newRoles []string
+	if old != nil {
+		oldRoles = old.DefaultRoles
+	}
+	if new != nil {
+		newRoles = new.DefaultRoles
+	}
+	if len(newRoles) > 0 || len(oldRoles) > 0 {
+		if err := alterUserDefaultRoles(ctx, db, user, host, newRoles); err != nil {
+			return fmt.Errorf("failed setting default roles of profile: %v", err)
+		}
+	}
+	return nil
+}
+
+var (
+	kReProfileQueries     = regexp.MustCompile(` MAX_QUERIES_PER_HOUR (\d+)`)
+	kReProfileUpdates     = regexp.MustCompile(` MAX_UPDATES_PER_HOUR (\d+)`)
+	kReProfileConnections = regexp.MustCompile(` MAX_CONNECTIONS_PER_HOUR (\d+)`)
+	kReProfileUserConns   = regexp.MustCompile(` MAX_USER_CONNECTIONS (\d+)`)
+	kReProfileExpire      = regexp.MustCompile(` PASSWORD EXPIRE (NEVER|DEFAULT|INTERVAL (\d+) DAY)`)
+	kReProfileHistory     = regexp.MustCompile(` PASSWORD HISTORY (\d+)`)
+	kReProfileReuse       = regexp.MustCompile(` PASSWORD REUSE INTERVAL (\d+) DAY`)
+	kReProfileAttempts    = regexp.MustCompile(` FAILED_LOGIN_ATTEMPTS (\d+)`)
+	kReProfileLockTime    = regexp.MustCompile(` PASSWORD_LOCK_TIME (\d+|UNBOUNDED)`)
+)
+
+// appliedUserProfile returns the settings of the profile the account has,
+// from SHOW CREATE USER output. Only settings of the profile are read, and
+// settings set on the user itself are taken from the profile. Settings left
+// at server defaults are unset.
+func appliedUserProfile(createUserStmt string, profile *userProfile, skipTLS, skipMaxUserConnections bool) *userProfile {
+	stmt := sanitizeCreateUserStatement(createUserStmt)
+	applied := *profile
+
+	if profile.TLSOption != "" && !skipTLS {
+		applied.TLSOption = readTLSOption(stmt)
+	}
+
+	// Limits of 0 aren't shown.
+	readInt := func(field **int, re *regexp.Regexp) {
+		if *field == nil {
+			return
+		}
+		value := 0
+		if m := re.FindStringSubmatch(stmt); m != nil {
+			value, _ = strconv.Atoi(m[1])
+		}
+		*field = &value
+	}
+	readInt(&applied.MaxQueriesPerHour, kReProfileQueries)
+	readInt(&applied.MaxUpdatesPerHour, kReProfileUpdates)
+	readInt(&applied.MaxConnectionsPerHour, kReProfileConnections)
+	if !skipMaxUserConnections {
+		readInt(&applied.MaxUserConnections, kReProfileUserConns)
+	}
+	readInt(&applied.FailedLoginAttempts, kReProfileAttempts)
+
+	// DEFAULT isn't a value of the profile.
+	readOptional := func(field **int, re *regexp.Regexp, parse func(m []string) (int, bool)) {
+		if *field == nil {
+			return
+		}
+		*field = nil
+		if m := re.FindStringSubmatch(stmt); m != nil {
+			if value, ok := parse(m); ok {
+				*field = &value
+			}
+		}
+	}
+	readOptional(&applied.PasswordExpireDays, kReProfileExpire, func(m []string) (int, bool) {
+		switch m[1] {
+		case "NEVER":
+			return 0, true
+		case "DEFAULT":
+			return 0, false
+		}
+		value, err := strconv.Atoi(m[2])
+		return value, err == nil
+	})
+	number := func(m []string) (int, bool) {
+		value, err := strconv.Atoi(m[1])
+		return value, err == nil
+	}
+	readOptional(&applied.PasswordHistory, kReProfileHistory, number)
+	readOptional(&applied.PasswordReuseDays, kReProfileReuse, number)
+	if applied.PasswordLockDays != nil {
+		value := 0
+		if m := kReProfileLockTime.FindStringSubmatch(stmt); m != nil {
+			if m[1] == "UNBOUNDED" {
+				value = -1
+			} else {
+				value, _ = strconv.Atoi(m[1])
+			}
+		}
+		applied.PasswordLockDays = &value
+	}
+
+	return &applied
+}
+
+// userProfileEqual reports whether two profiles have the same settings.
+func userProfileEqual(a, b *userProfile) bool {
+	normalize := func(p userProfile) userProfile {
+		if p.TLSOption != "" {
+			p.TLSOption = normalizeTLSOption(p.TLSOption)
+		}
+		p.DefaultRoles = slices.Sorted(slices.Values(p.DefaultRoles))
+		return p
+	}
+	return reflect.DeepEqual(normalize(*a), normalize(*b))
+}
+
+// readUserProfileDrift compares the account with its profile. When settings
+// of the profile were changed outside of Terraform, profile is set to the
+// settings the account has, so the next plan applies the profile again.
+func readUserProfileDrift(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, user, host, createUserStmt string) error {
+	profile, err := parseUserProfile(d.Get("profile").(string))
+	if err != nil || profile == nil {
+		return err
+	}
+	skipTLS := normalizeTLSOption(d.Get("tls_option").(string)) != "NONE"
+	_, skipMaxUserConnections := d.GetOk("max_user_connections")
+
+	applied := appliedUserProfile(createUserStmt, profile, skipTLS, skipMaxUserConnections)
+	if len(profile.DefaultRoles) > 0 {
+		roles, err := readUserDefaultRoles(ctx, db, meta, user, host)
+		if err != nil {
+			return fmt.Errorf("failed reading default roles of profile: %v", err)
+		}
+		applied.DefaultRoles = roles
+	}
+	if userProfileEqual(profile, applied) {
+		return nil
+	}
+
+	log.Printf("[WARN] Settings of profile %s drifted on %s", profile.Name, formatUserIdentifier(user, host))
+	definition, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+	d.Set("profile", string(definition))
+	return nil
+}
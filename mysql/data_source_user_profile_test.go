@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUser_profile(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.19")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserProfileConfig("SSL", 5),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+					resource.TestMatchResourceAttr("mysql_user.test", "create_user_statement", regexp.MustCompile(`REQUIRE SSL .*PASSWORD EXPIRE INTERVAL 90 DAY`)),
+					resource.TestMatchResourceAttr("mysql_user.test", "create_user_statement", regexp.MustCompile(`MAX_QUERIES_PER_HOUR 1000`)),
+				),
+			},
+			{
+				Config: testAccUserProfileConfig("X509", 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("mysql_user.test", "create_user_statement", regexp.MustCompile(`REQUIRE X509 .*FAILED_LOGIN_ATTEMPTS 10`)),
+				),
+			},
+		},
+	})
+}
+
+func TestUserProfileStatements(t *testing.T) {
+	five, ninety := 5, 90
+	old := &userProfile{TLSOption: "SSL", MaxUserConnections: &five, PasswordExpireDays: &ninety}
+	new := &userProfile{MaxQueriesPerHour: &five, PasswordHistory: &five}
+
+	got := userProfileStatements("`jdoe`@`%`", old, new, false, false)
+	expected := []string{
+		"ALTER USER `jdoe`@`%` REQUIRE NONE",
+		"ALTER USER `jdoe`@`%` WITH MAX_QUERIES_PER_HOUR 5 MAX_USER_CONNECTIONS 0",
+		"ALTER USER `jdoe`@`%` PASSWORD EXPIRE DEFAULT PASSWORD HISTORY 5",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected statements %q", got)
+	}
+
+	// Settings of the user win over the profile.
+	got = userProfileStatements("`jdoe`@`%`", nil, old, true, true)
+	expected = []string{"ALTER USER `jdoe`@`%` PASSWORD EXPIRE INTERVAL 90 DAY"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected statements %q", got)
+	}
+}
+
+func testAccUserProfileConfig(tlsOption string, failedLoginAttempts int) string {
+	return fmt.Sprintf(`
+data "mysql_user_profile" "standard" {
+  name                  = "standard"
+  tls_option            = "%s"
+  max_queries_per_hour  = 1000
+  password_expire_days  = 90
+  failed_login_attempts = %d
+  password_lock_days    = 1
+}
+
+resource "mysql_user" "test" {
+  user               = "jdoe-profile"
+  host               = "example.com"
+  plaintext_password = "password"
+  profile            = data.mysql_user_profile.standard.definition
+}
+`, tlsOption, failedLoginAttempts)
+}
+
+func TestAppliedUserProfile(t *testing.T) {
+	zero, two, five, ninety, unbounded := 0, 2, 5, 90, -1
+	profile := &userProfile{
+		Name:                "standard",
+		TLSOption:           "SSL",
+		MaxQueriesPerHour:   &five,
+		MaxUserConnections:  &five,
+		PasswordExpireDays:  &ninety,
+		PasswordHistory:     &five,
+		FailedLoginAttempts: &five,
+		PasswordLockDays:    &unbounded,
+	}
+
+	applied := "CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS 'x' REQUIRE SSL WITH MAX_QUERIES_PER_HOUR 5 MAX_USER_CONNECTIONS 5 PASSWORD EXPIRE INTERVAL 90 DAY ACCOUNT UNLOCK PASSWORD HISTORY 5 PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT FAILED_LOGIN_ATTEMPTS 5 PASSWORD_LOCK_TIME UNBOUNDED"
+	if got := appliedUserProfile(applied, profile, false, false); !userProfileEqual(got, profile) {
+		t.Errorf("expected no drift, got %+v", got)
+	}
+
+	drifted := "CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS 'x' REQUIRE NONE WITH MAX_USER_CONNECTIONS 2 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY 5 PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT"
+	got := appliedUserProfile(drifted, profile, false, false)
+	expected := &userProfile{
+		Name:                "standard",
+		TLSOption:           "NONE",
+		MaxQueriesPerHour:   &zero,
+		MaxUserConnections:  &two,
+		PasswordHistory:     &five,
+		FailedLoginAttempts: &zero,
+		PasswordLockDays:    &zero,
+	}
+	if !userProfileEqual(got, expected) {
+		t.Errorf("unexpected applied profile %+v", got)
+	}
+
+	// Settings of the user itself aren't drift of the profile.
+	got = appliedUserProfile(drifted, profile, true, true)
+	if got.TLSOption != "SSL" || *got.MaxUserConnections != 5 {
+		t.Errorf("expected settings of the user to be skipped, got %+v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// information_schema reports grantees as 'user'@'host', doubling quotes.
+var kReGrantee = regexp.MustCompile(`^'((?:[^']|'')*)'@'((?:[^']|'')*)'$`)
+
+func dataSourceUsersWithPrivilege() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadUsersWithPrivilege,
+		Schema: map[string]*schema.Schema{
+			"privilege": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Privilege to look for, e.g. SELECT",
+			},
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "*",
+			},
+			"table": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "*",
+			},
+			"column": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"level": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Where the privilege is granted: global, database, table or column",
+						},
+						"grant_option": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadUsersWithPrivilege(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getReadDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	privilege := strings.ToUpper(strings.TrimSpace(d.Get("privilege").(string)))
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	column := d.Get("column").(string)
+	if database == "*" && table != "*" {
+		return diag.Errorf("database must be set when table is set")
+	}
+	if column != "" && table == "*" {
+		return diag.Errorf("table must be set when column is set")
+	}
+
+	// Privileges on an object are granted on it or any level above it.
+	// Database level grants may use wildcards, so they are matched with LIKE.
+	queries := []string{"SELECT GRANTEE, 'global', IS_GRANTABLE FROM information_schema.USER_PRIVILEGES WHERE PRIVILEGE_TYPE = ?"}
+	args := []interface{}{privilege}
+	if database != "*" {
+		queries = append(queries, "SELECT GRANTEE, 'database', IS_GRANTABLE FROM information_schema.SCHEMA_PRIVILEGES WHERE PRIVILEGE_TYPE = ? AND ? LIKE TABLE_SCHEMA")
+		args = append(args, privilege, database)
+	}
+	if table != "*" {
+		queries = append(queries, "SELECT GRANTEE, 'table', IS_GRANTABLE FROM information_schema.TABLE_PRIVILEGES WHERE PRIVILEGE_TYPE = ? AND TABLE_SCHEMA = ? AND TABLE_NAME = ?")
+		args = append(args, privilege, database, table)
+	}
+	if column != "" {
+		queries = append(queries, "SELECT GRANTEE, 'column', IS_GRANTABLE FROM information_schema.COLUMN_PRIVILEGES WHERE PRIVILEGE_TYPE = ? AND TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME = ?")
+		args = append(args, privilege, database, table, column)
+	}
+	stmtSQL := strings.Join(queries, " UNION ALL ")
+
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return diag.Errorf("failed querying for privileges: %v", err)
+	}
+	defer rows.Close()
+
+	users := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		var grantee, level, grantable string
+		if err := rows.Scan(&grantee, &level, &grantable); err != nil {
+			return diag.Errorf("failed scanning MySQL rows: %v", err)
+		}
+		user, host, err := parseGrantee(grantee)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		users = append(users, map[string]interface{}{
+			"user":         user,
+			"host":         host,
+			"level":        level,
+			"grant_option": grantable == "YES",
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed querying for privileges: %v", err)
+	}
+
+	if err := d.Set("users", users); err != nil {
+		return diag.Errorf("failed setting users field: %v", err)
+	}
+
+	d.SetId(id.UniqueId())
+
+	return nil
+}
+
+func parseGrantee(grantee string) (string, string, error) {
+	m := kReGrantee.FindStringSubmatch(grantee)
+	if m == nil {
+		return "", "", fmt.Errorf("failed parsing grantee %q", grantee)
+	}
+	return strings.ReplaceAll(m[1], "''", "'"), strings.ReplaceAll(m[2], "''", "'"), nil
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceUsersWithPrivilege(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUsersWithPrivilegeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.mysql_users_with_privilege.test", "users.*", map[string]string{
+						"user":         "jdoe-privilege",
+						"host":         "example.com",
+						"level":        "table",
+						"grant_option": "false",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestParseGrantee(t *testing.T) {
+	tests := []struct {
+		grantee, user, host string
+	}{
+		{"'root'@'localhost'", "root", "localhost"},
+		{"'o''brien'@'%'", "o'brien", "%"},
+		{"'app'@'10.0.0.0/255.255.0.0'", "app", "10.0.0.0/255.255.0.0"},
+	}
+	for _, tt := range tests {
+		user, host, err := parseGrantee(tt.grantee)
+		if err != nil || user != tt.user || host != tt.host {
+			t.Errorf("parseGrantee(%q) = %q, %q, %v", tt.grantee, user, host, err)
+		}
+	}
+	if _, _, err := parseGrantee("root@localhost"); err == nil {
+		t.Errorf("expected an error for an unquoted grantee")
+	}
+}
+
+const testAccUsersWithPrivilegeConfig = `
+resource "mysql_user" "test" {
+  user               = "jdoe-privilege"
+  host               = "example.com"
+  plaintext_password = "password"
+}
+
+resource "mysql_grant" "test" {
+  user       = mysql_user.test.user
+  host       = mysql_user.test.host
+  database   = "mysql"
+  table      = "user"
+  privileges = ["SELECT"]
+}
+
+data "mysql_users_with_privilege" "test" {
+  privilege = "select"
+  database  = "mysql"
+  table     = "user"
+
+  depends_on = [mysql_grant.test]
+}
+`
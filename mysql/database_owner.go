@@ -0,0 +1,385 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// databaseGrantee is a user or a role with privileges on a database created
+// by mysql_database.
+type databaseGrantee struct {
+	User string
+	Host string
+	Role string
+}
+
+func (g databaseGrantee) String() string {
+	if g.Role != "" {
+		return "role " + g.Role
+	}
+	return fmt.Sprintf("user %s@%s", g.User, g.Host)
+}
+
+func (g databaseGrantee) sqlString() string {
+	if g.Role != "" {
+		return quoteIdentifier(g.Role)
+	}
+	return formatUserIdentifier(g.User, g.Host)
+}
+
+// databaseOwner is the owner block of mysql_database: an account created
+// with the database, with all privileges on it. Users of mysql_tenant are
+// created the same way, with a connection limit.
+type databaseOwner struct {
+	databaseGrantee
+	Password string
+	// MaxUserConnections limits the sessions of a user, 0 for no limit.
+	MaxUserConnections int
+}
+
+// databaseGrant is an element of the initial_grants of mysql_database.
+type databaseGrant struct {
+	databaseGrantee
+	Privileges []string
+}
+
+func databaseOwnerSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "User or role created with the database and granted all privileges on it, dropped with the database",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateNotReservedAccount,
+				},
+				"host": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "%",
+				},
+				"role": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"plaintext_password": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "Password of the owner user",
+				},
+			},
+		},
+	}
+}
+
+func databaseInitialGrantsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Privileges on the database granted to existing users or roles once it's created, and revoked before it's dropped",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateNotReservedAccount,
+				},
+				"host": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "%",
+				},
+				"role": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"privileges": {
+					Type:     schema.TypeSet,
+					Required: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Set:      schema.HashString,
+				},
+			},
+		},
+	}
+}
+
+func databaseGranteeFrom(m map[string]interface{}) databaseGrantee {
+	g := databaseGrantee{Role: m["role"].(string)}
+	if g.Role == "" {
+		g.User = m["user"].(string)
+		g.Host = m["host"].(string)
+	}
+	return g
+}
+
+// databaseOwnerFrom returns the owner of an owner block, or nil without
+// one.
+func databaseOwnerFrom(v interface{}) *databaseOwner {
+	block, _ := v.([]interface{})
+	if len(block) == 0 || block[0] == nil {
+		return nil
+	}
+	m := block[0].(map[string]interface{})
+	return &databaseOwner{databaseGrantee: databaseGranteeFrom(m), Password: m["plaintext_password"].(string)}
+}
+
+func databaseGrantsFrom(v interface{}) []databaseGrant {
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return nil
+	}
+	var grants []databaseGrant
+	for _, e := range set.List() {
+		m := e.(map[string]interface{})
+		grants = append(grants, databaseGrant{databaseGrantee: databaseGranteeFrom(m), Privileges: setToArray(m["privileges"])})
+	}
+	return grants
+}
+
+func (o *databaseOwner) createSQL() string {
+	if o.Role != "" {
+		return "CREATE ROLE " + o.sqlString()
+	}
+	stmtSQL := fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", o.sqlString(), quoteString(o.Password))
+	if o.MaxUserConnections > 0 {
+		stmtSQL += fmt.Sprintf(" WITH MAX_USER_CONNECTIONS %d", o.MaxUserConnections)
+	}
+	return stmtSQL
+}
+
+func (o *databaseOwner) dropSQL() string {
+	if o.Role != "" {
+		return "DROP ROLE IF EXISTS " + o.sqlString()
+	}
+	return "DROP USER IF EXISTS " + o.sqlString()
+}
+
+func (o *databaseOwner) grantSQL(database string) string {
+	return fmt.Sprintf("GRANT ALL PRIVILEGES ON %s.* TO %s", quoteIdentifier(database), o.sqlString())
+}
+
+func (g databaseGrant) grantSQL(database string) string {
+	return fmt.Sprintf("GRANT %s ON %s.* TO %s", strings.Join(g.Privileges, ", "), quoteIdentifier(database), g.sqlString())
+}
+
+func (g databaseGrant) revokeSQL(database string) string {
+	return fmt.Sprintf("REVOKE %s ON %s.* FROM %s", strings.Join(g.Privileges, ", "), quoteIdentifier(database), g.sqlString())
+}
+
+// checkDatabaseAccess validates the owner and initial_grants of a database
+// at plan time.
+func checkDatabaseAccess(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("owner") && !d.HasChange("initial_grants") {
+		return nil
+	}
+	var hasRole bool
+	if d.NewValueKnown("owner") {
+		if block, _ := d.Get("owner").([]interface{}); len(block) > 0 && block[0] != nil {
+			m := block[0].(map[string]interface{})
+			if err := checkDatabaseGrantee(m, "owner"); err != nil {
+				return err
+			}
+			if m["user"].(string) != "" && m["plaintext_password"].(string) == "" {
+				return errors.New("owner: plaintext_password is required for a user")
+			}
+			hasRole = hasRole || m["role"].(string) != ""
+		}
+	}
+	if d.NewValueKnown("initial_grants") {
+		for _, e := range d.Get("initial_grants").(*schema.Set).List() {
+			m := e.(map[string]interface{})
+			if err := checkDatabaseGrantee(m, "initial_grants"); err != nil {
+				return err
+			}
+			hasRole = hasRole || m["role"].(string) != ""
+		}
+	}
+	if hasRole {
+		return checkTiDBRoleSupport(ctx, meta, false)
+	}
+	return nil
+}
+
+func checkDatabaseGrantee(m map[string]interface{}, key string) error {
+	user, role := m["user"].(string), m["role"].(string)
+	if (user == "") == (role == "") {
+		return fmt.Errorf("%s: exactly one of user or role must be set", key)
+	}
+	return nil
+}
+
+// databaseStatements runs statements of the access of a database, undoing
+// the ones run so far when one fails.
+type databaseStatements struct {
+	db   *sql.DB
+	undo []string
+}
+
+func (s *databaseStatements) exec(ctx context.Context, stmtSQL, logged, undoSQL string) error {
+	log.Println("[DEBUG] Executing statement:", logged)
+	if _, err := s.db.ExecContext(ctx, stmtSQL); err != nil {
+		return err
+	}
+	if undoSQL != "" {
+		s.undo = append(s.undo, undoSQL)
+	}
+	return nil
+}
+
+// rollback runs the undo statements in reverse. MySQL commits DDL
+// implicitly, so they're the closest to a rollback.
+func (s *databaseStatements) rollback(ctx context.Context) {
+	for i := len(s.undo) - 1; i >= 0; i-- {
+		log.Println("[DEBUG] Rolling back with statement:", s.undo[i])
+		if _, err := s.db.ExecContext(ctx, s.undo[i]); err != nil {
+			log.Printf("[WARN] Failed rolling back with %q: %v", s.undo[i], err)
+		}
+	}
+}
+
+func createDatabaseOwner(ctx context.Context, s *databaseStatements, database string, owner *databaseOwner) error {
+	createSQL := owner.createSQL()
+	logged := createSQL
+	if owner.Role == "" {
+		logged = "CREATE USER " + owner.sqlString()
+	}
+	if err := s.exec(ctx, createSQL, logged, owner.dropSQL()); err != nil {
+		return fmt.Errorf("failed creating owner %s: %v", owner, err)
+	}
+	grantSQL := owner.grantSQL(database)
+	if err := s.exec(ctx, grantSQL, grantSQL, ""); err != nil {
+		return fmt.Errorf("failed granting owner %s: %v", owner, err)
+	}
+	return nil
+}
+
+// bootstrapDatabaseAccess creates the owner of a database just created and
+// makes its initial grants. When one fails, the owner and the grants are
+// undone, as well as the database with dropDatabase.
+func bootstrapDatabaseAccess(ctx context.Context, db *sql.DB, d *schema.ResourceData, dropDatabase bool) error {
+	database := d.Get("name").(string)
+	s := &databaseStatements{db: db}
+	if dropDatabase {
+		s.undo = append(s.undo, "DROP DATABASE "+quoteIdentifier(database))
+	}
+
+	err := func() error {
+		if owner := databaseOwnerFrom(d.Get("owner")); owner != nil {
+			if err := createDatabaseOwner(ctx, s, database, owner); err != nil {
+				return err
+			}
+		}
+		for _, g := range databaseGrantsFrom(d.Get("initial_grants")) {
+			if err := s.exec(ctx, g.grantSQL(database), g.grantSQL(database), g.revokeSQL(database)); err != nil {
+				return fmt.Errorf("failed granting %s on %s to %s: %v", strings.Join(g.Privileges, ", "), database, g.databaseGrantee, err)
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		s.rollback(ctx)
+		return err
+	}
+	return nil
+}
+
+// updateDatabaseAccess applies changes of the owner and initial_grants. A
+// new owner is created before the old one is dropped.
+func updateDatabaseAccess(ctx context.Context, db *sql.DB, d *schema.ResourceData) error {
+	database := d.Get("name").(string)
+	s := &databaseStatements{db: db}
+
+	if d.HasChange("owner") {
+		o, n := d.GetChange("owner")
+		oldOwner, newOwner := databaseOwnerFrom(o), databaseOwnerFrom(n)
+		switch {
+		case oldOwner != nil && newOwner != nil && oldOwner.databaseGrantee == newOwner.databaseGrantee:
+			if newOwner.Role == "" && oldOwner.Password != newOwner.Password {
+				stmtSQL := fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", newOwner.sqlString(), quoteString(newOwner.Password))
+				if err := s.exec(ctx, stmtSQL, "ALTER USER "+newOwner.sqlString(), ""); err != nil {
+					return fmt.Errorf("failed changing the password of owner %s: %v", newOwner, err)
+				}
+			}
+		default:
+			if newOwner != nil {
+				if err := createDatabaseOwner(ctx, s, database, newOwner); err != nil {
+					s.rollback(ctx)
+					return err
+				}
+			}
+			if oldOwner != nil {
+				if err := s.exec(ctx, oldOwner.dropSQL(), oldOwner.dropSQL(), ""); err != nil {
+					return fmt.Errorf("failed dropping previous owner %s: %v", oldOwner, err)
+				}
+			}
+		}
+	}
+
+	if d.HasChange("initial_grants") {
+		o, n := d.GetChange("initial_grants")
+		oldGrants, newGrants := o.(*schema.Set), n.(*schema.Set)
+		for _, g := range databaseGrantsFrom(oldGrants.Difference(newGrants)) {
+			if err := revokeDatabaseGrant(ctx, db, database, g); err != nil {
+				return err
+			}
+		}
+		for _, g := range databaseGrantsFrom(newGrants.Difference(oldGrants)) {
+			if err := s.exec(ctx, g.grantSQL(database), g.grantSQL(database), ""); err != nil {
+				return fmt.Errorf("failed granting %s on %s to %s: %v", strings.Join(g.Privileges, ", "), database, g.databaseGrantee, err)
+			}
+		}
+	}
+	return nil
+}
+
+// revokeDatabaseGrant revokes an initial grant. Grants revoked or accounts
+// dropped outside of Terraform are skipped.
+func revokeDatabaseGrant(ctx context.Context, db *sql.DB, database string, g databaseGrant) error {
+	stmtSQL := g.revokeSQL(database)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	_, err := db.ExecContext(ctx, stmtSQL)
+	if isNonExistingGrant(err) {
+		log.Printf("[WARN] Grant of %s on %s to %s not found, skipping its revoke", strings.Join(g.Privileges, ", "), database, g.databaseGrantee)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed revoking %s on %s from %s: %v", strings.Join(g.Privileges, ", "), database, g.databaseGrantee, err)
+	}
+	return nil
+}
+
+// revokeDatabaseAccess revokes the initial grants of a database before it's
+// dropped, as dropping a database keeps the grants on it.
+func revokeDatabaseAccess(ctx context.Context, db *sql.DB, d *schema.ResourceData) error {
+	for _, g := range databaseGrantsFrom(d.Get("initial_grants")) {
+		if err := revokeDatabaseGrant(ctx, db, d.Id(), g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropDatabaseOwner drops the owner of a dropped database.
+func dropDatabaseOwner(ctx context.Context, db *sql.DB, d *schema.ResourceData) error {
+	owner := databaseOwnerFrom(d.Get("owner"))
+	if owner == nil {
+		return nil
+	}
+	stmtSQL := owner.dropSQL()
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed dropping owner %s: %v", owner, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDatabaseAccessStatements(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabase().Schema, map[string]interface{}{
+		"name": "app",
+		"owner": []interface{}{map[string]interface{}{
+			"user":               "app",
+			"plaintext_password": "it's",
+		}},
+		"initial_grants": []interface{}{map[string]interface{}{
+			"role":       "readers",
+			"privileges": []interface{}{"SELECT"},
+		}},
+	})
+
+	owner := databaseOwnerFrom(d.Get("owner"))
+	if owner == nil {
+		t.Fatal("no owner")
+	}
+	grants := databaseGrantsFrom(d.Get("initial_grants"))
+	if len(grants) != 1 {
+		t.Fatalf("got %d initial grants, want 1", len(grants))
+	}
+	role := &databaseOwner{databaseGrantee: databaseGrantee{Role: "app_owner"}}
+
+	tests := []struct {
+		got, want string
+	}{
+		{owner.createSQL(), "CREATE USER `app`@`%` IDENTIFIED BY 'it\\'s'"},
+		{owner.grantSQL("app"), "GRANT ALL PRIVILEGES ON `app`.* TO `app`@`%`"},
+		{owner.dropSQL(), "DROP USER IF EXISTS `app`@`%`"},
+		{owner.String(), "user app@%"},
+		{grants[0].grantSQL("app"), "GRANT SELECT ON `app`.* TO `readers`"},
+		{grants[0].revokeSQL("app"), "REVOKE SELECT ON `app`.* FROM `readers`"},
+		{role.createSQL(), "CREATE ROLE `app_owner`"},
+		{role.dropSQL(), "DROP ROLE IF EXISTS `app_owner`"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+
+	if databaseOwnerFrom([]interface{}{}) != nil {
+		t.Error("empty owner block has an owner")
+	}
+}
+
+func TestCheckDatabaseGrantee(t *testing.T) {
+	tests := []struct {
+		user, role string
+		valid      bool
+	}{
+		{"app", "", true},
+		{"", "app", true},
+		{"", "", false},
+		{"app", "app", false},
+	}
+	for _, tt := range tests {
+		err := checkDatabaseGrantee(map[string]interface{}{"user": tt.user, "role": tt.role}, "owner")
+		if (err == nil) != tt.valid {
+			t.Errorf("checkDatabaseGrantee(%q, %q) = %v, want valid %v", tt.user, tt.role, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package mysql
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mergeDSNConfig returns the driver configuration of the dsn argument with
+// the settings of the provider applied on top, so any driver option can be
+// passed without its own provider argument. Options the provider relies on
+// are overridden, and insecure ones are refused.
+func mergeDSNConfig(dsnConf, provider *mysql.Config) (*mysql.Config, error) {
+	if dsnConf.AllowAllFiles {
+		return nil, fmt.Errorf("dsn option allowAllFiles is not supported")
+	}
+	for k := range dsnConf.Params {
+		// ANSI_QUOTES and other modes break the statements of the provider.
+		if strings.EqualFold(k, "sql_mode") {
+			return nil, fmt.Errorf("dsn parameter sql_mode is not supported")
+		}
+	}
+
+	conf := dsnConf.Clone()
+	conf.User = provider.User
+	conf.Passwd = provider.Passwd
+	conf.Net = provider.Net
+	conf.Addr = provider.Addr
+	conf.AllowNativePasswords = provider.AllowNativePasswords
+	conf.AllowCleartextPasswords = dsnConf.AllowCleartextPasswords || provider.AllowCleartextPasswords
+	conf.InterpolateParams = true
+
+	// The tls argument defaults to false, which leaves the TLS of the DSN.
+	if provider.TLS != nil || (provider.TLSConfig != "" && provider.TLSConfig != "false") {
+		conf.TLS = provider.TLS
+		conf.TLSConfig = provider.TLSConfig
+	}
+	if dsnConf.AllowCleartextPasswords && !provider.AllowCleartextPasswords &&
+		conf.Net != "unix" && conf.TLS == nil && (conf.TLSConfig == "" || conf.TLSConfig == "false") {
+		return nil, fmt.Errorf("dsn option allowCleartextPasswords requires TLS")
+	}
+
+	if conf.Params == nil {
+		conf.Params = make(map[string]string)
+	}
+	for k, v := range provider.Params {
+		conf.Params[k] = v
+	}
+	return conf, nil
+}
+
+// dsnComponents are the parts of a DSN of the driver, as the parse_dsn and
+// build_dsn functions return and take them. Params holds the driver options
+// and the session variables of the query string.
+type dsnComponents struct {
+	Username string
+	Password string
+	Protocol string
+	Address  string
+	Host     string
+	Port     int
+	Database string
+	Params   map[string]string
+}
+
+// parseDSNComponents parses dsn as the provider does for its dsn argument.
+// The options are read from the driver's formatting of the DSN, so they're
+// normalized the same way.
+func parseDSNComponents(dsn string) (*dsnComponents, error) {
+	conf, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	c := &dsnComponents{
+		Username: conf.User,
+		Password: conf.Passwd,
+		Protocol: conf.Net,
+		Address:  conf.Addr,
+		Database: conf.DBName,
+		Params:   map[string]string{},
+	}
+	if conf.Net == "tcp" {
+		host, port, err := net.SplitHostPort(conf.Addr)
+		if err == nil {
+			c.Host = host
+			c.Port, _ = strconv.Atoi(port)
+		}
+	}
+
+	formatted := conf.FormatDSN()
+	if i := strings.LastIndex(formatted, "/"); i >= 0 {
+		if j := strings.Index(formatted[i:], "?"); j >= 0 {
+			values, err := url.ParseQuery(formatted[i+j+1:])
+			if err != nil {
+				return nil, err
+			}
+			for k := range values {
+				c.Params[k] = values.Get(k)
+			}
+		}
+	}
+	return c, nil
+}
+
+// dsn builds the DSN of c, checked and formatted by the driver. Without an
+// address, it's built from the host and port, 3306 by default.
+func (c *dsnComponents) dsn() (string, error) {
+	conf := mysql.NewConfig()
+	conf.User = c.Username
+	conf.Passwd = c.Password
+	conf.Net = c.Protocol
+	conf.Addr = c.Address
+	conf.DBName = c.Database
+	if conf.Addr == "" && c.Host != "" {
+		port := c.Port
+		if port == 0 {
+			port = 3306
+		}
+		conf.Addr = net.JoinHostPort(c.Host, strconv.Itoa(port))
+	}
+	if conf.Net == "" && conf.Addr != "" {
+		conf.Net = "tcp"
+	}
+
+	dsn := conf.FormatDSN()
+	if len(c.Params) > 0 {
+		values := url.Values{}
+		for k, v := range c.Params {
+			values.Set(k, v)
+		}
+		dsn += "?" + values.Encode()
+	}
+	parsed, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	return parsed.FormatDSN(), nil
+}
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestMergeDSNConfig(t *testing.T) {
+	provider := &mysql.Config{
+		User:                 "terraform",
+		Passwd:               "secret",
+		Net:                  "tcp",
+		Addr:                 "db.example.com:3306",
+		TLSConfig:            "false",
+		AllowNativePasswords: true,
+		InterpolateParams:    true,
+		Params:               map[string]string{"wait_timeout": "60"},
+	}
+
+	dsnConf, err := mysql.ParseDSN("other:pw@tcp(127.0.0.1:3307)/app?compress=true&readTimeout=5s&tls=skip-verify&wait_timeout=30&interpolateParams=false")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf, err := mergeDSNConfig(dsnConf, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.User != "terraform" || conf.Addr != "db.example.com:3306" {
+		t.Errorf("got %s@%s, want the provider's account and endpoint", conf.User, conf.Addr)
+	}
+	if conf.ReadTimeout != 5*time.Second || conf.DBName != "app" {
+		t.Errorf("got readTimeout %v and database %q from the DSN", conf.ReadTimeout, conf.DBName)
+	}
+	if conf.TLSConfig != "skip-verify" {
+		t.Errorf("got tls %q, want the DSN's", conf.TLSConfig)
+	}
+	if !conf.InterpolateParams {
+		t.Error("interpolateParams was disabled")
+	}
+	if conf.Params["wait_timeout"] != "60" {
+		t.Errorf("got wait_timeout %q, want the one of conn_params", conf.Params["wait_timeout"])
+	}
+
+	for _, dsn := range []string{
+		"/?allowAllFiles=true",
+		"/?sql_mode=ANSI_QUOTES",
+		"/?allowCleartextPasswords=true",
+	} {
+		dsnConf, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mergeDSNConfig(dsnConf, provider); err == nil {
+			t.Errorf("no error for %s", dsn)
+		}
+	}
+}
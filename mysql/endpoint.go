@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const defaultMySQLPort = "3306"
+
+// parseEndpoint splits a TCP endpoint into host and port, which is empty when
+// the endpoint has none. It accepts host names and IPv4 addresses with an
+// optional port, IPv6 addresses in brackets with an optional port, and bare
+// IPv6 addresses. IPv6 addresses may have a zone, e.g. fe80::1%eth0, also
+// written as %25eth0 like in URLs.
+func parseEndpoint(endpoint string) (host, port string, err error) {
+	switch {
+	case strings.HasPrefix(endpoint, "["):
+		end := strings.Index(endpoint, "]")
+		if end < 0 {
+			return "", "", fmt.Errorf("endpoint %q is missing ]", endpoint)
+		}
+		host = strings.Replace(endpoint[1:end], "%25", "%", 1)
+		if rest := endpoint[end+1:]; rest != "" {
+			if !strings.HasPrefix(rest, ":") {
+				return "", "", fmt.Errorf("endpoint %q has %q after the address", endpoint, rest)
+			}
+			port = rest[1:]
+			if port == "" {
+				return "", "", fmt.Errorf("endpoint %q has an empty port", endpoint)
+			}
+		}
+		if !isIPv6(host) {
+			return "", "", fmt.Errorf("endpoint %q has no IPv6 address in brackets", endpoint)
+		}
+
+	case strings.Count(endpoint, ":") > 1:
+		// A bare IPv6 address can't have a port, it would be ambiguous.
+		host = strings.Replace(endpoint, "%25", "%", 1)
+		if !isIPv6(host) {
+			return "", "", fmt.Errorf("endpoint %q is not an IPv6 address; use [address]:port", endpoint)
+		}
+
+	default:
+		var hasPort bool
+		host, port, hasPort = strings.Cut(endpoint, ":")
+		if hasPort && port == "" {
+			return "", "", fmt.Errorf("endpoint %q has an empty port", endpoint)
+		}
+	}
+
+	if host == "" {
+		return "", "", fmt.Errorf("endpoint %q has no host", endpoint)
+	}
+	if port != "" {
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return "", "", fmt.Errorf("endpoint %q has an invalid port %q", endpoint, port)
+		}
+	}
+	return host, port, nil
+}
+
+func isIPv6(host string) bool {
+	address, _, _ := strings.Cut(host, "%")
+	ip := net.ParseIP(address)
+	return ip != nil && ip.To4() == nil
+}
+
+// endpointWithPort returns the endpoint as host:port, with the default MySQL
+// port when it has none and IPv6 addresses in brackets.
+func endpointWithPort(endpoint string) (string, error) {
+	host, port, err := parseEndpoint(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if port == "" {
+		port = defaultMySQLPort
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// endpointHost returns the host of an endpoint, ignoring a scheme like aws://.
+// Endpoints that can't be parsed, e.g. Cloud SQL instance names, are returned
+// as they are.
+func endpointHost(endpoint string) string {
+	if _, rest, ok := strings.Cut(endpoint, "://"); ok {
+		endpoint = rest
+	}
+	host, _, err := parseEndpoint(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return host
+}
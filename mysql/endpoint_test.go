@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestEndpointWithPort(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"db.example.com", "db.example.com:3306"},
+		{"db.example.com:3307", "db.example.com:3307"},
+		{"10.0.0.1", "10.0.0.1:3306"},
+		{"10.0.0.1:3307", "10.0.0.1:3307"},
+		{"::1", "[::1]:3306"},
+		{"[::1]", "[::1]:3306"},
+		{"[2001:db8::10]:3307", "[2001:db8::10]:3307"},
+		{"fe80::1%eth0", "[fe80::1%eth0]:3306"},
+		{"[fe80::1%25eth0]:3307", "[fe80::1%eth0]:3307"},
+	}
+	for _, tt := range tests {
+		got, err := endpointWithPort(tt.endpoint)
+		if err != nil {
+			t.Errorf("endpointWithPort(%q) failed: %v", tt.endpoint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("endpointWithPort(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+
+	for _, endpoint := range []string{
+		"[::1",
+		"[::1]3306",
+		"[::1]:",
+		"[db.example.com]:3306",
+		"db.example.com:",
+		"db.example.com:port",
+		"db.example.com:70000",
+		"2001:db8::10:3306:x",
+		":3306",
+	} {
+		if got, err := endpointWithPort(endpoint); err == nil {
+			t.Errorf("endpointWithPort(%q) = %q, want an error", endpoint, got)
+		}
+	}
+}
+
+func TestEndpointHost(t *testing.T) {
+	tests := map[string]string{
+		"db.example.com:3306":          "db.example.com",
+		"aws://db.example.com:3306":    "db.example.com",
+		"[2001:db8::10]:3306":          "2001:db8::10",
+		"cloudsql://project:region:db": "project:region:db",
+	}
+	for endpoint, want := range tests {
+		if got := endpointHost(endpoint); got != want {
+			t.Errorf("endpointHost(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
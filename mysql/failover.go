@@ -0,0 +1,325 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Error codes an Aurora writer returns once it's demoted to a reader.
+const (
+	optionPreventsStatementErrCode = 1290
+	readOnlyModeErrCode            = 1836
+)
+
+// kReSessionSetting matches statements changing a session variable, which
+// are replayed on new connections, and captures the variable.
+var kReSessionSetting = regexp.MustCompile(`(?i)^\s*SET\s+(?:SESSION\s+|@@SESSION\.)([A-Za-z0-9_$.]+)`)
+
+// isFailoverError reports whether err looks like a failover, and whether
+// the statement provably never ran, so it's safe to run it again: the
+// server became read-only and rejected it, or the connection was broken
+// before anything was written to it. Errors after the statement was sent,
+// e.g. a connection reset while waiting for the result, aren't retried, as
+// a statement that isn't idempotent may already have run.
+func isFailoverError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlError *mysql.MySQLError
+	if errors.As(err, &mysqlError) {
+		switch mysqlError.Number {
+		case optionPreventsStatementErrCode:
+			// 1290 is also used for e.g. --secure-file-priv
+			return strings.Contains(mysqlError.Message, "read-only")
+		case readOnlyModeErrCode:
+			return true
+		}
+		return false
+	}
+
+	// The driver returns driver.ErrBadConn only when nothing was written.
+	var opError *net.OpError
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		(errors.As(err, &opError) && opError.Op == "dial")
+}
+
+type noSettingReplayKey struct{}
+
+// withoutSettingReplay marks statements whose session changes mustn't be
+// restored on new connections, e.g. the ones of pre_sql and post_sql.
+func withoutSettingReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noSettingReplayKey{}, true)
+}
+
+// failoverConnector hands out connections that survive failovers: when a
+// statement fails with a failover error, the connection is replaced and the
+// statement retried. New connections dial the endpoint again, so they follow
+// the cluster DNS name to the new writer.
+type failoverConnector struct {
+	connector driver.Connector
+	retries   int
+	delay     time.Duration
+	// metrics is nil unless metrics_file is set.
+	metrics *providerMetrics
+
+	mu sync.Mutex
+	// settings holds the last statement setting each session variable, in
+	// the order the variables were first set.
+	settings  map[string]string
+	variables []string
+}
+
+func newFailoverConnector(connector driver.Connector, retries int, delay time.Duration) *failoverConnector {
+	return &failoverConnector{connector: connector, retries: retries, delay: delay}
+}
+
+func (c *failoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &failoverConn{wrappedConn: wrappedConn{conn}, connector: c}, nil
+}
+
+func (c *failoverConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// connect opens a connection and restores the session settings made so far.
+func (c *failoverConnector) connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	settings := make([]string, len(c.variables))
+	for i, variable := range c.variables {
+		settings[i] = c.settings[variable]
+	}
+	c.mu.Unlock()
+
+	for _, stmtSQL := range settings {
+		if _, err := conn.(driver.ExecerContext).ExecContext(ctx, stmtSQL, nil); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// rememberSetting records a statement setting a session variable. Setting
+// the variable again replaces the earlier statement, so the settings don't
+// grow with the number of statements.
+func (c *failoverConnector) rememberSetting(query string) {
+	match := kReSessionSetting.FindStringSubmatch(query)
+	if match == nil {
+		return
+	}
+	variable := strings.ToLower(match[1])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.settings == nil {
+		c.settings = map[string]string{}
+	}
+	if _, ok := c.settings[variable]; !ok {
+		c.variables = append(c.variables, variable)
+	}
+	c.settings[variable] = query
+}
+
+// failoverConn wraps a driver connection, which it replaces after failovers.
+type failoverConn struct {
+	wrappedConn
+	connector *failoverConnector
+	inTx      bool
+}
+
+// retry runs f, replacing the connection and running f again after failover
+// errors. Statements in transactions aren't retried.
+func (c *failoverConn) retry(ctx context.Context, f func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || c.inTx || attempt > c.connector.retries || !isFailoverError(err) {
+			return err
+		}
+
+		log.Printf("[WARN] Possible failover, reconnecting and retrying (attempt %d of %d): %v", attempt, c.connector.retries, err)
+		c.connector.metrics.failoverRetry()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(c.connector.delay):
+		}
+
+		conn, connectErr := c.connector.connect(ctx)
+		if connectErr != nil {
+			log.Printf("[WARN] Failed reconnecting after failover: %v", connectErr)
+			continue
+		}
+		c.Conn.Close()
+		c.Conn = conn
+	}
+}
+
+func (c *failoverConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, ok := c.Conn.(driver.ExecerContext); !ok {
+		return nil, driver.ErrSkip
+	}
+
+	var result driver.Result
+	err := c.retry(ctx, func() error {
+		var err error
+		result, err = c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+		return err
+	})
+	if err == nil && ctx.Value(noSettingReplayKey{}) == nil {
+		c.connector.rememberSetting(query)
+	}
+	return result, err
+}
+
+func (c *failoverConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if _, ok := c.Conn.(driver.QueryerContext); !ok {
+		return nil, driver.ErrSkip
+	}
+
+	var rows driver.Rows
+	err := c.retry(ctx, func() error {
+		var err error
+		rows, err = c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+		return err
+	})
+	return rows, err
+}
+
+func (c *failoverConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *failoverConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	err := c.retry(ctx, func() error {
+		var err error
+		stmt, err = c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &failoverStmt{conn: c, query: query, stmt: stmt, owner: c.Conn}, nil
+}
+
+func (c *failoverConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *failoverConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.inTx = true
+	return &failoverTx{conn: c, tx: tx}, nil
+}
+
+type failoverTx struct {
+	conn *failoverConn
+	tx   driver.Tx
+}
+
+func (t *failoverTx) Commit() error {
+	t.conn.inTx = false
+	return t.tx.Commit()
+}
+
+func (t *failoverTx) Rollback() error {
+	t.conn.inTx = false
+	return t.tx.Rollback()
+}
+
+// failoverStmt prepares the statement again when its connection was
+// replaced.
+type failoverStmt struct {
+	conn  *failoverConn
+	query string
+	stmt  driver.Stmt
+	owner driver.Conn
+}
+
+func (s *failoverStmt) current(ctx context.Context) (driver.Stmt, error) {
+	if s.owner == s.conn.Conn {
+		return s.stmt, nil
+	}
+
+	s.stmt.Close()
+	stmt, err := s.conn.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, s.query)
+	if err != nil {
+		return nil, err
+	}
+	s.stmt, s.owner = stmt, s.conn.Conn
+	return stmt, nil
+}
+
+func (s *failoverStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	var result driver.Result
+	err := s.conn.retry(ctx, func() error {
+		stmt, err := s.current(ctx)
+		if err != nil {
+			return err
+		}
+		result, err = stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+		return err
+	})
+	return result, err
+}
+
+func (s *failoverStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	err := s.conn.retry(ctx, func() error {
+		stmt, err := s.current(ctx)
+		if err != nil {
+			return err
+		}
+		rows, err = stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+		return err
+	})
+	return rows, err
+}
+
+func (s *failoverStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *failoverStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+// Exec and Query are unused, database/sql prefers the context variants.
+func (s *failoverStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *failoverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *failoverStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsFailoverError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{&mysql.MySQLError{Number: 1290, Message: "The MySQL server is running with the --read-only option so it cannot execute this statement"}, true},
+		{&mysql.MySQLError{Number: 1290, Message: "The MySQL server is running with the --secure-file-priv option so it cannot execute this statement"}, false},
+		{&mysql.MySQLError{Number: 1836, Message: "Running in read-only mode"}, true},
+		{&mysql.MySQLError{Number: 1045, Message: "Access denied"}, false},
+		{mysql.ErrInvalidConn, false},
+		{driver.ErrBadConn, true},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), false},
+		{io.EOF, false},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, false},
+		{fmt.Errorf("syntax error"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isFailoverError(tt.err); got != tt.expected {
+			t.Errorf("isFailoverError(%v) = %v, expected %v", tt.err, got, tt.expected)
+		}
+	}
+}
+
+// fakeFailoverServer fails the first statements with read-only errors, as a
+// demoted Aurora writer does.
+type fakeFailoverServer struct {
+	failures int
+	dials    int
+	executed []string
+}
+
+func (s *fakeFailoverServer) Connect(ctx context.Context) (driver.Conn, error) {
+	s.dials++
+	return &fakeFailoverConn{server: s}, nil
+}
+
+func (s *fakeFailoverServer) Driver() driver.Driver {
+	return nil
+}
+
+type fakeFailoverConn struct {
+	driver.Conn
+	server *fakeFailoverServer
+}
+
+func (c *fakeFailoverConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.server.failures > 0 && query != "SET SESSION sql_mode='ANSI'" {
+		c.server.failures--
+		return nil, &mysql.MySQLError{Number: 1290, Message: "The MySQL server is running with the --read-only option so it cannot execute this statement"}
+	}
+	c.server.executed = append(c.server.executed, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeFailoverConn) Close() error {
+	return nil
+}
+
+func TestFailoverConnectorRetries(t *testing.T) {
+	server := &fakeFailoverServer{}
+	db := sql.OpenDB(newFailoverConnector(server, 2, 0))
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "SET SESSION sql_mode='ANSI'"); err != nil {
+		t.Fatal(err)
+	}
+
+	server.failures = 2
+	if _, err := db.ExecContext(ctx, "CREATE USER 'jdoe'"); err != nil {
+		t.Fatalf("statement wasn't retried: %v", err)
+	}
+	if server.dials != 3 {
+		t.Errorf("expected 3 connections, got %d", server.dials)
+	}
+	// Session settings are restored on each new connection.
+	expected := []string{"SET SESSION sql_mode='ANSI'", "SET SESSION sql_mode='ANSI'", "SET SESSION sql_mode='ANSI'", "CREATE USER 'jdoe'"}
+	if fmt.Sprint(server.executed) != fmt.Sprint(expected) {
+		t.Errorf("unexpected statements %q", server.executed)
+	}
+
+	server.failures = 3
+	if _, err := db.ExecContext(ctx, "CREATE USER 'jdoe'"); err == nil {
+		t.Errorf("expected an error after running out of retries")
+	}
+}
+
+func TestFailoverConnectorRemembersSettings(t *testing.T) {
+	c := newFailoverConnector(nil, 0, 0)
+	for _, query := range []string{
+		"SET SESSION sql_mode='ANSI'",
+		"SET @@SESSION.time_zone='+00:00'",
+		"SET SESSION SQL_MODE='TRADITIONAL'",
+		"SELECT 1",
+	} {
+		c.rememberSetting(query)
+	}
+
+	server := &fakeFailoverServer{}
+	c.connector = server
+	if _, err := c.connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"SET SESSION SQL_MODE='TRADITIONAL'", "SET @@SESSION.time_zone='+00:00'"}
+	if fmt.Sprint(server.executed) != fmt.Sprint(expected) {
+		t.Errorf("replayed %q, want %q", server.executed, expected)
+	}
+}
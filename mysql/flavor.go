@@ -0,0 +1,164 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Flavors of MySQL-wire servers that support only a subset of the resources.
+// Plain MySQL, MariaDB and TiDB are handled per resource instead.
+const (
+	flavorVitess      = "Vitess/PlanetScale"
+	flavorSingleStore = "SingleStore"
+	flavorClickHouse  = "ClickHouse"
+)
+
+const unknownSystemVariableErrCode = 1193
+
+// unsupportedResourcesByFlavor lists resources whose statements the flavor
+// can't run or whose output we can't parse, so they fail at plan time rather
+// than on apply or with corrupted state.
+var unsupportedResourcesByFlavor = map[string][]string{
+	flavorVitess: vitessUnsupportedResources,
+	// SingleStore has its own privilege model (groups instead of roles) and
+	// SHOW GRANTS output we can't parse reliably; it has no MySQL plugins.
+	flavorSingleStore: {
+		"mysql_user_replica",
+		"mysql_grant",
+		"mysql_temporary_grant",
+		"mysql_role",
+		"mysql_default_roles",
+		"mysql_ti_config",
+		"mysql_rds_config",
+		"mysql_secure_installation",
+		"mysql_password_validation",
+		"mysql_connection_control",
+		"mysql_user_defined_function",
+		"mysql_heatwave_table",
+		"mysql_router_account",
+		"mysql_tool_account",
+		"mysql_backup_account",
+	},
+	// ClickHouse only emulates the MySQL protocol; accounts and settings are
+	// managed with ClickHouse SQL.
+	flavorClickHouse: {
+		"mysql_user",
+		"mysql_user_password",
+		"mysql_user_replica",
+		"mysql_grant",
+		"mysql_temporary_grant",
+		"mysql_role",
+		"mysql_default_roles",
+		"mysql_global_variable",
+		"mysql_global_variables",
+		"mysql_ti_config",
+		"mysql_rds_config",
+		"mysql_secure_installation",
+		"mysql_password_validation",
+		"mysql_connection_control",
+		"mysql_user_defined_function",
+		"mysql_heatwave_table",
+		"mysql_router_account",
+		"mysql_tool_account",
+		"mysql_backup_account",
+		"mysql_dump",
+		"mysql_restore",
+		"mysql_ephemeral_database",
+		"mysql_tenant",
+	},
+}
+
+// unsupportedFlavorHints tells how to manage what the provider can't, per
+// flavor.
+var unsupportedFlavorHints = map[string]string{
+	flavorVitess:      vitessUnsupportedHint,
+	flavorSingleStore: "manage it with SingleStore SQL instead",
+	flavorClickHouse:  "manage it with ClickHouse SQL instead",
+}
+
+// serverFlavor returns one of the flavor constants, or an empty string for
+// servers that support all resources. vitess is the provider setting forcing
+// the Vitess compatibility mode.
+func serverFlavor(db *sql.DB, vitess bool) (string, error) {
+	versionString, err := serverVersionString(db)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case vitess || serverVitess(versionString):
+		return flavorVitess, nil
+	case strings.Contains(versionString, "ClickHouse"):
+		return flavorClickHouse, nil
+	case strings.Contains(versionString, "MariaDB") || strings.Contains(versionString, "TiDB"):
+		return "", nil
+	}
+
+	// SingleStore reports a plain MySQL version, but has its own variable.
+	// Only servers no other detection matched pay for the query.
+	var memsqlVersion string
+	err = db.QueryRow("SELECT @@memsql_version").Scan(&memsqlVersion)
+	if err == nil {
+		return flavorSingleStore, nil
+	}
+	if mysqlErrorNumber(err) == unknownSystemVariableErrCode {
+		return "", nil
+	}
+	return "", err
+}
+
+// flavorFromMeta returns the flavor of the configured server, honoring the
+// vitess provider setting.
+func flavorFromMeta(ctx context.Context, meta interface{}) (string, error) {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok || conf.ProxySQL {
+		return "", nil
+	}
+	if conf.Vitess {
+		return flavorVitess, nil
+	}
+
+	oneConnection, err := connectToMySQLInternal(ctx, conf)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to MySQL: %v", err)
+	}
+	return oneConnection.Flavor, nil
+}
+
+func unsupportedFlavorsByResource() map[string][]string {
+	flavorsByResource := make(map[string][]string)
+	for flavor, resources := range unsupportedResourcesByFlavor {
+		for _, name := range resources {
+			flavorsByResource[name] = append(flavorsByResource[name], flavor)
+		}
+	}
+	return flavorsByResource
+}
+
+// rejectUnsupportedFlavors adds a plan-time check failing the resource on
+// any of the given flavors.
+func rejectUnsupportedFlavors(name string, r *schema.Resource, flavors []string) {
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		flavor, err := flavorFromMeta(ctx, meta)
+		if err != nil {
+			log.Printf("[WARN] Could not determine server flavor: %v", err)
+		} else if flavor != "" {
+			for _, unsupported := range flavors {
+				if flavor == unsupported {
+					return fmt.Errorf("%s is not supported by %s (unsupported flavor); %s", name, flavor, unsupportedFlavorHints[flavor])
+				}
+			}
+		}
+
+		if customizeDiff != nil {
+			return customizeDiff(ctx, d, meta)
+		}
+		return nil
+	}
+}
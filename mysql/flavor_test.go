@@ -0,0 +1,31 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUnsupportedFlavorResources(t *testing.T) {
+	provider := Provider()
+	meta := &MySQLConfiguration{Vitess: true}
+
+	for flavor, resources := range unsupportedResourcesByFlavor {
+		for _, name := range resources {
+			r, ok := provider.ResourcesMap[name]
+			if !ok {
+				t.Fatalf("resource %s unsupported by %s is not registered", name, flavor)
+			}
+			if r.CustomizeDiff == nil {
+				t.Fatalf("resource %s has no CustomizeDiff", name)
+			}
+		}
+	}
+
+	for _, name := range unsupportedResourcesByFlavor[flavorVitess] {
+		err := provider.ResourcesMap[name].CustomizeDiff(context.Background(), nil, meta)
+		if err == nil || !strings.Contains(err.Error(), "unsupported flavor") {
+			t.Errorf("expected unsupported flavor error for %s, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// providerFunction is a provider-defined function, called in configurations
+// as provider::mysql::<name>() with Terraform 1.8 or later. The SDK doesn't
+// support functions, so ProviderServer serves them.
+type providerFunction struct {
+	definition *tfprotov5.Function
+	// call gets the arguments decoded with the types of the parameters and
+	// returns a value of the return type.
+	call func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError)
+}
+
+var providerFunctions = map[string]providerFunction{
+	"quote_identifier": {
+		definition: &tfprotov5.Function{
+			Parameters: []*tfprotov5.FunctionParameter{{
+				Name:        "name",
+				Type:        tftypes.String,
+				Description: "Name of a database, table, column, user or host",
+			}},
+			Return:      &tfprotov5.FunctionReturn{Type: tftypes.String},
+			Summary:     "Quote an identifier",
+			Description: "Returns name quoted with backticks, with backticks in it doubled, as the provider quotes identifiers in its statements.",
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			name, ferr := stringArgument(args, 0)
+			if ferr != nil {
+				return tftypes.Value{}, ferr
+			}
+			if name == "" || strings.ContainsRune(name, 0) {
+				return tftypes.Value{}, argumentError(0, "name must not be empty or contain NUL characters")
+			}
+			return tftypes.NewValue(tftypes.String, quoteIdentifier(name)), nil
+		},
+	},
+	"escape_string": {
+		definition: &tfprotov5.Function{
+			Parameters: []*tfprotov5.FunctionParameter{{
+				Name:        "value",
+				Type:        tftypes.String,
+				Description: "Value of a string literal",
+			}},
+			Return:      &tfprotov5.FunctionReturn{Type: tftypes.String},
+			Summary:     "Escape a string literal",
+			Description: "Returns value with backslashes, quotes, NUL, newline and carriage return characters escaped, to be put between single quotes in SQL.",
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			value, ferr := stringArgument(args, 0)
+			if ferr != nil {
+				return tftypes.Value{}, ferr
+			}
+			return tftypes.NewValue(tftypes.String, escapeString(value)), nil
+		},
+	},
+	"parse_dsn": {
+		definition: &tfprotov5.Function{
+			Parameters: []*tfprotov5.FunctionParameter{{
+				Name:        "dsn",
+				Type:        tftypes.String,
+				Description: "DSN in the format of the Go MySQL driver, e.g. user:password@tcp(host:3306)/database?tls=true",
+			}},
+			Return:      &tfprotov5.FunctionReturn{Type: dsnObjectType},
+			Summary:     "Parse a DSN",
+			Description: "Returns the username, password, protocol, address, host, port, database and params of a DSN, parsed as the provider parses its dsn argument.",
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			dsn, ferr := stringArgument(args, 0)
+			if ferr != nil {
+				return tftypes.Value{}, ferr
+			}
+			c, err := parseDSNComponents(dsn)
+			if err != nil {
+				return tftypes.Value{}, argumentError(0, "invalid DSN: %v", err)
+			}
+			return dsnComponentsValue(c), nil
+		},
+	},
+	"build_dsn": {
+		definition: &tfprotov5.Function{
+			Parameters: []*tfprotov5.FunctionParameter{{
+				Name:        "components",
+				Type:        tftypes.DynamicPseudoType,
+				Description: "Object with any of the attributes parse_dsn returns",
+			}},
+			Return:      &tfprotov5.FunctionReturn{Type: tftypes.String},
+			Summary:     "Build a DSN",
+			Description: "Returns the DSN of the components, formatted by the Go MySQL driver. Without an address, it's built from host and port, which defaults to 3306.",
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			c, err := dsnComponentsFromValue(args[0])
+			if err != nil {
+				return tftypes.Value{}, argumentError(0, "%v", err)
+			}
+			dsn, err := c.dsn()
+			if err != nil {
+				return tftypes.Value{}, argumentError(0, "invalid DSN: %v", err)
+			}
+			return tftypes.NewValue(tftypes.String, dsn), nil
+		},
+	},
+	"expand_privileges": {
+		definition: &tfprotov5.Function{
+			Parameters: []*tfprotov5.FunctionParameter{{
+				Name:        "privileges",
+				Type:        tftypes.String,
+				Description: "Privilege or comma-separated privileges, e.g. ALL or SELECT, INSERT",
+			}, {
+				Name:        "version",
+				Type:        tftypes.String,
+				Description: "Server version, e.g. 8.0, 5.7 or 10.6.12-MariaDB",
+			}},
+			VariadicParameter: &tfprotov5.FunctionParameter{
+				Name:        "level",
+				Type:        tftypes.String,
+				Description: "Level of the grant: global, the default, database, table or routine",
+			},
+			Return:      &tfprotov5.FunctionReturn{Type: tftypes.List{ElementType: tftypes.String}},
+			Summary:     "Expand privileges",
+			Description: "Returns the sorted privileges a grant gives on the server version, with ALL PRIVILEGES expanded to the static privileges it grants and names normalized as mysql_grant compares them.",
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			privileges, ferr := stringArgument(args, 0)
+			if ferr != nil {
+				return tftypes.Value{}, ferr
+			}
+			serverVersion, ferr := stringArgument(args, 1)
+			if ferr != nil {
+				return tftypes.Value{}, ferr
+			}
+			level := grantLevelGlobal
+			switch len(args) {
+			case 2:
+			case 3:
+				if level, ferr = stringArgument(args, 2); ferr != nil {
+					return tftypes.Value{}, ferr
+				}
+				if _, ok := allPrivilegesByLevel[level]; !ok {
+					return tftypes.Value{}, argumentError(2, "level must be global, database, table or routine")
+				}
+			default:
+				return tftypes.Value{}, argumentError(3, "only one level can be given")
+			}
+
+			expanded, err := expandPrivileges(strings.Split(privileges, ","), serverVersion, level)
+			if err != nil {
+				return tftypes.Value{}, argumentError(1, "%v", err)
+			}
+			values := make([]tftypes.Value, len(expanded))
+			for i, p := range expanded {
+				values[i] = tftypes.NewValue(tftypes.String, p)
+			}
+			return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values), nil
+		},
+	},
+}
+
+func argumentError(i int, format string, a ...interface{}) *tfprotov5.FunctionError {
+	argument := int64(i)
+	return &tfprotov5.FunctionError{
+		Text:             fmt.Sprintf(format, a...),
+		FunctionArgument: &argument,
+	}
+}
+
+func stringArgument(args []tftypes.Value, i int) (string, *tfprotov5.FunctionError) {
+	var s string
+	if err := args[i].As(&s); err != nil {
+		return "", argumentError(i, "invalid string: %v", err)
+	}
+	return s, nil
+}
+
+func providerFunctionDefinitions() map[string]*tfprotov5.Function {
+	definitions := make(map[string]*tfprotov5.Function, len(providerFunctions))
+	for name, f := range providerFunctions {
+		definitions[name] = f.definition
+	}
+	return definitions
+}
+
+func providerFunctionMetadata() []tfprotov5.FunctionMetadata {
+	metadata := make([]tfprotov5.FunctionMetadata, 0, len(providerFunctions))
+	for name := range providerFunctions {
+		metadata = append(metadata, tfprotov5.FunctionMetadata{Name: name})
+	}
+	sort.Slice(metadata, func(i, j int) bool { return metadata[i].Name < metadata[j].Name })
+	return metadata
+}
+
+func (s *providerServer) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	return &tfprotov5.GetFunctionsResponse{Functions: providerFunctionDefinitions()}, nil
+}
+
+func (s *providerServer) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	f, ok := providerFunctions[req.Name]
+	if !ok {
+		return &tfprotov5.CallFunctionResponse{
+			Error: &tfprotov5.FunctionError{Text: fmt.Sprintf("Function Not Found: No function named %q was found in the provider.", req.Name)},
+		}, nil
+	}
+
+	// Terraform sends each variadic argument on its own.
+	params := f.definition.Parameters
+	if len(req.Arguments) < len(params) || (len(req.Arguments) > len(params) && f.definition.VariadicParameter == nil) {
+		return &tfprotov5.CallFunctionResponse{
+			Error: &tfprotov5.FunctionError{Text: fmt.Sprintf("%s takes %d arguments, got %d", req.Name, len(params), len(req.Arguments))},
+		}, nil
+	}
+	args := make([]tftypes.Value, len(req.Arguments))
+	for i := range req.Arguments {
+		param := f.definition.VariadicParameter
+		if i < len(params) {
+			param = params[i]
+		}
+		arg, err := req.Arguments[i].Unmarshal(param.Type)
+		if err != nil {
+			return &tfprotov5.CallFunctionResponse{Error: argumentError(i, "invalid %s: %v", param.Name, err)}, nil
+		}
+		args[i] = arg
+	}
+
+	result, ferr := f.call(args)
+	if ferr != nil {
+		return &tfprotov5.CallFunctionResponse{Error: ferr}, nil
+	}
+	value, err := tfprotov5.NewDynamicValue(f.definition.Return.Type, result)
+	if err != nil {
+		return &tfprotov5.CallFunctionResponse{
+			Error: &tfprotov5.FunctionError{Text: fmt.Sprintf("failed encoding the result of %s: %v", req.Name, err)},
+		}, nil
+	}
+	return &tfprotov5.CallFunctionResponse{Result: &value}, nil
+}
+
+var dsnObjectType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"username": tftypes.String,
+	"password": tftypes.String,
+	"protocol": tftypes.String,
+	"address":  tftypes.String,
+	"host":     tftypes.String,
+	"port":     tftypes.Number,
+	"database": tftypes.String,
+	"params":   tftypes.Map{ElementType: tftypes.String},
+}}
+
+func dsnComponentsValue(c *dsnComponents) tftypes.Value {
+	params := make(map[string]tftypes.Value, len(c.Params))
+	for k, v := range c.Params {
+		params[k] = tftypes.NewValue(tftypes.String, v)
+	}
+	var port interface{}
+	if c.Port != 0 {
+		port = new(big.Float).SetInt64(int64(c.Port))
+	}
+	return tftypes.NewValue(dsnObjectType, map[string]tftypes.Value{
+		"username": tftypes.NewValue(tftypes.String, c.Username),
+		"password": tftypes.NewValue(tftypes.String, c.Password),
+		"protocol": tftypes.NewValue(tftypes.String, c.Protocol),
+		"address":  tftypes.NewValue(tftypes.String, c.Address),
+		"host":     tftypes.NewValue(tftypes.String, c.Host),
+		"port":     tftypes.NewValue(tftypes.Number, port),
+		"database": tftypes.NewValue(tftypes.String, c.Database),
+		"params":   tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, params),
+	})
+}
+
+// dsnComponentsFromValue reads an object or map with attributes of
+// dsnObjectType. Null attributes are ignored.
+func dsnComponentsFromValue(v tftypes.Value) (*dsnComponents, error) {
+	var attributes map[string]tftypes.Value
+	if err := v.As(&attributes); err != nil {
+		return nil, fmt.Errorf("components must be an object: %v", err)
+	}
+	c := &dsnComponents{}
+	stringAttributes := map[string]*string{
+		"username": &c.Username,
+		"password": &c.Password,
+		"protocol": &c.Protocol,
+		"address":  &c.Address,
+		"host":     &c.Host,
+		"database": &c.Database,
+	}
+	for name, attribute := range attributes {
+		if attribute.IsNull() {
+			continue
+		}
+		switch {
+		case stringAttributes[name] != nil:
+			if err := attribute.As(stringAttributes[name]); err != nil {
+				return nil, fmt.Errorf("%s must be a string", name)
+			}
+		case name == "port":
+			port, err := numberOrString(attribute)
+			if err != nil || port < 1 || port > 65535 {
+				return nil, fmt.Errorf("port must be a number between 1 and 65535")
+			}
+			c.Port = port
+		case name == "params":
+			var params map[string]tftypes.Value
+			if err := attribute.As(&params); err != nil {
+				return nil, fmt.Errorf("params must be a map of strings")
+			}
+			c.Params = make(map[string]string, len(params))
+			for k, p := range params {
+				var s string
+				if err := p.As(&s); err != nil {
+					return nil, fmt.Errorf("params must be a map of strings")
+				}
+				c.Params[k] = s
+			}
+		default:
+			return nil, fmt.Errorf("unsupported attribute %s", name)
+		}
+	}
+	return c, nil
+}
+
+func numberOrString(v tftypes.Value) (int, error) {
+	if v.Type().Is(tftypes.String) {
+		var s string
+		if err := v.As(&s); err != nil {
+			return 0, err
+		}
+		return strconv.Atoi(s)
+	}
+	var f big.Float
+	if err := v.As(&f); err != nil {
+		return 0, err
+	}
+	i, accuracy := f.Int64()
+	if accuracy != big.Exact {
+		return 0, fmt.Errorf("not an integer")
+	}
+	return int(i), nil
+}
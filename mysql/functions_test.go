@@ -0,0 +1,218 @@
+package mysql
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// callFunction calls a provider-defined function through the provider
+// server, as Terraform does.
+func callFunction(t *testing.T, name string, args ...tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+	t.Helper()
+	req := &tfprotov5.CallFunctionRequest{Name: name}
+	for i, arg := range args {
+		typ := arg.Type()
+		if f, ok := providerFunctions[name]; ok && i < len(f.definition.Parameters) {
+			typ = f.definition.Parameters[i].Type
+		}
+		value, err := tfprotov5.NewDynamicValue(typ, arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Arguments = append(req.Arguments, &value)
+	}
+	resp, err := ProviderServer().CallFunction(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil {
+		return tftypes.Value{}, resp.Error
+	}
+	result, err := resp.Result.Unmarshal(providerFunctions[name].definition.Return.Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, nil
+}
+
+func TestProviderFunctionsServed(t *testing.T) {
+	server := ProviderServer()
+	ctx := context.Background()
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadataResp, err := server.GetMetadata(ctx, &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range providerFunctions {
+		if schemaResp.Functions[name] == nil {
+			t.Errorf("function %s missing from the provider schema", name)
+		}
+	}
+	if len(metadataResp.Functions) != len(providerFunctions) {
+		t.Errorf("got %d functions in metadata, want %d", len(metadataResp.Functions), len(providerFunctions))
+	}
+
+	if _, ferr := callFunction(t, "no_such_function"); ferr == nil {
+		t.Error("expected an error calling an unknown function")
+	}
+}
+
+func TestQuoteIdentifierFunction(t *testing.T) {
+	result, ferr := callFunction(t, "quote_identifier", tftypes.NewValue(tftypes.String, "odd`name"))
+	if ferr != nil {
+		t.Fatal(ferr.Text)
+	}
+	var got string
+	if err := result.As(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got != "`odd``name`" {
+		t.Errorf("quote_identifier = %s, want `odd``name`", got)
+	}
+
+	if _, ferr := callFunction(t, "quote_identifier", tftypes.NewValue(tftypes.String, "")); ferr == nil || ferr.FunctionArgument == nil || *ferr.FunctionArgument != 0 {
+		t.Errorf("expected an error for the first argument, got %+v", ferr)
+	}
+}
+
+func TestEscapeStringFunction(t *testing.T) {
+	result, ferr := callFunction(t, "escape_string", tftypes.NewValue(tftypes.String, "it's a \\ \"test\"\n"))
+	if ferr != nil {
+		t.Fatal(ferr.Text)
+	}
+	var got string
+	if err := result.As(&got); err != nil {
+		t.Fatal(err)
+	}
+	if want := `it\'s a \\ \"test\"\n`; got != want {
+		t.Errorf("escape_string = %s, want %s", got, want)
+	}
+}
+
+func TestParseDSNFunction(t *testing.T) {
+	result, ferr := callFunction(t, "parse_dsn", tftypes.NewValue(tftypes.String, "app:p@ss@tcp(db.example.com:3307)/shop?tls=skip-verify&readTimeout=5s&wait_timeout=60"))
+	if ferr != nil {
+		t.Fatal(ferr.Text)
+	}
+	var attributes map[string]tftypes.Value
+	if err := result.As(&attributes); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"username": "app",
+		"password": "p@ss",
+		"protocol": "tcp",
+		"address":  "db.example.com:3307",
+		"host":     "db.example.com",
+		"database": "shop",
+	} {
+		var got string
+		if err := attributes[name].As(&got); err != nil || got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	var port big.Float
+	if err := attributes["port"].As(&port); err != nil || port.String() != "3307" {
+		t.Errorf("port = %s, want 3307", port.String())
+	}
+	var paramValues map[string]tftypes.Value
+	if err := attributes["params"].As(&paramValues); err != nil {
+		t.Fatal(err)
+	}
+	params := map[string]string{}
+	for k, v := range paramValues {
+		var s string
+		if err := v.As(&s); err != nil {
+			t.Fatal(err)
+		}
+		params[k] = s
+	}
+	want := map[string]string{"tls": "skip-verify", "readTimeout": "5s", "wait_timeout": "60"}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v, want %v", params, want)
+	}
+
+	if _, ferr := callFunction(t, "parse_dsn", tftypes.NewValue(tftypes.String, "no-slash")); ferr == nil {
+		t.Error("expected an error parsing an invalid DSN")
+	}
+}
+
+func TestBuildDSNFunction(t *testing.T) {
+	paramsType := tftypes.Map{ElementType: tftypes.String}
+	components := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"username": tftypes.String,
+		"password": tftypes.String,
+		"host":     tftypes.String,
+		"port":     tftypes.Number,
+		"database": tftypes.String,
+		"params":   paramsType,
+	}}, map[string]tftypes.Value{
+		"username": tftypes.NewValue(tftypes.String, "app"),
+		"password": tftypes.NewValue(tftypes.String, "secret"),
+		"host":     tftypes.NewValue(tftypes.String, "2001:db8::10"),
+		"port":     tftypes.NewValue(tftypes.Number, 3307),
+		"database": tftypes.NewValue(tftypes.String, "shop"),
+		"params": tftypes.NewValue(paramsType, map[string]tftypes.Value{
+			"wait_timeout": tftypes.NewValue(tftypes.String, "60"),
+			"tls":          tftypes.NewValue(tftypes.String, "true"),
+		}),
+	})
+	result, ferr := callFunction(t, "build_dsn", components)
+	if ferr != nil {
+		t.Fatal(ferr.Text)
+	}
+	var got string
+	if err := result.As(&got); err != nil {
+		t.Fatal(err)
+	}
+	if want := "app:secret@tcp([2001:db8::10]:3307)/shop?tls=true&wait_timeout=60"; got != want {
+		t.Errorf("build_dsn = %s, want %s", got, want)
+	}
+
+	unsupported := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"user": tftypes.String}},
+		map[string]tftypes.Value{"user": tftypes.NewValue(tftypes.String, "app")})
+	if _, ferr := callFunction(t, "build_dsn", unsupported); ferr == nil {
+		t.Error("expected an error for an unsupported attribute")
+	}
+}
+
+func TestExpandPrivilegesFunction(t *testing.T) {
+	result, ferr := callFunction(t, "expand_privileges",
+		tftypes.NewValue(tftypes.String, "ALL"),
+		tftypes.NewValue(tftypes.String, "8.0"),
+		tftypes.NewValue(tftypes.String, "routine"))
+	if ferr != nil {
+		t.Fatal(ferr.Text)
+	}
+	var values []tftypes.Value
+	if err := result.As(&values); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range values {
+		var s string
+		if err := v.As(&s); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s)
+	}
+	if want := []string{"ALTER ROUTINE", "EXECUTE"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expand_privileges = %v, want %v", got, want)
+	}
+
+	if _, ferr := callFunction(t, "expand_privileges",
+		tftypes.NewValue(tftypes.String, "ALL"),
+		tftypes.NewValue(tftypes.String, "8.0"),
+		tftypes.NewValue(tftypes.String, "column")); ferr == nil || *ferr.FunctionArgument != 2 {
+		t.Errorf("expected an error for the level, got %+v", ferr)
+	}
+}
@@ -0,0 +1,193 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	variableTypeInt    = "int"
+	variableTypeFloat  = "float"
+	variableTypeBool   = "bool"
+	variableTypeEnum   = "enum"
+	variableTypeString = "string"
+)
+
+// variableMetadata describes the values a system variable takes. Min and Max
+// are empty when the range is unknown. ReadOnly is only known on MariaDB;
+// MySQL tells by failing SET GLOBAL.
+type variableMetadata struct {
+	Type       string
+	Min        string
+	Max        string
+	EnumValues []string
+	ReadOnly   bool
+}
+
+var kReSizeValue = regexp.MustCompile(`(?i)^(\d+)([KMGTPE])$`)
+
+var sizeSuffixShift = map[string]uint{"K": 10, "M": 20, "G": 30, "T": 40, "P": 50, "E": 60}
+
+// expandSize returns sizes like 64M in bytes, and other values as they are.
+func expandSize(value string) string {
+	m := kReSizeValue.FindStringSubmatch(value)
+	if m == nil {
+		return value
+	}
+	n, ok := new(big.Int).SetString(m[1], 10)
+	if !ok {
+		return value
+	}
+	return n.Lsh(n, sizeSuffixShift[strings.ToUpper(m[2])]).String()
+}
+
+// normalizeBoolValue returns ON or OFF for the ways servers accept booleans.
+func normalizeBoolValue(value string) (string, bool) {
+	switch strings.ToUpper(value) {
+	case "ON", "1", "TRUE", "YES":
+		return "ON", true
+	case "OFF", "0", "FALSE", "NO":
+		return "OFF", true
+	}
+	return "", false
+}
+
+// equivalentVariableValues reports whether two values of a variable are the
+// same in different formats, e.g. ON and 1, 1G and 1073741824, or enum values
+// in different case.
+func equivalentVariableValues(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	if boolA, ok := normalizeBoolValue(a); ok {
+		if boolB, ok := normalizeBoolValue(b); ok {
+			return boolA == boolB
+		}
+	}
+	return expandSize(a) == expandSize(b)
+}
+
+// normalizeVariableValue validates value against the metadata of the
+// variable and returns it as the server stores it.
+func normalizeVariableValue(name string, metadata *variableMetadata, value string) (string, error) {
+	if metadata == nil {
+		return value, nil
+	}
+
+	switch metadata.Type {
+	case variableTypeBool:
+		normalized, ok := normalizeBoolValue(value)
+		if !ok {
+			return "", fmt.Errorf("%s is a boolean, got %q", name, value)
+		}
+		return normalized, nil
+
+	case variableTypeInt, variableTypeFloat:
+		normalized := expandSize(value)
+		n, ok := new(big.Float).SetString(normalized)
+		if !ok || (metadata.Type == variableTypeInt && !n.IsInt()) {
+			return "", fmt.Errorf("%s is a number of type %s, got %q", name, metadata.Type, value)
+		}
+		if min, ok := new(big.Float).SetString(metadata.Min); ok && metadata.Min != metadata.Max && n.Cmp(min) < 0 {
+			return "", fmt.Errorf("%s must be at least %s, got %q", name, metadata.Min, value)
+		}
+		if max, ok := new(big.Float).SetString(metadata.Max); ok && metadata.Min != metadata.Max && n.Cmp(max) > 0 {
+			return "", fmt.Errorf("%s must be at most %s, got %q", name, metadata.Max, value)
+		}
+		return normalized, nil
+
+	case variableTypeEnum:
+		for _, allowed := range metadata.EnumValues {
+			if strings.EqualFold(allowed, value) {
+				return allowed, nil
+			}
+		}
+		// Enums are also set by their position.
+		if i, err := strconv.Atoi(value); err == nil && i >= 0 && i < len(metadata.EnumValues) {
+			return metadata.EnumValues[i], nil
+		}
+		return "", fmt.Errorf("%s must be one of %s, got %q", name, strings.Join(metadata.EnumValues, ", "), value)
+	}
+	return value, nil
+}
+
+// readVariableMetadata reads the type and range of a variable from
+// information_schema.SYSTEM_VARIABLES on MariaDB or
+// performance_schema.variables_info on MySQL 8. It returns nil when the
+// server has neither.
+func readVariableMetadata(ctx context.Context, db *sql.DB, name string) (*variableMetadata, error) {
+	mariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if mariaDB {
+		stmtSQL := "SELECT VARIABLE_TYPE, COALESCE(NUMERIC_MIN_VALUE, ''), COALESCE(NUMERIC_MAX_VALUE, ''), COALESCE(ENUM_VALUE_LIST, ''), READ_ONLY = 'YES' FROM information_schema.SYSTEM_VARIABLES WHERE VARIABLE_NAME = ?"
+		log.Printf("[DEBUG] SQL: %s", stmtSQL)
+		var variableType, enumValues string
+		metadata := &variableMetadata{}
+		err := db.QueryRowContext(ctx, stmtSQL, name).Scan(&variableType, &metadata.Min, &metadata.Max, &enumValues, &metadata.ReadOnly)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		metadata.Type = mariaDBVariableType(variableType)
+		if metadata.Type == variableTypeEnum {
+			metadata.EnumValues = strings.Split(enumValues, ",")
+		}
+		return metadata, nil
+	}
+
+	stmtSQL := "SELECT vi.MIN_VALUE, vi.MAX_VALUE, gv.VARIABLE_VALUE FROM performance_schema.variables_info vi JOIN performance_schema.global_variables gv USING (VARIABLE_NAME) WHERE vi.VARIABLE_NAME = ?"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	var current string
+	metadata := &variableMetadata{}
+	err = db.QueryRowContext(ctx, stmtSQL, name).Scan(&metadata.Min, &metadata.Max, &current)
+	if errors.Is(err, sql.ErrNoRows) || mysqlErrorNumber(err) == noSuchTableErrCode {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	metadata.Type = mysqlVariableType(metadata.Min, metadata.Max, current)
+	return metadata, nil
+}
+
+func mariaDBVariableType(variableType string) string {
+	switch {
+	case variableType == "BOOLEAN":
+		return variableTypeBool
+	case strings.Contains(variableType, "INT"):
+		return variableTypeInt
+	case variableType == "DOUBLE":
+		return variableTypeFloat
+	case variableType == "ENUM":
+		return variableTypeEnum
+	}
+	return variableTypeString
+}
+
+// mysqlVariableType infers the type from variables_info, which only has a
+// range for numeric variables, and from the current value. MySQL doesn't
+// expose the values of enums.
+func mysqlVariableType(min, max, current string) string {
+	if min != max {
+		if strings.Contains(min+max, ".") {
+			return variableTypeFloat
+		}
+		return variableTypeInt
+	}
+	if current == "ON" || current == "OFF" {
+		return variableTypeBool
+	}
+	return variableTypeString
+}
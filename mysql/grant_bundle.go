@@ -0,0 +1,156 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// bundlePrivilege is a privilege of a bundle. Flavor limits it to MySQL or
+// MariaDB, MinVersion and MaxVersion (exclusive) to versions of the flavor.
+type bundlePrivilege struct {
+	Name       string
+	Flavor     string
+	MinVersion string
+	MaxVersion string
+}
+
+// privilegeBundle is a vetted list of privileges for a common role. Global
+// bundles contain privileges only grantable on *.*.
+type privilegeBundle struct {
+	Global     bool
+	Privileges []bundlePrivilege
+}
+
+// replicationClient is REPLICATION CLIENT under the name SHOW GRANTS uses;
+// MariaDB 10.5.2 renamed it to BINLOG MONITOR and 10.5.9 split SLAVE MONITOR
+// off it.
+var replicationClient = []bundlePrivilege{
+	{Name: "REPLICATION CLIENT", Flavor: accountFlavorMySQL},
+	{Name: "REPLICATION CLIENT", Flavor: accountFlavorMariaDB, MaxVersion: "10.5.2"},
+	{Name: "BINLOG MONITOR", Flavor: accountFlavorMariaDB, MinVersion: "10.5.2"},
+	{Name: "SLAVE MONITOR", Flavor: accountFlavorMariaDB, MinVersion: "10.5.9"},
+}
+
+var privilegeBundles = map[string]privilegeBundle{
+	"replication_monitor": {
+		Global: true,
+		Privileges: append([]bundlePrivilege{
+			{Name: "PROCESS"},
+		}, replicationClient...),
+	},
+	"backup_operator": {
+		Global: true,
+		Privileges: append([]bundlePrivilege{
+			{Name: "SELECT"},
+			{Name: "SHOW VIEW"},
+			{Name: "EVENT"},
+			{Name: "TRIGGER"},
+			{Name: "LOCK TABLES"},
+			{Name: "RELOAD"},
+			{Name: "PROCESS"},
+			{Name: "BACKUP_ADMIN", Flavor: accountFlavorMySQL, MinVersion: "8.0.0"},
+		}, replicationClient...),
+	},
+	"read_only_analyst": {
+		Privileges: []bundlePrivilege{
+			{Name: "SELECT"},
+			{Name: "SHOW VIEW"},
+		},
+	},
+}
+
+func privilegeBundleNames() []string {
+	names := make([]string, 0, len(privilegeBundles))
+	for name := range privilegeBundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expandPrivilegeBundle returns the privileges of the bundle on a server of
+// the flavor and version.
+func expandPrivilegeBundle(name string, mariaDB bool, serverVersion *version.Version) []string {
+	flavor := accountFlavorMySQL
+	if mariaDB {
+		flavor = accountFlavorMariaDB
+	}
+
+	var privileges []string
+	for _, p := range privilegeBundles[name].Privileges {
+		if p.Flavor != "" && p.Flavor != flavor {
+			continue
+		}
+		if p.MinVersion != "" && serverVersion.LessThan(version.Must(version.NewVersion(p.MinVersion))) {
+			continue
+		}
+		if p.MaxVersion != "" && !serverVersion.LessThan(version.Must(version.NewVersion(p.MaxVersion))) {
+			continue
+		}
+		privileges = append(privileges, p.Name)
+	}
+	return normalizePerms(privileges)
+}
+
+func checkBundleDatabase(name, database string) error {
+	if privilegeBundles[name].Global && database != "*" {
+		return fmt.Errorf("bundle %s can only be granted on all databases; set database to \"*\"", name)
+	}
+	return nil
+}
+
+// checkPrivilegeBundle plans the privileges of the bundle. When the server
+// can't be reached yet, they are expanded on apply.
+func checkPrivilegeBundle(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	name := d.Get("bundle").(string)
+	if name == "" {
+		return nil
+	}
+	if d.NewValueKnown("database") {
+		if err := checkBundleDatabase(name, d.Get("database").(string)); err != nil {
+			return err
+		}
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not expand privilege bundle %s: %v", name, err)
+		return d.SetNewComputed("privileges")
+	}
+	mariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return err
+	}
+	privileges := expandPrivilegeBundle(name, mariaDB, getVersionFromMeta(ctx, meta))
+	if slices.Equal(normalizePerms(setToArray(d.Get("privileges"))), privileges) {
+		return nil
+	}
+	return d.SetNew("privileges", privileges)
+}
+
+// applyPrivilegeBundle sets privileges to the privileges of the bundle on
+// the server, before the grant is parsed.
+func applyPrivilegeBundle(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("bundle").(string)
+	if name == "" {
+		return nil
+	}
+	if err := checkBundleDatabase(name, d.Get("database").(string)); err != nil {
+		return err
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+	mariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return err
+	}
+	return d.Set("privileges", expandPrivilegeBundle(name, mariaDB, getVersionFromMeta(ctx, meta)))
+}
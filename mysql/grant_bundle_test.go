@@ -0,0 +1,41 @@
+package mysql
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestExpandPrivilegeBundle(t *testing.T) {
+	tests := []struct {
+		bundle  string
+		mariaDB bool
+		version string
+		want    []string
+	}{
+		{"replication_monitor", false, "8.0.36", []string{"PROCESS", "REPLICATION CLIENT"}},
+		{"replication_monitor", true, "10.4.30", []string{"PROCESS", "REPLICATION CLIENT"}},
+		{"replication_monitor", true, "10.5.4", []string{"BINLOG MONITOR", "PROCESS"}},
+		{"replication_monitor", true, "10.11.6", []string{"BINLOG MONITOR", "PROCESS", "SLAVE MONITOR"}},
+		{"backup_operator", false, "5.7.44", []string{"EVENT", "LOCK TABLES", "PROCESS", "RELOAD", "REPLICATION CLIENT", "SELECT", "SHOW VIEW", "TRIGGER"}},
+		{"backup_operator", false, "8.0.36", []string{"BACKUP_ADMIN", "EVENT", "LOCK TABLES", "PROCESS", "RELOAD", "REPLICATION CLIENT", "SELECT", "SHOW VIEW", "TRIGGER"}},
+		{"read_only_analyst", true, "10.11.6", []string{"SELECT", "SHOW VIEW"}},
+	}
+	for _, tt := range tests {
+		got := expandPrivilegeBundle(tt.bundle, tt.mariaDB, version.Must(version.NewVersion(tt.version)))
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("expandPrivilegeBundle(%s, %t, %s) = %v, want %v", tt.bundle, tt.mariaDB, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBundleDatabase(t *testing.T) {
+	if err := checkBundleDatabase("replication_monitor", "app"); err == nil {
+		t.Errorf("replication_monitor on a database should fail")
+	}
+	if err := checkBundleDatabase("read_only_analyst", "app"); err != nil {
+		t.Errorf("read_only_analyst on a database should succeed: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Privileges ALL PRIVILEGES stands for below the global level. There they
+// can be revoked one by one, keeping the ones that remain granted. Globally
+// ALL also covers dynamic privileges, which differ between servers.
+var (
+	allDatabasePrivileges = []string{
+		"ALTER", "ALTER ROUTINE", "CREATE", "CREATE ROUTINE", "CREATE TEMPORARY TABLES", "CREATE VIEW",
+		"DELETE", "DROP", "EVENT", "EXECUTE", "INDEX", "INSERT", "LOCK TABLES", "REFERENCES", "SELECT",
+		"SHOW VIEW", "TRIGGER", "UPDATE",
+	}
+	allTablePrivileges = []string{
+		"ALTER", "CREATE", "CREATE VIEW", "DELETE", "DROP", "INDEX", "INSERT", "REFERENCES", "SELECT",
+		"SHOW VIEW", "TRIGGER", "UPDATE",
+	}
+	allRoutinePrivileges = []string{"ALTER ROUTINE", "EXECUTE"}
+)
+
+var kReColumnPrivilege = regexp.MustCompile(`^([^(]*)\((.*)\)$`)
+
+// allPrivilegesExpansion returns the privileges ALL PRIVILEGES stands for on
+// the object of the grant, or nil when they aren't known.
+func allPrivilegesExpansion(grant MySQLGrant, mariaDB bool) []string {
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		if g.GetDatabase() == "*" {
+			return nil
+		}
+		privs := allTablePrivileges
+		if g.GetTable() == "*" {
+			privs = allDatabasePrivileges
+		}
+		if mariaDB {
+			privs = append(slices.Clone(privs), "DELETE HISTORY")
+		}
+		return privs
+	case *ProcedurePrivilegeGrant:
+		return allRoutinePrivileges
+	}
+	return nil
+}
+
+// splitColumnPrivilege splits a normalized privilege like SELECT(`a`, `b`)
+// into its name and columns.
+func splitColumnPrivilege(priv string) (string, []string) {
+	m := kReColumnPrivilege.FindStringSubmatch(priv)
+	if m == nil {
+		return priv, nil
+	}
+	return strings.TrimSpace(m[1]), strings.Split(m[2], ", ")
+}
+
+// privilegesToRevokeAfterGrant returns what to revoke once the kept
+// privileges were granted, so that no kept privilege is ever missing. Of a
+// removed column privilege only the columns not kept are revoked, and a
+// removed ALL PRIVILEGES is revoked as the privileges it stands for. It
+// returns false when ALL PRIVILEGES can't be revoked that way, so revoking
+// must come before granting.
+func privilegesToRevokeAfterGrant(removed, kept, allPrivileges []string) ([]string, bool) {
+	keptColumns := make(map[string][]string)
+	keptNames := make(map[string]bool)
+	for _, priv := range kept {
+		name, columns := splitColumnPrivilege(priv)
+		if columns == nil {
+			keptNames[strings.ToUpper(name)] = true
+		} else {
+			keptColumns[strings.ToUpper(name)] = append(keptColumns[strings.ToUpper(name)], columns...)
+		}
+	}
+
+	var revoke []string
+	for _, priv := range removed {
+		if containsAllPrivilege([]string{strings.ToUpper(priv)}) {
+			if allPrivileges == nil {
+				return nil, false
+			}
+			for _, p := range allPrivileges {
+				if keptColumns[p] != nil {
+					// Revoking it from the table also revokes it from the
+					// columns.
+					return nil, false
+				}
+				if !keptNames[p] && !slices.Contains(revoke, p) {
+					revoke = append(revoke, p)
+				}
+			}
+			continue
+		}
+
+		name, columns := splitColumnPrivilege(priv)
+		if columns == nil {
+			if !slices.Contains(revoke, priv) {
+				revoke = append(revoke, priv)
+			}
+			continue
+		}
+		var dropped []string
+		for _, column := range columns {
+			if !slices.Contains(keptColumns[strings.ToUpper(name)], column) {
+				dropped = append(dropped, column)
+			}
+		}
+		if len(dropped) > 0 {
+			revoke = append(revoke, fmt.Sprintf("%s(%s)", name, strings.Join(dropped, ", ")))
+		}
+	}
+	return revoke, true
+}
@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrivilegesToRevokeAfterGrant(t *testing.T) {
+	tests := []struct {
+		name    string
+		removed []string
+		kept    []string
+		all     []string
+		want    []string
+		ok      bool
+	}{
+		{
+			name:    "plain privileges",
+			removed: []string{"INSERT", "UPDATE"},
+			kept:    []string{"SELECT"},
+			all:     allTablePrivileges,
+			want:    []string{"INSERT", "UPDATE"},
+			ok:      true,
+		},
+		{
+			name:    "columns",
+			removed: []string{"SELECT(`a`, `b`)"},
+			kept:    []string{"SELECT(`b`, `c`)"},
+			all:     allTablePrivileges,
+			want:    []string{"SELECT(`a`)"},
+			ok:      true,
+		},
+		{
+			name:    "columns added",
+			removed: []string{"SELECT(`a`)"},
+			kept:    []string{"SELECT(`a`, `b`)"},
+			all:     allTablePrivileges,
+			want:    nil,
+			ok:      true,
+		},
+		{
+			name:    "all narrowed",
+			removed: []string{"ALL PRIVILEGES"},
+			kept:    []string{"SELECT", "INSERT"},
+			all:     allTablePrivileges,
+			want:    []string{"ALTER", "CREATE", "CREATE VIEW", "DELETE", "DROP", "INDEX", "REFERENCES", "SHOW VIEW", "TRIGGER", "UPDATE"},
+			ok:      true,
+		},
+		{
+			name:    "all narrowed on a routine",
+			removed: []string{"ALL PRIVILEGES"},
+			kept:    []string{"EXECUTE"},
+			all:     allRoutinePrivileges,
+			want:    []string{"ALTER ROUTINE"},
+			ok:      true,
+		},
+		{
+			name:    "all narrowed globally",
+			removed: []string{"ALL PRIVILEGES"},
+			kept:    []string{"SELECT"},
+			all:     nil,
+			ok:      false,
+		},
+		{
+			name:    "all narrowed to columns",
+			removed: []string{"ALL PRIVILEGES"},
+			kept:    []string{"SELECT(`a`)"},
+			all:     allTablePrivileges,
+			ok:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := privilegesToRevokeAfterGrant(tt.removed, tt.kept, tt.all)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, %t; want %v, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAllPrivilegesExpansion(t *testing.T) {
+	global := &TablePrivilegeGrant{Database: "*", Table: "*"}
+	if got := allPrivilegesExpansion(global, false); got != nil {
+		t.Errorf("got %v for a global grant", got)
+	}
+	database := &TablePrivilegeGrant{Database: "app", Table: "*"}
+	if got := allPrivilegesExpansion(database, false); !reflect.DeepEqual(got, allDatabasePrivileges) {
+		t.Errorf("got %v for a database grant", got)
+	}
+	table := &TablePrivilegeGrant{Database: "app", Table: "t"}
+	if got := allPrivilegesExpansion(table, true); len(got) != len(allTablePrivileges)+1 || got[len(got)-1] != "DELETE HISTORY" {
+		t.Errorf("got %v for a table grant on MariaDB", got)
+	}
+}
@@ -0,0 +1,234 @@
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	privilegeComparisonStrict   = "strict"
+	privilegeComparisonSemantic = "semantic"
+)
+
+const (
+	grantLevelGlobal   = "global"
+	grantLevelDatabase = "database"
+	grantLevelTable    = "table"
+	grantLevelRoutine  = "routine"
+)
+
+// allPrivilegesByLevel lists the static privileges ALL PRIVILEGES grants at
+// each level on every supported MySQL and MariaDB version. Servers grant
+// more, e.g. CREATE ROLE on MySQL 8 or DELETE HISTORY on MariaDB, and MySQL 8
+// adds its dynamic privileges at the global level.
+var allPrivilegesByLevel = map[string][]string{
+	grantLevelGlobal: {
+		"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "RELOAD",
+		"SHUTDOWN", "PROCESS", "FILE", "REFERENCES", "INDEX", "ALTER",
+		"SHOW DATABASES", "SUPER", "CREATE TEMPORARY TABLES", "LOCK TABLES",
+		"EXECUTE", "REPLICATION SLAVE", "REPLICATION CLIENT", "CREATE VIEW",
+		"SHOW VIEW", "CREATE ROUTINE", "ALTER ROUTINE", "CREATE USER", "EVENT",
+		"TRIGGER", "CREATE TABLESPACE",
+	},
+	grantLevelDatabase: {
+		"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "REFERENCES",
+		"INDEX", "ALTER", "CREATE TEMPORARY TABLES", "LOCK TABLES", "EXECUTE",
+		"CREATE VIEW", "SHOW VIEW", "CREATE ROUTINE", "ALTER ROUTINE", "EVENT",
+		"TRIGGER",
+	},
+	grantLevelTable: {
+		"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "REFERENCES",
+		"INDEX", "ALTER", "CREATE VIEW", "SHOW VIEW", "TRIGGER",
+	},
+	grantLevelRoutine: {
+		"EXECUTE", "ALTER ROUTINE",
+	},
+}
+
+// privilegeComparisonFromMeta returns the provider's privilege_comparison.
+func privilegeComparisonFromMeta(meta interface{}) string {
+	if conf, ok := meta.(*MySQLConfiguration); ok && conf.PrivilegeComparison != "" {
+		return conf.PrivilegeComparison
+	}
+	return privilegeComparisonStrict
+}
+
+// grantLevel returns the level a grant is on.
+func grantLevel(grant MySQLGrant) string {
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		switch {
+		case g.Database == "*":
+			return grantLevelGlobal
+		case g.Table == "*" || g.Table == "":
+			return grantLevelDatabase
+		default:
+			return grantLevelTable
+		}
+	case *ProcedurePrivilegeGrant:
+		return grantLevelRoutine
+	}
+	return ""
+}
+
+// impliedPrivilegeSet normalizes privileges for privilegesEquivalent, using
+// the names servers report them as.
+func impliedPrivilegeSet(privileges []string) map[string]bool {
+	set := map[string]bool{}
+	for _, p := range normalizePerms(privileges) {
+		set[normalizeVerifiedName(p)] = true
+	}
+	return set
+}
+
+// coversAllPrivileges reports whether privileges include everything ALL
+// PRIVILEGES grants at the level.
+func coversAllPrivileges(privileges map[string]bool, level string) bool {
+	required, ok := allPrivilegesByLevel[level]
+	if !ok {
+		return false
+	}
+	for _, p := range required {
+		if !privileges[normalizeVerifiedName(p)] {
+			return false
+		}
+	}
+	return true
+}
+
+// privilegesEquivalent reports whether two lists of privileges grant the
+// same access at the level: ALL PRIVILEGES matches the list the server
+// expands it to, privileges match the aliases servers report them as and
+// USAGE grants nothing.
+func privilegesEquivalent(a, b []string, level string) bool {
+	setA := impliedPrivilegeSet(a)
+	setB := impliedPrivilegeSet(b)
+
+	allA := setA["ALL PRIVILEGES"]
+	allB := setB["ALL PRIVILEGES"]
+	switch {
+	case allA && allB:
+		return len(setA) == len(setB)
+	case allA:
+		return len(setA) == 1 && coversAllPrivileges(setB, level)
+	case allB:
+		return len(setB) == 1 && coversAllPrivileges(setA, level)
+	}
+
+	if len(setA) != len(setB) {
+		return false
+	}
+	for p := range setA {
+		if !setB[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// privilegesMatch compares the privileges in state with the ones read from
+// the server, strictly or with privilegesEquivalent.
+func privilegesMatch(current, read []string, level, comparison string) bool {
+	if comparison == privilegeComparisonSemantic {
+		return privilegesEquivalent(current, read, level)
+	}
+	return arePrivilegesSetsEqual(current, read)
+}
+
+// versionPrivileges are static privileges ALL PRIVILEGES grants since a
+// version, besides allPrivilegesByLevel.
+type versionPrivileges struct {
+	mariaDB    bool
+	since      string
+	levels     []string
+	privileges []string
+}
+
+var allPrivilegesSince = []versionPrivileges{
+	{false, "8.0.0", []string{grantLevelGlobal}, []string{"CREATE ROLE", "DROP ROLE"}},
+	{true, "10.3.4", []string{grantLevelGlobal, grantLevelDatabase, grantLevelTable}, []string{"DELETE HISTORY"}},
+	// MariaDB 10.5 split SUPER.
+	{true, "10.5.2", []string{grantLevelGlobal}, []string{
+		"BINLOG ADMIN", "BINLOG REPLAY", "CONNECTION ADMIN", "FEDERATED ADMIN",
+		"READ_ONLY ADMIN", "REPLICATION MASTER ADMIN", "REPLICATION SLAVE ADMIN",
+		"SET USER",
+	}},
+}
+
+var kReVersionNumber = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// parseFlavorVersion reads a version such as 8.0, 10.6.12-MariaDB or
+// mariadb-11.4. Versions from 10 on are MariaDB's.
+func parseFlavorVersion(serverVersion string) (bool, *version.Version, error) {
+	number := kReVersionNumber.FindString(serverVersion)
+	if number == "" {
+		return false, nil, fmt.Errorf("invalid version %q", serverVersion)
+	}
+	v, err := version.NewVersion(number)
+	if err != nil {
+		return false, nil, fmt.Errorf("invalid version %q: %v", serverVersion, err)
+	}
+	mariaDB := strings.Contains(strings.ToLower(serverVersion), "mariadb") || v.Segments()[0] >= 10
+	return mariaDB, v, nil
+}
+
+// expandPrivileges returns the privileges a grant at the level gives on the
+// version, with ALL PRIVILEGES expanded to the static privileges it grants,
+// named as the server reports them. Dynamic privileges of MySQL 8 depend on
+// the installed components and aren't included.
+func expandPrivileges(privileges []string, serverVersion, level string) ([]string, error) {
+	mariaDB, v, err := parseFlavorVersion(serverVersion)
+	if err != nil {
+		return nil, err
+	}
+	allPrivileges, ok := allPrivilegesByLevel[level]
+	if !ok {
+		return nil, fmt.Errorf("invalid level %q", level)
+	}
+	// MariaDB 10.5 reports the replication privileges by new names.
+	aliases := mariaDB && v.GreaterThanOrEqual(version.Must(version.NewVersion("10.5.2")))
+
+	set := map[string]bool{}
+	add := func(p string) {
+		name, columns, _ := strings.Cut(p, "(")
+		p = strings.ToUpper(strings.Join(strings.Fields(name), " "))
+		if columns != "" {
+			set[p+"("+columns] = true
+			return
+		}
+		if alias, ok := grantedPrivilegeAliases[p]; ok && aliases {
+			p = alias
+		}
+		set[p] = true
+	}
+	for _, p := range normalizePerms(privileges) {
+		switch strings.ToUpper(p) {
+		case "USAGE":
+		case "ALL PRIVILEGES":
+			for _, p := range allPrivileges {
+				add(p)
+			}
+			for _, extra := range allPrivilegesSince {
+				if extra.mariaDB == mariaDB && v.GreaterThanOrEqual(version.Must(version.NewVersion(extra.since))) && slices.Contains(extra.levels, level) {
+					for _, p := range extra.privileges {
+						add(p)
+					}
+				}
+			}
+		default:
+			add(p)
+		}
+	}
+
+	expanded := make([]string, 0, len(set))
+	for p := range set {
+		expanded = append(expanded, p)
+	}
+	sort.Strings(expanded)
+	return expanded, nil
+}
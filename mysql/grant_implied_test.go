@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPrivilegesEquivalent(t *testing.T) {
+	tableAll := []string{"ALTER", "CREATE", "CREATE VIEW", "DELETE", "DROP", "INDEX", "INSERT", "REFERENCES", "SELECT", "SHOW VIEW", "TRIGGER", "UPDATE"}
+	tests := []struct {
+		name  string
+		a, b  []string
+		level string
+		want  bool
+	}{
+		{"same", []string{"SELECT", "INSERT"}, []string{"insert", "select"}, grantLevelDatabase, true},
+		{"different", []string{"SELECT"}, []string{"SELECT", "INSERT"}, grantLevelDatabase, false},
+		{"usage", []string{"SELECT", "USAGE"}, []string{"SELECT"}, grantLevelDatabase, true},
+		{"all aliases", []string{"ALL"}, []string{"ALL PRIVILEGES"}, grantLevelDatabase, true},
+		{"all expanded", []string{"ALL"}, tableAll, grantLevelTable, true},
+		{"expanded all", tableAll, []string{"ALL PRIVILEGES"}, grantLevelTable, true},
+		{"all expanded partially", []string{"ALL"}, tableAll[1:], grantLevelTable, false},
+		{"all expanded on another level", []string{"ALL"}, tableAll, grantLevelDatabase, false},
+		{"all with others", []string{"ALL", "GRANT OPTION"}, tableAll, grantLevelTable, false},
+		{"reported alias", []string{"REPLICATION CLIENT"}, []string{"BINLOG MONITOR"}, grantLevelGlobal, true},
+		{"routine", []string{"ALL"}, []string{"EXECUTE", "ALTER ROUTINE"}, grantLevelRoutine, true},
+		{"columns", []string{"SELECT(b, a)"}, []string{"SELECT(`a`,`b`)"}, grantLevelTable, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := privilegesEquivalent(tt.a, tt.b, tt.level); got != tt.want {
+				t.Errorf("privilegesEquivalent(%v, %v, %s) = %v, want %v", tt.a, tt.b, tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivilegesMatch(t *testing.T) {
+	current := []string{"ALL"}
+	read := []string{"EXECUTE", "ALTER ROUTINE"}
+	if privilegesMatch(current, read, grantLevelRoutine, privilegeComparisonStrict) {
+		t.Errorf("strict comparison matched %v and %v", current, read)
+	}
+	if !privilegesMatch(current, read, grantLevelRoutine, privilegeComparisonSemantic) {
+		t.Errorf("semantic comparison didn't match %v and %v", current, read)
+	}
+}
+
+func TestExpandPrivileges(t *testing.T) {
+	tests := []struct {
+		privileges []string
+		version    string
+		level      string
+		want       []string
+	}{
+		{[]string{"ALL"}, "8.0", grantLevelRoutine, []string{"ALTER ROUTINE", "EXECUTE"}},
+		{[]string{"ALL PRIVILEGES", "GRANT OPTION"}, "5.7.44", grantLevelTable, []string{"ALTER", "CREATE", "CREATE VIEW", "DELETE", "DROP", "GRANT OPTION", "INDEX", "INSERT", "REFERENCES", "SELECT", "SHOW VIEW", "TRIGGER", "UPDATE"}},
+		{[]string{"ALL"}, "10.6.12-MariaDB", grantLevelTable, []string{"ALTER", "CREATE", "CREATE VIEW", "DELETE", "DELETE HISTORY", "DROP", "INDEX", "INSERT", "REFERENCES", "SELECT", "SHOW VIEW", "TRIGGER", "UPDATE"}},
+		{[]string{" select ", "USAGE", "Select"}, "8.0", grantLevelDatabase, []string{"SELECT"}},
+		{[]string{"REPLICATION CLIENT"}, "8.0", grantLevelGlobal, []string{"REPLICATION CLIENT"}},
+		{[]string{"REPLICATION CLIENT"}, "mariadb-10.11", grantLevelGlobal, []string{"BINLOG MONITOR"}},
+		{[]string{"select(b, a)"}, "8.0", grantLevelTable, []string{"SELECT(`a`, `b`)"}},
+	}
+	for _, tt := range tests {
+		got, err := expandPrivileges(tt.privileges, tt.version, tt.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandPrivileges(%v, %s, %s) = %v, want %v", tt.privileges, tt.version, tt.level, got, tt.want)
+		}
+	}
+
+	global, err := expandPrivileges([]string{"ALL"}, "8.0.36", grantLevelGlobal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(global, "CREATE ROLE") || slices.Contains(global, "DELETE HISTORY") {
+		t.Errorf("expandPrivileges(ALL, 8.0.36) = %v, want MySQL 8 privileges", global)
+	}
+	if !privilegesEquivalent([]string{"ALL"}, global, grantLevelGlobal) {
+		t.Errorf("expanded privileges %v aren't equivalent to ALL", global)
+	}
+
+	if _, err := expandPrivileges([]string{"ALL"}, "latest", grantLevelGlobal); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
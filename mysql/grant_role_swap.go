@@ -0,0 +1,288 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	grantUpdateInPlace  = "in_place"
+	grantUpdateRoleSwap = "role_swap"
+)
+
+// checkGrantUpdateStrategy validates role_swap, which stages privileges on a
+// role and swaps it in place of the previous one when they change.
+func checkGrantUpdateStrategy(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// Grants from before update_strategy existed are in place.
+	if o, n := d.GetChange("update_strategy"); d.Id() != "" && o.(string) != n.(string) && (o.(string) != "" || n.(string) != grantUpdateInPlace) {
+		if err := d.ForceNew("update_strategy"); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("update_strategy").(string) != grantUpdateRoleSwap {
+		return nil
+	}
+	if d.Get("user").(string) == "" {
+		return fmt.Errorf("update_strategy role_swap requires user")
+	}
+	if _, ok := d.GetOk("roles"); ok {
+		return fmt.Errorf("update_strategy role_swap requires privileges, not roles")
+	}
+	if d.Id() != "" && (d.HasChange("privileges") || d.HasChange("grant")) {
+		return d.SetNewComputed("swap_role")
+	}
+	return nil
+}
+
+func checkRoleSwapSupport(ctx context.Context, db *sql.DB, meta interface{}) error {
+	if err := checkDefaultRolesSupport(ctx, meta); err != nil {
+		return fmt.Errorf("update_strategy role_swap: %w", err)
+	}
+	mariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return err
+	}
+	isTiDB, _, _, err := serverTiDB(db)
+	if err != nil {
+		return err
+	}
+	if mariaDB || isTiDB {
+		return fmt.Errorf("update_strategy role_swap is only supported on MySQL")
+	}
+	return nil
+}
+
+// swapRoleName returns the role holding the privileges of the grant. It only
+// changes with them, so a new role is staged whenever they do.
+func swapRoleName(d *schema.ResourceData) string {
+	privileges := normalizePerms(setToArray(d.Get("privileges")))
+	key := fmt.Sprintf("%s:%s:%s:%s:%t", d.Get("user"), d.Get("database"), d.Get("table"), strings.Join(privileges, ","), d.Get("grant"))
+	return "tf_swap_" + hashSum(key)[:16]
+}
+
+// swapRoleGrant returns the grant with the role as grantee.
+func swapRoleGrant(d *schema.ResourceData, role string) (MySQLGrant, diag.Diagnostics) {
+	grant, diags := parseResourceFromDataForHost(d, grantHosts(d)[0])
+	if diags.HasError() {
+		return nil, diags
+	}
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		g.UserOrRole = UserOrRole{Name: role}
+		g.TLSOption = ""
+	case *ProcedurePrivilegeGrant:
+		g.UserOrRole = UserOrRole{Name: role}
+		g.TLSOption = ""
+	default:
+		return nil, diag.Errorf("update_strategy role_swap requires privileges")
+	}
+	return grant, nil
+}
+
+// swapRoleHosts returns the hosts of the user of a role swap grant. Unlike
+// grantHosts, a grant without hosts has its host attribute, as the role is
+// granted to the user itself.
+func swapRoleHosts(d *schema.ResourceData) []string {
+	hosts := grantHosts(d)
+	if len(hosts) == 1 && hosts[0] == "" {
+		return []string{d.Get("host").(string)}
+	}
+	return hosts
+}
+
+// createSwapRole creates the role with the privileges of the grant, grants
+// it to the user and makes it a default role, in addition to the ones the
+// user already has.
+func createSwapRole(ctx context.Context, db *sql.DB, d *schema.ResourceData, role string, hosts []string) error {
+	stmtSQL := "CREATE ROLE IF NOT EXISTS " + quoteString(role)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed creating role %s: %v", role, err)
+	}
+
+	grant, diags := swapRoleGrant(d, role)
+	if diags.HasError() {
+		return fmt.Errorf("failed parsing grant: %v", diags)
+	}
+	stmtSQL = grant.SQLGrantStatement()
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed granting privileges to role %s: %v", role, err)
+	}
+
+	for _, host := range hosts {
+		if err := grantSwapRole(ctx, db, d.Get("user").(string), host, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func grantSwapRole(ctx context.Context, db *sql.DB, user, host, role string) error {
+	roleGrant := &RoleGrant{Roles: []string{role}, UserOrRole: UserOrRole{Name: user, Host: host}}
+	stmtSQL := roleGrant.SQLGrantStatement()
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed granting role %s: %v", role, err)
+	}
+
+	defaultRoles, err := readDefaultRoles(ctx, db, user, host)
+	if err != nil {
+		return fmt.Errorf("failed reading default roles: %v", err)
+	}
+	if !slices.Contains(defaultRoles, role) {
+		if err := alterUserDefaultRoles(ctx, db, user, host, append(defaultRoles, role)); err != nil {
+			return fmt.Errorf("failed setting default roles: %v", err)
+		}
+	}
+	return nil
+}
+
+func revokeSwapRole(ctx context.Context, db *sql.DB, user, host, role string) error {
+	defaultRoles, err := readDefaultRoles(ctx, db, user, host)
+	if err != nil {
+		return fmt.Errorf("failed reading default roles: %v", err)
+	}
+	if i := slices.Index(defaultRoles, role); i >= 0 {
+		if err := alterUserDefaultRoles(ctx, db, user, host, slices.Delete(defaultRoles, i, i+1)); err != nil {
+			return fmt.Errorf("failed setting default roles: %v", err)
+		}
+	}
+
+	roleGrant := &RoleGrant{Roles: []string{role}, UserOrRole: UserOrRole{Name: user, Host: host}}
+	stmtSQL := roleGrant.SQLRevokeStatement()
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil && !isNonExistingGrant(err) {
+		return fmt.Errorf("failed revoking role %s: %v", role, err)
+	}
+	return nil
+}
+
+// dropSwapRole takes the role away from the user and drops it.
+func dropSwapRole(ctx context.Context, db *sql.DB, user string, hosts []string, role string) error {
+	for _, host := range hosts {
+		if err := revokeSwapRole(ctx, db, user, host, role); err != nil {
+			return err
+		}
+	}
+	stmtSQL := "DROP ROLE IF EXISTS " + quoteString(role)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed dropping role %s: %v", role, err)
+	}
+	return nil
+}
+
+func createRoleSwapGrant(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}) error {
+	if err := checkRoleSwapSupport(ctx, db, meta); err != nil {
+		return err
+	}
+	role := swapRoleName(d)
+	if err := createSwapRole(ctx, db, d, role, swapRoleHosts(d)); err != nil {
+		return err
+	}
+	d.Set("swap_role", role)
+	return nil
+}
+
+func readRoleSwapGrant(ctx context.Context, db *sql.DB, d *schema.ResourceData) diag.Diagnostics {
+	role := d.Get("swap_role").(string)
+	grant, diags := swapRoleGrant(d, role)
+	if diags.HasError() {
+		return diags
+	}
+	grantFromDb, err := getMatchingGrant(ctx, db, grant)
+	if err != nil {
+		return diag.Errorf("ReadGrant - getting all grants failed: %v", err)
+	}
+	if grantFromDb == nil {
+		log.Printf("[WARN] GRANT not found for role %s - removing from state", role)
+		d.SetId("")
+		return nil
+	}
+
+	// The user has to have the role on each host, otherwise it's granted
+	// again by the next apply.
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	var existing []string
+	for _, h := range swapRoleHosts(d) {
+		roleGrant, err := getMatchingGrant(ctx, db, &RoleGrant{UserOrRole: UserOrRole{Name: user, Host: h}})
+		if err != nil {
+			return diag.Errorf("ReadGrant - getting all grants failed: %v", err)
+		}
+		if roleGrant, ok := roleGrant.(*RoleGrant); ok && slices.Contains(roleGrant.Roles, role) {
+			existing = append(existing, h)
+		}
+	}
+	if len(existing) == 0 {
+		log.Printf("[WARN] Role %s not granted to %s - removing from state", role, user)
+		d.SetId("")
+		return nil
+	}
+
+	setDataFromGrant(grantFromDb, d, privilegeComparisonStrict)
+	d.Set("user", user)
+	d.Set("host", host)
+	if len(setToArray(d.Get("hosts"))) > 0 {
+		d.Set("hosts", existing)
+	}
+	return nil
+}
+
+// updateRoleSwapGrant stages changed privileges on a new role, makes it a
+// default role of the user next to the previous one and only then drops the
+// previous role. New sessions get either all old or all new privileges;
+// sessions connected before lose the privileges until they reconnect.
+func updateRoleSwapGrant(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkRoleSwapSupport(ctx, db, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	oldRoleIf, _ := d.GetChange("swap_role")
+	oldRole := oldRoleIf.(string)
+	hosts := swapRoleHosts(d)
+	oldHosts := hosts
+	if d.HasChange("hosts") {
+		o, _ := d.GetChange("hosts")
+		oldHosts = setToArray(o)
+	}
+
+	newRole := swapRoleName(d)
+	if newRole != oldRole {
+		if err := createSwapRole(ctx, db, d, newRole, hosts); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := dropSwapRole(ctx, db, user, oldHosts, oldRole); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("swap_role", newRole)
+		return nil
+	}
+
+	for _, host := range hosts {
+		if !slices.Contains(oldHosts, host) {
+			if err := grantSwapRole(ctx, db, user, host, newRole); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+	for _, host := range oldHosts {
+		if !slices.Contains(hosts, host) {
+			if err := revokeSwapRole(ctx, db, user, host, newRole); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+	d.Set("swap_role", newRole)
+	return nil
+}
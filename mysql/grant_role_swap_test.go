@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSwapRoleHosts(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		want   []string
+	}{
+		{map[string]interface{}{"user": "app", "host": "example.com"}, []string{"example.com"}},
+		{map[string]interface{}{"user": "app", "hosts": []interface{}{"b.example.com", "a.example.com"}}, []string{"a.example.com", "b.example.com"}},
+	}
+	for _, tt := range tests {
+		d := schema.TestResourceDataRaw(t, resourceGrant().Schema, tt.config)
+		if got := swapRoleHosts(d); !slices.Equal(got, tt.want) {
+			t.Errorf("swapRoleHosts(%v) = %v, want %v", tt.config, got, tt.want)
+		}
+	}
+}
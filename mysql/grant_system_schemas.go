@@ -0,0 +1,47 @@
+package mysql
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// performanceSchemaPrivileges are the privileges that apply to
+// performance_schema tables. MySQL refuses ALL PRIVILEGES there and ignores
+// other privileges, so they'd show as drift.
+var performanceSchemaPrivileges = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "DROP", "LOCK TABLES"}
+
+// checkSystemSchemaPrivileges fails for grants the server refuses or
+// ignores on its system schemas: information_schema is readable by every
+// account and takes no grants, and performance_schema only takes
+// performanceSchemaPrivileges.
+func checkSystemSchemaPrivileges(database string, privileges []string) error {
+	switch strings.ToLower(database) {
+	case "information_schema":
+		return fmt.Errorf("privileges can't be granted on information_schema, every account can read the rows of the objects it has privileges for")
+	case "performance_schema":
+		var unsupported []string
+		for _, p := range normalizePerms(privileges) {
+			// Column privileges are checked by their privilege, e.g. SELECT(a).
+			name := strings.ToUpper(strings.TrimSpace(strings.SplitN(p, "(", 2)[0]))
+			if !slices.Contains(performanceSchemaPrivileges, name) {
+				unsupported = append(unsupported, p)
+			}
+		}
+		if len(unsupported) > 0 {
+			return fmt.Errorf("privileges %s can't be granted on performance_schema, use %s", strings.Join(unsupported, ", "), strings.Join(performanceSchemaPrivileges, ", "))
+		}
+	}
+	return nil
+}
+
+// checkSystemSchemaGrant runs checkSystemSchemaPrivileges at plan time, once
+// the database and privileges are known.
+func checkSystemSchemaGrant(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("database") || !d.NewValueKnown("privileges") {
+		return nil
+	}
+	return checkSystemSchemaPrivileges(d.Get("database").(string), setToArray(d.Get("privileges")))
+}
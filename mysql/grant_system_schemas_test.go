@@ -0,0 +1,60 @@
+package mysql
+
+import "testing"
+
+func TestCheckSystemSchemaPrivileges(t *testing.T) {
+	tests := []struct {
+		database   string
+		privileges []string
+		valid      bool
+	}{
+		{"performance_schema", []string{"SELECT"}, true},
+		{"performance_schema", []string{"SELECT", "UPDATE", "DROP"}, true},
+		{"performance_schema", []string{"SELECT(`NAME`)"}, true},
+		{"performance_schema", []string{"ALL"}, false},
+		{"performance_schema", []string{"SELECT", "CREATE VIEW"}, false},
+		{"information_schema", []string{"SELECT"}, false},
+		{"INFORMATION_SCHEMA", []string{"SELECT"}, false},
+		{"sys", []string{"ALL"}, true},
+		{"app", []string{"ALL"}, true},
+	}
+	for _, tt := range tests {
+		err := checkSystemSchemaPrivileges(tt.database, tt.privileges)
+		if (err == nil) != tt.valid {
+			t.Errorf("checkSystemSchemaPrivileges(%s, %v) = %v, want valid %v", tt.database, tt.privileges, err, tt.valid)
+		}
+	}
+}
+
+func TestParseSystemSchemaGrants(t *testing.T) {
+	tests := []struct {
+		grant    string
+		database string
+		table    string
+	}{
+		{"GRANT SELECT ON `performance_schema`.* TO `monitor`@`%`", "performance_schema", "*"},
+		{"GRANT SELECT, UPDATE ON `performance_schema`.`setup_instruments` TO `monitor`@`%`", "performance_schema", "setup_instruments"},
+		{"GRANT SELECT ON `sys`.`sys_config` TO `monitor`@`%`", "sys", "sys_config"},
+	}
+	for _, tt := range tests {
+		grant, err := parseGrantFromRow(tt.grant)
+		if err != nil {
+			t.Fatalf("parseGrantFromRow(%q): %v", tt.grant, err)
+		}
+		tableGrant, ok := grant.(*TablePrivilegeGrant)
+		if !ok {
+			t.Fatalf("parseGrantFromRow(%q) = %T, want a table grant", tt.grant, grant)
+		}
+		if tableGrant.Database != tt.database || tableGrant.Table != tt.table {
+			t.Errorf("parseGrantFromRow(%q) is on %s.%s, want %s.%s", tt.grant, tableGrant.Database, tableGrant.Table, tt.database, tt.table)
+		}
+	}
+
+	grant, err := parseGrantFromRow("GRANT EXECUTE ON PROCEDURE `sys`.`ps_setup_enable_instrument` TO `monitor`@`%`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := grant.(*ProcedurePrivilegeGrant); !ok {
+		t.Errorf("grant on a sys procedure parsed as %T", grant)
+	}
+}
@@ -0,0 +1,143 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// granteeAccounts returns the accounts a grant is given to: the user at
+// each of its hosts or the role.
+func granteeAccounts(user, role, host string, hosts []string) []UserOrRole {
+	if role != "" {
+		return []UserOrRole{{Name: role}}
+	}
+	if len(hosts) == 0 {
+		hosts = []string{host}
+	}
+	accounts := make([]UserOrRole, 0, len(hosts))
+	for _, h := range hosts {
+		accounts = append(accounts, UserOrRole{Name: user, Host: h})
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Host < accounts[j].Host })
+	return accounts
+}
+
+// granteeFingerprint returns the accounts that exist, read with a single
+// query. A changed fingerprint means an account was dropped, which dropped
+// its privileges, so they need to be granted again once it's recreated, e.g.
+// by a mysql_user in the same apply. It returns an empty string when the
+// provider user can't read the grant tables.
+//
+// It doesn't include password_last_changed: that changes with every password
+// change, and for accounts whose password expires, planning grants again that
+// never converge.
+func granteeFingerprint(ctx context.Context, db *sql.DB, accounts []UserOrRole) string {
+	if len(accounts) == 0 {
+		return ""
+	}
+	names := make([]interface{}, 0, len(accounts))
+	placeholders := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		names = append(names, account.Name)
+		placeholders = append(placeholders, "?")
+	}
+	stmtSQL := fmt.Sprintf("SELECT User, Host FROM mysql.user WHERE User IN (%s)", strings.Join(placeholders, ", "))
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, names...)
+	if err != nil {
+		log.Printf("[WARN] Could not read grantee accounts: %v", err)
+		return ""
+	}
+	defer rows.Close()
+
+	existing := map[UserOrRole]bool{}
+	for rows.Next() {
+		var account UserOrRole
+		if err := rows.Scan(&account.Name, &account.Host); err != nil {
+			log.Printf("[WARN] Could not read grantee accounts: %v", err)
+			return ""
+		}
+		// Roles have the host % in MySQL and an empty one in MariaDB.
+		if account.Host == "%" || account.Host == "" {
+			existing[UserOrRole{Name: account.Name}] = true
+		}
+		existing[account] = true
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[WARN] Could not read grantee accounts: %v", err)
+		return ""
+	}
+
+	parts := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		if existing[account] {
+			parts = append(parts, account.IDString())
+		}
+	}
+	if len(parts) == 0 {
+		return granteesMissing
+	}
+	return strings.Join(parts, ",")
+}
+
+// granteesMissing is the fingerprint when none of the accounts exist.
+const granteesMissing = "none"
+
+// legacyGranteeFingerprint reports whether the fingerprint was recorded by
+// releases including password_last_changed, as account=timestamp.
+func legacyGranteeFingerprint(fingerprint string) bool {
+	return strings.Contains(fingerprint, "=")
+}
+
+// granteeFingerprintFromData returns the fingerprint of the accounts of a
+// grant resource.
+func granteeFingerprintFromData(ctx context.Context, db *sql.DB, d *schema.ResourceData) string {
+	return granteeFingerprint(ctx, db, granteeAccounts(
+		d.Get("user").(string),
+		d.Get("role").(string),
+		d.Get("host").(string),
+		setToArray(d.Get("hosts"))))
+}
+
+// setInitialGranteeFingerprint records the fingerprint of imported grants
+// and grants created by older releases. Later reads keep the recorded one, so
+// the plan can compare it to the accounts.
+func setInitialGranteeFingerprint(ctx context.Context, db *sql.DB, d *schema.ResourceData) {
+	if fingerprint := d.Get("grantee_fingerprint").(string); fingerprint == "" || legacyGranteeFingerprint(fingerprint) {
+		d.Set("grantee_fingerprint", granteeFingerprintFromData(ctx, db, d))
+	}
+}
+
+// diffGranteeFingerprint plans granting the privileges again when accounts
+// were dropped since they were granted.
+func diffGranteeFingerprint(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	old := d.Get("grantee_fingerprint").(string)
+	if d.Id() == "" || old == "" || legacyGranteeFingerprint(old) {
+		return nil
+	}
+	if _, ok := meta.(*MySQLConfiguration); !ok {
+		return nil
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not check grantee accounts: %v", err)
+		return nil
+	}
+
+	current := granteeFingerprint(ctx, db, granteeAccounts(
+		d.Get("user").(string),
+		d.Get("role").(string),
+		d.Get("host").(string),
+		setToArray(d.Get("hosts"))))
+	if current == "" || current == old {
+		return nil
+	}
+	log.Printf("[INFO] Grantee accounts changed from %s to %s; granting again", old, current)
+	return d.SetNewComputed("grantee_fingerprint")
+}
@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// killQueryTimeout limits connecting and running KILL QUERY once a statement
+// was cancelled.
+const killQueryTimeout = 10 * time.Second
+
+// killQueryConnector stops statements on the server when their context is
+// cancelled, e.g. when Terraform is interrupted or a timeout passes. The
+// driver only closes the connection, and the server would keep running e.g.
+// a long ALTER TABLE. KILL QUERY is sent through a separate connection.
+type killQueryConnector struct {
+	connector driver.Connector
+}
+
+func (c killQueryConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := connectionID(ctx, conn)
+	if err != nil {
+		// Statements of the connection run without it.
+		log.Printf("[WARN] Could not read the connection ID, statements won't be killed when cancelled: %v", err)
+		return conn, nil
+	}
+	return &killQueryConn{wrappedConn: wrappedConn{conn}, connector: c.connector, id: id}, nil
+}
+
+func (c killQueryConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+func connectionID(ctx context.Context, conn driver.Conn) (uint64, error) {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return 0, fmt.Errorf("driver can't query")
+	}
+	rows, err := queryer.QueryContext(ctx, "SELECT CONNECTION_ID()", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return 0, err
+	}
+	switch v := values[0].(type) {
+	case int64:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case []byte:
+		return strconv.ParseUint(string(v), 10, 64)
+	}
+	return 0, fmt.Errorf("unexpected connection ID %v", values[0])
+}
+
+// killQueryConn is a connection whose statements are killed when cancelled.
+type killQueryConn struct {
+	wrappedConn
+	connector driver.Connector
+	id        uint64
+}
+
+// watch kills the running statement once ctx is done, until stop is called.
+func (c *killQueryConn) watch(ctx context.Context) (stop func() bool) {
+	return context.AfterFunc(ctx, c.kill)
+}
+
+func (c *killQueryConn) kill() {
+	ctx, cancel := context.WithTimeout(context.Background(), killQueryTimeout)
+	defer cancel()
+
+	stmtSQL := fmt.Sprintf("KILL QUERY %d", c.id)
+	log.Printf("[INFO] Statement cancelled, executing %s", stmtSQL)
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		log.Printf("[WARN] Failed connecting to kill the cancelled statement: %v", err)
+		return
+	}
+	defer conn.Close()
+	if _, err := conn.(driver.ExecerContext).ExecContext(ctx, stmtSQL, nil); err != nil {
+		log.Printf("[WARN] Failed killing the cancelled statement: %v", err)
+	}
+}
+
+func (c *killQueryConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	stop := c.watch(ctx)
+	defer stop()
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *killQueryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	stop := c.watch(ctx)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	// The statement runs until its rows are read.
+	return &killQueryRows{wrappedRows: wrappedRows{rows}, stop: stop}, nil
+}
+
+type killQueryRows struct {
+	wrappedRows
+	stop func() bool
+}
+
+func (r *killQueryRows) Close() error {
+	defer r.stop()
+	return r.Rows.Close()
+}
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeKillServer runs ALTER statements until they're cancelled and records
+// the KILL statements it receives.
+type fakeKillServer struct {
+	mu     sync.Mutex
+	dials  int
+	killed []string
+}
+
+func (s *fakeKillServer) Connect(ctx context.Context) (driver.Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dials++
+	return &fakeKillConn{server: s, id: int64(s.dials)}, nil
+}
+
+func (s *fakeKillServer) Driver() driver.Driver {
+	return nil
+}
+
+func (s *fakeKillServer) killedStatements() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.killed...)
+}
+
+type fakeKillConn struct {
+	driver.Conn
+	server *fakeKillServer
+	id     int64
+}
+
+func (c *fakeKillConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &fakeIDRows{id: c.id}, nil
+}
+
+func (c *fakeKillConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if strings.HasPrefix(query, "KILL QUERY") {
+		c.server.mu.Lock()
+		c.server.killed = append(c.server.killed, query)
+		c.server.mu.Unlock()
+		return driver.RowsAffected(0), nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *fakeKillConn) Close() error {
+	return nil
+}
+
+type fakeIDRows struct {
+	id   int64
+	read bool
+}
+
+func (r *fakeIDRows) Columns() []string {
+	return []string{"CONNECTION_ID()"}
+}
+
+func (r *fakeIDRows) Close() error {
+	return nil
+}
+
+func (r *fakeIDRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.id
+	return nil
+}
+
+func TestKillQueryOnCancel(t *testing.T) {
+	server := &fakeKillServer{}
+	db := sql.OpenDB(killQueryConnector{connector: server})
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, "ALTER TABLE t ADD INDEX (a)"); err == nil {
+		t.Fatal("expected the cancelled statement to fail")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.killedStatements()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if killed := server.killedStatements(); len(killed) != 1 || killed[0] != "KILL QUERY 1" {
+		t.Errorf("killed = %v, want [KILL QUERY 1]", killed)
+	}
+}
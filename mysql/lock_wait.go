@@ -0,0 +1,180 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// kBlockingMetadataLocksSQL lists the sessions holding metadata locks a
+// connection is waiting for. %d is the connection ID.
+const kBlockingMetadataLocksSQL = `SELECT t.PROCESSLIST_ID, t.PROCESSLIST_USER, t.PROCESSLIST_HOST, t.PROCESSLIST_COMMAND, t.PROCESSLIST_TIME, t.PROCESSLIST_INFO,
+	g.OBJECT_TYPE, g.OBJECT_SCHEMA, g.OBJECT_NAME, g.LOCK_TYPE
+FROM performance_schema.metadata_locks p
+JOIN performance_schema.threads pt ON pt.THREAD_ID = p.OWNER_THREAD_ID
+JOIN performance_schema.metadata_locks g ON g.OBJECT_TYPE = p.OBJECT_TYPE
+	AND g.OBJECT_SCHEMA <=> p.OBJECT_SCHEMA AND g.OBJECT_NAME <=> p.OBJECT_NAME
+	AND g.LOCK_STATUS = 'GRANTED' AND g.OWNER_THREAD_ID <> p.OWNER_THREAD_ID
+JOIN performance_schema.threads t ON t.THREAD_ID = g.OWNER_THREAD_ID
+WHERE p.LOCK_STATUS = 'PENDING' AND pt.PROCESSLIST_ID = %d`
+
+// lockBlocker is a session holding a metadata lock a statement waits for.
+type lockBlocker struct {
+	ID       string
+	User     string
+	Host     string
+	Command  string
+	Time     string
+	Info     string
+	Object   string
+	LockType string
+}
+
+func (b lockBlocker) String() string {
+	s := fmt.Sprintf("connection %s (%s@%s, %s for %ss", b.ID, b.User, b.Host, b.Command, b.Time)
+	if b.Info != "" {
+		s += fmt.Sprintf(", running %q", b.Info)
+	}
+	return s + fmt.Sprintf(") holding %s on %s", b.LockType, b.Object)
+}
+
+func describeLockBlockers(blockers []lockBlocker) string {
+	descriptions := make([]string, len(blockers))
+	for i, b := range blockers {
+		descriptions[i] = b.String()
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// lockWaitConnector reports the sessions blocking DDL statements that wait
+// for metadata locks longer than after. It logs them as a warning and adds
+// them to the error of the statement, e.g. when lock_wait_timeout passes.
+type lockWaitConnector struct {
+	connector driver.Connector
+	after     time.Duration
+}
+
+func (c lockWaitConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := connectionID(ctx, conn)
+	if err != nil {
+		log.Printf("[WARN] Could not read the connection ID, metadata lock waits won't be diagnosed: %v", err)
+		return conn, nil
+	}
+	return &lockWaitConn{wrappedConn: wrappedConn{conn}, connector: c.connector, after: c.after, id: id}, nil
+}
+
+func (c lockWaitConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+type lockWaitConn struct {
+	wrappedConn
+	connector driver.Connector
+	after     time.Duration
+	id        uint64
+}
+
+func (c *lockWaitConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if statementClass(query) != statementClassDDL {
+		return execer.ExecContext(ctx, query, args)
+	}
+
+	var mu sync.Mutex
+	var blockers []lockBlocker
+	timer := time.AfterFunc(c.after, func() {
+		found, err := c.blockers()
+		if err != nil {
+			log.Printf("[WARN] Statement has been running for %s, failed reading metadata locks: %v", c.after, err)
+			return
+		}
+		if len(found) == 0 {
+			return
+		}
+		log.Printf("[WARN] Statement has been waiting for metadata locks for %s, held by %s", c.after, describeLockBlockers(found))
+		mu.Lock()
+		blockers = found
+		mu.Unlock()
+	})
+	result, err := execer.ExecContext(ctx, query, args)
+	timer.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err != nil && len(blockers) > 0 {
+		return nil, fmt.Errorf("%w; it waited for metadata locks held by %s", err, describeLockBlockers(blockers))
+	}
+	return result, err
+}
+
+// blockers reads the sessions blocking the connection through a separate
+// connection.
+func (c *lockWaitConn) blockers() ([]lockBlocker, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), killQueryTimeout)
+	defer cancel()
+
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return nil, fmt.Errorf("driver can't query")
+	}
+	rows, err := queryer.QueryContext(ctx, fmt.Sprintf(kBlockingMetadataLocksSQL, c.id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blockers []lockBlocker
+	values := make([]driver.Value, len(rows.Columns()))
+	for {
+		if err := rows.Next(values); err == io.EOF {
+			return blockers, nil
+		} else if err != nil {
+			return nil, err
+		}
+		s := make([]string, len(values))
+		for i, v := range values {
+			s[i] = driverValueString(v)
+		}
+		object := s[8]
+		if s[7] != "" {
+			object = s[7] + "." + s[8]
+		}
+		blockers = append(blockers, lockBlocker{
+			ID:       s[0],
+			User:     s[1],
+			Host:     s[2],
+			Command:  s[3],
+			Time:     s[4],
+			Info:     s[5],
+			Object:   strings.ToLower(s[6]) + " " + object,
+			LockType: s[9],
+		})
+	}
+}
+
+func driverValueString(v driver.Value) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// fakeLockedServer runs ALTER statements that wait for a metadata lock held
+// by connection 7 until lock_wait_timeout passes.
+type fakeLockedServer struct{}
+
+func (s fakeLockedServer) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeLockedConn{}, nil
+}
+
+func (s fakeLockedServer) Driver() driver.Driver {
+	return nil
+}
+
+type fakeLockedConn struct {
+	driver.Conn
+}
+
+func (c *fakeLockedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if query == "SELECT CONNECTION_ID()" {
+		return &fakeValueRows{rows: [][]driver.Value{{int64(3)}}}, nil
+	}
+	if !strings.Contains(query, "pt.PROCESSLIST_ID = 3") {
+		return &fakeValueRows{}, nil
+	}
+	return &fakeValueRows{rows: [][]driver.Value{{
+		[]byte("7"), []byte("app"), []byte("10.0.0.5"), []byte("Sleep"), []byte("120"), nil,
+		[]byte("TABLE"), []byte("shop"), []byte("orders"), []byte("SHARED_READ"),
+	}}}, nil
+}
+
+func (c *fakeLockedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(200 * time.Millisecond)
+	return nil, &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}
+}
+
+func (c *fakeLockedConn) Close() error {
+	return nil
+}
+
+type fakeValueRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeValueRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeValueRows) Close() error {
+	return nil
+}
+
+func (r *fakeValueRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestLockWaitDiagnostics(t *testing.T) {
+	db := sql.OpenDB(lockWaitConnector{connector: fakeLockedServer{}, after: 10 * time.Millisecond})
+	defer db.Close()
+
+	_, err := db.ExecContext(context.Background(), "ALTER TABLE shop.orders ADD COLUMN note TEXT")
+	if err == nil {
+		t.Fatal("expected the statement to fail")
+	}
+	want := "held by connection 7 (app@10.0.0.5, Sleep for 120s) holding SHARED_READ on table shop.orders"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+	if mysqlErrorNumber(err) != 1205 {
+		t.Errorf("mysqlErrorNumber = %d, want 1205 kept", mysqlErrorNumber(err))
+	}
+}
@@ -0,0 +1,224 @@
+package mysql
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// slowestOperationsKept limits the individual operations in the metrics
+// file.
+const slowestOperationsKept = 20
+
+// metricsSessions holds the metrics of the configured providers, which
+// FlushMetrics writes when the provider stops.
+var metricsSessions struct {
+	mu       sync.Mutex
+	sessions []*providerMetrics
+}
+
+func registerProviderMetrics(m *providerMetrics) {
+	metricsSessions.mu.Lock()
+	defer metricsSessions.mu.Unlock()
+	metricsSessions.sessions = append(metricsSessions.sessions, m)
+}
+
+// FlushMetrics writes the metrics files of the session. It's called once the
+// plugin server stopped, when Terraform is done with the provider.
+func FlushMetrics() {
+	metricsSessions.mu.Lock()
+	defer metricsSessions.mu.Unlock()
+	for _, m := range metricsSessions.sessions {
+		if err := m.write(); err != nil {
+			log.Printf("[WARN] Failed writing metrics to %s: %v", m.path, err)
+		}
+	}
+}
+
+// metricsFromMeta returns the metrics of the provider configuration, nil
+// unless metrics_file is set.
+func metricsFromMeta(meta interface{}) *providerMetrics {
+	if conf, ok := meta.(*MySQLConfiguration); ok {
+		return conf.Metrics
+	}
+	return nil
+}
+
+// providerMetrics collects what a provider session did. Its methods do
+// nothing on a nil receiver, so callers don't need to check whether metrics
+// are enabled.
+type providerMetrics struct {
+	path string
+
+	mu                sync.Mutex
+	startedAt         time.Time
+	statements        map[string]int
+	failedStatements  int
+	connectionsOpened int
+	failoverRetries   int
+	connectRetries    int
+	operations        map[string]*operationMetrics
+	slowest           []slowOperation
+}
+
+type operationMetrics struct {
+	Count   int   `json:"count"`
+	Errors  int   `json:"errors"`
+	TotalMs int64 `json:"total_ms"`
+	MaxMs   int64 `json:"max_ms"`
+}
+
+type slowOperation struct {
+	Resource   string `json:"resource"`
+	Operation  string `json:"operation"`
+	ID         string `json:"id"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+func newProviderMetrics(path string) *providerMetrics {
+	return &providerMetrics{
+		path:       path,
+		startedAt:  time.Now(),
+		statements: map[string]int{},
+		operations: map[string]*operationMetrics{},
+	}
+}
+
+// statementKind returns the leading keywords of a statement, e.g. GRANT or
+// CREATE USER.
+func statementKind(query string) string {
+	words := strings.Fields(strings.ToUpper(query))
+	if len(words) == 0 {
+		return ""
+	}
+	switch words[0] {
+	case "CREATE", "ALTER", "DROP", "SHOW":
+		if len(words) > 1 {
+			return words[0] + " " + words[1]
+		}
+	}
+	return words[0]
+}
+
+func (m *providerMetrics) statement(query string, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statements[statementKind(query)]++
+	if err != nil {
+		m.failedStatements++
+	}
+}
+
+func (m *providerMetrics) connectionOpened() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connectionsOpened++
+}
+
+func (m *providerMetrics) failoverRetry() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failoverRetries++
+}
+
+func (m *providerMetrics) connectRetry() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connectRetries++
+}
+
+// operation records a finished resource operation.
+func (m *providerMetrics) operation(typeName, operation, id string, duration time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := typeName + "." + operation
+	op, ok := m.operations[key]
+	if !ok {
+		op = &operationMetrics{}
+		m.operations[key] = op
+	}
+	ms := duration.Milliseconds()
+	op.Count++
+	op.TotalMs += ms
+	if ms > op.MaxMs {
+		op.MaxMs = ms
+	}
+	if failed {
+		op.Errors++
+	}
+
+	m.slowest = append(m.slowest, slowOperation{Resource: typeName, Operation: operation, ID: id, DurationMs: ms})
+	sort.SliceStable(m.slowest, func(i, j int) bool {
+		return m.slowest[i].DurationMs > m.slowest[j].DurationMs
+	})
+	if len(m.slowest) > slowestOperationsKept {
+		m.slowest = m.slowest[:slowestOperationsKept]
+	}
+}
+
+func (m *providerMetrics) summary() map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	total := 0
+	for _, count := range m.statements {
+		total += count
+	}
+	return map[string]interface{}{
+		"started_at": m.startedAt.UTC().Format(time.RFC3339),
+		"updated_at": time.Now().UTC().Format(time.RFC3339),
+		"statements": map[string]interface{}{
+			"total":   total,
+			"failed":  m.failedStatements,
+			"by_kind": m.statements,
+		},
+		"connections_opened": m.connectionsOpened,
+		"retries": map[string]int{
+			"failover": m.failoverRetries,
+			"connect":  m.connectRetries,
+		},
+		"operations":         m.operations,
+		"slowest_operations": m.slowest,
+	}
+}
+
+// write replaces the file, so readers never see a partial summary.
+func (m *providerMetrics) write() error {
+	data, err := json.MarshalIndent(m.summary(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
@@ -0,0 +1,78 @@
+package mysql
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatementKind(t *testing.T) {
+	tests := map[string]string{
+		"GRANT SELECT ON *.* TO `a`@`%`": "GRANT",
+		"create user `a`@`%`":            "CREATE USER",
+		"  SHOW GRANTS FOR `a`@`%`":      "SHOW GRANTS",
+		"":                               "",
+	}
+	for query, expected := range tests {
+		if got := statementKind(query); got != expected {
+			t.Errorf("statementKind(%q) = %q, expected %q", query, got, expected)
+		}
+	}
+}
+
+func TestProviderMetricsWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	m := newProviderMetrics(path)
+	m.connectionOpened()
+	m.statement("GRANT SELECT ON *.* TO `a`@`%`", nil)
+	m.statement("GRANT SELECT ON *.* TO `b`@`%`", errors.New("failed"))
+	m.failoverRetry()
+	m.operation("mysql_grant", "create", "a@%:*", 3*time.Millisecond, false)
+	m.operation("mysql_grant", "create", "b@%:*", 5*time.Millisecond, true)
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("metrics were written before the provider stopped: %v", err)
+	}
+	registerProviderMetrics(m)
+	FlushMetrics()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary struct {
+		Statements struct {
+			Total  int            `json:"total"`
+			Failed int            `json:"failed"`
+			ByKind map[string]int `json:"by_kind"`
+		} `json:"statements"`
+		ConnectionsOpened int                          `json:"connections_opened"`
+		Retries           map[string]int               `json:"retries"`
+		Operations        map[string]*operationMetrics `json:"operations"`
+		Slowest           []slowOperation              `json:"slowest_operations"`
+	}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Statements.Total != 2 || summary.Statements.Failed != 1 || summary.Statements.ByKind["GRANT"] != 2 {
+		t.Errorf("unexpected statements %+v", summary.Statements)
+	}
+	if summary.ConnectionsOpened != 1 || summary.Retries["failover"] != 1 {
+		t.Errorf("unexpected connections or retries in %s", data)
+	}
+	if op := summary.Operations["mysql_grant.create"]; op == nil || op.Count != 2 || op.Errors != 1 || op.MaxMs != 5 {
+		t.Errorf("unexpected operations in %s", data)
+	}
+	if len(summary.Slowest) != 2 || summary.Slowest[0].ID != "b@%:*" {
+		t.Errorf("unexpected slowest operations %+v", summary.Slowest)
+	}
+}
+
+func TestProviderMetricsNil(t *testing.T) {
+	var m *providerMetrics
+	m.statement("SELECT 1", nil)
+	m.operation("mysql_grant", "read", "a@%:*", time.Millisecond, false)
+}
@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// ociConfig holds the API signing key credentials used to call OCI APIs, as
+// found in ~/.oci/config. It's the common.ConfigurationProvider signing the
+// requests.
+type ociConfig struct {
+	tenancy     string
+	user        string
+	fingerprint string
+	region      string
+	privateKey  *rsa.PrivateKey
+}
+
+func (c *ociConfig) TenancyOCID() (string, error)            { return c.tenancy, nil }
+func (c *ociConfig) UserOCID() (string, error)               { return c.user, nil }
+func (c *ociConfig) KeyFingerprint() (string, error)         { return c.fingerprint, nil }
+func (c *ociConfig) Region() (string, error)                 { return c.region, nil }
+func (c *ociConfig) PrivateRSAKey() (*rsa.PrivateKey, error) { return c.privateKey, nil }
+
+func (c *ociConfig) KeyID() (string, error) {
+	return fmt.Sprintf("%s/%s/%s", c.tenancy, c.user, c.fingerprint), nil
+}
+
+func (c *ociConfig) AuthType() (common.AuthConfig, error) {
+	return common.AuthConfig{AuthType: common.UserPrincipal}, nil
+}
+
+func defaultOciConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".oci", "config")
+}
+
+// buildOciConfig merges the oci_config block with the config file profile;
+// values set in the block win.
+func buildOciConfig(ociConfigBlock []interface{}) (*ociConfig, error) {
+	block := map[string]interface{}{}
+	if len(ociConfigBlock) > 0 && ociConfigBlock[0] != nil {
+		block = ociConfigBlock[0].(map[string]interface{})
+	}
+	get := func(key string) string {
+		if v, ok := block[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	configFile := get("config_file")
+	if configFile == "" {
+		configFile = defaultOciConfigFile()
+	}
+	profile := get("profile")
+	if profile == "" {
+		profile = "DEFAULT"
+	}
+	if _, err := os.Stat(configFile); err != nil && (get("config_file") != "" || get("profile") != "") {
+		return nil, fmt.Errorf("failed reading OCI config file: %v", err)
+	}
+	file, err := common.ConfigurationProviderFromFileWithProfile(configFile, profile, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed reading OCI config file: %v", err)
+	}
+
+	value := func(blockKey string, fromFile func() (string, error)) string {
+		if v := get(blockKey); v != "" {
+			return v
+		}
+		v, _ := fromFile()
+		return v
+	}
+	conf := &ociConfig{
+		tenancy:     value("tenancy_ocid", file.TenancyOCID),
+		user:        value("user_ocid", file.UserOCID),
+		fingerprint: value("fingerprint", file.KeyFingerprint),
+		region:      value("region", file.Region),
+	}
+	if conf.tenancy == "" || conf.user == "" || conf.fingerprint == "" || conf.region == "" {
+		return nil, fmt.Errorf("tenancy_ocid, user_ocid, fingerprint and region must be set in oci_config or the OCI config file")
+	}
+
+	keyData := []byte(get("private_key"))
+	if keyFile := get("private_key_path"); len(keyData) == 0 && keyFile != "" {
+		if strings.HasPrefix(keyFile, "~/") {
+			home, _ := os.UserHomeDir()
+			keyFile = filepath.Join(home, keyFile[2:])
+		}
+		if keyData, err = os.ReadFile(keyFile); err != nil {
+			return nil, fmt.Errorf("failed reading private key: %v", err)
+		}
+	}
+	if len(keyData) > 0 {
+		conf.privateKey, err = common.PrivateKeyFromBytes(keyData, nil)
+	} else {
+		conf.privateKey, err = file.PrivateRSAKey()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("private_key or private_key_path must be set in oci_config or the OCI config file: %v", err)
+	}
+
+	return conf, nil
+}
+
+func ociSecretsEndpoint(region string) string {
+	return fmt.Sprintf("https://secrets.vaults.%s.oci.oraclecloud.com", region)
+}
+
+// getOciSecret returns the current content of an OCI Vault secret.
+func getOciSecret(ctx context.Context, conf *ociConfig, endpoint, secretId string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/20190301/secretbundles/%s", endpoint, secretId), nil)
+	if err != nil {
+		return "", err
+	}
+	if err := common.DefaultRequestSigner(conf).Sign(req); err != nil {
+		return "", fmt.Errorf("failed signing request: %v", err)
+	}
+
+	resp, err := remoteSourceClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OCI Vault returned %s: %s", resp.Status, body)
+	}
+
+	var bundle struct {
+		SecretBundleContent struct {
+			ContentType string `json:"contentType"`
+			Content     string `json:"content"`
+		} `json:"secretBundleContent"`
+	}
+	if err := json.Unmarshal(body, &bundle); err != nil {
+		return "", fmt.Errorf("failed parsing secret bundle: %v", err)
+	}
+	if bundle.SecretBundleContent.ContentType != "BASE64" {
+		return "", fmt.Errorf("unsupported secret content type %s", bundle.SecretBundleContent.ContentType)
+	}
+
+	content, err := base64.StdEncoding.DecodeString(bundle.SecretBundleContent.Content)
+	if err != nil {
+		return "", fmt.Errorf("failed decoding secret content: %v", err)
+	}
+	return string(content), nil
+}
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestOciVaultSecret(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.pem")
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(keyFile, keyPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(dir, "config")
+	config := fmt.Sprintf(`[DEFAULT]
+user=ocid1.user.oc1..default
+
+[mysql]
+user=ocid1.user.oc1..aaa
+fingerprint=aa:bb:cc
+tenancy=ocid1.tenancy.oc1..bbb
+region=eu-frankfurt-1
+key_file=%s
+`, keyFile)
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := buildOciConfig([]interface{}{map[string]interface{}{
+		"config_file": configFile,
+		"profile":     "mysql",
+		"region":      "us-ashburn-1",
+	}})
+	if err != nil {
+		t.Fatalf("buildOciConfig failed: %v", err)
+	}
+	if conf.user != "ocid1.user.oc1..aaa" || conf.region != "us-ashburn-1" {
+		t.Fatalf("unexpected config %#v", conf)
+	}
+
+	reSignature := regexp.MustCompile(`keyId="ocid1.tenancy.oc1..bbb/ocid1.user.oc1..aaa/aa:bb:cc",.*signature="([^"]+)"`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m := reSignature.FindStringSubmatch(r.Header.Get("Authorization"))
+		if m == nil {
+			http.Error(w, "missing signature", http.StatusUnauthorized)
+			return
+		}
+		signature, _ := base64.StdEncoding.DecodeString(m[1])
+		signingString := fmt.Sprintf("date: %s\n(request-target): get %s\nhost: %s", r.Header.Get("Date"), r.URL.RequestURI(), r.Host)
+		digest := sha256.Sum256([]byte(signingString))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"secretBundleContent":{"contentType":"BASE64","content":"%s"}}`, base64.StdEncoding.EncodeToString([]byte("s3cret")))
+	}))
+	defer server.Close()
+
+	secret, err := getOciSecret(context.Background(), conf, server.URL, "ocid1.vaultsecret.oc1..ccc")
+	if err != nil {
+		t.Fatalf("getOciSecret failed: %v", err)
+	}
+	if secret != "s3cret" {
+		t.Errorf("unexpected secret %q", secret)
+	}
+}
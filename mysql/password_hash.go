@@ -0,0 +1,224 @@
+package mysql
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	// caching_sha2_password stores $A$<rounds / 1000>$<salt><digest>.
+	cachingSha2Rounds     = 5000
+	cachingSha2SaltLength = 20
+	cryptAlphabet         = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var (
+	kReNativePasswordHash      = regexp.MustCompile(`^\*[0-9A-Fa-f]{40}$`)
+	kReCachingSha2PasswordHash = regexp.MustCompile(`^\$A\$[0-9A-Fa-f]{3}\$(?s).{20}[./0-9A-Za-z]{43}$`)
+)
+
+// decodeAuthString decodes auth_string_hashed, which is taken as is unless
+// it's 0x-prefixed hex or base64: prefixed base64. caching_sha2_password
+// hashes are binary and can't always be written as plain text.
+func decodeAuthString(authString string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(authString, "0x") || strings.HasPrefix(authString, "0X"):
+		return hex.DecodeString(authString[2:])
+	case strings.HasPrefix(authString, "base64:"):
+		return base64.StdEncoding.DecodeString(strings.TrimPrefix(authString, "base64:"))
+	default:
+		return []byte(authString), nil
+	}
+}
+
+func validateAuthStringEncoding(v interface{}, k string) (ws []string, es []error) {
+	if isEncryptedStateValue(v.(string)) {
+		return
+	}
+	if _, err := decodeAuthString(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s can't be decoded: %v", k, err))
+	}
+	return
+}
+
+// validateAuthString checks the format of auth strings of plugins we know.
+func validateAuthString(plugin string, authString []byte) error {
+	switch plugin {
+	case "mysql_native_password":
+		if !kReNativePasswordHash.Match(authString) {
+			return fmt.Errorf("mysql_native_password auth string must be * followed by 40 hex digits")
+		}
+	case "caching_sha2_password":
+		if !kReCachingSha2PasswordHash.Match(authString) {
+			return fmt.Errorf("caching_sha2_password auth string must be $A$, 3 hex digits of rounds, $, 20 bytes of salt and 43 characters of digest")
+		}
+	}
+	return nil
+}
+
+// authStringLiteral returns authString as an SQL literal. Binary auth
+// strings are written as hex, as the server does with
+// print_identified_with_as_hex.
+func authStringLiteral(authString []byte) string {
+	for _, c := range authString {
+		if c < 0x20 || c > 0x7e {
+			return "0x" + strings.ToUpper(hex.EncodeToString(authString))
+		}
+	}
+	return quoteString(string(authString))
+}
+
+// hashedAuthStringLiteral decodes and validates auth_string_hashed and
+// returns it as an SQL literal.
+func hashedAuthStringLiteral(plugin, hashed string) (string, error) {
+	authString, err := decodeAuthString(hashed)
+	if err != nil {
+		return "", fmt.Errorf("failed decoding auth_string_hashed: %v", err)
+	}
+	if err := validateAuthString(plugin, authString); err != nil {
+		return "", err
+	}
+	return authStringLiteral(authString), nil
+}
+
+// clientSideHashingPlugins are the auth plugins whose auth string the
+// provider can compute itself.
+var clientSideHashingPlugins = []string{"mysql_native_password", "caching_sha2_password"}
+
+// clientSideAuthClause returns an IDENTIFIED WITH ... AS clause carrying the
+// auth string of password, so the plaintext never reaches the server.
+func clientSideAuthClause(plugin, password string) (string, error) {
+	var authString string
+	switch plugin {
+	case "mysql_native_password":
+		authString = nativePasswordHash(password)
+	case "caching_sha2_password":
+		salt, err := randomCryptSalt(cachingSha2SaltLength)
+		if err != nil {
+			return "", fmt.Errorf("failed generating salt: %v", err)
+		}
+		authString = cachingSha2PasswordHash(password, salt)
+	default:
+		return "", fmt.Errorf("client_side_hashing requires auth_plugin to be one of %s, got %q", strings.Join(clientSideHashingPlugins, ", "), plugin)
+	}
+	return fmt.Sprintf(" IDENTIFIED WITH %s AS 0x%s", plugin, strings.ToUpper(hex.EncodeToString([]byte(authString)))), nil
+}
+
+// nativePasswordHash is PASSWORD() of MySQL 4.1+: * and the hex SHA1 of the
+// SHA1 of the password.
+func nativePasswordHash(password string) string {
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+	return "*" + strings.ToUpper(hex.EncodeToString(stage2[:]))
+}
+
+func cachingSha2PasswordHash(password, salt string) string {
+	return fmt.Sprintf("$A$%03X$%s%s", cachingSha2Rounds/1000, salt, sha256Crypt([]byte(password), []byte(salt), cachingSha2Rounds))
+}
+
+func randomCryptSalt(length int) (string, error) {
+	salt := make([]byte, length)
+	for i := range salt {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(cryptAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		salt[i] = cryptAlphabet[n.Int64()]
+	}
+	return string(salt), nil
+}
+
+// sha256Crypt is the SHA-256 based crypt() by Ulrich Drepper, returning only
+// the encoded digest. Unlike crypt(), the salt isn't limited to 16 bytes, as
+// in MySQL's implementation.
+func sha256Crypt(password, salt []byte, rounds int) string {
+	alt := sha256.New()
+	alt.Write(password)
+	alt.Write(salt)
+	alt.Write(password)
+	altSum := alt.Sum(nil)
+
+	a := sha256.New()
+	a.Write(password)
+	a.Write(salt)
+	cnt := len(password)
+	for ; cnt > 32; cnt -= 32 {
+		a.Write(altSum)
+	}
+	a.Write(altSum[:cnt])
+	for cnt = len(password); cnt > 0; cnt >>= 1 {
+		if cnt&1 != 0 {
+			a.Write(altSum)
+		} else {
+			a.Write(password)
+		}
+	}
+	sum := a.Sum(nil)
+
+	dp := sha256.New()
+	for i := 0; i < len(password); i++ {
+		dp.Write(password)
+	}
+	p := repeatBytes(dp.Sum(nil), len(password))
+
+	ds := sha256.New()
+	for i := 0; i < 16+int(sum[0]); i++ {
+		ds.Write(salt)
+	}
+	s := repeatBytes(ds.Sum(nil), len(salt))
+
+	for i := 0; i < rounds; i++ {
+		c := sha256.New()
+		if i&1 != 0 {
+			c.Write(p)
+		} else {
+			c.Write(sum)
+		}
+		if i%3 != 0 {
+			c.Write(s)
+		}
+		if i%7 != 0 {
+			c.Write(p)
+		}
+		if i&1 != 0 {
+			c.Write(sum)
+		} else {
+			c.Write(p)
+		}
+		sum = c.Sum(nil)
+	}
+
+	var out strings.Builder
+	encode := func(b2, b1, b0 byte, n int) {
+		w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+		for ; n > 0; n-- {
+			out.WriteByte(cryptAlphabet[w&0x3f])
+			w >>= 6
+		}
+	}
+	for _, g := range [][3]int{{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14}, {15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29}} {
+		encode(sum[g[0]], sum[g[1]], sum[g[2]], 4)
+	}
+	encode(0, sum[31], sum[30], 3)
+	return out.String()
+}
+
+// repeatBytes repeats b up to length bytes.
+func repeatBytes(b []byte, length int) []byte {
+	out := make([]byte, 0, length)
+	for len(out) < length {
+		n := length - len(out)
+		if n > len(b) {
+			n = len(b)
+		}
+		out = append(out, b[:n]...)
+	}
+	return out
+}
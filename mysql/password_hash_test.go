@@ -0,0 +1,78 @@
+package mysql
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSha256Crypt(t *testing.T) {
+	// Same as openssl passwd -5 -salt <salt> <password>
+	tests := []struct {
+		password, salt, expected string
+	}{
+		{"Hello world!", "saltstring", "5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+		{"we have a short salt string but not a short password", "short", "k38kpRP0CfxHLIcmymaSTFLK.iFfF4tKryDuEGKp6SC"},
+	}
+	for _, tt := range tests {
+		if got := sha256Crypt([]byte(tt.password), []byte(tt.salt), 5000); got != tt.expected {
+			t.Errorf("sha256Crypt(%q, %q) = %q, expected %q", tt.password, tt.salt, got, tt.expected)
+		}
+	}
+}
+
+func TestNativePasswordHash(t *testing.T) {
+	if got := nativePasswordHash("password"); got != "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19" {
+		t.Errorf("unexpected hash %q", got)
+	}
+}
+
+func TestClientSideAuthClause(t *testing.T) {
+	clause, err := clientSideAuthClause("caching_sha2_password", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// $A$005$ hex encoded, then 20 bytes of salt and 43 of digest
+	if !strings.HasPrefix(clause, " IDENTIFIED WITH caching_sha2_password AS 0x24412430303524") || len(clause) != len(" IDENTIFIED WITH caching_sha2_password AS 0x")+2*(7+20+43) {
+		t.Errorf("unexpected clause %q", clause)
+	}
+
+	if _, err := clientSideAuthClause("auth_pam", "password"); err == nil {
+		t.Errorf("expected an error for an unsupported plugin")
+	}
+}
+
+func TestHashedAuthStringLiteral(t *testing.T) {
+	cachingSha2 := cachingSha2PasswordHash("password", "0123456789abcdefghij")
+	binaryCachingSha2 := "$A$005$" + strings.Repeat("\x01", 20) + strings.Repeat("a", 43)
+
+	tests := []struct {
+		plugin, hashed, expected string
+	}{
+		{"mysql_native_password", "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19", "'*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19'"},
+		{"caching_sha2_password", cachingSha2, quoteString(cachingSha2)},
+		{"caching_sha2_password", "base64:" + base64.StdEncoding.EncodeToString([]byte(cachingSha2)), quoteString(cachingSha2)},
+		{"caching_sha2_password", "0x" + hex.EncodeToString([]byte(binaryCachingSha2)), "0x" + strings.ToUpper(hex.EncodeToString([]byte(binaryCachingSha2)))},
+		{"auth_pam", "anything", "'anything'"},
+	}
+	for _, tt := range tests {
+		got, err := hashedAuthStringLiteral(tt.plugin, tt.hashed)
+		if err != nil {
+			t.Errorf("hashedAuthStringLiteral(%q, %q) failed: %v", tt.plugin, tt.hashed, err)
+		} else if got != tt.expected {
+			t.Errorf("hashedAuthStringLiteral(%q, %q) = %q, expected %q", tt.plugin, tt.hashed, got, tt.expected)
+		}
+	}
+
+	for _, tt := range []struct{ plugin, hashed string }{
+		{"mysql_native_password", "*2470C0C0"},
+		{"caching_sha2_password", "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19"},
+		{"caching_sha2_password", "base64:not base64"},
+		{"caching_sha2_password", "0xZZ"},
+	} {
+		if _, err := hashedAuthStringLiteral(tt.plugin, tt.hashed); err == nil {
+			t.Errorf("hashedAuthStringLiteral(%q, %q) should fail", tt.plugin, tt.hashed)
+		}
+	}
+}
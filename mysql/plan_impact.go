@@ -0,0 +1,333 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// PlanResourceChange adds a warning to each planned change that summarizes
+// the SQL statements it will run when plan_impact_diagnostics is enabled.
+func (s *providerServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	if err != nil || resp == nil || !planImpactDiagnosticsFromMeta(s.provider.Meta()) {
+		return resp, err
+	}
+
+	r, ok := s.provider.ResourcesMap[req.TypeName]
+	if !ok || req.PriorState == nil || resp.PlannedState == nil {
+		return resp, err
+	}
+	ty := r.CoreConfigSchema().ImpliedType()
+	prior, err := msgpack.Unmarshal(req.PriorState.MsgPack, ty)
+	if err != nil {
+		return resp, nil
+	}
+	planned, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, ty)
+	if err != nil {
+		return resp, nil
+	}
+
+	impact := planImpact(req.TypeName, r.Schema, prior, planned, len(resp.RequiresReplace) > 0)
+	if impact.empty() {
+		return resp, nil
+	}
+	resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  fmt.Sprintf("MySQL impact of %s: %s", req.TypeName, impact.summary()),
+		Detail:   impact.detail(),
+	})
+	return resp, nil
+}
+
+// planImpactDiagnosticsFromMeta returns plan_impact_diagnostics of the
+// provider configuration.
+func planImpactDiagnosticsFromMeta(meta interface{}) bool {
+	conf, ok := meta.(*MySQLConfiguration)
+	return ok && conf.PlanImpactDiagnostics
+}
+
+// planImpactIgnoredAttributes only change how the provider behaves, so
+// updating them runs no SQL.
+var planImpactIgnoredAttributes = map[string]bool{
+	"timeouts":                 true,
+	"adopt_existing":           true,
+	"backup_before_destroy":    true,
+	"check_user_name":          true,
+	"create_missing_database":  true,
+	"expiry_action":            true,
+	"ignore_body_whitespace":   true,
+	"keep_database_on_destroy": true,
+	"multi_statements":         true,
+	"persist_read_only":        true,
+	"rds_instance_identifier":  true,
+	"rds_reboot":               true,
+	"skip_missing_users":       true,
+	"truncate_on_destroy":      true,
+	"unmanaged_drift":          true,
+	"update_strategy":          true,
+	"validate_object_exists":   true,
+	"wait_for_replicas":        true,
+}
+
+// sqlImpact counts the statements a change runs, by kind.
+type sqlImpact struct {
+	statements map[string]int
+	accounts   []string
+}
+
+func (i *sqlImpact) add(statement string, count int) {
+	if count > 0 {
+		i.statements[statement] += count
+	}
+}
+
+func (i *sqlImpact) empty() bool {
+	return len(i.statements) == 0
+}
+
+func (i *sqlImpact) summary() string {
+	kinds := make([]string, 0, len(i.statements))
+	for kind := range i.statements {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%d %s", i.statements[kind], kind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (i *sqlImpact) detail() string {
+	if len(i.accounts) == 0 {
+		return "Statements: " + i.summary()
+	}
+	return fmt.Sprintf("Statements: %s\nAccounts: %s", i.summary(), strings.Join(i.accounts, ", "))
+}
+
+// planImpact estimates the statements changing prior to planned run. A null
+// prior is a create, a null planned is a destroy. attributes is the schema
+// of the resource, whose computed and provider-side attributes don't run SQL
+// when they change.
+func planImpact(typeName string, attributes map[string]*schema.Schema, prior, planned cty.Value, replace bool) *sqlImpact {
+	impact := &sqlImpact{statements: map[string]int{}}
+	creating, destroying := prior.IsNull(), planned.IsNull()
+	if creating && destroying || !creating && !destroying && !replace && !sqlAttributesChanged(attributes, prior, planned) {
+		return impact
+	}
+	if replace {
+		creating, destroying = true, true
+	}
+
+	current := planned
+	if planned.IsNull() {
+		current = prior
+	}
+
+	switch typeName {
+	case "mysql_grant":
+		// Each host of a grant gets its own statements.
+		impact.accounts = grantAccounts(current)
+		if destroying {
+			impact.add("REVOKE", len(grantAccounts(prior)))
+		}
+		if creating {
+			impact.add("GRANT", len(impact.accounts))
+		}
+		if !creating && !destroying {
+			addedAccounts, removedAccounts, keptAccounts := accountChanges(grantAccounts(prior), impact.accounts)
+			impact.add("GRANT", len(addedAccounts))
+			impact.add("REVOKE", len(removedAccounts))
+
+			added, removed := ctySetDifference(prior, planned, "privileges")
+			priorGrantOption, plannedGrantOption := ctyBool(prior, "grant"), ctyBool(planned, "grant")
+			if len(added) > 0 || plannedGrantOption && !priorGrantOption {
+				impact.add("GRANT", len(keptAccounts))
+			}
+			if len(removed) > 0 || priorGrantOption && !plannedGrantOption {
+				impact.add("REVOKE", len(keptAccounts))
+			}
+		}
+	case "mysql_user", "mysql_role":
+		if typeName == "mysql_role" {
+			impact.accounts = []string{ctyString(current, "name")}
+			addCreateAlterDrop(impact, "ROLE", creating, destroying, 1)
+			break
+		}
+		// Each host of a user is its own account.
+		impact.accounts = hostAccounts(current)
+		if destroying {
+			impact.add("DROP USER", len(hostAccounts(prior)))
+		}
+		if creating {
+			impact.add("CREATE USER", len(impact.accounts))
+		}
+		if !creating && !destroying {
+			addedAccounts, removedAccounts, keptAccounts := accountChanges(hostAccounts(prior), impact.accounts)
+			impact.add("CREATE USER", len(addedAccounts))
+			impact.add("DROP USER", len(removedAccounts))
+			if sqlAttributesChanged(attributes, prior, planned, "hosts") {
+				impact.add("ALTER USER", len(keptAccounts))
+			}
+		}
+	case "mysql_default_roles":
+		impact.accounts = []string{formatAccount(ctyString(current, "user"), ctyString(current, "host"))}
+		impact.add("ALTER USER", 1)
+	case "mysql_database":
+		addCreateAlterDrop(impact, "DATABASE", creating, destroying, 1)
+	default:
+		addCreateAlterDrop(impact, strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(typeName, "mysql_"), "_", " ")), creating, destroying, 1)
+	}
+	return impact
+}
+
+// sqlAttributesChanged reports whether an update changes any attribute
+// that is sent to the server, other than the except ones.
+func sqlAttributesChanged(attributes map[string]*schema.Schema, prior, planned cty.Value, except ...string) bool {
+	if attributes == nil {
+		if len(except) == 0 || !prior.Type().IsObjectType() {
+			return !prior.RawEquals(planned)
+		}
+		attributes = map[string]*schema.Schema{}
+		for name := range prior.Type().AttributeTypes() {
+			attributes[name] = &schema.Schema{Optional: true}
+		}
+	}
+	for name, attribute := range attributes {
+		if planImpactIgnoredAttributes[name] || attribute.Computed && !attribute.Optional || slices.Contains(except, name) {
+			continue
+		}
+		if !ctyAttr(prior, name).RawEquals(ctyAttr(planned, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func addCreateAlterDrop(impact *sqlImpact, kind string, creating, destroying bool, count int) {
+	if destroying {
+		impact.add("DROP "+kind, count)
+	}
+	if creating {
+		impact.add("CREATE "+kind, count)
+	}
+	if !creating && !destroying {
+		impact.add("ALTER "+kind, count)
+	}
+}
+
+func grantAccounts(v cty.Value) []string {
+	if role := ctyString(v, "role"); role != "" {
+		return []string{role}
+	}
+	return hostAccounts(v)
+}
+
+// hostAccounts returns the account of each host of a user or grant, set with
+// host or hosts.
+func hostAccounts(v cty.Value) []string {
+	user := ctyString(v, "user")
+	hosts := ctyStrings(v, "hosts")
+	if len(hosts) == 0 {
+		hosts = []string{ctyString(v, "host")}
+	}
+	accounts := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		accounts = append(accounts, formatAccount(user, host))
+	}
+	return accounts
+}
+
+// accountChanges splits the accounts of an update into the added, removed
+// and kept ones.
+func accountChanges(prior, planned []string) (added, removed, kept []string) {
+	for _, account := range planned {
+		if slices.Contains(prior, account) {
+			kept = append(kept, account)
+		} else {
+			added = append(added, account)
+		}
+	}
+	for _, account := range prior {
+		if !slices.Contains(planned, account) {
+			removed = append(removed, account)
+		}
+	}
+	return added, removed, kept
+}
+
+func formatAccount(user, host string) string {
+	if host == "" {
+		return fmt.Sprintf("'%s'", user)
+	}
+	return fmt.Sprintf("'%s'@'%s'", user, host)
+}
+
+func ctyAttr(v cty.Value, name string) cty.Value {
+	if v.IsNull() || !v.IsKnown() || !v.Type().IsObjectType() || !v.Type().HasAttribute(name) {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	attr := v.GetAttr(name)
+	if attr.IsNull() || !attr.IsKnown() {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return attr
+}
+
+func ctyString(v cty.Value, name string) string {
+	attr := ctyAttr(v, name)
+	if attr.IsNull() || attr.Type() != cty.String {
+		return ""
+	}
+	return attr.AsString()
+}
+
+func ctyBool(v cty.Value, name string) bool {
+	attr := ctyAttr(v, name)
+	return !attr.IsNull() && attr.Type() == cty.Bool && attr.True()
+}
+
+func ctyStrings(v cty.Value, name string) []string {
+	attr := ctyAttr(v, name)
+	if attr.IsNull() || !attr.CanIterateElements() {
+		return nil
+	}
+	var values []string
+	for it := attr.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		if element.IsKnown() && !element.IsNull() && element.Type() == cty.String {
+			values = append(values, element.AsString())
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// ctySetDifference returns the elements of a string set attribute added in
+// planned and removed from prior.
+func ctySetDifference(prior, planned cty.Value, name string) (added, removed []string) {
+	old := map[string]bool{}
+	for _, value := range ctyStrings(prior, name) {
+		old[value] = true
+	}
+	for _, value := range ctyStrings(planned, name) {
+		if !old[value] {
+			added = append(added, value)
+		}
+		delete(old, value)
+	}
+	for value := range old {
+		removed = append(removed, value)
+	}
+	sort.Strings(removed)
+	return added, removed
+}
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestPlanImpact(t *testing.T) {
+	grant := func(privileges ...string) cty.Value {
+		values := make([]cty.Value, 0, len(privileges))
+		for _, privilege := range privileges {
+			values = append(values, cty.StringVal(privilege))
+		}
+		return cty.ObjectVal(map[string]cty.Value{
+			"user":       cty.StringVal("app"),
+			"host":       cty.StringVal("%"),
+			"role":       cty.NullVal(cty.String),
+			"hosts":      cty.NullVal(cty.Set(cty.String)),
+			"privileges": cty.SetVal(values),
+			"grant":      cty.False,
+		})
+	}
+	grantType := grant("SELECT").Type()
+	hostsGrant := func(hosts []string, privileges ...string) cty.Value {
+		v := grant(privileges...).AsValueMap()
+		v["host"] = cty.StringVal("")
+		v["hosts"] = hostsVal(hosts)
+		return cty.ObjectVal(v)
+	}
+	user := cty.ObjectVal(map[string]cty.Value{
+		"user":  cty.StringVal("app"),
+		"host":  cty.StringVal("%"),
+		"hosts": cty.NullVal(cty.Set(cty.String)),
+	})
+	hostsUser := func(maxUserConnections int64, hosts ...string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"user":                 cty.StringVal("app"),
+			"host":                 cty.StringVal(""),
+			"hosts":                hostsVal(hosts),
+			"max_user_connections": cty.NumberIntVal(maxUserConnections),
+		})
+	}
+
+	tests := []struct {
+		name            string
+		typeName        string
+		prior, planned  cty.Value
+		replace         bool
+		summary, detail string
+	}{
+		{"create grant", "mysql_grant", cty.NullVal(grantType), grant("SELECT"), false, "1 GRANT", "Statements: 1 GRANT\nAccounts: 'app'@'%'"},
+		{"update grant", "mysql_grant", grant("SELECT", "DELETE"), grant("SELECT", "INSERT", "UPDATE"), false, "1 GRANT, 1 REVOKE", "Statements: 1 GRANT, 1 REVOKE\nAccounts: 'app'@'%'"},
+		{"update grant of hosts", "mysql_grant", hostsGrant([]string{"a", "b"}, "SELECT"), hostsGrant([]string{"a", "b"}, "SELECT", "INSERT"), false, "2 GRANT", "Statements: 2 GRANT\nAccounts: 'app'@'a', 'app'@'b'"},
+		{"change hosts of grant", "mysql_grant", hostsGrant([]string{"a", "b"}, "SELECT"), hostsGrant([]string{"b", "c"}, "SELECT", "INSERT"), false, "2 GRANT, 1 REVOKE", "Statements: 2 GRANT, 1 REVOKE\nAccounts: 'app'@'b', 'app'@'c'"},
+		{"destroy grant of hosts", "mysql_grant", hostsGrant([]string{"a", "b"}, "SELECT"), cty.NullVal(grantType), false, "2 REVOKE", "Statements: 2 REVOKE\nAccounts: 'app'@'a', 'app'@'b'"},
+		{"create user of hosts", "mysql_user", cty.NullVal(hostsUser(0).Type()), hostsUser(0, "a", "b"), false, "2 CREATE USER", "Statements: 2 CREATE USER\nAccounts: 'app'@'a', 'app'@'b'"},
+		{"add host of user", "mysql_user", hostsUser(0, "a"), hostsUser(0, "a", "b"), false, "1 CREATE USER", "Statements: 1 CREATE USER\nAccounts: 'app'@'a', 'app'@'b'"},
+		{"update user of hosts", "mysql_user", hostsUser(0, "a", "b"), hostsUser(10, "b", "c"), false, "1 ALTER USER, 1 CREATE USER, 1 DROP USER", "Statements: 1 ALTER USER, 1 CREATE USER, 1 DROP USER\nAccounts: 'app'@'b', 'app'@'c'"},
+		{"replace grant", "mysql_grant", grant("SELECT"), grant("SELECT"), true, "1 GRANT, 1 REVOKE", "Statements: 1 GRANT, 1 REVOKE\nAccounts: 'app'@'%'"},
+		{"destroy user", "mysql_user", user, cty.NullVal(user.Type()), false, "1 DROP USER", "Statements: 1 DROP USER\nAccounts: 'app'@'%'"},
+		{"create database", "mysql_database", cty.NullVal(cty.EmptyObject), cty.EmptyObjectVal, false, "1 CREATE DATABASE", "Statements: 1 CREATE DATABASE"},
+	}
+	for _, tt := range tests {
+		impact := planImpact(tt.typeName, nil, tt.prior, tt.planned, tt.replace)
+		if impact.summary() != tt.summary || impact.detail() != tt.detail {
+			t.Errorf("%s: got %q and %q, expected %q and %q", tt.name, impact.summary(), impact.detail(), tt.summary, tt.detail)
+		}
+	}
+
+	if impact := planImpact("mysql_grant", nil, grant("SELECT"), grant("SELECT"), false); !impact.empty() {
+		t.Errorf("expected no impact without changes, got %q", impact.summary())
+	}
+
+	database := func(collation string, timeout string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"name":              cty.StringVal("app"),
+			"default_collation": cty.StringVal(collation),
+			"wait_for_replicas": cty.StringVal(timeout),
+		})
+	}
+	attributes := map[string]*schema.Schema{
+		"name":              {Type: schema.TypeString, Required: true},
+		"default_collation": {Type: schema.TypeString, Optional: true},
+		"wait_for_replicas": {Type: schema.TypeString, Optional: true},
+	}
+	if impact := planImpact("mysql_database", attributes, database("utf8mb4_bin", "10s"), database("utf8mb4_bin", "1m"), false); !impact.empty() {
+		t.Errorf("expected no impact when only provider-side attributes change, got %q", impact.summary())
+	}
+	if impact := planImpact("mysql_database", attributes, database("utf8mb4_bin", "10s"), database("utf8mb4_general_ci", "10s"), false); impact.summary() != "1 ALTER DATABASE" {
+		t.Errorf("expected 1 ALTER DATABASE, got %q", impact.summary())
+	}
+}
+
+func hostsVal(hosts []string) cty.Value {
+	if len(hosts) == 0 {
+		return cty.SetValEmpty(cty.String)
+	}
+	values := make([]cty.Value, 0, len(hosts))
+	for _, host := range hosts {
+		values = append(values, cty.StringVal(host))
+	}
+	return cty.SetVal(values)
+}
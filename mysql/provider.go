@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -15,6 +16,8 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -58,6 +61,13 @@ const (
 type OneConnection struct {
 	Db      *sql.DB
 	Version *version.Version
+	Flavor  string
+	// SystemTablesReadable is false when the provider user can't read the
+	// mysql schema, so accounts are read with SHOW statements.
+	SystemTablesReadable bool
+	// AuthMode is empty unless the server runs in a degraded
+	// authentication mode, e.g. with skip-grant-tables.
+	AuthMode string
 }
 
 type MySQLConfiguration struct {
@@ -65,11 +75,74 @@ type MySQLConfiguration struct {
 	MaxConnLifetime        time.Duration
 	MaxOpenConns           int
 	ConnectRetryTimeoutSec time.Duration
+	FailoverRetries        int
+	FailoverRetryDelay     time.Duration
+	// WsrepSyncWait is -1 when the server default should be kept.
+	WsrepSyncWait               int
+	GroupReplicationConsistency string
+	TransactionIsolation        string
+	// Autocommit is nil when the server default should be kept.
+	Autocommit         *bool
+	ProxySQL           bool
+	ProxySQLSaveToDisk bool
+	Vitess             bool
+	DefaultUserHost    string
+	DefaultDatabase    string
+	// ReadEndpoint is the replica data sources query, if any. ReadPassword
+	// replaces the password when it's specific to the endpoint.
+	ReadEndpoint string
+	ReadPassword string
+	// AuditLog is nil unless changes of accounts are recorded.
+	AuditLog *auditLogConfig
+	// ExpectedServerUUID and ExpectedVersionPrefix guard against applying
+	// to the wrong server.
+	ExpectedServerUUID    string
+	ExpectedVersionPrefix string
+	// AWSConfigBlock is the aws_config block, loaded when resources call
+	// the RDS API.
+	AWSConfigBlock []interface{}
+	// RDSReboots merges the reboots of RDS instances that resources ask for
+	// during an apply.
+	RDSReboots *rdsRebooter
+	// WaitForReady is nil unless connections wait for the server to be
+	// ready.
+	WaitForReady *waitForReadyConfig
+	// StatementTimeouts is nil unless statements are limited by class.
+	StatementTimeouts *statementTimeoutsConfig
+	// KillQueryOnCancel makes cancelled statements stop on the server.
+	KillQueryOnCancel bool
+	// LockWaitDiagnosticsAfter is how long DDL statements wait for
+	// metadata locks before their blockers are reported, 0 not to report.
+	LockWaitDiagnosticsAfter time.Duration
+	// ShowStatementsOnly makes reads of accounts use SHOW statements
+	// instead of the mysql schema.
+	ShowStatementsOnly bool
+	// ReapEphemeralDatabases drops expired mysql_ephemeral_database
+	// databases before creating new ones.
+	ReapEphemeralDatabases bool
+	// PrivilegeComparison is how mysql_grant compares the privileges in
+	// state with the ones the server reports.
+	PrivilegeComparison string
+	// DegradedAuth is how account resources behave on servers running
+	// with skip-grant-tables: refuse or warn.
+	DegradedAuth string
+	// AuthMode is the authentication mode of the server, detected once for
+	// all account resources.
+	AuthMode *authModeState
+	// StateEncryption is nil unless password hashes are encrypted in the
+	// state.
+	StateEncryption *stateEncryptor
+	// Metrics is nil unless metrics_file is set.
+	Metrics *providerMetrics
+	// PlanImpactDiagnostics adds the SQL impact of planned changes as
+	// warnings.
+	PlanImpactDiagnostics bool
 }
 
 type RDSDataAPIConfiguration struct {
-	Config    *rds.Config
-	AWSConfig aws.Config
+	Config          *rds.Config
+	AWSConfig       aws.Config
+	StateEncryption *stateEncryptor
 }
 
 type CustomTLS struct {
@@ -92,7 +165,7 @@ func init() {
 }
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"endpoint": {
 				Type:        schema.TypeString,
@@ -100,6 +173,29 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("MYSQL_ENDPOINT", nil),
 			},
 
+			"read_endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_READ_ENDPOINT", nil),
+				Description: "Replica that data sources query instead of endpoint, with the same credentials",
+			},
+
+			"compression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("MYSQL_COMPRESSION", "none"),
+				ValidateFunc: validation.StringInSlice([]string{"none", "zlib"}, false),
+				Description:  "Compress the protocol: none or zlib. The driver doesn't support zstd.",
+			},
+
+			"dsn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_DSN", nil),
+				Description: "go-sql-driver DSN whose options are passed to the driver. endpoint, username and password default to the ones in it.",
+			},
+
 			"username": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -109,6 +205,7 @@ func Provider() *schema.Provider {
 			"password": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("MYSQL_PASSWORD", nil),
 			},
 
@@ -170,6 +267,7 @@ func Provider() *schema.Provider {
 						"client_key": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Sensitive:   true,
 							DefaultFunc: schema.EnvDefaultFunc("MYSQL_TLS_CLIENT_KEY", ""),
 						},
 					},
@@ -187,9 +285,17 @@ func Provider() *schema.Provider {
 			},
 
 			"conn_params": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Default:  nil,
+				Type:      schema.TypeMap,
+				Optional:  true,
+				Sensitive: true,
+				Default:   nil,
+			},
+
+			"connection_attributes": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Connection attributes identifying the provider's sessions in performance_schema.session_connect_attrs, added to program_name, workspace and run_id.",
 			},
 
 			"authentication_plugin": {
@@ -205,6 +311,158 @@ func Provider() *schema.Provider {
 				Default:  300,
 			},
 
+			"wait_for_ready": waitForReadySchema(),
+
+			"statement_timeouts": statementTimeoutsSchema(),
+
+			"lock_wait_diagnostics_sec": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Seconds a DDL statement may run before the sessions holding metadata locks it waits for are logged and added to its error. 0 disables it.",
+			},
+
+			"kill_query_on_cancel": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Run KILL QUERY through a separate connection for statements cancelled by an interrupted run or a timeout, so e.g. a long ALTER TABLE doesn't keep running on the server",
+			},
+
+			"failover_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How many times to reconnect and retry a statement failing because of a failover, e.g. of an Aurora cluster. 0 disables retries.",
+			},
+
+			"failover_retry_delay_sec": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Seconds to wait before reconnecting after a failover.",
+			},
+
+			"wsrep_sync_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      -1,
+				ValidateFunc: validation.IntBetween(-1, 15),
+				Description:  "Session value of wsrep_sync_wait for Galera clusters, e.g. 1 so reads wait for writes made through other nodes. -1 keeps the server default.",
+			},
+
+			"group_replication_consistency": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"EVENTUAL", "BEFORE_ON_PRIMARY_FAILOVER", "BEFORE", "AFTER", "BEFORE_AND_AFTER"}, true),
+				Description:  "Session value of group_replication_consistency for MySQL Group Replication, e.g. BEFORE so reads see writes made through other members.",
+			},
+
+			"transaction_isolation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"READ UNCOMMITTED", "READ COMMITTED", "REPEATABLE READ", "SERIALIZABLE"}, true),
+				Description:  "Isolation level of the provider's sessions, e.g. READ COMMITTED to avoid gap locks during concurrent applies.",
+			},
+
+			"autocommit": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Session value of autocommit, which can only be true, e.g. when init_connect turns it off. The server default is kept when unset.",
+			},
+
+			"metrics_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a JSON file summarizing the statements, operation durations, retries and connections of the provider session.",
+			},
+
+			"plan_impact_diagnostics": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Add a warning to each planned change summarizing the GRANT, REVOKE, CREATE, ALTER and DROP statements it runs and the accounts it affects.",
+			},
+
+			"proxysql": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Connect to the ProxySQL admin interface. Only mysql_proxysql_* resources can be used in this mode.",
+			},
+
+			"proxysql_save_to_disk": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Persist ProxySQL configuration with SAVE ... TO DISK after loading it to runtime.",
+			},
+
+			"default_database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Database used by resources and data sources that take a database when it's omitted.",
+			},
+
+			"expected_server_uuid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Fail unless the server_uuid of the server matches, protecting against a wrong endpoint",
+			},
+
+			"expected_version_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Fail unless the version of the server starts with this prefix, e.g. 8.0.",
+			},
+
+			"show_statements_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read accounts with SHOW statements only, for provider users denied SELECT on the mysql schema. It's enabled automatically when reading mysql.user fails.",
+			},
+
+			"reap_expired_ephemeral_databases": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Drop expired mysql_ephemeral_database databases, e.g. left behind by cancelled runs, before creating new ones",
+			},
+
+			"privilege_comparison": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      privilegeComparisonStrict,
+				ValidateFunc: validation.StringInSlice([]string{privilegeComparisonStrict, privilegeComparisonSemantic}, false),
+				Description:  "How mysql_grant compares privileges with the ones the server reports: strict, or semantic to ignore differences such as ALL PRIVILEGES and its expanded list",
+			},
+
+			"degraded_auth_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      degradedAuthRefuse,
+				ValidateFunc: validation.StringInSlice([]string{degradedAuthRefuse, degradedAuthWarn}, false),
+				Description:  "How account resources behave on a server running with --skip-grant-tables: refuse to plan, or warn and keep their state without reading or changing it",
+			},
+
+			"default_user_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "localhost",
+				Description: "Host used by mysql_user and mysql_grant when host is omitted.",
+			},
+
+			"vitess": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Enable Vitess/PlanetScale compatibility mode. It's enabled automatically when the server reports itself as Vitess.",
+			},
+
 			"iam_database_authentication": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -230,12 +488,14 @@ func Provider() *schema.Provider {
 							Optional: true,
 						},
 						"access_key": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 						},
 						"secret_key": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 						},
 						"role_arn": {
 							Type:        schema.TypeString,
@@ -267,6 +527,83 @@ func Provider() *schema.Provider {
 					},
 				},
 			},
+			"oci_config": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"config_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path of the OCI config file. Defaults to ~/.oci/config.",
+						},
+						"profile": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							DefaultFunc: schema.EnvDefaultFunc("OCI_CLI_PROFILE", nil),
+						},
+						"region": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"tenancy_ocid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"user_ocid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"fingerprint": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"private_key": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"private_key_path": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"password_secret_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "OCID of the OCI Vault secret holding the password of the MySQL user.",
+						},
+					},
+				},
+			},
+
+			"audit_log": auditLogSchema(),
+
+			"state_encryption": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kms_key_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "AWS KMS key used to wrap the data key that encrypts password hashes in the state",
+						},
+						"region": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Region of the KMS key. Defaults to the region of aws_config.",
+						},
+						"endpoint": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Custom AWS KMS endpoint, e.g. a VPC endpoint",
+						},
+					},
+				},
+			},
+
 			"azure_config": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -290,8 +627,9 @@ func Provider() *schema.Provider {
 							}, nil),
 						},
 						"client_secret": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 							DefaultFunc: schema.MultiEnvDefaultFunc([]string{
 								"AZURE_CLIENT_SECRET",
 								"ARM_CLIENT_SECRET",
@@ -317,25 +655,107 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"mysql_databases": dataSourceDatabases(),
-			"mysql_tables":    dataSourceTables(),
+			"mysql_databases":             dataSourceDatabases(),
+			"mysql_compliance_report":     dataSourceComplianceReport(),
+			"mysql_tables":                dataSourceTables(),
+			"mysql_user_definition":       dataSourceUserDefinition(),
+			"mysql_heatwave":              dataSourceHeatwave(),
+			"mysql_innodb_cluster":        dataSourceInnoDBCluster(),
+			"mysql_users_with_privilege":  dataSourceUsersWithPrivilege(),
+			"mysql_schema_diff":           dataSourceSchemaDiff(),
+			"mysql_user_profile":          dataSourceUserProfile(),
+			"mysql_host_cache":            dataSourceHostCache(),
+			"mysql_database_size":         dataSourceDatabaseSize(),
+			"mysql_generated_config":      dataSourceGeneratedConfig(),
+			"mysql_password_policy":       dataSourcePasswordPolicy(),
+			"mysql_table_checksums":       dataSourceTableChecksums(),
+			"mysql_provider_capabilities": dataSourceProviderCapabilities(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"mysql_database":        resourceDatabase(),
-			"mysql_global_variable": resourceGlobalVariable(),
-			"mysql_grant":           resourceGrant(),
-			"mysql_role":            resourceRole(),
-			"mysql_sql":             resourceSql(),
-			"mysql_user_password":   resourceUserPassword(),
-			"mysql_user":            resourceUser(),
-			"mysql_ti_config":       resourceTiConfigVariable(),
-			"mysql_rds_config":      resourceRDSConfig(),
-			"mysql_default_roles":   resourceDefaultRoles(),
+			"mysql_database":              resourceDatabase(),
+			"mysql_global_variable":       resourceGlobalVariable(),
+			"mysql_global_variables":      resourceGlobalVariables(),
+			"mysql_grant":                 resourceGrant(),
+			"mysql_role":                  resourceRole(),
+			"mysql_sql":                   resourceSql(),
+			"mysql_user_password":         resourceUserPassword(),
+			"mysql_user":                  resourceUser(),
+			"mysql_ti_config":             resourceTiConfigVariable(),
+			"mysql_rds_config":            resourceRDSConfig(),
+			"mysql_default_roles":         resourceDefaultRoles(),
+			"mysql_secure_installation":   resourceSecureInstallation(),
+			"mysql_password_validation":   resourcePasswordValidation(),
+			"mysql_connection_control":    resourceConnectionControl(),
+			"mysql_user_defined_function": resourceUserDefinedFunction(),
+			"mysql_proxysql_server":       resourceProxySQLServer(),
+			"mysql_proxysql_user":         resourceProxySQLUser(),
+			"mysql_proxysql_query_rule":   resourceProxySQLQueryRule(),
+			"mysql_user_replica":          resourceUserReplica(),
+			"mysql_heatwave_table":        resourceHeatwaveTable(),
+			"mysql_users":                 resourceUsers(),
+			"mysql_group_role_sync":       resourceGroupRoleSync(),
+			"mysql_host_cache_flush":      resourceHostCacheFlush(),
+			"mysql_load_data":             resourceLoadData(),
+			"mysql_foreign_server":        resourceForeignServer(),
+			"mysql_spider_table":          resourceSpiderTable(),
+			"mysql_router_account":        resourceRouterAccount(),
+			"mysql_tool_account":          resourceToolAccount(),
+			"mysql_backup_account":        resourceBackupAccount(),
+			"mysql_dump":                  resourceDump(),
+			"mysql_restore":               resourceRestore(),
+			"mysql_temporary_grant":       resourceTemporaryGrant(),
+			"mysql_ephemeral_database":    resourceEphemeralDatabase(),
+			"mysql_tenant":                resourceTenant(),
 		},
 
 		ConfigureContextFunc: providerConfigure,
 	}
+
+	for _, name := range accountResources {
+		guardDegradedAuth(name, provider.ResourcesMap[name])
+	}
+
+	for name, flavors := range unsupportedFlavorsByResource() {
+		rejectUnsupportedFlavors(name, provider.ResourcesMap[name], flavors)
+	}
+
+	for _, r := range provider.ResourcesMap {
+		setDefaultTimeouts(r)
+	}
+
+	for name, r := range provider.ResourcesMap {
+		auditResource(name, r)
+		instrumentResource(name, r)
+	}
+	for name, r := range provider.DataSourcesMap {
+		instrumentResource(name, r)
+	}
+
+	return provider
+}
+
+// defaultResourceTimeout matches the SDK default, used before resources
+// declared their timeouts.
+const defaultResourceTimeout = 20 * time.Minute
+
+// setDefaultTimeouts enables the timeouts block on a resource. The SDK runs
+// CRUD functions with a context limited by these timeouts, and we pass that
+// context to every SQL statement.
+func setDefaultTimeouts(r *schema.Resource) {
+	if r.Timeouts != nil {
+		return
+	}
+
+	r.Timeouts = &schema.ResourceTimeout{
+		Create:  schema.DefaultTimeout(defaultResourceTimeout),
+		Read:    schema.DefaultTimeout(defaultResourceTimeout),
+		Delete:  schema.DefaultTimeout(defaultResourceTimeout),
+		Default: schema.DefaultTimeout(defaultResourceTimeout),
+	}
+	if r.UpdateContext != nil {
+		r.Timeouts.Update = schema.DefaultTimeout(defaultResourceTimeout)
+	}
 }
 
 func parseConnParams(d *schema.ResourceData, connParams map[string]string) error {
@@ -349,6 +769,44 @@ func parseConnParams(d *schema.ResourceData, connParams map[string]string) error
 	return nil
 }
 
+// connectionAttributes returns the driver's connection attributes. Sessions
+// are tagged with program_name, and workspace and run_id when Terraform
+// (Cloud) exposes them through the environment. Custom attributes override
+// them.
+func connectionAttributes(custom map[string]interface{}, getenv func(string) string) (string, error) {
+	attributes := map[string]string{"program_name": "terraform-provider-mysql"}
+	for name, variables := range map[string][]string{
+		"workspace": {"TF_WORKSPACE", "TFC_WORKSPACE_NAME"},
+		"run_id":    {"TF_VAR_run_id", "TFC_RUN_ID"},
+	} {
+		for _, variable := range variables {
+			if value := getenv(variable); value != "" {
+				// The driver separates attributes with commas.
+				attributes[name] = strings.ReplaceAll(value, ",", "_")
+				break
+			}
+		}
+	}
+	for k, v := range custom {
+		value := v.(string)
+		if strings.ContainsAny(k, ",:") || strings.Contains(value, ",") {
+			return "", fmt.Errorf("invalid connection attribute %q: names can't contain commas or colons, values can't contain commas", k)
+		}
+		attributes[k] = value
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+":"+attributes[name])
+	}
+	return strings.Join(pairs, ","), nil
+}
+
 func buildAwsConfig(ctx context.Context, awsConfigBlock []interface{}) (aws.Config, error) {
 	if len(awsConfigBlock) == 0 || awsConfigBlock[0] == nil {
 		return awsConfig.LoadDefaultConfig(ctx)
@@ -400,6 +858,8 @@ func buildAwsConfig(ctx context.Context, awsConfigBlock []interface{}) (aws.Conf
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	var endpoint = d.Get("endpoint").(string)
+	var readEndpoint = d.Get("read_endpoint").(string)
+	var readPassword string
 	var connParams = make(map[string]string)
 	var authPlugin = d.Get("authentication_plugin").(string)
 	var allowClearTextPasswords = authPlugin == cleartextPasswords
@@ -412,6 +872,24 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	var tlsConfigStruct *tls.Config
 	configKey := "default"
 
+	var dsnConf *mysql.Config
+	if dsn := d.Get("dsn").(string); dsn != "" {
+		var err error
+		dsnConf, err = mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, diag.Errorf("failed parsing dsn: %v", err)
+		}
+		if endpoint == "" {
+			endpoint = dsnConf.Addr
+		}
+		if username == "" {
+			username = dsnConf.User
+		}
+		if password == "" {
+			password = dsnConf.Passwd
+		}
+	}
+
 	// Read AWS config settings
 	var awsRdsIamAuth bool
 	var useRdsDataApi bool
@@ -500,26 +978,43 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		// Configure for cleartext authentication (required for AWS RDS IAM)
 		allowClearTextPasswords = true
 
-		// Add default port if not specified
-		if !strings.Contains(endpoint, ":") {
-			endpoint = endpoint + ":3306"
-		}
-
 		// Build AWS configuration
 		awsConfigObj, err := buildAwsConfig(ctx, awsConfigBlock)
 		if err != nil {
 			return nil, diag.Errorf("failed to build AWS config: %v", err)
 		}
 
+		// Tokens are built for host:port.
+		endpoint, err = endpointWithPort(endpoint)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
 		// Generate AWS RDS IAM auth token
 		password, err = awsRdsAuth.BuildAuthToken(ctx, endpoint, awsConfigObj.Region, username, awsConfigObj.Credentials)
 		if err != nil {
 			return nil, diag.Errorf("failed to build AWS RDS auth token: %v", err)
 		}
 
+		// Tokens are only valid for the endpoint they were built for.
+		if readEndpoint != "" {
+			readEndpoint, err = endpointWithPort(strings.TrimPrefix(readEndpoint, "aws://"))
+			if err != nil {
+				return nil, diag.Errorf("invalid read_endpoint: %v", err)
+			}
+			readPassword, err = awsRdsAuth.BuildAuthToken(ctx, readEndpoint, awsConfigObj.Region, username, awsConfigObj.Credentials)
+			if err != nil {
+				return nil, diag.Errorf("failed to build AWS RDS auth token for read_endpoint: %v", err)
+			}
+		}
+
+	} else if strings.HasPrefix(endpoint, srvEndpointPrefix) {
+		proto = srvNet
+		endpoint = strings.TrimPrefix(endpoint, srvEndpointPrefix)
 	} else if strings.HasPrefix(endpoint, "cloudsql://") {
 		proto = "cloudsql"
 		endpoint = strings.ReplaceAll(endpoint, "cloudsql://", "")
+		readEndpoint = strings.ReplaceAll(readEndpoint, "cloudsql://", "")
 		var err error
 		if iamAuth { // Access token will be in the password field
 
@@ -581,6 +1076,7 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		// has to be configured only with ?allowClearTextPasswords=true not with allowNativePasswords=false in this case
 		allowClearTextPasswords = true
 		endpoint = strings.ReplaceAll(endpoint, "azure://", "")
+		readEndpoint = strings.ReplaceAll(readEndpoint, "azure://", "")
 
 		var azScope string
 		switch azEnvironment {
@@ -612,6 +1108,52 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		password = azToken.Token
 	}
 
+	if proto == "tcp" && endpoint != "" {
+		var err error
+		if endpoint, err = endpointWithPort(endpoint); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+	if readEndpoint != "" && proto != "cloudsql" && !strings.HasPrefix(readEndpoint, "/") && !strings.HasPrefix(readEndpoint, srvEndpointPrefix) {
+		var err error
+		if readEndpoint, err = endpointWithPort(readEndpoint); err != nil {
+			return nil, diag.Errorf("invalid read_endpoint: %v", err)
+		}
+	}
+
+	ociConfigBlock := d.Get("oci_config").([]interface{})
+	if len(ociConfigBlock) > 0 && ociConfigBlock[0] != nil {
+		secretId := ociConfigBlock[0].(map[string]interface{})["password_secret_id"].(string)
+		if secretId != "" {
+			ociConf, err := buildOciConfig(ociConfigBlock)
+			if err != nil {
+				return nil, diag.Errorf("failed to build OCI config: %v", err)
+			}
+
+			log.Printf("[DEBUG] Reading password from OCI Vault secret %s", secretId)
+			password, err = getOciSecret(ctx, ociConf, ociSecretsEndpoint(ociConf.region), secretId)
+			if err != nil {
+				return nil, diag.Errorf("failed to read OCI Vault secret: %v", err)
+			}
+		}
+	}
+
+	var metrics *providerMetrics
+	if path := d.Get("metrics_file").(string); path != "" {
+		metrics = newProviderMetrics(path)
+		registerProviderMetrics(metrics)
+	}
+
+	var stateEncryption *stateEncryptor
+	stateEncryptionBlock := d.Get("state_encryption").([]interface{})
+	if len(stateEncryptionBlock) > 0 && stateEncryptionBlock[0] != nil {
+		var err error
+		stateEncryption, err = buildStateEncryptor(ctx, awsConfigBlock, stateEncryptionBlock[0].(map[string]interface{}))
+		if err != nil {
+			return nil, diag.Errorf("failed to configure state encryption: %v", err)
+		}
+	}
+
 	// Parse connection parameters
 	if err := parseConnParams(d, connParams); err != nil {
 		return nil, diag.FromErr(err)
@@ -637,8 +1179,9 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		}
 
 		return &RDSDataAPIConfiguration{
-			Config:    rdsConfig,
-			AWSConfig: awsConfigObj,
+			Config:          rdsConfig,
+			AWSConfig:       awsConfigObj,
+			StateEncryption: stateEncryption,
 		}, nil
 	}
 
@@ -653,6 +1196,29 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		InterpolateParams:       true,
 		Params:                  connParams,
 	}
+	if dsnConf != nil {
+		merged, err := mergeDSNConfig(dsnConf, &conf)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		conf = *merged
+	}
+	if d.Get("compression").(string) == "zlib" {
+		if err := conf.Apply(mysql.EnableCompression(true)); err != nil {
+			return nil, diag.Errorf("failed enabling compression: %v", err)
+		}
+	}
+
+	auditLog, err := buildAuditLogConfig(d.Get("audit_log").([]interface{}), os.Getenv)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	connAttrs, err := connectionAttributes(d.Get("connection_attributes").(map[string]interface{}), os.Getenv)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	conf.ConnectionAttributes = connAttrs
 
 	if tlsConfigStruct != nil {
 		conf.TLS = tlsConfigStruct
@@ -666,45 +1232,126 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	mysql.RegisterDialContext("tcp", func(ctx context.Context, network string) (net.Conn, error) {
 		return dialer.Dial("tcp", network)
 	})
+	mysql.RegisterDialContext(srvNet, func(ctx context.Context, name string) (net.Conn, error) {
+		return dialSRV(ctx, dialer, lookupSRV, name)
+	})
+
+	// The provider doesn't COMMIT its statements, so with autocommit off
+	// their changes would be rolled back when the connection closes.
+	autocommit := optionalBool(d, "autocommit")
+	if autocommit != nil && !*autocommit {
+		return nil, diag.Errorf("autocommit = false is not supported, as the provider doesn't commit its statements and their changes would be rolled back")
+	}
 
 	mysqlConf := &MySQLConfiguration{
-		Config:                 &conf,
-		MaxConnLifetime:        time.Duration(d.Get("max_conn_lifetime_sec").(int)) * time.Second,
-		MaxOpenConns:           d.Get("max_open_conns").(int),
-		ConnectRetryTimeoutSec: time.Duration(d.Get("connect_retry_timeout_sec").(int)) * time.Second,
+		Config:                      &conf,
+		MaxConnLifetime:             time.Duration(d.Get("max_conn_lifetime_sec").(int)) * time.Second,
+		MaxOpenConns:                d.Get("max_open_conns").(int),
+		ConnectRetryTimeoutSec:      time.Duration(d.Get("connect_retry_timeout_sec").(int)) * time.Second,
+		FailoverRetries:             d.Get("failover_retries").(int),
+		FailoverRetryDelay:          time.Duration(d.Get("failover_retry_delay_sec").(int)) * time.Second,
+		WsrepSyncWait:               d.Get("wsrep_sync_wait").(int),
+		GroupReplicationConsistency: strings.ToUpper(d.Get("group_replication_consistency").(string)),
+		TransactionIsolation:        strings.ToUpper(d.Get("transaction_isolation").(string)),
+		Autocommit:                  autocommit,
+		ProxySQL:                    d.Get("proxysql").(bool),
+		ProxySQLSaveToDisk:          d.Get("proxysql_save_to_disk").(bool),
+		Vitess:                      d.Get("vitess").(bool),
+		DefaultUserHost:             d.Get("default_user_host").(string),
+		DefaultDatabase:             d.Get("default_database").(string),
+		ReadEndpoint:                readEndpoint,
+		ReadPassword:                readPassword,
+		AuditLog:                    auditLog,
+		ExpectedServerUUID:          d.Get("expected_server_uuid").(string),
+		ExpectedVersionPrefix:       d.Get("expected_version_prefix").(string),
+		AWSConfigBlock:              awsConfigBlock,
+		RDSReboots:                  newRDSRebooter(),
+		WaitForReady:                buildWaitForReadyConfig(d.Get("wait_for_ready").([]interface{})),
+		StatementTimeouts:           buildStatementTimeoutsConfig(d.Get("statement_timeouts").([]interface{})),
+		KillQueryOnCancel:           d.Get("kill_query_on_cancel").(bool),
+		LockWaitDiagnosticsAfter:    time.Duration(d.Get("lock_wait_diagnostics_sec").(int)) * time.Second,
+		PrivilegeComparison:         d.Get("privilege_comparison").(string),
+		ShowStatementsOnly:          d.Get("show_statements_only").(bool),
+		ReapEphemeralDatabases:      d.Get("reap_expired_ephemeral_databases").(bool),
+		DegradedAuth:                d.Get("degraded_auth_mode").(string),
+		AuthMode:                    newAuthModeState(),
+		StateEncryption:             stateEncryption,
+		PlanImpactDiagnostics:       d.Get("plan_impact_diagnostics").(bool),
+		Metrics:                     metrics,
+	}
+	// The server has to accept connections within the readiness timeout.
+	if mysqlConf.WaitForReady != nil && mysqlConf.WaitForReady.Timeout > mysqlConf.ConnectRetryTimeoutSec {
+		mysqlConf.ConnectRetryTimeoutSec = mysqlConf.WaitForReady.Timeout
 	}
 
 	return mysqlConf, nil
 }
 
-func afterConnectVersion(ctx context.Context, mysqlConf *MySQLConfiguration, db *sql.DB) (*version.Version, error) {
+// afterConnectVersion configures the sessions of the provider. The settings
+// run on the open connection and on each connection the pool opens later.
+func afterConnectVersion(ctx context.Context, mysqlConf *MySQLConfiguration, db *sql.DB, settings *sessionSettings) (*version.Version, error) {
 	// Set up env so that we won't create users randomly.
 	currentVersion, err := serverVersion(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed getting server version: %v", err)
 	}
 
+	var statements []sessionSetting
 	versionMinInclusive, _ := version.NewVersion("5.7.5")
 	versionMaxExclusive, _ := version.NewVersion("8.0.0")
 	if currentVersion.GreaterThanOrEqual(versionMinInclusive) &&
 		currentVersion.LessThan(versionMaxExclusive) {
 		// We set NO_AUTO_CREATE_USER to prevent provider from creating user when creating grants. Newer MySQL has it automatically.
 		// We don't want any other modes, esp. not ANSI_QUOTES.
-		_, err = db.ExecContext(ctx, `SET SESSION sql_mode='NO_AUTO_CREATE_USER'`)
-		if err != nil {
-			return nil, fmt.Errorf("failed setting SQL mode: %v", err)
-		}
+		statements = append(statements, sessionSetting{"SQL mode", `SET SESSION sql_mode='NO_AUTO_CREATE_USER'`})
 	} else {
 		// We don't want any modes, esp. not ANSI_QUOTES.
-		_, err = db.ExecContext(ctx, `SET SESSION sql_mode=''`)
-		if err != nil {
-			return nil, fmt.Errorf("failed setting SQL mode: %v", err)
+		statements = append(statements, sessionSetting{"SQL mode", `SET SESSION sql_mode=''`})
+	}
+
+	// With a load balancer spreading connections across cluster nodes, a
+	// read may reach a node that hasn't applied our previous write yet.
+	if mysqlConf.WsrepSyncWait >= 0 {
+		statements = append(statements, sessionSetting{"wsrep_sync_wait", fmt.Sprintf("SET SESSION wsrep_sync_wait=%d", mysqlConf.WsrepSyncWait)})
+	}
+	if mysqlConf.GroupReplicationConsistency != "" {
+		statements = append(statements, sessionSetting{"group_replication_consistency", fmt.Sprintf("SET SESSION group_replication_consistency='%s'", mysqlConf.GroupReplicationConsistency)})
+	}
+
+	// SET TRANSACTION works on all flavors, unlike transaction_isolation,
+	// which older MySQL and MariaDB call tx_isolation.
+	if mysqlConf.TransactionIsolation != "" {
+		statements = append(statements, sessionSetting{"transaction isolation", "SET SESSION TRANSACTION ISOLATION LEVEL " + mysqlConf.TransactionIsolation})
+	}
+	if mysqlConf.Autocommit != nil {
+		statements = append(statements, sessionSetting{"autocommit", "SET SESSION autocommit=1"})
+	}
+
+	for _, setting := range statements {
+		if _, err := db.ExecContext(ctx, setting.stmtSQL); err != nil {
+			return nil, fmt.Errorf("failed setting %s: %v", setting.name, err)
 		}
 	}
+	settings.set(statements)
 
 	return currentVersion, nil
 }
 
+// optionalBool returns a bool attribute of the provider, or nil when it
+// isn't configured.
+func optionalBool(d *schema.ResourceData, key string) *bool {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+	v := raw.GetAttr(key)
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	b := v.True()
+	return &b
+}
+
 var identQuoteReplacer = strings.NewReplacer("`", "``")
 
 // httpProxyDialer implements the proxy.Dialer interface for HTTP proxies
@@ -768,11 +1415,7 @@ func shouldUseProxy(endpoint, noProxy string) bool {
 		return true
 	}
 
-	host := endpoint
-	if strings.Contains(endpoint, ":") {
-		parts := strings.Split(endpoint, ":")
-		host = parts[0]
-	}
+	host := endpointHost(endpoint)
 
 	for _, pattern := range strings.Split(noProxy, ",") {
 		pattern = strings.TrimSpace(pattern)
@@ -969,8 +1612,9 @@ func connectToMySQLInternal(ctx context.Context, conf *MySQLConfiguration) (*One
 
 	dsn := conf.Config.FormatDSN()
 	log.Printf("[DEBUG] Using dsn: %s", dsn)
-	if connectionCache[dsn] != nil {
-		return connectionCache[dsn], nil
+	key := connectionCacheKey(dsn, conf)
+	if connectionCache[key] != nil {
+		return connectionCache[key], nil
 	}
 
 	connection, err := createNewConnection(ctx, conf)
@@ -978,13 +1622,112 @@ func connectToMySQLInternal(ctx context.Context, conf *MySQLConfiguration) (*One
 		return nil, fmt.Errorf("could not create new connection: %v", err)
 	}
 
-	connectionCache[dsn] = connection
-	return connectionCache[dsn], nil
+	connectionCache[key] = connection
+	return connectionCache[key], nil
+}
+
+// connectionCacheKey identifies a connection by its DSN and the provider
+// settings outside of the DSN that shape its session and connectors, so
+// provider aliases only share connections when they are set up the same.
+func connectionCacheKey(dsn string, conf *MySQLConfiguration) string {
+	autocommit := ""
+	if conf.Autocommit != nil {
+		autocommit = strconv.FormatBool(*conf.Autocommit)
+	}
+	var statementTimeouts, waitForReady, metricsPath string
+	if conf.StatementTimeouts != nil {
+		statementTimeouts = fmt.Sprintf("%+v", *conf.StatementTimeouts)
+	}
+	if conf.WaitForReady != nil {
+		waitForReady = fmt.Sprintf("%+v", *conf.WaitForReady)
+	}
+	if conf.Metrics != nil {
+		metricsPath = conf.Metrics.path
+	}
+	return fmt.Sprintf("%s|%+v", dsn, struct {
+		MaxConnLifetime             time.Duration
+		FailoverRetries             int
+		FailoverRetryDelay          time.Duration
+		WsrepSyncWait               int
+		GroupReplicationConsistency string
+		TransactionIsolation        string
+		Autocommit                  string
+		ProxySQL                    bool
+		Vitess                      bool
+		ExpectedServerUUID          string
+		ExpectedVersionPrefix       string
+		WaitForReady                string
+		StatementTimeouts           string
+		KillQueryOnCancel           bool
+		LockWaitDiagnosticsAfter    time.Duration
+		ShowStatementsOnly          bool
+		MetricsPath                 string
+	}{
+		conf.MaxConnLifetime,
+		conf.FailoverRetries,
+		conf.FailoverRetryDelay,
+		conf.WsrepSyncWait,
+		conf.GroupReplicationConsistency,
+		conf.TransactionIsolation,
+		autocommit,
+		conf.ProxySQL,
+		conf.Vitess,
+		conf.ExpectedServerUUID,
+		conf.ExpectedVersionPrefix,
+		waitForReady,
+		statementTimeouts,
+		conf.KillQueryOnCancel,
+		conf.LockWaitDiagnosticsAfter,
+		conf.ShowStatementsOnly,
+		metricsPath,
+	})
+}
+
+// openDB opens the connection pool, wrapping the connector of the driver
+// so connections run the session settings and, when configured, retry,
+// limit, kill or trace statements.
+func openDB(driverName string, conf *MySQLConfiguration, settings *sessionSettings) (*sql.DB, error) {
+	killQuery := conf.KillQueryOnCancel && !conf.ProxySQL
+	lockWait := conf.LockWaitDiagnosticsAfter > 0 && !conf.ProxySQL
+
+	var connector driver.Connector
+	var err error
+	if driverName == "mysql" {
+		connector, err = mysql.NewConnector(conf.Config)
+	} else {
+		connector, err = openConnector(driverName, conf.Config.FormatDSN())
+	}
+	if err != nil {
+		return nil, err
+	}
+	connector = sessionConnector{connector: connector, settings: settings}
+	if lockWait {
+		connector = lockWaitConnector{connector: connector, after: conf.LockWaitDiagnosticsAfter}
+	}
+	if killQuery {
+		connector = killQueryConnector{connector: connector}
+	}
+	// Without retries, the failover connector still restores the session
+	// variables resources set on connections replacing ones closed by a
+	// cancelled statement.
+	if driverName == "mysql" && (conf.FailoverRetries > 0 || conf.StatementTimeouts != nil || killQuery) {
+		failover := newFailoverConnector(connector, conf.FailoverRetries, conf.FailoverRetryDelay)
+		failover.metrics = conf.Metrics
+		connector = failover
+	}
+	if conf.StatementTimeouts != nil {
+		connector = statementTimeoutConnector{connector: connector, timeouts: conf.StatementTimeouts}
+	}
+	if instrumentConnections(conf) {
+		connector = tracingConnector{connector: connector, metrics: conf.Metrics}
+	}
+	return sql.OpenDB(connector), nil
 }
 
 func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*OneConnection, error) {
 	var db *sql.DB
 	var err error
+	settings := &sessionSettings{}
 
 	driverName := "mysql"
 	if conf.Config.Net == "cloudsql" {
@@ -996,8 +1739,13 @@ func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*OneCon
 	// when Terraform thinks it's available and when it is actually available.
 	// This is particularly acute when provisioning a server and then immediately
 	// trying to provision a database on it.
+	attempts := 0
 	retryError := retry.RetryContext(ctx, conf.ConnectRetryTimeoutSec, func() *retry.RetryError {
-		db, err = sql.Open(driverName, conf.Config.FormatDSN())
+		attempts++
+		if attempts > 1 {
+			conf.Metrics.connectRetry()
+		}
+		db, err = openDB(driverName, conf, settings)
 		if err != nil {
 			if mysqlErrorNumber(err) != 0 || cloudsqlErrorNumber(err) != 0 || ctx.Err() != nil {
 				return retry.NonRetryableError(err)
@@ -1027,13 +1775,56 @@ func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*OneCon
 	// TODO: find a way to support more open connections while able to set custom settings for each of them.
 	db.SetMaxOpenConns(1)
 
-	currentVersion, err := afterConnectVersion(ctx, conf, db)
+	// ProxySQL admin interface is backed by SQLite and supports neither
+	// @@GLOBAL.version nor sql_mode.
+	if conf.ProxySQL {
+		return &OneConnection{
+			Db: db,
+		}, nil
+	}
+
+	if conf.WaitForReady != nil {
+		if err := waitForServerReady(ctx, conf.WaitForReady, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	if err := checkExpectedServer(ctx, conf, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	flavor, err := serverFlavor(db, conf.Vitess)
+	if err != nil {
+		return nil, fmt.Errorf("failed detecting server flavor: %v", err)
+	}
+
+	var currentVersion *version.Version
+	if flavor == flavorClickHouse {
+		// ClickHouse doesn't know sql_mode, so we only read the version.
+		currentVersion, err = serverVersion(db)
+	} else {
+		currentVersion, err = afterConnectVersion(ctx, conf, db, settings)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed running after connect command: %v", err)
 	}
 
+	systemTablesReadable := false
+	if flavor != flavorClickHouse && !conf.ShowStatementsOnly {
+		systemTablesReadable = probeSystemTables(ctx, db)
+	}
+	authMode := ""
+	if flavor != flavorClickHouse {
+		authMode = serverAuthMode(ctx, db, systemTablesReadable)
+	}
+
 	return &OneConnection{
-		Db:      db,
-		Version: currentVersion,
+		Db:                   db,
+		Version:              currentVersion,
+		Flavor:               flavor,
+		SystemTablesReadable: systemTablesReadable,
+		AuthMode:             authMode,
 	}, nil
 }
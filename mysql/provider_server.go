@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServer serves Provider with what the SDK doesn't support: the
+// impact warnings of planned changes (plan_impact.go), moving resources from
+// other providers (state_upgrade.go) and provider functions (functions.go).
+func ProviderServer() tfprotov5.ProviderServer {
+	provider := Provider()
+	return &providerServer{
+		ProviderServer: schema.NewGRPCProviderServer(provider),
+		provider:       provider,
+	}
+}
+
+type providerServer struct {
+	tfprotov5.ProviderServer
+	provider *schema.Provider
+}
+
+func (s *providerServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.ProviderServer.GetProviderSchema(ctx, req)
+	if resp != nil {
+		resp.Functions = providerFunctionDefinitions()
+		if resp.ServerCapabilities != nil {
+			resp.ServerCapabilities.MoveResourceState = true
+		}
+	}
+	return resp, err
+}
+
+func (s *providerServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.ProviderServer.GetMetadata(ctx, req)
+	if resp != nil {
+		resp.Functions = providerFunctionMetadata()
+		if resp.ServerCapabilities != nil {
+			resp.ServerCapabilities.MoveResourceState = true
+		}
+	}
+	return resp, err
+}
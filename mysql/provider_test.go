@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 
@@ -359,3 +360,48 @@ func testAccPreCheckSkipNotTiDB(t *testing.T) {
 		t.Skip(msg)
 	}
 }
+
+func TestProviderResourceTimeouts(t *testing.T) {
+	for name, r := range Provider().ResourcesMap {
+		if r.Timeouts == nil || r.Timeouts.Create == nil || r.Timeouts.Read == nil || r.Timeouts.Delete == nil {
+			t.Errorf("%s: missing timeouts", name)
+		}
+		if (r.UpdateContext != nil) != (r.Timeouts != nil && r.Timeouts.Update != nil) {
+			t.Errorf("%s: update timeout doesn't match UpdateContext", name)
+		}
+	}
+}
+
+func TestConnectionAttributes(t *testing.T) {
+	env := map[string]string{"TFC_WORKSPACE_NAME": "prod", "TFC_RUN_ID": "run-a,b"}
+	got, err := connectionAttributes(map[string]interface{}{"team": "dba"}, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "program_name:terraform-provider-mysql,run_id:run-a_b,team:dba,workspace:prod"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	if _, err := connectionAttributes(map[string]interface{}{"a:b": "c"}, func(string) string { return "" }); err == nil {
+		t.Error("expected an error for a colon in the name")
+	}
+}
+
+func TestConnectionCacheKey(t *testing.T) {
+	conf := &MySQLConfiguration{TransactionIsolation: "READ COMMITTED"}
+	key := connectionCacheKey("root@tcp(localhost:3306)/", conf)
+	if same := connectionCacheKey("root@tcp(localhost:3306)/", &MySQLConfiguration{TransactionIsolation: "READ COMMITTED"}); same != key {
+		t.Errorf("expected equal settings to share a connection, got %q and %q", key, same)
+	}
+	for _, other := range []*MySQLConfiguration{
+		{TransactionIsolation: "SERIALIZABLE"},
+		{TransactionIsolation: "READ COMMITTED", KillQueryOnCancel: true},
+		{TransactionIsolation: "READ COMMITTED", FailoverRetries: 3},
+		{TransactionIsolation: "READ COMMITTED", StatementTimeouts: &statementTimeoutsConfig{DDL: time.Minute}},
+	} {
+		if connectionCacheKey("root@tcp(localhost:3306)/", other) == key {
+			t.Errorf("expected %+v not to share the connection of %+v", other, conf)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// getProxySQLFromMeta returns connection to the ProxySQL admin interface.
+func getProxySQLFromMeta(ctx context.Context, meta interface{}) (*sql.DB, bool, error) {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok || !conf.ProxySQL {
+		return nil, false, fmt.Errorf("mysql_proxysql_* resources require proxysql = true in the provider configuration")
+	}
+
+	oneConnection, err := connectToMySQLInternal(ctx, conf)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to connect to ProxySQL: %v", err)
+	}
+	return oneConnection.Db, conf.ProxySQLSaveToDisk, nil
+}
+
+// proxysqlNullableQuote quotes s or returns NULL for an empty string.
+func proxysqlNullableQuote(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return quoteString(s)
+}
+
+// proxysqlExecAndLoad runs statements against the admin configuration tables
+// and then activates them: LOAD MYSQL <module> TO RUNTIME and optionally SAVE
+// MYSQL <module> TO DISK.
+func proxysqlExecAndLoad(ctx context.Context, db *sql.DB, saveToDisk bool, module string, stmts ...string) error {
+	stmts = append(stmts, fmt.Sprintf("LOAD MYSQL %s TO RUNTIME", module))
+	if saveToDisk {
+		stmts = append(stmts, fmt.Sprintf("SAVE MYSQL %s TO DISK", module))
+	}
+
+	for _, stmtSQL := range stmts {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed executing %s: %v", stmtSQL, err)
+		}
+	}
+	return nil
+}
+
+func proxysqlBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
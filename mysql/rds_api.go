@@ -0,0 +1,244 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+const (
+	rdsHostSuffix  = ".rds.amazonaws.com"
+	rdsPollingWait = 15 * time.Second
+	// rdsRebootPolls is how often the instance may still look available
+	// before the reboot is assumed to be over already.
+	rdsRebootPolls = 4
+	// rdsAPITimeout bounds each call of the RDS API.
+	rdsAPITimeout = 30 * time.Second
+)
+
+// rdsRebootBatchWait is how long a reboot waits for other resources asking
+// to reboot the same instance, so they share it.
+var rdsRebootBatchWait = 10 * time.Second
+
+// rdsEndpoint is an RDS instance, or an Aurora cluster, the provider is
+// connected to.
+type rdsEndpoint struct {
+	identifier string
+	region     string
+	// cluster is set for Aurora cluster endpoints, whose identifier is the
+	// one of the cluster, not of an instance.
+	cluster bool
+}
+
+// rdsEndpointFromHost parses RDS endpoints like
+// mydb.abc123.us-east-1.rds.amazonaws.com, or
+// mycluster.cluster-abc123.us-east-1.rds.amazonaws.com for Aurora clusters.
+func rdsEndpointFromHost(host string) (rdsEndpoint, bool) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if !strings.HasSuffix(host, rdsHostSuffix) {
+		return rdsEndpoint{}, false
+	}
+	labels := strings.Split(strings.TrimSuffix(host, rdsHostSuffix), ".")
+	if len(labels) != 3 {
+		return rdsEndpoint{}, false
+	}
+	return rdsEndpoint{
+		identifier: labels[0],
+		region:     labels[2],
+		cluster:    strings.HasPrefix(labels[1], "cluster-"),
+	}, true
+}
+
+// rdsEndpointFromMeta returns the RDS instance or cluster the provider is
+// connected to. The instance identifier can be given for endpoints not named
+// after the instance, e.g. custom DNS names. It returns false for other
+// servers.
+func rdsEndpointFromMeta(meta interface{}, identifier string) (rdsEndpoint, bool) {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok {
+		return rdsEndpoint{}, false
+	}
+	endpoint, ok := rdsEndpointFromHost(endpointHost(conf.Config.Addr))
+	if identifier != "" {
+		return rdsEndpoint{identifier: identifier, region: endpoint.region}, true
+	}
+	return endpoint, ok
+}
+
+// rebootRDSFromMeta reboots the RDS instance the provider is connected to,
+// or all instances of its Aurora cluster, using the credentials and region of
+// aws_config. Resources asking to reboot the same endpoint while a reboot is
+// pending share it.
+func rebootRDSFromMeta(ctx context.Context, meta interface{}, identifier string) error {
+	endpoint, ok := rdsEndpointFromMeta(meta, identifier)
+	if !ok {
+		return fmt.Errorf("the endpoint is not an RDS endpoint; set rds_instance_identifier")
+	}
+	conf := meta.(*MySQLConfiguration)
+	cfg, err := buildAwsConfig(ctx, conf.AWSConfigBlock)
+	if err != nil {
+		return fmt.Errorf("failed to build AWS config: %v", err)
+	}
+	if endpoint.region == "" {
+		endpoint.region = cfg.Region
+	}
+	if endpoint.region == "" {
+		return fmt.Errorf("region of RDS instance %s is unknown; set region in aws_config", endpoint.identifier)
+	}
+	client := awsrds.NewFromConfig(cfg, func(o *awsrds.Options) {
+		o.Region = endpoint.region
+	})
+
+	reboot := func(ctx context.Context) error {
+		return rebootRDSEndpoint(ctx, client, endpoint)
+	}
+	if conf.RDSReboots == nil {
+		return reboot(ctx)
+	}
+	return conf.RDSReboots.reboot(ctx, endpoint, reboot)
+}
+
+// rdsRebooter merges the reboots resources of one apply ask for.
+type rdsRebooter struct {
+	mu      sync.Mutex
+	pending map[rdsEndpoint]*rdsReboot
+}
+
+// rdsReboot is a reboot that hasn't started yet, or its result.
+type rdsReboot struct {
+	done chan struct{}
+	err  error
+}
+
+func newRDSRebooter() *rdsRebooter {
+	return &rdsRebooter{pending: map[rdsEndpoint]*rdsReboot{}}
+}
+
+// reboot runs f after rdsRebootBatchWait, unless a reboot of the endpoint is
+// pending already, in which case it waits for that one. Once a reboot
+// started, the next caller starts a new one, as its change may have come
+// too late for the running one.
+func (r *rdsRebooter) reboot(ctx context.Context, endpoint rdsEndpoint, f func(context.Context) error) error {
+	r.mu.Lock()
+	pending := r.pending[endpoint]
+	if pending == nil {
+		pending = &rdsReboot{done: make(chan struct{})}
+		r.pending[endpoint] = pending
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(rdsRebootBatchWait):
+		}
+		r.mu.Lock()
+		delete(r.pending, endpoint)
+		r.mu.Unlock()
+
+		if err := ctx.Err(); err != nil {
+			pending.err = err
+		} else {
+			pending.err = f(ctx)
+		}
+		close(pending.done)
+		return pending.err
+	}
+	r.mu.Unlock()
+
+	log.Printf("[DEBUG] Joining pending reboot of RDS %s", endpoint.identifier)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-pending.done:
+		return pending.err
+	}
+}
+
+// rebootRDSEndpoint reboots the instance, or the instances of the cluster,
+// the readers first, so the writer the provider uses is rebooted last.
+func rebootRDSEndpoint(ctx context.Context, client *awsrds.Client, endpoint rdsEndpoint) error {
+	if !endpoint.cluster {
+		return rebootRDSInstance(ctx, client, endpoint.identifier)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, rdsAPITimeout)
+	out, err := client.DescribeDBClusters(callCtx, &awsrds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(endpoint.identifier),
+	})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed describing RDS cluster %s: %v", endpoint.identifier, err)
+	}
+	if len(out.DBClusters) == 0 {
+		return fmt.Errorf("RDS cluster %s not found", endpoint.identifier)
+	}
+
+	var readers, writers []string
+	for _, member := range out.DBClusters[0].DBClusterMembers {
+		if aws.ToBool(member.IsClusterWriter) {
+			writers = append(writers, aws.ToString(member.DBInstanceIdentifier))
+		} else {
+			readers = append(readers, aws.ToString(member.DBInstanceIdentifier))
+		}
+	}
+	for _, identifier := range append(readers, writers...) {
+		if err := rebootRDSInstance(ctx, client, identifier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func describeRDSInstanceStatus(ctx context.Context, client *awsrds.Client, identifier string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, rdsAPITimeout)
+	defer cancel()
+	out, err := client.DescribeDBInstances(ctx, &awsrds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(identifier),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed describing RDS instance %s: %v", identifier, err)
+	}
+	if len(out.DBInstances) == 0 {
+		return "", fmt.Errorf("RDS instance %s not found", identifier)
+	}
+	return aws.ToString(out.DBInstances[0].DBInstanceStatus), nil
+}
+
+// rebootRDSInstance reboots the instance and waits until it's available
+// again, so the values persisted for the restart are active afterwards.
+func rebootRDSInstance(ctx context.Context, client *awsrds.Client, identifier string) error {
+	log.Printf("[INFO] Rebooting RDS instance %s", identifier)
+	callCtx, cancel := context.WithTimeout(ctx, rdsAPITimeout)
+	_, err := client.RebootDBInstance(callCtx, &awsrds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String(identifier),
+	})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed rebooting RDS instance %s: %v", identifier, err)
+	}
+
+	rebooting := false
+	for polls := 1; ; polls++ {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for RDS instance %s to reboot", identifier)
+		case <-time.After(rdsPollingWait):
+		}
+		status, err := describeRDSInstanceStatus(ctx, client, identifier)
+		if err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] RDS instance %s is %s", identifier, status)
+		// The status may still be available right after the call.
+		if status != "available" {
+			rebooting = true
+		} else if rebooting || polls >= rdsRebootPolls {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+func TestRDSEndpointFromHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		endpoint rdsEndpoint
+		ok       bool
+	}{
+		{"mydb.abc123xyz.us-east-1.rds.amazonaws.com", rdsEndpoint{"mydb", "us-east-1", false}, true},
+		{"MyDB.abc123xyz.eu-west-2.rds.amazonaws.com.", rdsEndpoint{"mydb", "eu-west-2", false}, true},
+		{"mycluster.cluster-abc123xyz.us-east-1.rds.amazonaws.com", rdsEndpoint{"mycluster", "us-east-1", true}, true},
+		{"mycluster.cluster-ro-abc123xyz.us-east-1.rds.amazonaws.com", rdsEndpoint{"mycluster", "us-east-1", true}, true},
+		{"db.example.com", rdsEndpoint{}, false},
+		{"abc123xyz.us-east-1.rds.amazonaws.com", rdsEndpoint{}, false},
+	}
+	for _, tt := range tests {
+		endpoint, ok := rdsEndpointFromHost(tt.host)
+		if endpoint != tt.endpoint || ok != tt.ok {
+			t.Errorf("rdsEndpointFromHost(%q) = %+v, %t, want %+v, %t", tt.host, endpoint, ok, tt.endpoint, tt.ok)
+		}
+	}
+}
+
+func testRDSClient(url string) *awsrds.Client {
+	return awsrds.New(awsrds.Options{
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		BaseEndpoint: aws.String(url),
+	})
+}
+
+func TestDescribeRDSInstanceStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("request is not signed: %q", r.Header.Get("Authorization"))
+		}
+		if r.Form.Get("Action") != "DescribeDBInstances" {
+			t.Errorf("unexpected action %q", r.Form.Get("Action"))
+		}
+		if r.Form.Get("DBInstanceIdentifier") != "mydb" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<ErrorResponse><Error><Code>DBInstanceNotFound</Code><Message>DBInstance missing not found.</Message></Error></ErrorResponse>`)
+			return
+		}
+		fmt.Fprint(w, `<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances><DBInstance><DBInstanceStatus>rebooting</DBInstanceStatus></DBInstance></DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`)
+	}))
+	defer server.Close()
+
+	client := testRDSClient(server.URL)
+	status, err := describeRDSInstanceStatus(context.Background(), client, "mydb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "rebooting" {
+		t.Errorf("status = %q, want rebooting", status)
+	}
+
+	_, err = describeRDSInstanceStatus(context.Background(), client, "missing")
+	if err == nil || !strings.Contains(err.Error(), "DBInstanceNotFound") {
+		t.Errorf("expected DBInstanceNotFound, got %v", err)
+	}
+}
+
+func TestRDSRebooterSharesPendingReboot(t *testing.T) {
+	defaultWait := rdsRebootBatchWait
+	rdsRebootBatchWait = time.Second
+	defer func() { rdsRebootBatchWait = defaultWait }()
+
+	rebooter := newRDSRebooter()
+	endpoint := rdsEndpoint{identifier: "mydb", region: "us-east-1"}
+	var reboots atomic.Int32
+	reboot := func(ctx context.Context) error {
+		reboots.Add(1)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := rebooter.reboot(context.Background(), endpoint, reboot); err != nil {
+			t.Error(err)
+		}
+	}()
+	// The second reboot joins the pending one.
+	for {
+		rebooter.mu.Lock()
+		pending := rebooter.pending[endpoint] != nil
+		rebooter.mu.Unlock()
+		if pending {
+			break
+		}
+	}
+	if err := rebooter.reboot(context.Background(), endpoint, reboot); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if n := reboots.Load(); n != 1 {
+		t.Errorf("rebooted %d times, want 1", n)
+	}
+}
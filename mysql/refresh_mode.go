@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	refreshAlways          = "always"
+	refreshNever           = "never"
+	refreshOnVersionChange = "on_version_change"
+)
+
+// withRefreshMode adds refresh_mode to an expensive resource, letting large
+// estates skip reading it on every plan. Creates and updates still read it.
+func withRefreshMode(r *schema.Resource) *schema.Resource {
+	r.Schema["refresh_mode"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      refreshAlways,
+		ValidateFunc: validation.StringInSlice([]string{refreshAlways, refreshNever, refreshOnVersionChange}, false),
+		Description:  "When to read the resource from the server: always, never or on_version_change",
+	}
+	r.Schema["refreshed_version"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Server version the resource was last read from",
+	}
+
+	read := r.ReadContext
+	r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		serverVersion := refreshServerVersion(ctx, meta)
+		switch d.Get("refresh_mode").(string) {
+		case refreshNever:
+			log.Printf("[DEBUG] Not reading %s, refresh_mode is never", d.Id())
+			return nil
+		case refreshOnVersionChange:
+			if serverVersion != "" && serverVersion == d.Get("refreshed_version").(string) {
+				log.Printf("[DEBUG] Not reading %s, server version %s is unchanged", d.Id(), serverVersion)
+				return nil
+			}
+		}
+
+		diags := read(ctx, d, meta)
+		if !diags.HasError() && d.Id() != "" {
+			d.Set("refreshed_version", serverVersion)
+		}
+		return diags
+	}
+
+	// Create and update read the resource without the wrapper above.
+	if r.CreateContext != nil {
+		r.CreateContext = recordRefreshedVersion(r.CreateContext)
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = recordRefreshedVersion(r.UpdateContext)
+	}
+	return r
+}
+
+func recordRefreshedVersion(apply func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		diags := apply(ctx, d, meta)
+		if !diags.HasError() && d.Id() != "" {
+			d.Set("refreshed_version", refreshServerVersion(ctx, meta))
+		}
+		return diags
+	}
+}
+
+// refreshServerVersion returns the version of the server, or an empty string
+// when it's unknown.
+func refreshServerVersion(ctx context.Context, meta interface{}) string {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok {
+		return ""
+	}
+	oneConnection, err := connectToMySQLInternal(ctx, conf)
+	if err != nil || oneConnection.Version == nil {
+		return ""
+	}
+	return oneConnection.Version.String()
+}
@@ -0,0 +1,40 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestWithRefreshMode(t *testing.T) {
+	reads := 0
+	r := withRefreshMode(&schema.Resource{
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			reads++
+			return nil
+		},
+		Schema: map[string]*schema.Schema{},
+	})
+
+	for _, c := range []struct {
+		mode  string
+		reads int
+	}{
+		{refreshAlways, 1},
+		{refreshNever, 0},
+		// Without a known server version the resource is always read.
+		{refreshOnVersionChange, 1},
+	} {
+		reads = 0
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"refresh_mode": c.mode})
+		d.SetId("test")
+		if diags := r.ReadContext(context.Background(), d, nil); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if reads != c.reads {
+			t.Errorf("refresh_mode %s read %d times, want %d", c.mode, reads, c.reads)
+		}
+	}
+}
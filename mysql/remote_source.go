@@ -0,0 +1,260 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// remoteSourceTimeout bounds reading a remote source, on top of the
+	// timeout of the resource operation.
+	remoteSourceTimeout = 30 * time.Minute
+	// remoteSourceConnectTimeout bounds connecting and waiting for the
+	// response headers, so an unreachable endpoint fails fast.
+	remoteSourceConnectTimeout = 30 * time.Second
+)
+
+// remoteSourceClient is the HTTP client for sources and uploads, including
+// the ones of S3 and GCS.
+var remoteSourceClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: remoteSourceConnectTimeout}).DialContext,
+		TLSHandshakeTimeout:   remoteSourceConnectTimeout,
+		ResponseHeaderTimeout: remoteSourceConnectTimeout,
+		IdleConnTimeout:       90 * time.Second,
+	},
+}
+
+// s3ClientOptions is applied to S3 clients. Tests replace it.
+var s3ClientOptions = func(o *s3.Options) {}
+
+// parseSourceURL returns the URL of a remote source, or nil for local files.
+func parseSourceURL(source string) *url.URL {
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 || u.Scheme == "file" {
+		// Not a URL, or a Windows drive letter.
+		return nil
+	}
+	return u
+}
+
+func localSourcePath(source string) string {
+	if u, err := url.Parse(source); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	return source
+}
+
+// fetchSource reads a local file or an http(s)://, s3:// or gs:// URL. S3
+// and GCS are read with the default credentials of the environment. It also
+// returns the version of the source, see sourceVersion.
+func fetchSource(ctx context.Context, source string) ([]byte, string, error) {
+	u := parseSourceURL(source)
+	if u == nil {
+		content, err := os.ReadFile(localSourcePath(source))
+		return content, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteSourceTimeout)
+	defer cancel()
+
+	var req *http.Request
+	var err error
+	switch u.Scheme {
+	case "http", "https":
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	case "s3":
+		return getS3Object(ctx, u.Host, strings.TrimPrefix(u.Path, "/"))
+	case "gs":
+		req, err = gcsRequest(ctx, gcsObjectURL(u.Host, strings.TrimPrefix(u.Path, "/"))+"?alt=media")
+	default:
+		return nil, "", unsupportedSourceScheme(u.Scheme)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := remoteSourceClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", sourceStatusError(source, resp)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	version := resp.Header.Get("ETag")
+	if u.Scheme == "gs" {
+		version = resp.Header.Get("X-Goog-Generation")
+	}
+	return content, version, nil
+}
+
+// sourceVersion returns an identifier of the content of a remote source, its
+// ETag or generation, without reading the content. It returns an empty
+// string for local files and servers reporting no ETag, whose content needs
+// to be read instead.
+func sourceVersion(ctx context.Context, source string) (string, error) {
+	u := parseSourceURL(source)
+	if u == nil {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteSourceConnectTimeout)
+	defer cancel()
+
+	switch u.Scheme {
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, source, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := remoteSourceClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			// Some servers don't allow HEAD; reading the content tells.
+			return "", nil
+		}
+		return resp.Header.Get("ETag"), nil
+	case "s3":
+		client, err := newS3Client(ctx)
+		if err != nil {
+			return "", err
+		}
+		out, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(u.Host),
+			Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed reading %s: %v", source, err)
+		}
+		return aws.ToString(out.ETag), nil
+	case "gs":
+		req, err := gcsRequest(ctx, gcsObjectURL(u.Host, strings.TrimPrefix(u.Path, "/"))+"?fields=generation")
+		if err != nil {
+			return "", err
+		}
+		resp, err := remoteSourceClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", sourceStatusError(source, resp)
+		}
+		var metadata struct {
+			Generation string `json:"generation"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+			return "", fmt.Errorf("failed reading metadata of %s: %v", source, err)
+		}
+		return metadata.Generation, nil
+	}
+	return "", unsupportedSourceScheme(u.Scheme)
+}
+
+func unsupportedSourceScheme(scheme string) error {
+	return fmt.Errorf("unsupported source scheme %s, use a path or an http(s)://, s3:// or gs:// URL", scheme)
+}
+
+func sourceStatusError(source string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("fetching %s failed with %s: %s", source, resp.Status, strings.TrimSpace(string(body)))
+}
+
+func newS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := awsConfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading AWS config: %v", err)
+	}
+	return newS3ClientFromConfig(cfg), nil
+}
+
+func newS3ClientFromConfig(cfg aws.Config) *s3.Client {
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if o.Region == "" {
+			o.Region = "us-east-1"
+		}
+		o.HTTPClient = remoteSourceClient
+	}, s3ClientOptions)
+}
+
+func getS3Object(ctx context.Context, bucket, key string) ([]byte, string, error) {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed reading s3://%s/%s: %v", bucket, key, err)
+	}
+	defer out.Body.Close()
+	content, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed reading s3://%s/%s: %v", bucket, key, err)
+	}
+	return content, aws.ToString(out.ETag), nil
+}
+
+// putS3Object uploads the file to S3 with the credentials of cfg.
+func putS3Object(ctx context.Context, cfg aws.Config, bucket, key string, file *os.File, size int64) error {
+	if cfg.Credentials == nil {
+		return errors.New("no AWS credentials configured")
+	}
+	_, err := newS3ClientFromConfig(cfg).PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		Body:          file,
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		return fmt.Errorf("uploading s3://%s/%s failed: %v", bucket, key, err)
+	}
+	return nil
+}
+
+func gcsObjectURL(bucket, object string) string {
+	return fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o/%s", url.PathEscape(bucket), url.PathEscape(object))
+}
+
+func gcsRequest(ctx context.Context, endpoint string) (*http.Request, error) {
+	tokenSource, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/devstorage.read_only")
+	if err != nil {
+		return nil, fmt.Errorf("failed loading Google credentials: %v", err)
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting Google token: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	token.SetAuthHeader(req)
+	return req, nil
+}
@@ -0,0 +1,282 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// replicaPollInterval is how often replica lag is checked without GTIDs.
+const replicaPollInterval = time.Second
+
+// waitForReplicasSchema is the wait_for_replicas block of resources running
+// DDL.
+func waitForReplicasSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Wait for replicas to catch up before the apply of this resource completes",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"endpoints": {
+					Type:        schema.TypeList,
+					Required:    true,
+					MinItems:    1,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+					Description: "host:port of the replicas, reached with the provider's credentials",
+				},
+				"channels": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+					Description: "Replication channels (connection names on MariaDB) to check without GTIDs, all by default",
+				},
+				"max_lag_seconds": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Lag accepted without GTIDs",
+				},
+				"timeout_seconds": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      300,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+type replicaWait struct {
+	endpoints  []string
+	channels   []string
+	maxLag     int64
+	timeout    time.Duration
+	gtidSet    string
+	gtidWaitFn string
+}
+
+// waitForReplicas blocks until the replicas of wait_for_replicas applied
+// the changes made so far. With GTIDs, replicas wait until they executed
+// the primary's GTID set; otherwise their lag is polled.
+func waitForReplicas(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	blocks := d.Get("wait_for_replicas").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	block := blocks[0].(map[string]interface{})
+
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok {
+		return errors.New("wait_for_replicas requires a MySQL connection")
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	wait := &replicaWait{
+		endpoints: toStringList(block["endpoints"]),
+		channels:  toStringList(block["channels"]),
+		maxLag:    int64(block["max_lag_seconds"].(int)),
+		timeout:   time.Duration(block["timeout_seconds"].(int)) * time.Second,
+	}
+	wait.gtidSet, wait.gtidWaitFn, err = primaryGTIDSet(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed reading GTIDs of the primary: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, wait.timeout)
+	defer cancel()
+
+	for _, endpoint := range wait.endpoints {
+		replicaConfig := conf.Config.Clone()
+		replicaConfig.Addr = endpoint
+		replicaConf := *conf
+		replicaConf.Config = replicaConfig
+
+		replica, err := connectToMySQL(ctx, &replicaConf)
+		if err != nil {
+			return fmt.Errorf("failed connecting to replica %s: %v", endpoint, err)
+		}
+		if err := wait.waitFor(ctx, replica); err != nil {
+			return fmt.Errorf("replica %s didn't catch up: %v", endpoint, err)
+		}
+	}
+	return nil
+}
+
+func toStringList(v interface{}) []string {
+	var values []string
+	for _, value := range v.([]interface{}) {
+		values = append(values, value.(string))
+	}
+	return values
+}
+
+// primaryGTIDSet returns the GTIDs executed on the primary and the function
+// replicas wait for them with, or empty strings when GTIDs are off.
+func primaryGTIDSet(ctx context.Context, db *sql.DB) (string, string, error) {
+	isMariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return "", "", err
+	}
+
+	if isMariaDB {
+		var pos string
+		if err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_binlog_pos").Scan(&pos); err != nil {
+			return "", "", err
+		}
+		return pos, "MASTER_GTID_WAIT", nil
+	}
+
+	var mode, executed string
+	err = db.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_mode, @@GLOBAL.gtid_executed").Scan(&mode, &executed)
+	if mysqlErrorNumber(err) == unknownSystemVariableErrCode {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if mode != "ON" {
+		return "", "", nil
+	}
+	return executed, "WAIT_FOR_EXECUTED_GTID_SET", nil
+}
+
+func (w *replicaWait) waitFor(ctx context.Context, replica *sql.DB) error {
+	if w.gtidSet != "" {
+		return w.waitForGTIDs(ctx, replica)
+	}
+	for {
+		caughtUp, err := w.lagWithin(ctx, replica)
+		if err != nil || caughtUp {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("lag is still above %d seconds after %s", w.maxLag, w.timeout)
+		case <-time.After(replicaPollInterval):
+		}
+	}
+}
+
+func (w *replicaWait) waitForGTIDs(ctx context.Context, replica *sql.DB) error {
+	deadline, _ := ctx.Deadline()
+	timeout := int(time.Until(deadline).Seconds())
+	if timeout < 1 {
+		timeout = 1
+	}
+
+	// Both functions return 0 once the GTIDs are applied.
+	stmtSQL := fmt.Sprintf("SELECT %s(?, ?)", w.gtidWaitFn)
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	var result sql.NullInt64
+	if err := replica.QueryRowContext(ctx, stmtSQL, w.gtidSet, timeout).Scan(&result); err != nil {
+		return err
+	}
+	if !result.Valid || result.Int64 != 0 {
+		return fmt.Errorf("transactions %s weren't applied within %s", w.gtidSet, w.timeout)
+	}
+	return nil
+}
+
+// lagWithin reports whether every checked channel replicates with at most
+// the accepted lag.
+func (w *replicaWait) lagWithin(ctx context.Context, replica *sql.DB) (bool, error) {
+	statuses, err := replicaStatuses(ctx, replica)
+	if err != nil {
+		return false, err
+	}
+	if len(statuses) == 0 {
+		return false, errors.New("server isn't a replica")
+	}
+
+	checked := map[string]bool{}
+	for _, channel := range w.channels {
+		checked[channel] = true
+	}
+	found := 0
+	for _, status := range statuses {
+		channel := status["Channel_Name"]
+		if channel == "" {
+			channel = status["Connection_name"]
+		}
+		if len(checked) > 0 && !checked[channel] {
+			continue
+		}
+		found++
+
+		lag := status["Seconds_Behind_Source"]
+		if lag == "" {
+			lag = status["Seconds_Behind_Master"]
+		}
+		// The lag is NULL while replication is stopped.
+		seconds, err := strconv.ParseInt(lag, 10, 64)
+		if err != nil || seconds > w.maxLag {
+			log.Printf("[DEBUG] Replication channel %q lags behind: %q seconds", channel, lag)
+			return false, nil
+		}
+	}
+	if found < len(checked) {
+		return false, fmt.Errorf("replica doesn't have all channels of %v", w.channels)
+	}
+	return true, nil
+}
+
+// replicaStatuses returns a row of SHOW REPLICA STATUS for each channel.
+func replicaStatuses(ctx context.Context, replica *sql.DB) ([]map[string]string, error) {
+	isMariaDB, err := serverMariaDB(replica)
+	if err != nil {
+		return nil, err
+	}
+	stmts := []string{"SHOW REPLICA STATUS", "SHOW SLAVE STATUS"}
+	if isMariaDB {
+		stmts = []string{"SHOW ALL SLAVES STATUS"}
+	}
+
+	var rows *sql.Rows
+	for _, stmtSQL := range stmts {
+		log.Printf("[DEBUG] SQL: %s", stmtSQL)
+		rows, err = replica.QueryContext(ctx, stmtSQL)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var statuses []map[string]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		status := map[string]string{}
+		for i, column := range columns {
+			status[column] = values[i].String
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// backupAccountGrants are the grants the physical backup tools document for
+// their accounts. MySQL 8 takes the backup lock with LOCK INSTANCE FOR BACKUP,
+// which needs BACKUP_ADMIN; MariaDB takes it with BACKUP STAGE, which needs
+// RELOAD.
+var backupAccountGrants = map[string][]accountGrant{
+	"xtrabackup": {
+		{Privileges: []string{"RELOAD", "PROCESS", "LOCK TABLES", "REPLICATION CLIENT"}, Database: "*", Table: "*"},
+		{Privileges: []string{"BACKUP_ADMIN"}, Database: "*", Table: "*", Flavor: accountFlavorMySQL, MinVersion: "8.0.0"},
+		{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "log_status", Flavor: accountFlavorMySQL, MinVersion: "8.0.0"},
+		{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "keyring_component_status", Flavor: accountFlavorMySQL, MinVersion: "8.0.24"},
+	},
+	"meb": {
+		{Privileges: []string{"RELOAD", "PROCESS", "REPLICATION CLIENT"}, Database: "*", Table: "*"},
+		{Privileges: []string{"SUPER"}, Database: "*", Table: "*", Flavor: accountFlavorMySQL, MaxVersion: "8.0.0"},
+		{Privileges: []string{"BACKUP_ADMIN"}, Database: "*", Table: "*", Flavor: accountFlavorMySQL, MinVersion: "8.0.0"},
+		{Privileges: []string{"CREATE", "INSERT", "DROP", "UPDATE"}, Database: "mysql", Table: "backup_progress"},
+		{Privileges: []string{"CREATE", "INSERT", "SELECT", "DROP", "UPDATE", "ALTER"}, Database: "mysql", Table: "backup_history"},
+		{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "replication_group_members", Flavor: accountFlavorMySQL, MinVersion: "8.0.0"},
+		{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "log_status", Flavor: accountFlavorMySQL, MinVersion: "8.0.0"},
+	},
+	"mariabackup": {
+		{Privileges: []string{"RELOAD", "PROCESS", "LOCK TABLES", "REPLICATION CLIENT"}, Database: "*", Table: "*"},
+		// --safe-slave-backup and --slave-info need SLAVE MONITOR since 10.5.9.
+		{Privileges: []string{"SLAVE MONITOR"}, Database: "*", Table: "*", Flavor: accountFlavorMariaDB, MinVersion: "10.5.9"},
+	},
+}
+
+func backupAccountTools() []string {
+	names := make([]string, 0, len(backupAccountGrants))
+	for name := range backupAccountGrants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func resourceBackupAccount() *schema.Resource {
+	return accountProfileResource(func(d interface{ Get(string) interface{} }) []accountGrant {
+		return backupAccountGrants[d.Get("tool").(string)]
+	}, map[string]*schema.Schema{
+		"tool": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Default:      "xtrabackup",
+			ValidateFunc: validation.StringInSlice(backupAccountTools(), false),
+			Description:  "Backup tool the account is for: mariabackup, meb (MySQL Enterprise Backup) or xtrabackup",
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccBackupAccount_xtrabackup(t *testing.T) {
+	resourceName := "mysql_backup_account.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_backup_account" "test" {
+  user               = "tf_xtrabackup"
+  host               = "localhost"
+  plaintext_password = "secret"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "grants.0", "RELOAD, PROCESS, LOCK TABLES, REPLICATION CLIENT ON *.*"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,188 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// stable non-empty ID, connection_control is configured globally
+const mysqlConnectionControlId = "connection_control"
+
+var connectionControlPlugins = []string{"CONNECTION_CONTROL", "CONNECTION_CONTROL_FAILED_LOGIN_ATTEMPTS"}
+
+// connectionControlSettings maps resource attributes to server variables.
+var connectionControlSettings = map[string]string{
+	"failed_connections_threshold": "connection_control_failed_connections_threshold",
+	"min_connection_delay":         "connection_control_min_connection_delay",
+	"max_connection_delay":         "connection_control_max_connection_delay",
+}
+
+func resourceConnectionControl() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateConnectionControl,
+		UpdateContext: UpdateConnectionControl,
+		ReadContext:   ReadConnectionControl,
+		DeleteContext: DeleteConnectionControl,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"failed_connections_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of consecutive failed connection attempts before the server adds a delay (0 disables throttling)",
+			},
+			"min_connection_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1000),
+				Description:  "Minimum delay in milliseconds for failed connection attempts above the threshold",
+			},
+			"max_connection_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1000),
+				Description:  "Maximum delay in milliseconds for failed connection attempts above the threshold",
+			},
+		},
+	}
+}
+
+func CreateConnectionControl(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, plugin := range connectionControlPlugins {
+		installed, err := pluginActive(ctx, db, plugin)
+		if err != nil {
+			return diag.Errorf("failed reading plugin %s status: %v", plugin, err)
+		}
+		if installed {
+			continue
+		}
+
+		stmtSQL := fmt.Sprintf("INSTALL PLUGIN %s SONAME 'connection_control.so'", plugin)
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed installing plugin %s: %v", plugin, err)
+		}
+	}
+
+	var attrs []string
+	rawConfig := d.GetRawConfig()
+	for attr := range connectionControlSettings {
+		if !rawConfig.IsNull() && !rawConfig.GetAttr(attr).IsNull() {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if err := setConnectionControlVariables(ctx, db, d, attrs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(mysqlConnectionControlId)
+
+	return ReadConnectionControl(ctx, d, meta)
+}
+
+func UpdateConnectionControl(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var attrs []string
+	for attr := range connectionControlSettings {
+		if d.HasChange(attr) {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if err := setConnectionControlVariables(ctx, db, d, attrs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return ReadConnectionControl(ctx, d, meta)
+}
+
+func ReadConnectionControl(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, plugin := range connectionControlPlugins {
+		active, err := pluginActive(ctx, db, plugin)
+		if err != nil {
+			return diag.Errorf("failed reading plugin %s status: %v", plugin, err)
+		}
+		if !active {
+			log.Printf("[WARN] Plugin %s is not active; removing from state", plugin)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	for attr, variable := range connectionControlSettings {
+		var name, value string
+		err := db.QueryRowContext(ctx, "SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME = ?", variable).Scan(&name, &value)
+		if err != nil {
+			return diag.Errorf("failed reading %s: %v", variable, err)
+		}
+
+		intValue, err := strconv.Atoi(value)
+		if err != nil {
+			return diag.Errorf("failed parsing %s value %q: %v", variable, value, err)
+		}
+		d.Set(attr, intValue)
+	}
+
+	return nil
+}
+
+func DeleteConnectionControl(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Uninstalling the plugins also drops their variables.
+	for i := len(connectionControlPlugins) - 1; i >= 0; i-- {
+		stmtSQL := fmt.Sprintf("UNINSTALL PLUGIN %s", connectionControlPlugins[i])
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed uninstalling plugin %s: %v", connectionControlPlugins[i], err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func setConnectionControlVariables(ctx context.Context, db *sql.DB, d *schema.ResourceData, attrs []string) error {
+	for _, attr := range attrs {
+		stmtSQL := setGlobalVariableSQL(connectionControlSettings[attr], strconv.Itoa(d.Get(attr).(int)))
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed setting %s: %v", attr, err)
+		}
+	}
+	return nil
+}
+
+func pluginActive(ctx context.Context, db *sql.DB, plugin string) (bool, error) {
+	return queryHasRows(ctx, db, "SELECT 1 FROM information_schema.PLUGINS WHERE PLUGIN_NAME = ? AND PLUGIN_STATUS = 'ACTIVE'", plugin)
+}
@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccConnectionControl_basic(t *testing.T) {
+	resourceName := "mysql_connection_control.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccConnectionControlCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConnectionControlConfig(3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "failed_connections_threshold", "3"),
+					resource.TestCheckResourceAttrSet(resourceName, "min_connection_delay"),
+				),
+			},
+			{
+				Config: testAccConnectionControlConfig(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "failed_connections_threshold", "5"),
+				),
+			},
+		},
+	})
+}
+
+func testAccConnectionControlCheckDestroy(s *terraform.State) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+
+	for _, plugin := range connectionControlPlugins {
+		active, err := pluginActive(ctx, db, plugin)
+		if err != nil {
+			return err
+		}
+		if active {
+			return fmt.Errorf("plugin %s is still active", plugin)
+		}
+	}
+	return nil
+}
+
+func testAccConnectionControlConfig(threshold int) string {
+	return fmt.Sprintf(`
+resource "mysql_connection_control" "test" {
+  failed_connections_threshold = %d
+  min_connection_delay         = 2000
+}
+`, threshold)
+}
@@ -11,11 +11,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const defaultCharacterSetKeyword = "CHARACTER SET "
 const defaultCollateKeyword = "COLLATE "
 const unknownDatabaseErrCode = 1049
+const databaseExistsErrCode = 1007
 
 func resourceDatabase() *schema.Resource {
 	return &schema.Resource{
@@ -23,6 +25,12 @@ func resourceDatabase() *schema.Resource {
 		UpdateContext: UpdateDatabase,
 		ReadContext:   ReadDatabase,
 		DeleteContext: DeleteDatabase,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := checkDatabaseCollation(ctx, d, meta); err != nil {
+				return err
+			}
+			return checkDatabaseAccess(ctx, d, meta)
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportDatabase,
 		},
@@ -44,6 +52,49 @@ func resourceDatabase() *schema.Resource {
 				Optional: true,
 				Default:  "utf8mb4_general_ci",
 			},
+
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Take over the database if it already exists instead of failing, then alter its character set and collation to the configured ones",
+			},
+
+			"owner":          databaseOwnerSchema(),
+			"initial_grants": databaseInitialGrantsSchema(),
+
+			"pre_sql":  sqlHookSchema("before"),
+			"post_sql": sqlHookSchema("after"),
+
+			"wait_for_replicas": waitForReplicasSchema(),
+
+			"backup_before_destroy": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Dump the database before it's dropped",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"destination": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path or s3:// URL the dump is written to; compressed with gzip when it ends with .gz",
+						},
+						"include_data": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"max_rows": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Rows dumped of each table with include_data, 0 for all of them",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -55,15 +106,41 @@ func CreateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	stmtSQL := databaseConfigSQL("CREATE", d)
+	defer resetSQLHookSession(db, d)
+	if err := runSQLHooks(ctx, db, d, "pre_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
+	adopted := false
+	if mysqlErrorNumber(err) == databaseExistsErrCode && d.Get("adopt_existing").(bool) {
+		log.Printf("[INFO] Adopting existing database %s", d.Get("name").(string))
+		stmtSQL = databaseConfigSQL("ALTER", d)
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		_, err = db.ExecContext(ctx, stmtSQL)
+		adopted = true
+	}
 	if err != nil {
 		return diag.Errorf("failed running SQL to create DB: %v", err)
 	}
 
+	// An adopted database is kept if its owner or grants fail.
+	if err := bootstrapDatabaseAccess(ctx, db, d, !adopted); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(d.Get("name").(string))
 
+	if err := runSQLHooks(ctx, db, d, "post_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := waitForReplicas(ctx, d, meta); err != nil {
+		return diag.Errorf("failed waiting for replicas: %v", err)
+	}
+
 	return ReadDatabase(ctx, d, meta)
 }
 
@@ -74,6 +151,11 @@ func UpdateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	stmtSQL := databaseConfigSQL("ALTER", d)
+	defer resetSQLHookSession(db, d)
+	if err := runSQLHooks(ctx, db, d, "pre_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
@@ -81,6 +163,18 @@ func UpdateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("failed updating DB: %v", err)
 	}
 
+	if err := updateDatabaseAccess(ctx, db, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := runSQLHooks(ctx, db, d, "post_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := waitForReplicas(ctx, d, meta); err != nil {
+		return diag.Errorf("failed waiting for replicas: %v", err)
+	}
+
 	return ReadDatabase(ctx, d, meta)
 }
 
@@ -156,7 +250,20 @@ func DeleteDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	name := d.Id()
+	if err := backupDatabaseBeforeDestroy(ctx, db, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
 	stmtSQL := "DROP DATABASE " + quoteIdentifier(name)
+	defer resetSQLHookSession(db, d)
+	if err := runSQLHooks(ctx, db, d, "pre_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := revokeDatabaseAccess(ctx, db, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
@@ -164,10 +271,43 @@ func DeleteDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("failed deleting DB: %v", err)
 	}
 
+	if err := dropDatabaseOwner(ctx, db, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := runSQLHooks(ctx, db, d, "post_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := waitForReplicas(ctx, d, meta); err != nil {
+		return diag.Errorf("failed waiting for replicas: %v", err)
+	}
+
 	d.SetId("")
 	return nil
 }
 
+// backupDatabaseBeforeDestroy dumps the database as configured in
+// backup_before_destroy; a failed dump keeps the database.
+func backupDatabaseBeforeDestroy(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}) error {
+	backups := d.Get("backup_before_destroy").([]interface{})
+	if len(backups) == 0 || backups[0] == nil {
+		return nil
+	}
+	backup := backups[0].(map[string]interface{})
+	destination := backup["destination"].(string)
+
+	result, err := writeDumpFile(ctx, db, meta, destination, []string{d.Id()}, dumpOptions{
+		NoData:  !backup["include_data"].(bool),
+		MaxRows: backup["max_rows"].(int),
+	})
+	if err != nil {
+		return fmt.Errorf("failed backing up database %s, not dropping it: %v", d.Id(), err)
+	}
+	log.Printf("[INFO] Backed up database %s to %s (%d bytes, SHA-256 %s)", d.Id(), destination, result.Size, result.Checksum)
+	return nil
+}
+
 func databaseConfigSQL(verb string, d *schema.ResourceData) string {
 	name := d.Get("name").(string)
 	defaultCharset := d.Get("default_character_set").(string)
@@ -205,6 +345,7 @@ func extractIdentAfter(sql string, keyword string) string {
 }
 
 func ImportDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("adopt_existing", false)
 	err := ReadDatabase(ctx, d, meta)
 	if err != nil {
 		return nil, fmt.Errorf("error while importing: %v", err)
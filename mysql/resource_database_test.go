@@ -3,6 +3,8 @@ package mysql
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -79,6 +81,106 @@ func TestAccDatabase_collationChange(t *testing.T) {
 	})
 }
 
+func TestAccDatabase_adoptExisting(t *testing.T) {
+	dbName := "terraform_acceptance_test_adopt"
+	ctx := context.Background()
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatalf("Could not connect to MySQL instance: %v", err)
+					}
+					if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %s CHARACTER SET latin1 COLLATE latin1_bin", dbName)); err != nil {
+						t.Fatalf("Failed to create database: %v", err)
+					}
+				},
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name                  = "%s"
+  default_character_set = "utf8mb4"
+  default_collation     = "utf8mb4_bin"
+  adopt_existing        = true
+}`, dbName),
+				Check: testAccDatabaseCheckFull("mysql_database.test", dbName, "utf8mb4", "utf8mb4_bin"),
+			},
+		},
+	})
+}
+
+func TestAccDatabase_sqlHooks(t *testing.T) {
+	dbName := "terraform_acceptance_test_hooks"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfigSQLHooks(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckBasic("mysql_database.test", dbName),
+					func(s *terraform.State) error {
+						db, err := connectToMySQL(context.Background(), testAccProvider.Meta().(*MySQLConfiguration))
+						if err != nil {
+							return err
+						}
+						var value string
+						if err := db.QueryRow(fmt.Sprintf("SELECT v FROM %s.hook", dbName)).Scan(&value); err != nil {
+							return fmt.Errorf("post_sql didn't run: %v", err)
+						}
+						if value != "pre" {
+							return fmt.Errorf("post_sql saw %q instead of the session variable set by pre_sql", value)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatabase_backupBeforeDestroy(t *testing.T) {
+	dbName := "terraform_acceptance_test_backup"
+	destination := filepath.Join(t.TempDir(), "backup.sql")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if err := testAccDatabaseCheckDestroy(dbName)(s); err != nil {
+				return err
+			}
+			content, err := os.ReadFile(destination)
+			if err != nil {
+				return fmt.Errorf("database wasn't backed up: %v", err)
+			}
+			if !strings.Contains(string(content), "CREATE DATABASE IF NOT EXISTS `"+dbName+"`") {
+				return fmt.Errorf("backup doesn't create the database:\n%s", content)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+
+  backup_before_destroy {
+    destination  = "%s"
+    include_data = true
+    max_rows     = 100
+  }
+}
+`, dbName, destination),
+				Check: testAccDatabaseCheckFull("mysql_database.test", dbName, "utf8mb4", "utf8mb4_general_ci"),
+			},
+		},
+	})
+}
+
 func testAccDatabaseCheckBasic(rn string, name string) resource.TestCheckFunc {
 	return testAccDatabaseCheckFull(rn, name, "utf8mb4", "utf8mb4_bin")
 }
@@ -161,3 +263,12 @@ resource "mysql_database" "test" {
     default_collation = "%s"
 }`, name, charset, collation)
 }
+
+func testAccDatabaseConfigSQLHooks(name string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name     = "%[1]s"
+  pre_sql  = ["SET @tf_hook = 'pre'"]
+  post_sql = ["CREATE TABLE %[1]s.hook (v VARCHAR(10))", "INSERT INTO %[1]s.hook VALUES (@tf_hook)"]
+}`, name)
+}
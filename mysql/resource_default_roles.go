@@ -22,12 +22,37 @@ func resourceDefaultRoles() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportDefaultRoles,
 		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := checkTiDBRoleSupport(ctx, meta, false); err != nil {
+				return err
+			}
+			if d.Get("roles").(*schema.Set).Len() <= 1 {
+				return nil
+			}
+			// Like the TiDB check, an unreachable server only skips the
+			// check, it's repeated when applying.
+			db, err := getDatabaseFromMeta(ctx, meta)
+			if err != nil {
+				log.Printf("[WARN] Could not check default role support: %v", err)
+				return nil
+			}
+			isMariaDB, err := serverMariaDB(db)
+			if err != nil {
+				log.Printf("[WARN] Could not check default role support: %v", err)
+				return nil
+			}
+			if isMariaDB {
+				return errors.New("MariaDB supports only one default role per user")
+			}
+			return nil
+		},
 
 		Schema: map[string]*schema.Schema{
 			"user": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNotReservedAccount,
 			},
 
 			"host": {
@@ -58,6 +83,21 @@ func checkDefaultRolesSupport(ctx context.Context, meta interface{}) error {
 }
 
 func alterUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string, roles []string) error {
+	isMariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return err
+	}
+	if isMariaDB {
+		return setMariaDBDefaultRole(ctx, db, user, host, roles)
+	}
+	isTiDB, _, _, err := serverTiDB(db)
+	if err != nil {
+		return err
+	}
+	if isTiDB {
+		return setTiDBDefaultRoles(ctx, db, user, host, roles)
+	}
+
 	var stmtSQL string
 
 	stmtSQL = fmt.Sprintf("ALTER USER '%s'@'%s' DEFAULT ROLE ", user, host)
@@ -69,7 +109,7 @@ func alterUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string, r
 	}
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
-	_, err := db.ExecContext(ctx, stmtSQL)
+	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
 		return fmt.Errorf("failed executing SQL: %w", err)
 	}
@@ -77,6 +117,68 @@ func alterUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string, r
 	return nil
 }
 
+// setMariaDBDefaultRole sets the default role on MariaDB, which has a single
+// default role per user and its own syntax.
+func setMariaDBDefaultRole(ctx context.Context, db *sql.DB, user, host string, roles []string) error {
+	if len(roles) > 1 {
+		return errors.New("MariaDB supports only one default role per user")
+	}
+
+	role := "NONE"
+	if len(roles) == 1 {
+		role = quoteIdentifier(roles[0])
+	}
+	stmtSQL := fmt.Sprintf("SET DEFAULT ROLE %s FOR %s", role, formatUserIdentifier(user, host))
+
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed executing SQL: %w", err)
+	}
+
+	return nil
+}
+
+// setTiDBDefaultRoles sets default roles on TiDB, which doesn't support
+// ALTER USER ... DEFAULT ROLE. Some TiDB versions accept SET DEFAULT ROLE
+// without persisting it, so the result is checked.
+func setTiDBDefaultRoles(ctx context.Context, db *sql.DB, user, host string, roles []string) error {
+	stmtSQL := "SET DEFAULT ROLE NONE TO " + formatUserIdentifier(user, host)
+	if len(roles) > 0 {
+		stmtSQL = fmt.Sprintf("SET DEFAULT ROLE '%s' TO %s", strings.Join(roles, "', '"), formatUserIdentifier(user, host))
+	}
+
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed executing SQL: %w", err)
+	}
+
+	persisted, err := readDefaultRoles(ctx, db, user, host)
+	if err != nil {
+		return err
+	}
+	if len(persisted) != len(roles) {
+		return fmt.Errorf("TiDB didn't persist the default roles of %s, this TiDB version doesn't support default roles", formatUserIdentifier(user, host))
+	}
+	return nil
+}
+
+// readMariaDBDefaultRole reads the default role of the account on MariaDB. It
+// returns sql.ErrNoRows when the account doesn't exist.
+func readMariaDBDefaultRole(ctx context.Context, db *sql.DB, user, host string) ([]string, error) {
+	stmtSQL := "SELECT default_role FROM mysql.user WHERE user = ? AND host = ?"
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	var role string
+	if err := db.QueryRowContext(ctx, stmtSQL, user, host).Scan(&role); err != nil {
+		return nil, err
+	}
+
+	if role == "" {
+		return []string{}, nil
+	}
+	return []string{role}, nil
+}
+
 func getRolesFromData(d *schema.ResourceData) []string {
 	defaultRoles := d.Get("roles").(*schema.Set).List()
 	roles := make([]string, len(defaultRoles))
@@ -140,33 +242,58 @@ func ReadDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interfac
 		return diag.Errorf("cannot use default roles: %v", err)
 	}
 
+	defaultRoles, err := readUserDefaultRoles(ctx, db, meta, d.Get("user").(string), d.Get("host").(string))
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Printf("[WARN] User %s not found - removing default roles from state", formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("failed to read user default roles: %v", err)
+	}
+
+	d.Set("roles", defaultRoles)
+
+	return nil
+}
+
+// readUserDefaultRoles reads the default roles of the account on any flavor,
+// from SHOW GRANTS when the system tables aren't readable.
+func readUserDefaultRoles(ctx context.Context, db *sql.DB, meta interface{}, user, host string) ([]string, error) {
+	isMariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect MariaDB: %v", err)
+	}
+	if !systemTablesReadable(ctx, meta) {
+		return showDefaultRoles(ctx, db, user, host, isMariaDB)
+	}
+	if isMariaDB {
+		return readMariaDBDefaultRole(ctx, db, user, host)
+	}
+	return readDefaultRoles(ctx, db, user, host)
+}
+
+func readDefaultRoles(ctx context.Context, db *sql.DB, user, host string) ([]string, error) {
 	stmtSQL := "SELECT default_role_user FROM mysql.default_roles WHERE user = ? AND host = ?"
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
-	rows, err := db.QueryContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string))
+	rows, err := db.QueryContext(ctx, stmtSQL, user, host)
 	if err != nil {
-		return diag.Errorf("failed to read user default roles from DB: %v", err)
+		return nil, err
 	}
 	defer rows.Close()
 
 	var defaultRoles = make([]string, 0)
 	for rows.Next() {
 		var role string
-		err := rows.Scan(&role)
-		if err != nil {
-			return diag.Errorf("failed scanning default roles: %v", err)
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed scanning default roles: %w", err)
 		}
 		defaultRoles = append(defaultRoles, role)
 	}
 
-	if rows.Err() != nil {
-		return diag.Errorf("failed getting rows: %v", rows.Err())
-	}
-
-	d.Set("roles", defaultRoles)
-
-	return nil
+	return defaultRoles, rows.Err()
 }
 
 func DeleteDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
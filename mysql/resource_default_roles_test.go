@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -63,6 +64,36 @@ func TestAccDefaultRoles_basic(t *testing.T) {
 	})
 }
 
+func TestAccDefaultRoles_mariaDB(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckRequireMariaDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDefaultRolesBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "roles.#", "1"),
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "roles.0", "role1"),
+				),
+			},
+			{
+				Config:      testAccDefaultRolesMultiple,
+				ExpectError: regexp.MustCompile("MariaDB supports only one default role"),
+			},
+			{
+				Config: testAccDefaultRolesNone,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "roles.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func testAccDefaultRoles(rn string, roles ...string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
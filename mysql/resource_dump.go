@@ -0,0 +1,475 @@
+package mysql
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dumpInsertBytes is the size at which an INSERT of a dump is split, like
+// net_buffer_length of mysqldump.
+const dumpInsertBytes = 1024 * 1024
+
+func resourceDump() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateDump,
+		ReadContext:   ReadDump,
+		DeleteContext: DeleteDump,
+
+		Schema: map[string]*schema.Schema{
+			"databases": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"destination": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Path or s3:// URL the dump is written to; compressed with gzip when it ends with .gz",
+			},
+			"no_data": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Dump only the schema, without rows",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that take the dump again when changed",
+			},
+			"checksum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 of the dump file",
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"tables": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of tables and views dumped",
+			},
+		},
+	}
+}
+
+// CreateDump writes a dump of the databases that the mysql client can
+// restore, from a consistent snapshot like mysqldump --single-transaction.
+func CreateDump(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result, err := writeDumpFile(ctx, db, meta, d.Get("destination").(string), toStringList(d.Get("databases")), dumpOptions{NoData: d.Get("no_data").(bool)})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id.UniqueId())
+	d.Set("checksum", result.Checksum)
+	d.Set("size", int(result.Size))
+	d.Set("tables", result.Tables)
+	return nil
+}
+
+// ReadDump does nothing, the dump is a one-off action repeated only when
+// its arguments change.
+func ReadDump(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// DeleteDump keeps the dump file, so it outlives the resources it backs up.
+func DeleteDump(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// dumpOptions limit what a dump contains. MaxRows limits the rows of each
+// table, 0 dumps all of them.
+type dumpOptions struct {
+	NoData  bool
+	MaxRows int
+}
+
+type dumpResult struct {
+	Checksum string
+	Size     int64
+	Tables   int
+}
+
+// writeDumpFile writes a dump of the databases to a path or an s3:// URL,
+// compressed when the destination ends with .gz. S3 is written with the
+// credentials of aws_config.
+func writeDumpFile(ctx context.Context, db *sql.DB, meta interface{}, destination string, databases []string, opts dumpOptions) (*dumpResult, error) {
+	u, err := url.Parse(destination)
+	isS3 := err == nil && u.Scheme == "s3"
+
+	dir := ""
+	if !isS3 {
+		dir = filepath.Dir(destination)
+	}
+	file, err := os.CreateTemp(dir, ".mysql-dump-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed creating dump file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	checksum := sha256.New()
+	tables, err := writeDump(ctx, db, io.MultiWriter(file, checksum), databases, opts, strings.HasSuffix(destination, ".gz"))
+	if err != nil {
+		return nil, fmt.Errorf("failed dumping databases: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed writing dump file: %v", err)
+	}
+
+	if isS3 {
+		conf, ok := meta.(*MySQLConfiguration)
+		if !ok {
+			return nil, fmt.Errorf("uploading dumps to S3 requires a MySQL connection")
+		}
+		cfg, err := buildAwsConfig(ctx, conf.AWSConfigBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build AWS config: %v", err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed reading dump file: %v", err)
+		}
+		if err := putS3Object(ctx, cfg, u.Host, strings.TrimPrefix(u.Path, "/"), file, info.Size()); err != nil {
+			return nil, fmt.Errorf("failed uploading dump: %v", err)
+		}
+	} else {
+		if err := file.Close(); err != nil {
+			return nil, fmt.Errorf("failed writing dump file: %v", err)
+		}
+		if err := os.Rename(file.Name(), destination); err != nil {
+			return nil, fmt.Errorf("failed writing %s: %v", destination, err)
+		}
+	}
+
+	return &dumpResult{
+		Checksum: hex.EncodeToString(checksum.Sum(nil)),
+		Size:     info.Size(),
+		Tables:   tables,
+	}, nil
+}
+
+// writeDump writes the dump to w and returns the number of tables and views.
+func writeDump(ctx context.Context, db *sql.DB, w io.Writer, databases []string, opts dumpOptions, compress bool) (int, error) {
+	// The snapshot belongs to a session, so the dump needs a single
+	// connection. Its session changes aren't restored after failovers, and
+	// the connection is discarded afterwards, so they don't outlive the dump.
+	ctx = withoutSettingReplay(ctx)
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer discardConn(conn)
+
+	for _, stmtSQL := range []string{
+		"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ",
+		"START TRANSACTION WITH CONSISTENT SNAPSHOT",
+		"SET SESSION time_zone = '+00:00'",
+	} {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := conn.ExecContext(ctx, stmtSQL); err != nil {
+			return 0, err
+		}
+	}
+	defer conn.ExecContext(ctx, "ROLLBACK")
+
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	dw := &dumpWriter{conn: conn, w: bufio.NewWriter(w)}
+
+	dw.printf("-- Dump of %s by terraform-provider-mysql\n\n", strings.Join(databases, ", "))
+	dw.printf("/*!40101 SET NAMES utf8mb4 */;\n")
+	dw.printf("/*!40103 SET TIME_ZONE='+00:00' */;\n")
+	dw.printf("/*!40014 SET UNIQUE_CHECKS=0 */;\n")
+	dw.printf("/*!40014 SET FOREIGN_KEY_CHECKS=0 */;\n")
+	dw.printf("/*!40101 SET SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;\n")
+	for _, database := range databases {
+		if err := dw.database(ctx, database, opts); err != nil {
+			return dw.tables, fmt.Errorf("database %s: %v", database, err)
+		}
+	}
+
+	if err := dw.w.Flush(); err != nil {
+		return dw.tables, err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return dw.tables, err
+		}
+	}
+	return dw.tables, dw.err
+}
+
+type dumpWriter struct {
+	conn   *sql.Conn
+	w      *bufio.Writer
+	tables int
+	err    error
+}
+
+// printf writes to the dump, keeping the first error for the end.
+func (dw *dumpWriter) printf(format string, args ...interface{}) {
+	if dw.err == nil {
+		_, dw.err = fmt.Fprintf(dw.w, format, args...)
+	}
+}
+
+// showCreate returns the statement in column col of a SHOW CREATE statement.
+func (dw *dumpWriter) showCreate(ctx context.Context, stmtSQL string, col int) (string, error) {
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := dw.conn.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%s returned no rows", stmtSQL)
+	}
+	values := make([]sql.NullString, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", err
+	}
+	return values[col].String, nil
+}
+
+func (dw *dumpWriter) database(ctx context.Context, database string, opts dumpOptions) error {
+	createSQL, err := dw.showCreate(ctx, "SHOW CREATE DATABASE "+quoteIdentifier(database), 1)
+	if err != nil {
+		return err
+	}
+	dw.printf("\n--\n-- Database %s\n--\n\n", quoteIdentifier(database))
+	dw.printf("%s;\n\nUSE %s;\n", strings.Replace(createSQL, "CREATE DATABASE ", "CREATE DATABASE IF NOT EXISTS ", 1), quoteIdentifier(database))
+
+	stmtSQL := "SELECT TABLE_NAME, TABLE_TYPE FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := dw.conn.QueryContext(ctx, stmtSQL, database)
+	if err != nil {
+		return err
+	}
+	var tables, views []string
+	for rows.Next() {
+		var name, tableType string
+		if err := rows.Scan(&name, &tableType); err != nil {
+			rows.Close()
+			return err
+		}
+		if tableType == "VIEW" {
+			views = append(views, name)
+		} else {
+			tables = append(tables, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := dw.table(ctx, database, table, opts); err != nil {
+			return fmt.Errorf("table %s: %v", table, err)
+		}
+	}
+	// Views go last, as they may select from any of the tables.
+	for _, view := range views {
+		createSQL, err := dw.showCreate(ctx, "SHOW CREATE VIEW "+quoteIdentifier(database)+"."+quoteIdentifier(view), 1)
+		if err != nil {
+			return fmt.Errorf("view %s: %v", view, err)
+		}
+		dw.printf("\nDROP VIEW IF EXISTS %s;\n%s;\n", quoteIdentifier(view), createSQL)
+		dw.tables++
+	}
+	return dw.err
+}
+
+func (dw *dumpWriter) table(ctx context.Context, database, table string, opts dumpOptions) error {
+	name := quoteIdentifier(database) + "." + quoteIdentifier(table)
+	createSQL, err := dw.showCreate(ctx, "SHOW CREATE TABLE "+name, 1)
+	if err != nil {
+		return err
+	}
+	dw.printf("\n--\n-- Table %s\n--\n\nDROP TABLE IF EXISTS %s;\n%s;\n", quoteIdentifier(table), quoteIdentifier(table), createSQL)
+	dw.tables++
+
+	if !opts.NoData {
+		if err := dw.rows(ctx, database, table, opts.MaxRows); err != nil {
+			return err
+		}
+	}
+
+	stmtSQL := "SELECT TRIGGER_NAME FROM information_schema.TRIGGERS WHERE EVENT_OBJECT_SCHEMA = ? AND EVENT_OBJECT_TABLE = ? ORDER BY TRIGGER_NAME"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := dw.conn.QueryContext(ctx, stmtSQL, database, table)
+	if err != nil {
+		return err
+	}
+	var triggers []string
+	for rows.Next() {
+		var trigger string
+		if err := rows.Scan(&trigger); err != nil {
+			rows.Close()
+			return err
+		}
+		triggers = append(triggers, trigger)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, trigger := range triggers {
+		createSQL, err := dw.showCreate(ctx, "SHOW CREATE TRIGGER "+quoteIdentifier(database)+"."+quoteIdentifier(trigger), 2)
+		if err != nil {
+			return fmt.Errorf("trigger %s: %v", trigger, err)
+		}
+		dw.printf("\nDELIMITER ;;\n%s;;\nDELIMITER ;\n", createSQL)
+	}
+	return dw.err
+}
+
+// columns returns the quoted columns of the table whose values can be
+// inserted, in order. Generated columns can't, so they're left out.
+func (dw *dumpWriter) columns(ctx context.Context, database, table string) ([]string, error) {
+	stmtSQL := "SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND EXTRA NOT LIKE '%GENERATED%' ORDER BY ORDINAL_POSITION"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := dw.conn.QueryContext(ctx, stmtSQL, database, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, quoteIdentifier(column))
+	}
+	return columns, rows.Err()
+}
+
+// rows writes up to maxRows rows of the table as INSERT statements of up to
+// dumpInsertBytes, naming the columns, so generated ones are computed again.
+func (dw *dumpWriter) rows(ctx context.Context, database, table string, maxRows int) error {
+	columns, err := dw.columns(ctx, database, table)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	columnList := strings.Join(columns, ",")
+
+	stmtSQL := fmt.Sprintf("SELECT %s FROM %s.%s", columnList, quoteIdentifier(database), quoteIdentifier(table))
+	if maxRows > 0 {
+		stmtSQL += fmt.Sprintf(" LIMIT %d", maxRows)
+	}
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := dw.conn.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	values := make([]sql.RawBytes, len(types))
+	dest := make([]interface{}, len(types))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var stmt strings.Builder
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		if stmt.Len() == 0 {
+			stmt.WriteString("INSERT INTO " + quoteIdentifier(table) + " (" + columnList + ") VALUES ")
+		} else {
+			stmt.WriteString(",")
+		}
+		stmt.WriteString("(")
+		for i, v := range values {
+			if i > 0 {
+				stmt.WriteString(",")
+			}
+			stmt.WriteString(dumpValue(v, types[i].DatabaseTypeName()))
+		}
+		stmt.WriteString(")")
+		if stmt.Len() >= dumpInsertBytes {
+			dw.printf("%s;\n", stmt.String())
+			stmt.Reset()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if stmt.Len() > 0 {
+		dw.printf("%s;\n", stmt.String())
+	}
+	return dw.err
+}
+
+// dumpValue returns the SQL literal of a value of a column of the type.
+// Numbers are written as they are, binary values in hex.
+func dumpValue(v sql.RawBytes, typeName string) string {
+	if v == nil {
+		return "NULL"
+	}
+	switch strings.TrimPrefix(typeName, "UNSIGNED ") {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "DECIMAL", "FLOAT", "DOUBLE", "YEAR":
+		return string(v)
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY":
+		if len(v) == 0 {
+			return "''"
+		}
+		return "0x" + hex.EncodeToString(v)
+	}
+	return quoteString(string(v))
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDumpValue(t *testing.T) {
+	tests := []struct {
+		value    sql.RawBytes
+		typeName string
+		want     string
+	}{
+		{nil, "VARCHAR", "NULL"},
+		{sql.RawBytes("42"), "UNSIGNED BIGINT", "42"},
+		{sql.RawBytes("1.50"), "DECIMAL", "1.50"},
+		{sql.RawBytes("it's"), "VARCHAR", `'it\'s'`},
+		{sql.RawBytes("a\nb"), "TEXT", `'a\nb'`},
+		{sql.RawBytes{0x00, 0xff}, "VARBINARY", "0x00ff"},
+		{sql.RawBytes{}, "BLOB", "''"},
+		{sql.RawBytes("2024-01-02 03:04:05"), "DATETIME", "'2024-01-02 03:04:05'"},
+	}
+	for _, tt := range tests {
+		if got := dumpValue(tt.value, tt.typeName); got != tt.want {
+			t.Errorf("dumpValue(%q, %s) = %s, want %s", tt.value, tt.typeName, got, tt.want)
+		}
+	}
+}
+
+func TestAccDump(t *testing.T) {
+	dbName := "tf_dump_test"
+	destination := filepath.Join(t.TempDir(), "dump.sql")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_sql" "table" {
+  name             = "dump_table"
+  multi_statements = true
+  create_sql       = "CREATE TABLE ${mysql_database.test.name}.t (id INT PRIMARY KEY, name VARCHAR(20), upper_name VARCHAR(20) AS (UPPER(name))); INSERT INTO ${mysql_database.test.name}.t (id, name) VALUES (1, 'one')"
+  delete_sql       = "DROP TABLE ${mysql_database.test.name}.t"
+}
+
+resource "mysql_dump" "test" {
+  databases   = [mysql_database.test.name]
+  destination = "%s"
+
+  depends_on = [mysql_sql.table]
+}
+`, dbName, destination),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_dump.test", "tables", "1"),
+					resource.TestCheckResourceAttrSet("mysql_dump.test", "checksum"),
+					func(s *terraform.State) error {
+						content, err := os.ReadFile(destination)
+						if err != nil {
+							return err
+						}
+						if !strings.Contains(string(content), "INSERT INTO `t` (`id`,`name`) VALUES (1,'one');") {
+							return fmt.Errorf("dump doesn't contain the row of t:\n%s", content)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
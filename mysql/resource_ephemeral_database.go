@@ -0,0 +1,189 @@
+package mysql
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// ephemeralMarkerTable holds the expiry of an ephemeral database in its
+	// comment. MySQL has no comments on databases.
+	ephemeralMarkerTable  = "_terraform_ephemeral"
+	ephemeralCommentLabel = "terraform-ephemeral expires_at="
+)
+
+func resourceEphemeralDatabase() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateEphemeralDatabase,
+		ReadContext:   ReadEphemeralDatabase,
+		DeleteContext: DeleteEphemeralDatabase,
+		CustomizeDiff: checkDatabaseCollation,
+
+		Schema: map[string]*schema.Schema{
+			"prefix": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z0-9_]{1,48}$`), "must be at most 48 letters, digits or underscores"),
+				Description:  "Prefix of the database name, followed by an underscore and a random suffix",
+			},
+			"ttl": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTemporaryGrantDuration,
+				Description:  "How long the database is kept before it may be reaped, e.g. 2h",
+			},
+			"default_character_set": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "utf8mb4",
+			},
+			"default_collation": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "utf8mb4_general_ci",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the database may be reaped, in RFC 3339",
+			},
+		},
+	}
+}
+
+func CreateEphemeralDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if conf, ok := meta.(*MySQLConfiguration); ok && conf.ReapEphemeralDatabases {
+		if _, err := reapEphemeralDatabases(ctx, db, time.Now()); err != nil {
+			return diag.Errorf("failed reaping expired ephemeral databases: %v", err)
+		}
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Get("prefix").(string) + "_" + hex.EncodeToString(suffix)
+	ttl, _ := time.ParseDuration(d.Get("ttl").(string))
+	expiresAt := time.Now().Add(ttl).UTC().Format(time.RFC3339)
+
+	stmtSQL := fmt.Sprintf("CREATE DATABASE %s %s%s %s%s",
+		quoteIdentifier(name),
+		defaultCharacterSetKeyword, quoteIdentifier(d.Get("default_character_set").(string)),
+		defaultCollateKeyword, quoteIdentifier(d.Get("default_collation").(string)))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed creating database %s: %v", name, err)
+	}
+	d.SetId(name)
+
+	stmtSQL = fmt.Sprintf("CREATE TABLE %s.%s (id INT PRIMARY KEY) COMMENT %s",
+		quoteIdentifier(name), quoteIdentifier(ephemeralMarkerTable), quoteString(ephemeralCommentLabel+expiresAt))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed marking database %s as ephemeral: %v", name, err)
+	}
+
+	d.Set("name", name)
+	d.Set("expires_at", expiresAt)
+	return ReadEphemeralDatabase(ctx, d, meta)
+}
+
+func ReadEphemeralDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	exists, err := queryHasRows(ctx, db, stmtSQL, d.Id())
+	if err != nil {
+		return diag.Errorf("failed reading database %s: %v", d.Id(), err)
+	}
+	if !exists {
+		log.Printf("[WARN] Ephemeral database %s was dropped, maybe reaped, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	d.Set("name", d.Id())
+	return nil
+}
+
+func DeleteEphemeralDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "DROP DATABASE IF EXISTS " + quoteIdentifier(d.Id())
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed dropping database %s: %v", d.Id(), err)
+	}
+	return nil
+}
+
+// reapEphemeralDatabases drops the ephemeral databases whose expiry passed,
+// e.g. ones left behind by cancelled CI runs, and returns their names.
+func reapEphemeralDatabases(ctx context.Context, db *sql.DB, now time.Time) ([]string, error) {
+	stmtSQL := "SELECT TABLE_SCHEMA, TABLE_COMMENT FROM information_schema.TABLES WHERE TABLE_NAME = ? AND TABLE_COMMENT LIKE ?"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, ephemeralMarkerTable, ephemeralCommentLabel+"%")
+	if err != nil {
+		return nil, err
+	}
+	var expired []string
+	for rows.Next() {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		isExpired, err := userExpired(strings.TrimPrefix(comment, ephemeralCommentLabel), now)
+		if err != nil {
+			log.Printf("[WARN] Not reaping %s: %v", name, err)
+			continue
+		}
+		if isExpired {
+			expired = append(expired, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range expired {
+		stmtSQL := "DROP DATABASE IF EXISTS " + quoteIdentifier(name)
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return nil, fmt.Errorf("failed dropping %s: %v", name, err)
+		}
+		log.Printf("[INFO] Reaped expired ephemeral database %s", name)
+	}
+	return expired, nil
+}
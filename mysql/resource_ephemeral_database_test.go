@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccEphemeralDatabase_reap(t *testing.T) {
+	resourceName := "mysql_ephemeral_database.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_ephemeral_database" "test" {
+  prefix = "tf_ci"
+  ttl    = "1h"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resourceName, "name", regexp.MustCompile(`^tf_ci_[0-9a-f]{8}$`)),
+					resource.TestCheckResourceAttrSet(resourceName, "expires_at"),
+					testAccEphemeralDatabaseReaped(resourceName),
+				),
+				// The check reaps the database, so the next plan creates it again.
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccEphemeralDatabaseReaped checks that the database isn't reaped
+// before its expiry, but is after.
+func testAccEphemeralDatabaseReaped(rn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		reaped, err := reapEphemeralDatabases(ctx, db, time.Now())
+		if err != nil {
+			return err
+		}
+		if slices.Contains(reaped, rs.Primary.ID) {
+			return fmt.Errorf("database %s reaped before it expired", rs.Primary.ID)
+		}
+
+		reaped, err = reapEphemeralDatabases(ctx, db, time.Now().Add(2*time.Hour))
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(reaped, rs.Primary.ID) {
+			return fmt.Errorf("database %s not reaped after it expired, reaped %v", rs.Primary.ID, reaped)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,204 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// foreignServerOptions maps the attributes of mysql_foreign_server to the
+// options of CREATE SERVER.
+var foreignServerOptions = []struct {
+	attribute string
+	option    string
+}{
+	{"host", "HOST"},
+	{"database", "DATABASE"},
+	{"user", "USER"},
+	{"password", "PASSWORD"},
+	{"socket", "SOCKET"},
+	{"owner", "OWNER"},
+	{"port", "PORT"},
+}
+
+func resourceForeignServer() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateForeignServer,
+		UpdateContext: UpdateForeignServer,
+		ReadContext:   ReadForeignServer,
+		DeleteContext: DeleteForeignServer,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 64),
+			},
+			"wrapper": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "mysql",
+				Description: "Foreign data wrapper: mysql for FEDERATED and FederatedX, or the wrapper of a CONNECT table type",
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumberOrZero,
+			},
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"user": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"socket": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"owner": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// foreignServerOptionsSQL returns the OPTIONS clause of the given attributes.
+func foreignServerOptionsSQL(d *schema.ResourceData, attributes map[string]bool) string {
+	var options []string
+	for _, o := range foreignServerOptions {
+		if attributes != nil && !attributes[o.attribute] {
+			continue
+		}
+		if o.attribute == "port" {
+			options = append(options, fmt.Sprintf("PORT %d", d.Get("port").(int)))
+			continue
+		}
+		options = append(options, fmt.Sprintf("%s %s", o.option, quoteString(d.Get(o.attribute).(string))))
+	}
+	return "OPTIONS (" + strings.Join(options, ", ") + ")"
+}
+
+func CreateForeignServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	name := d.Get("name").(string)
+	stmtSQL := fmt.Sprintf("CREATE SERVER %s FOREIGN DATA WRAPPER %s %s",
+		quoteIdentifier(name),
+		quoteIdentifier(d.Get("wrapper").(string)),
+		foreignServerOptionsSQL(d, nil))
+	log.Println("[DEBUG] Executing statement:", strings.Replace(stmtSQL, quoteString(d.Get("password").(string)), "'<sensitive>'", 1))
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed creating server %s: %v", name, err)
+	}
+
+	d.SetId(name)
+
+	return ReadForeignServer(ctx, d, meta)
+}
+
+func UpdateForeignServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	changed := map[string]bool{}
+	for _, o := range foreignServerOptions {
+		if d.HasChange(o.attribute) {
+			changed[o.attribute] = true
+		}
+	}
+	if len(changed) == 0 {
+		return ReadForeignServer(ctx, d, meta)
+	}
+
+	stmtSQL := fmt.Sprintf("ALTER SERVER %s %s", quoteIdentifier(d.Id()), foreignServerOptionsSQL(d, changed))
+	if changed["password"] {
+		log.Println("[DEBUG] Executing statement:", strings.Replace(stmtSQL, quoteString(d.Get("password").(string)), "'<sensitive>'", 1))
+	} else {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+	}
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed altering server %s: %v", d.Id(), err)
+	}
+
+	return ReadForeignServer(ctx, d, meta)
+}
+
+func ReadForeignServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "SELECT Server_name, Wrapper, Host, Port, Db, Username, Password, Socket, Owner FROM mysql.servers WHERE Server_name = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var name, wrapper, host, database, user, password, socket, owner string
+	var port int
+	err = db.QueryRowContext(ctx, stmtSQL, d.Id()).Scan(&name, &wrapper, &host, &port, &database, &user, &password, &socket, &owner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] Server (%s) not found; removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading server: %v", err)
+	}
+
+	d.Set("name", name)
+	d.Set("wrapper", wrapper)
+	d.Set("host", host)
+	d.Set("port", port)
+	d.Set("database", database)
+	d.Set("user", user)
+	d.Set("password", password)
+	d.Set("socket", socket)
+	d.Set("owner", owner)
+
+	return nil
+}
+
+func DeleteForeignServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "DROP SERVER IF EXISTS " + quoteIdentifier(d.Id())
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed dropping server %s: %v", d.Id(), err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccForeignServer(t *testing.T) {
+	resourceName := "mysql_foreign_server.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccForeignServerCheckDestroy("tf_test_server"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccForeignServerConfig("10.0.0.1", "secret"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "host", "10.0.0.1"),
+					resource.TestCheckResourceAttr(resourceName, "port", "3306"),
+					resource.TestCheckResourceAttr(resourceName, "wrapper", "mysql"),
+				),
+			},
+			{
+				Config: testAccForeignServerConfig("10.0.0.2", "rotated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "host", "10.0.0.2"),
+					resource.TestCheckResourceAttr(resourceName, "password", "rotated"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccForeignServerCheckDestroy(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		found, err := queryHasRows(ctx, db, "SELECT 1 FROM mysql.servers WHERE Server_name = ?", name)
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("server %s still exists", name)
+		}
+		return nil
+	}
+}
+
+func testAccForeignServerConfig(host, password string) string {
+	return fmt.Sprintf(`
+resource "mysql_foreign_server" "test" {
+  name     = "tf_test_server"
+  host     = "%s"
+  port     = 3306
+  database = "remote_db"
+  user     = "federated"
+  password = "%s"
+}
+`, host, password)
+}
@@ -5,14 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
 	"regexp"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// readOnlyVariableErrCode is returned by SET GLOBAL for variables that can
+// only be set at startup.
+const readOnlyVariableErrCode = 1238
+
 func resourceGlobalVariable() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateOrUpdateGlobalVariable,
@@ -22,6 +26,7 @@ func resourceGlobalVariable() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: checkGlobalVariableValue,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -39,14 +44,74 @@ func resourceGlobalVariable() *schema.Resource {
 					}
 					return
 				},
+				// The server reports ON for 1, bytes for 64M and so on.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return equivalentVariableValues(old, new)
+				},
+			},
+			"persist_read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Persist values of read-only variables with SET PERSIST_ONLY, to take effect at the next restart",
+			},
+			"rds_reboot": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Reboot the RDS instance through the RDS API to apply read-only variables",
+			},
+			"rds_instance_identifier": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Identifier of the RDS instance, when the endpoint isn't its RDS endpoint",
+			},
+			"restart_required": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the persisted value only takes effect after the server restarts",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the variable (int, float, bool, enum or string), empty when the server doesn't expose it",
 			},
 		},
 	}
 }
 
-func CreateOrUpdateGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	var sqlCommand string
+// checkGlobalVariableValue validates the value against the type and range of
+// the variable at plan time. The check is skipped when the server can't be
+// reached yet.
+func checkGlobalVariableValue(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("value") || !d.NewValueKnown("value") || !d.NewValueKnown("name") {
+		return nil
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not validate global variable value: %v", err)
+		return nil
+	}
+	name := d.Get("name").(string)
+	metadata, err := readVariableMetadata(ctx, db, name)
+	if err != nil {
+		log.Printf("[WARN] Could not read metadata of global variable %s: %v", name, err)
+		return nil
+	}
+	if _, err := normalizeVariableValue(name, metadata, d.Get("value").(string)); err != nil {
+		return err
+	}
+	if metadata != nil && metadata.ReadOnly && !d.Get("persist_read_only").(bool) && !d.Get("rds_reboot").(bool) {
+		return readOnlyVariableError(name)
+	}
+	return nil
+}
+
+func readOnlyVariableError(name string) error {
+	return fmt.Errorf("%s is read-only and only changes with a server restart; set persist_read_only to persist it for the next restart", name)
+}
 
+func CreateOrUpdateGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
@@ -54,20 +119,30 @@ func CreateOrUpdateGlobalVariable(ctx context.Context, d *schema.ResourceData, m
 	name := d.Get("name").(string)
 	value := d.Get("value").(string)
 
-	sqlBaseQuery := fmt.Sprintf("SET GLOBAL %s = ", quoteIdentifier(name))
-
-	// Detect number or string
-	if _, err := strconv.ParseFloat(value, 64); err == nil {
-		sqlCommand = fmt.Sprintf("%s%s", sqlBaseQuery, value)
-	} else {
-		sqlCommand = fmt.Sprintf("%s'%s'", sqlBaseQuery, value)
+	metadata, err := readVariableMetadata(ctx, db, name)
+	if err != nil {
+		return diag.Errorf("error reading metadata of %s: %s", name, err)
+	}
+	value, err = normalizeVariableValue(name, metadata, value)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("[DEBUG] SQL: %s", sqlCommand)
+	readOnly := metadata != nil && metadata.ReadOnly
+	if !readOnly {
+		sqlCommand := setGlobalVariableSQL(name, value)
+		log.Printf("[DEBUG] SQL: %s", sqlCommand)
+		_, err = db.ExecContext(ctx, sqlCommand)
+		readOnly = mysqlErrorNumber(err) == readOnlyVariableErrCode
+		if err != nil && !readOnly {
+			return diag.Errorf("error setting value: %s", err)
+		}
+	}
 
-	_, err = db.ExecContext(ctx, sqlCommand)
-	if err != nil {
-		return diag.Errorf("error setting value: %s", err)
+	if readOnly {
+		if err := setReadOnlyGlobalVariable(ctx, db, d, meta, name, value); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	d.SetId(name)
@@ -75,6 +150,70 @@ func CreateOrUpdateGlobalVariable(ctx context.Context, d *schema.ResourceData, m
 	return ReadGlobalVariable(ctx, d, meta)
 }
 
+// setReadOnlyGlobalVariable persists the value of a read-only variable for
+// the next restart. RDS doesn't allow SET PERSIST, read-only variables belong
+// in the DB parameter group there; rds_reboot reboots the instance to apply
+// pending parameter group changes and checks the value afterwards.
+func setReadOnlyGlobalVariable(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, name, value string) error {
+	rdsIdentifier := d.Get("rds_instance_identifier").(string)
+	endpoint, isRDS := rdsEndpointFromMeta(meta, rdsIdentifier)
+	identifier := endpoint.identifier
+	rdsReboot := d.Get("rds_reboot").(bool)
+
+	if d.Get("persist_read_only").(bool) {
+		sqlCommand := "SET PERSIST_ONLY " + globalVariableAssignment(name, value)
+		log.Printf("[DEBUG] SQL: %s", sqlCommand)
+		_, err := db.ExecContext(ctx, sqlCommand)
+		if err == nil {
+			if rdsReboot {
+				return rebootRDSFromMeta(ctx, meta, rdsIdentifier)
+			}
+			return nil
+		}
+		if !isRDS {
+			return fmt.Errorf("error persisting value of read-only variable %s: %s", name, err)
+		}
+		log.Printf("[WARN] Could not persist %s on RDS: %v", name, err)
+	}
+
+	if !isRDS {
+		return readOnlyVariableError(name)
+	}
+	if !rdsReboot {
+		return fmt.Errorf("%s is read-only and RDS doesn't persist variables; set it in the DB parameter group of %s, then reboot the instance or set rds_reboot", name, identifier)
+	}
+	if err := rebootRDSFromMeta(ctx, meta, rdsIdentifier); err != nil {
+		return err
+	}
+	current, err := readGlobalVariableValue(ctx, db, name)
+	if err != nil {
+		return fmt.Errorf("error reading %s after rebooting RDS %s: %s", name, identifier, err)
+	}
+	if !equivalentVariableValues(current, value) {
+		return fmt.Errorf("%s is still %s after rebooting RDS %s; set it to %s in its DB parameter group", name, current, identifier, value)
+	}
+	return nil
+}
+
+func readGlobalVariableValue(ctx context.Context, db *sql.DB, name string) (string, error) {
+	var variableName, value string
+	err := db.QueryRowContext(ctx, "SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME = ?", name).Scan(&variableName, &value)
+	return value, err
+}
+
+// setGlobalVariableSQL builds SET GLOBAL, detecting whether value is a number or a string.
+func setGlobalVariableSQL(name, value string) string {
+	return "SET GLOBAL " + globalVariableAssignment(name, value)
+}
+
+// globalVariableAssignment returns name = value for SET GLOBAL.
+func globalVariableAssignment(name, value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return fmt.Sprintf("%s = %s", quoteIdentifier(name), value)
+	}
+	return fmt.Sprintf("%s = %s", quoteIdentifier(name), quoteString(value))
+}
+
 func ReadGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -94,8 +233,32 @@ func ReadGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.Errorf("error during show global variables: %s", err)
 	}
 
+	// A value persisted for the next restart is what the resource manages;
+	// the running value only catches up after the restart.
+	restartRequired := false
+	if d.Get("persist_read_only").(bool) {
+		persisted, err := readPersistedVariable(ctx, db, d.Id())
+		if err != nil {
+			log.Printf("[WARN] Could not read persisted value of %s: %v", d.Id(), err)
+		} else if persisted != "" && !equivalentVariableValues(persisted, value) {
+			value = persisted
+			restartRequired = true
+		}
+	}
+
 	d.Set("name", name)
 	d.Set("value", value)
+	d.Set("restart_required", restartRequired)
+
+	metadata, err := readVariableMetadata(ctx, db, d.Id())
+	if err != nil {
+		log.Printf("[WARN] Could not read metadata of global variable %s: %v", d.Id(), err)
+	}
+	if metadata != nil {
+		d.Set("type", metadata.Type)
+	} else {
+		d.Set("type", "")
+	}
 
 	return nil
 }
@@ -107,6 +270,14 @@ func DeleteGlobalVariable(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 	name := d.Get("name").(string)
 
+	if d.Get("persist_read_only").(bool) {
+		sqlCommand := fmt.Sprintf("RESET PERSIST IF EXISTS %s", quoteIdentifier(name))
+		log.Printf("[DEBUG] SQL: %s", sqlCommand)
+		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+			log.Printf("[WARN] Could not reset persisted value of %s: %v", name, err)
+		}
+	}
+
 	sqlCommand := fmt.Sprintf("SET GLOBAL %s = DEFAULT", quoteIdentifier(name))
 	log.Printf("[DEBUG] SQL: %s", sqlCommand)
 
@@ -119,3 +290,16 @@ func DeleteGlobalVariable(ctx context.Context, d *schema.ResourceData, meta inte
 
 	return nil
 }
+
+// readPersistedVariable returns the value persisted in mysqld-auto.cnf, or ""
+// when the variable isn't persisted or the server doesn't persist variables.
+func readPersistedVariable(ctx context.Context, db *sql.DB, name string) (string, error) {
+	stmtSQL := "SELECT VARIABLE_VALUE FROM performance_schema.persisted_variables WHERE VARIABLE_NAME = ?"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	var value string
+	err := db.QueryRowContext(ctx, stmtSQL, name).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) || mysqlErrorNumber(err) == noSuchTableErrCode {
+		return "", nil
+	}
+	return value, err
+}
@@ -122,6 +122,52 @@ func TestAccGlobalVar_parseBoolean(t *testing.T) {
 	})
 }
 
+func TestAccGlobalVar_persistReadOnly(t *testing.T) {
+	resourceName := "mysql_global_variable.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGlobalVarPersistedCheckDestroy("back_log"),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccGlobalVarConfigBasic("back_log", "100"),
+				ExpectError: regexp.MustCompile("back_log is read-only"),
+			},
+			{
+				Config: testAccGlobalVarConfigPersistReadOnly("back_log", "100"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "value", "100"),
+					resource.TestCheckResourceAttr(resourceName, "restart_required", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccGlobalVarPersistedCheckDestroy(varName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		persisted, err := readPersistedVariable(ctx, db, varName)
+		if err != nil {
+			return err
+		}
+		if persisted != "" {
+			return fmt.Errorf("global variable '%s' is still persisted as %s", varName, persisted)
+		}
+		return nil
+	}
+}
+
 func testAccGlobalVarExists(varName, varExpected string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		ctx := context.Background()
@@ -186,3 +232,118 @@ resource "mysql_global_variable" "test" {
 }
 `, varName, varValue)
 }
+
+func testAccGlobalVarConfigPersistReadOnly(varName, varValue string) string {
+	return fmt.Sprintf(`
+resource "mysql_global_variable" "test" {
+  name              = "%s"
+  value             = "%s"
+  persist_read_only = true
+}
+`, varName, varValue)
+}
+
+func TestEquivalentVariableValues(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"ON", "1", true},
+		{"on", "TRUE", true},
+		{"OFF", "0", true},
+		{"ON", "OFF", false},
+		{"64M", "67108864", true},
+		{"1g", "1073741824", true},
+		{"64K", "65535", false},
+		{"ROW", "row", true},
+		{"ROW", "MIXED", false},
+	}
+	for _, tt := range tests {
+		if got := equivalentVariableValues(tt.a, tt.b); got != tt.want {
+			t.Errorf("equivalentVariableValues(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGlobalVariableAssignment(t *testing.T) {
+	tests := []struct {
+		name, value, want string
+	}{
+		{"max_connections", "100", "`max_connections` = 100"},
+		{"sql_mode", "ANSI", "`sql_mode` = 'ANSI'"},
+		{"init_connect", "SET @x = 'a'", "`init_connect` = 'SET @x = \\'a\\''"},
+	}
+	for _, tt := range tests {
+		if got := globalVariableAssignment(tt.name, tt.value); got != tt.want {
+			t.Errorf("globalVariableAssignment(%q, %q) = %q, want %q", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeVariableValue(t *testing.T) {
+	boolVar := &variableMetadata{Type: variableTypeBool}
+	intVar := &variableMetadata{Type: variableTypeInt, Min: "1", Max: "100000"}
+	floatVar := &variableMetadata{Type: variableTypeFloat, Min: "0.000000", Max: "31536000.000000"}
+	enumVar := &variableMetadata{Type: variableTypeEnum, EnumValues: []string{"MIXED", "STATEMENT", "ROW"}}
+
+	tests := []struct {
+		metadata *variableMetadata
+		value    string
+		want     string
+	}{
+		{nil, "anything", "anything"},
+		{boolVar, "1", "ON"},
+		{boolVar, "off", "OFF"},
+		{intVar, "500", "500"},
+		{intVar, "64K", "65536"},
+		{floatVar, "10.5", "10.5"},
+		{enumVar, "row", "ROW"},
+		{enumVar, "1", "STATEMENT"},
+		{&variableMetadata{Type: variableTypeString}, "utf8mb4", "utf8mb4"},
+	}
+	for _, tt := range tests {
+		got, err := normalizeVariableValue("v", tt.metadata, tt.value)
+		if err != nil {
+			t.Errorf("normalizeVariableValue(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("normalizeVariableValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		metadata *variableMetadata
+		value    string
+	}{
+		{boolVar, "maybe"},
+		{intVar, "0"},
+		{intVar, "1M"},
+		{intVar, "1.5"},
+		{intVar, "lots"},
+		{floatVar, "-1"},
+		{enumVar, "ROWS"},
+		{enumVar, "3"},
+	} {
+		if _, err := normalizeVariableValue("v", tt.metadata, tt.value); err == nil {
+			t.Errorf("normalizeVariableValue(%q) should fail", tt.value)
+		}
+	}
+}
+
+func TestMySQLVariableType(t *testing.T) {
+	tests := []struct {
+		min, max, current string
+		want              string
+	}{
+		{"1", "100000", "151", variableTypeInt},
+		{"0.000000", "31536000.000000", "10.000000", variableTypeFloat},
+		{"0", "0", "ON", variableTypeBool},
+		{"0", "0", "ROW", variableTypeString},
+	}
+	for _, tt := range tests {
+		if got := mysqlVariableType(tt.min, tt.max, tt.current); got != tt.want {
+			t.Errorf("mysqlVariableType(%q, %q, %q) = %q, want %q", tt.min, tt.max, tt.current, got, tt.want)
+		}
+	}
+}
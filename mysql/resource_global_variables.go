@@ -0,0 +1,302 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceGlobalVariables() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateGlobalVariables,
+		UpdateContext: UpdateGlobalVariables,
+		ReadContext:   ReadGlobalVariables,
+		DeleteContext: DeleteGlobalVariables,
+		CustomizeDiff: checkGlobalVariablesValues,
+
+		Schema: map[string]*schema.Schema{
+			"variables": {
+				Type:     schema.TypeMap,
+				Required: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile("(^`(.*)`$|')"), "can't contain any ' string or `<value>`"),
+				},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					if strings.HasSuffix(k, ".%") {
+						return false
+					}
+					return equivalentVariableValues(old, new)
+				},
+			},
+			"authoritative_prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z0-9_]+%?$`), "must be a variable name prefix, e.g. innodb_"),
+				Description:  "Prefix of variable names, e.g. innodb_, whose values set at runtime outside of variables are reported as drift",
+			},
+			"unmanaged_drift": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Variables matching authoritative_prefix, not in variables, that were set at runtime",
+			},
+		},
+	}
+}
+
+// globalVariableState is a global variable as read from the server. Dynamic
+// is true when the variable was set at runtime, as far as the server tells.
+type globalVariableState struct {
+	value   string
+	dynamic bool
+}
+
+func sortedVariableNames(variables map[string]interface{}) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// authoritativePrefix returns the prefix without a trailing LIKE wildcard.
+func authoritativePrefix(d *schema.ResourceData) string {
+	return strings.ToLower(strings.TrimSuffix(d.Get("authoritative_prefix").(string), "%"))
+}
+
+// setGlobalVariablesSQL sets all variables with a single statement. Nil
+// values set the variable to DEFAULT. Sizes like 64M are sent in bytes, as
+// SET GLOBAL doesn't take suffixes.
+func setGlobalVariablesSQL(variables map[string]interface{}) string {
+	assignments := make([]string, 0, len(variables))
+	for _, name := range sortedVariableNames(variables) {
+		value, ok := variables[name].(string)
+		if !ok {
+			assignments = append(assignments, quoteIdentifier(name)+" = DEFAULT")
+			continue
+		}
+		assignments = append(assignments, globalVariableAssignment(name, expandSize(value)))
+	}
+	return "SET GLOBAL " + strings.Join(assignments, ", ")
+}
+
+func execSetGlobalVariables(ctx context.Context, db *sql.DB, variables map[string]interface{}) error {
+	if len(variables) == 0 {
+		return nil
+	}
+	stmtSQL := setGlobalVariablesSQL(variables)
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("error setting values: %s", err)
+	}
+	return nil
+}
+
+// checkGlobalVariablesValues validates changed values against the type and
+// range of their variables at plan time, like mysql_global_variable.
+func checkGlobalVariablesValues(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("variables") || !d.NewValueKnown("variables") {
+		return nil
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not validate global variable values: %v", err)
+		return nil
+	}
+
+	o, n := d.GetChange("variables")
+	oldVariables := o.(map[string]interface{})
+	newVariables := n.(map[string]interface{})
+	for _, name := range sortedVariableNames(newVariables) {
+		value := newVariables[name].(string)
+		if old, ok := oldVariables[name].(string); ok && equivalentVariableValues(old, value) {
+			continue
+		}
+		metadata, err := readVariableMetadata(ctx, db, name)
+		if err != nil {
+			log.Printf("[WARN] Could not read metadata of global variable %s: %v", name, err)
+			return nil
+		}
+		if _, err := normalizeVariableValue(name, metadata, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CreateGlobalVariables(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := execSetGlobalVariables(ctx, db, d.Get("variables").(map[string]interface{})); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id.UniqueId())
+	return ReadGlobalVariables(ctx, d, meta)
+}
+
+func UpdateGlobalVariables(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	o, n := d.GetChange("variables")
+	oldVariables := o.(map[string]interface{})
+	newVariables := n.(map[string]interface{})
+
+	changed := map[string]interface{}{}
+	for name := range oldVariables {
+		if _, ok := newVariables[name]; !ok {
+			changed[name] = nil
+		}
+	}
+	for name, value := range newVariables {
+		if old, ok := oldVariables[name]; !ok || old.(string) != value.(string) {
+			changed[name] = value
+		}
+	}
+	if err := execSetGlobalVariables(ctx, db, changed); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return ReadGlobalVariables(ctx, d, meta)
+}
+
+// readGlobalVariableStates reads all global variables with a single query.
+// Where the server tracks it, it also reads whether each was set at runtime.
+func readGlobalVariableStates(ctx context.Context, db *sql.DB) (map[string]globalVariableState, error) {
+	mariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []string{
+		"SELECT gv.VARIABLE_NAME, gv.VARIABLE_VALUE, vi.VARIABLE_SOURCE IN ('DYNAMIC', 'PERSISTED') FROM performance_schema.global_variables gv JOIN performance_schema.variables_info vi USING (VARIABLE_NAME)",
+		"SHOW GLOBAL VARIABLES",
+	}
+	if mariaDB {
+		queries[0] = "SELECT VARIABLE_NAME, COALESCE(GLOBAL_VALUE, ''), GLOBAL_VALUE_ORIGIN = 'SQL' FROM information_schema.SYSTEM_VARIABLES"
+	}
+
+	var rows *sql.Rows
+	for _, stmtSQL := range queries {
+		log.Printf("[DEBUG] SQL: %s", stmtSQL)
+		rows, err = db.QueryContext(ctx, stmtSQL)
+		if err == nil {
+			break
+		}
+		log.Printf("[DEBUG] Could not read global variables: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]globalVariableState{}
+	for rows.Next() {
+		var name, value string
+		var dynamic sql.NullBool
+		dest := []interface{}{&name, &value}
+		if len(columns) > 2 {
+			dest = append(dest, &dynamic)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		states[strings.ToLower(name)] = globalVariableState{value: value, dynamic: dynamic.Bool}
+	}
+	return states, rows.Err()
+}
+
+// ReadGlobalVariables refreshes all variables with a single query. Values set
+// at runtime to variables matching authoritative_prefix, but not managed by
+// the resource, are reported as unmanaged_drift with a warning.
+func ReadGlobalVariables(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	states, err := readGlobalVariableStates(ctx, db)
+	if err != nil {
+		return diag.Errorf("error reading global variables: %s", err)
+	}
+
+	variables := map[string]interface{}{}
+	managed := map[string]bool{}
+	for name := range d.Get("variables").(map[string]interface{}) {
+		managed[strings.ToLower(name)] = true
+		state, ok := states[strings.ToLower(name)]
+		if !ok {
+			log.Printf("[WARN] Global variable %s not found, removing it from state", name)
+			continue
+		}
+		variables[name] = state.value
+	}
+	if err := d.Set("variables", variables); err != nil {
+		return diag.Errorf("failed setting variables field: %v", err)
+	}
+
+	drift := map[string]interface{}{}
+	if prefix := authoritativePrefix(d); prefix != "" {
+		for name, state := range states {
+			if state.dynamic && strings.HasPrefix(name, prefix) && !managed[name] {
+				drift[name] = state.value
+			}
+		}
+	}
+	if err := d.Set("unmanaged_drift", drift); err != nil {
+		return diag.Errorf("failed setting unmanaged_drift field: %v", err)
+	}
+
+	if len(drift) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Unmanaged global variables changed",
+		Detail:   fmt.Sprintf("Variables matching %s%% were set outside of Terraform: %s", authoritativePrefix(d), strings.Join(sortedVariableNames(drift), ", ")),
+	}}
+}
+
+func DeleteGlobalVariables(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	defaults := map[string]interface{}{}
+	for name := range d.Get("variables").(map[string]interface{}) {
+		defaults[name] = nil
+	}
+	if err := execSetGlobalVariables(ctx, db, defaults); err == nil {
+		return nil
+	}
+
+	// Not every variable supports DEFAULT, which fails the whole statement.
+	for _, name := range sortedVariableNames(defaults) {
+		if err := execSetGlobalVariables(ctx, db, map[string]interface{}{name: nil}); err != nil {
+			log.Printf("[WARN] Could not set %s to DEFAULT: %v", name, err)
+		}
+	}
+	return nil
+}
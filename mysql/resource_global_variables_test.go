@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGlobalVariables_basic(t *testing.T) {
+	resourceName := "mysql_global_variables.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQL8(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGlobalVarCheckDestroy("innodb_lock_wait_timeout", "43"),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec("SET GLOBAL innodb_print_all_deadlocks = ON"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccGlobalVariablesConfig("42", "64M"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGlobalVarExists("innodb_lock_wait_timeout", "42"),
+					testAccGlobalVarExists("max_allowed_packet", "67108864"),
+					resource.TestCheckResourceAttr(resourceName, "unmanaged_drift.innodb_print_all_deadlocks", "ON"),
+				),
+			},
+			{
+				Config:   testAccGlobalVariablesConfig("42", "67108864"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccGlobalVariablesConfig("43", "64M"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGlobalVarExists("innodb_lock_wait_timeout", "43"),
+				),
+			},
+		},
+	})
+}
+
+func TestSetGlobalVariablesSQL(t *testing.T) {
+	got := setGlobalVariablesSQL(map[string]interface{}{
+		"max_allowed_packet":       "64M",
+		"innodb_lock_wait_timeout": "42",
+		"sql_mode":                 "STRICT_ALL_TABLES",
+		"max_connections":          nil,
+	})
+	want := "SET GLOBAL `innodb_lock_wait_timeout` = 42, `max_allowed_packet` = 67108864, `max_connections` = DEFAULT, `sql_mode` = 'STRICT_ALL_TABLES'"
+	if got != want {
+		t.Errorf("setGlobalVariablesSQL() = %q, want %q", got, want)
+	}
+}
+
+func testAccGlobalVariablesConfig(lockWaitTimeout, maxAllowedPacket string) string {
+	return fmt.Sprintf(`
+resource "mysql_global_variables" "test" {
+  variables = {
+    innodb_lock_wait_timeout = "%s"
+    max_allowed_packet       = "%s"
+  }
+  authoritative_prefix = "innodb_"
+}
+`, lockWaitTimeout, maxAllowedPacket)
+}
@@ -10,16 +10,20 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 type ObjectT string
 
+const noSuchTableErrCode = 1146
+
 var grantCreateMutex = NewKeyedMutex()
 
 type MySQLGrant interface {
@@ -58,6 +62,12 @@ type PrivilegesPartiallyRevocable interface {
 	SQLPartialRevokePrivilegesStatement(privilegesToRevoke []string, revokeGrantOption bool) string
 }
 
+// GrantOptionRevocable grants can drop WITH GRANT OPTION (or WITH ADMIN
+// OPTION) while keeping the privileges or roles themselves.
+type GrantOptionRevocable interface {
+	SQLRevokeGrantOptionStatements(mariaDB bool) []string
+}
+
 type UserOrRole struct {
 	Name string
 	Host string
@@ -181,6 +191,10 @@ func (t *TablePrivilegeGrant) SQLPartialRevokePrivilegesStatement(privilegesToRe
 	return fmt.Sprintf("REVOKE %s ON %s.%s FROM %s", strings.Join(privilegesToRevoke, ", "), t.GetDatabase(), t.GetTable(), t.UserOrRole.SQLString())
 }
 
+func (t *TablePrivilegeGrant) SQLRevokeGrantOptionStatements(mariaDB bool) []string {
+	return []string{fmt.Sprintf("REVOKE GRANT OPTION ON %s.%s FROM %s", t.GetDatabase(), t.GetTable(), t.UserOrRole.SQLString())}
+}
+
 type ProcedurePrivilegeGrant struct {
 	Database     string
 	ObjectT      ObjectT
@@ -250,6 +264,10 @@ func (t *ProcedurePrivilegeGrant) SQLPartialRevokePrivilegesStatement(privileges
 	return fmt.Sprintf("REVOKE %s ON %s %s.%s FROM %s", strings.Join(privs, ", "), t.ObjectT, t.GetDatabase(), t.GetCallableName(), t.UserOrRole.SQLString())
 }
 
+func (t *ProcedurePrivilegeGrant) SQLRevokeGrantOptionStatements(mariaDB bool) []string {
+	return []string{fmt.Sprintf("REVOKE GRANT OPTION ON %s %s.%s FROM %s", t.ObjectT, t.GetDatabase(), t.GetCallableName(), t.UserOrRole.SQLString())}
+}
+
 func (t *ProcedurePrivilegeGrant) ConflictsWithGrant(other MySQLGrant) bool {
 	otherTyped, ok := other.(*ProcedurePrivilegeGrant)
 	if !ok {
@@ -293,6 +311,17 @@ func (t *RoleGrant) SQLRevokeStatement() string {
 	return fmt.Sprintf("REVOKE '%s' FROM %s", strings.Join(t.Roles, "', '"), t.UserOrRole.SQLString())
 }
 
+// SQLRevokeGrantOptionStatements drops WITH ADMIN OPTION. MySQL can't revoke
+// it alone, so the roles are revoked and granted again without it, which
+// isn't atomic: the account lacks the roles between both statements.
+// revokePrivileges retries the grant. t must already have Grant unset.
+func (t *RoleGrant) SQLRevokeGrantOptionStatements(mariaDB bool) []string {
+	if mariaDB {
+		return []string{fmt.Sprintf("REVOKE ADMIN OPTION FOR '%s' FROM %s", strings.Join(t.Roles, "', '"), t.UserOrRole.SQLString())}
+	}
+	return []string{t.SQLRevokeStatement(), t.SQLGrantStatement()}
+}
+
 func (t *RoleGrant) GetRoles() []string {
 	return t.Roles
 }
@@ -311,7 +340,7 @@ func (t *RoleGrant) ConflictsWithGrant(other MySQLGrant) bool {
 }
 
 func resourceGrant() *schema.Resource {
-	return &schema.Resource{
+	return withIDStateUpgrader(withRefreshMode(&schema.Resource{
 		CreateContext: CreateGrant,
 		UpdateContext: UpdateGrant,
 		ReadContext:   ReadGrant,
@@ -320,11 +349,67 @@ func resourceGrant() *schema.Resource {
 			StateContext: ImportGrant,
 		},
 
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			_, hasRoles := d.GetOk("roles")
+			if _, hasRole := d.GetOk("role"); hasRoles || hasRole {
+				if err := checkTiDBRoleSupport(ctx, meta, hasRoles && d.Get("grant").(bool)); err != nil {
+					return err
+				}
+			}
+
+			// Role grants don't use database; keep the historical "*".
+			fallback := "*"
+			if _, ok := d.GetOk("roles"); ok {
+				if err := setDefaultDatabase(d, nil, fallback); err != nil {
+					return err
+				}
+			} else if err := setDefaultDatabase(d, meta, fallback); err != nil {
+				return err
+			}
+
+			if err := checkPrivilegeBundle(ctx, d, meta); err != nil {
+				return err
+			}
+
+			if err := checkSystemSchemaGrant(d); err != nil {
+				return err
+			}
+
+			if err := checkAuroraPrivileges(ctx, d, meta); err != nil {
+				return err
+			}
+
+			if err := diffGranteeFingerprint(ctx, d, meta); err != nil {
+				return err
+			}
+
+			if err := checkGrantUpdateStrategy(ctx, d, meta); err != nil {
+				return err
+			}
+
+			if _, ok := d.GetOk("role"); ok {
+				return nil
+			}
+
+			// Switching between host and hosts changes the grant identity.
+			if d.Id() != "" && d.HasChange("hosts") {
+				o, n := d.GetChange("hosts")
+				if o.(*schema.Set).Len() == 0 || n.(*schema.Set).Len() == 0 {
+					if err := d.ForceNew("hosts"); err != nil {
+						return err
+					}
+				}
+			}
+
+			return setDefaultUserHost(d, meta)
+		},
+
 		Schema: map[string]*schema.Schema{
 			"user": {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ForceNew:      true,
+				ValidateFunc:  validateNotReservedAccount,
 				ConflictsWith: []string{"role"},
 			},
 
@@ -332,22 +417,31 @@ func resourceGrant() *schema.Resource {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ForceNew:      true,
-				ConflictsWith: []string{"user", "host"},
+				ConflictsWith: []string{"user", "host", "hosts"},
 			},
 
 			"host": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				Computed:      true,
 				ForceNew:      true,
-				Default:       "localhost",
 				ConflictsWith: []string{"role"},
 			},
 
+			"hosts": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"role", "host"},
+				Description:   "Grant the same privileges to the user at each of these hosts",
+			},
+
 			"database": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
-				Default:  "*",
 			},
 
 			"table": {
@@ -360,10 +454,25 @@ func resourceGrant() *schema.Resource {
 			"privileges": {
 				Type:     schema.TypeSet,
 				Optional: true,
+				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set:      schema.HashString,
 			},
 
+			"bundle": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"privileges", "roles"},
+				ValidateFunc:  validation.StringInSlice(privilegeBundleNames(), false),
+				Description:   "Named set of privileges, expanded for the flavor and version of the server",
+			},
+
+			"grantee_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The grantee accounts that exist; the privileges are granted again when an account was dropped",
+			},
+
 			"roles": {
 				Type:          schema.TypeSet,
 				Optional:      true,
@@ -376,10 +485,23 @@ func resourceGrant() *schema.Resource {
 			"grant": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
 				Default:  false,
 			},
 
+			"update_strategy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      grantUpdateInPlace,
+				ValidateFunc: validation.StringInSlice([]string{grantUpdateInPlace, grantUpdateRoleSwap}, false),
+				Description:  "How changed privileges are applied: in_place, or role_swap staging them on a new role that replaces the previous one",
+			},
+
+			"swap_role": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Role holding the privileges with update_strategy role_swap",
+			},
+
 			"tls_option": {
 				Type:       schema.TypeString,
 				Optional:   true,
@@ -387,8 +509,36 @@ func resourceGrant() *schema.Resource {
 				Deprecated: "Please use tls_option in mysql_user.",
 				Default:    "NONE",
 			},
+
+			"validate_object_exists": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail instead of granting on a database, table, view or routine that doesn't exist yet",
+			},
+
+			"create_missing_database": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Create the database before granting privileges on it if it doesn't exist",
+			},
+
+			"default_character_set": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"create_missing_database"},
+				Description:  "Character set of the database created by create_missing_database",
+			},
+
+			"default_collation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"create_missing_database"},
+				Description:  "Collation of the database created by create_missing_database",
+			},
 		},
-	}
+	}), upgradeGrantStateV0)
 }
 
 func supportsRoles(ctx context.Context, meta interface{}) (bool, error) {
@@ -403,11 +553,21 @@ var kReProcedureWithoutDatabase = regexp.MustCompile(`(?i)^(function|procedure)
 var kReProcedureWithDatabase = regexp.MustCompile(`(?i)^(function|procedure) ([^.]*)\.([^.]*)$`)
 
 func parseResourceFromData(d *schema.ResourceData) (MySQLGrant, diag.Diagnostics) {
+	return parseResourceFromDataForHost(d, "")
+}
+
+// parseResourceFromDataForHost parses the grant, using host instead of the
+// host attribute if it's not empty.
+func parseResourceFromDataForHost(d *schema.ResourceData, host string) (MySQLGrant, diag.Diagnostics) {
 
 	// Step 1: Parse the user/role
 	var userOrRole UserOrRole
 	userAttr, userOk := d.GetOk("user")
-	hostAttr, hostOk := d.GetOk("host")
+	var hostAttr interface{} = host
+	hostOk := host != ""
+	if !hostOk {
+		hostAttr, hostOk = d.GetOk("host")
+	}
 	roleAttr, roleOk := d.GetOk("role")
 	if (userOk && userAttr.(string) == "") && (roleOk && roleAttr == "") {
 		return nil, diag.Errorf("User or role name must be specified")
@@ -491,9 +651,13 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
+	if err := applyPrivilegeBundle(ctx, d, meta); err != nil {
+		return diag.Errorf("failed expanding privilege bundle: %v", err)
+	}
+
 	// Parse the ResourceData
-	grant, diagErr := parseResourceFromData(d)
-	if err != nil {
+	grant, diagErr := parseResourceFromDataForHost(d, grantHosts(d)[0])
+	if diagErr != nil {
 		return diagErr
 	}
 
@@ -506,6 +670,115 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.Errorf("role grants are not supported by this version of MySQL")
 	}
 
+	if d.Get("create_missing_database").(bool) {
+		if tableGrant, ok := grant.(*TablePrivilegeGrant); ok && tableGrant.Database != "*" {
+			if err := createMissingDatabase(ctx, db, tableGrant.Database, d.Get("default_character_set").(string), d.Get("default_collation").(string)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if d.Get("validate_object_exists").(bool) {
+		if err := checkGrantObjectExists(ctx, db, grant); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.Get("update_strategy").(string) == grantUpdateRoleSwap {
+		if err := createRoleSwapGrant(ctx, db, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		for _, host := range grantHosts(d) {
+			hostGrant, diagErr := parseResourceFromDataForHost(d, host)
+			if diagErr != nil {
+				return diagErr
+			}
+			if diagErr := createGrant(ctx, db, hostGrant); diagErr != nil {
+				return diagErr
+			}
+		}
+	}
+
+	d.SetId(grant.GetId())
+	d.Set("grantee_fingerprint", granteeFingerprintFromData(ctx, db, d))
+	return ReadGrant(ctx, d, meta)
+}
+
+// checkGrantObjectExists fails when the database, table (or view) or routine
+// a grant is on doesn't exist. MySQL allows such grants, so they take effect
+// once the object is created.
+func checkGrantObjectExists(ctx context.Context, db *sql.DB, grant MySQLGrant) error {
+	var kind, stmtSQL string
+	var args []interface{}
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		switch {
+		case g.Database == "*":
+			return nil
+		case g.Table == "*" || g.Table == "":
+			kind = fmt.Sprintf("database %s", g.GetDatabase())
+			stmtSQL = "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?"
+			args = []interface{}{g.Database}
+		default:
+			kind = fmt.Sprintf("table or view %s.%s", g.GetDatabase(), g.GetTable())
+			stmtSQL = "SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+			args = []interface{}{g.Database, g.Table}
+		}
+	case *ProcedurePrivilegeGrant:
+		kind = fmt.Sprintf("%s %s.%s", strings.ToLower(string(g.ObjectT)), g.GetDatabase(), g.GetCallableName())
+		stmtSQL = "SELECT 1 FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = ?"
+		args = []interface{}{g.Database, g.CallableName, string(g.ObjectT)}
+	default:
+		return nil
+	}
+
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	exists, err := queryHasRows(ctx, db, stmtSQL, args...)
+	if err != nil {
+		return fmt.Errorf("failed checking that %s exists: %v", kind, err)
+	}
+	if !exists {
+		return fmt.Errorf("%s doesn't exist (validate_object_exists is set)", kind)
+	}
+	return nil
+}
+
+// createMissingDatabase creates the database a grant is on. Grants on
+// database patterns like tenant\_% don't name a database to create.
+func createMissingDatabase(ctx context.Context, db *sql.DB, database, charset, collation string) error {
+	if strings.Contains(strings.ReplaceAll(database, `\%`, ""), "%") {
+		log.Printf("[DEBUG] Not creating database for pattern %s", database)
+		return nil
+	}
+	database = strings.NewReplacer(`\_`, "_", `\%`, "%").Replace(database)
+
+	stmtSQL := "CREATE DATABASE IF NOT EXISTS " + quoteIdentifier(database)
+	if charset != "" {
+		stmtSQL += " " + defaultCharacterSetKeyword + quoteIdentifier(charset)
+	}
+	if collation != "" {
+		stmtSQL += " " + defaultCollateKeyword + quoteIdentifier(collation)
+	}
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed creating database %s: %v", database, err)
+	}
+	return nil
+}
+
+// grantHosts returns the sorted hosts of a grant using hosts, or a single
+// empty string meaning the host (or role) attribute.
+func grantHosts(d *schema.ResourceData) []string {
+	hosts := setToArray(d.Get("hosts"))
+	if len(hosts) == 0 {
+		return []string{""}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func createGrant(ctx context.Context, db *sql.DB, grant MySQLGrant) diag.Diagnostics {
 	// Acquire a lock for the user
 	// This is necessary so that the conflicting grant check is correct with respect to other grants being created
 	grantCreateMutex.Lock(grant.GetUserOrRole().IDString())
@@ -524,12 +797,83 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 	_, err = db.ExecContext(ctx, stmtSQL)
+	if mysqlErrorNumber(err) == noSuchTableErrCode {
+		return diag.Errorf("Error running SQL (%v): %v - this server doesn't allow grants on tables that don't exist yet, create the table first", stmtSQL, err)
+	}
 	if err != nil {
 		return diag.Errorf("Error running SQL (%v): %v", stmtSQL, err)
 	}
 
-	d.SetId(grant.GetId())
-	return ReadGrant(ctx, d, meta)
+	if err := verifyGrant(ctx, db, grant); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// grantedPrivilegeAliases maps privileges to the names servers report them
+// as.
+var grantedPrivilegeAliases = map[string]string{
+	// MariaDB 10.5+
+	"REPLICATION CLIENT": "BINLOG MONITOR",
+	"REPLICATION SLAVE":  "REPLICATION REPLICA",
+}
+
+// normalizeVerifiedName normalizes privileges and roles for verifyGrant.
+func normalizeVerifiedName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(strings.NewReplacer("'", "", "`", "").Replace(name)))
+	name = strings.TrimSuffix(name, "@%")
+	if alias, ok := grantedPrivilegeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// verifyGrant checks that global and role grants took effect. Some servers,
+// e.g. TiDB, silently ignore privileges they don't support.
+func verifyGrant(ctx context.Context, db *sql.DB, grant MySQLGrant) error {
+	var requested []string
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		if g.GetDatabase() != "*" || g.GetTable() != "*" || containsAllPrivilege(g.Privileges) {
+			return nil
+		}
+		requested = normalizePerms(g.Privileges)
+	case *RoleGrant:
+		requested = g.Roles
+	default:
+		return nil
+	}
+
+	actual, err := getMatchingGrant(ctx, db, grant)
+	if err != nil {
+		return fmt.Errorf("failed verifying grant: %w", err)
+	}
+	var granted []string
+	switch g := actual.(type) {
+	case *TablePrivilegeGrant:
+		granted = normalizePerms(g.Privileges)
+	case *RoleGrant:
+		granted = g.Roles
+	}
+
+	var missing []string
+	for _, want := range requested {
+		found := false
+		for _, have := range granted {
+			if strings.EqualFold(normalizeVerifiedName(want), normalizeVerifiedName(have)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, want)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("server accepted the grant to %s, but didn't grant: %s", grant.GetUserOrRole().SQLString(), strings.Join(missing, ", "))
+	}
+	return nil
 }
 
 func ReadGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -538,22 +882,69 @@ func ReadGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diag.Errorf("failed getting database from Meta: %v", err)
 	}
 
-	grantFromTf, diagErr := parseResourceFromData(d)
-	if diagErr != nil {
-		return diagErr
+	if d.Get("update_strategy").(string) == grantUpdateRoleSwap {
+		return readRoleSwapGrant(ctx, db, d)
 	}
 
-	grantFromDb, err := getMatchingGrant(ctx, db, grantFromTf)
-	if err != nil {
-		return diag.Errorf("ReadGrant - getting all grants failed: %v", err)
+	hosts := setToArray(d.Get("hosts"))
+	if len(hosts) == 0 {
+		grantFromTf, diagErr := parseResourceFromData(d)
+		if diagErr != nil {
+			return diagErr
+		}
+
+		grantFromDb, err := getMatchingGrant(ctx, db, grantFromTf)
+		if err != nil {
+			return diag.Errorf("ReadGrant - getting all grants failed: %v", err)
+		}
+		if grantFromDb == nil {
+			log.Printf("[WARN] GRANT not found for %#v - removing from state", grantFromTf.GetUserOrRole())
+			d.SetId("")
+			return nil
+		}
+
+		setDataFromGrant(grantFromDb, d, privilegeComparisonFromMeta(meta))
+		setInitialGranteeFingerprint(ctx, db, d)
+
+		return nil
+	}
+
+	// Each host is read separately; hosts without the grant are dropped
+	// from state so the next apply grants them again.
+	id := d.Id()
+	var existing []string
+	var grantsFromDb []MySQLGrant
+	for _, host := range grantHosts(d) {
+		grantFromTf, diagErr := parseResourceFromDataForHost(d, host)
+		if diagErr != nil {
+			return diagErr
+		}
+
+		grantFromDb, err := getMatchingGrant(ctx, db, grantFromTf)
+		if err != nil {
+			return diag.Errorf("ReadGrant - getting all grants failed: %v", err)
+		}
+		if grantFromDb == nil {
+			log.Printf("[WARN] GRANT not found for %#v - removing host from state", grantFromTf.GetUserOrRole())
+			continue
+		}
+
+		grantsFromDb = append(grantsFromDb, grantFromDb)
+		existing = append(existing, host)
 	}
-	if grantFromDb == nil {
-		log.Printf("[WARN] GRANT not found for %#v - removing from state", grantFromTf.GetUserOrRole())
+
+	if len(existing) == 0 {
+		log.Printf("[WARN] GRANT not found for any host - removing from state")
 		d.SetId("")
 		return nil
 	}
 
-	setDataFromGrant(grantFromDb, d)
+	setDataFromHostGrants(grantsFromDb, d, privilegeComparisonFromMeta(meta))
+
+	d.Set("hosts", existing)
+	d.Set("host", "")
+	d.SetId(id)
+	setInitialGranteeFingerprint(ctx, db, d)
 
 	return nil
 }
@@ -564,21 +955,85 @@ func UpdateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
+	if err := applyPrivilegeBundle(ctx, d, meta); err != nil {
+		return diag.Errorf("failed expanding privilege bundle: %v", err)
+	}
+
 	if err != nil {
 		return diag.Errorf("failed getting user or role: %v", err)
 	}
 
-	if d.HasChange("privileges") {
-		grant, diagErr := parseResourceFromData(d)
-		if diagErr != nil {
-			return diagErr
+	if d.Get("update_strategy").(string) == grantUpdateRoleSwap {
+		diags := updateRoleSwapGrant(ctx, db, d, meta)
+		d.Set("grantee_fingerprint", granteeFingerprintFromData(ctx, db, d))
+		return diags
+	}
+
+	added := schema.NewSet(schema.HashString, nil)
+	if d.HasChange("hosts") {
+		o, n := d.GetChange("hosts")
+		added = n.(*schema.Set).Difference(o.(*schema.Set))
+
+		for _, host := range setToArray(o.(*schema.Set).Difference(n.(*schema.Set))) {
+			grant, diagErr := parseResourceFromDataForHost(d, host)
+			if diagErr != nil {
+				return diagErr
+			}
+			if diagErr := revokeGrant(ctx, db, grant); diagErr != nil {
+				return diagErr
+			}
 		}
 
-		err = updatePrivileges(ctx, db, d, grant)
-		if err != nil {
-			return diag.Errorf("failed updating privileges: %v", err)
+		for _, host := range setToArray(added) {
+			grant, diagErr := parseResourceFromDataForHost(d, host)
+			if diagErr != nil {
+				return diagErr
+			}
+			if diagErr := createGrant(ctx, db, grant); diagErr != nil {
+				return diagErr
+			}
+		}
+	}
+
+	if d.HasChange("privileges") || d.HasChange("grant") {
+		for _, host := range grantHosts(d) {
+			if added.Contains(host) {
+				continue
+			}
+
+			grant, diagErr := parseResourceFromDataForHost(d, host)
+			if diagErr != nil {
+				return diagErr
+			}
+
+			err = updatePrivileges(ctx, db, d, grant)
+			if err != nil {
+				return diag.Errorf("failed updating privileges: %v", err)
+			}
+		}
+	}
+
+	// A dropped account lost its privileges; GRANT adds back whatever is
+	// missing and leaves the rest as is.
+	if d.HasChange("grantee_fingerprint") {
+		for _, host := range grantHosts(d) {
+			if added.Contains(host) {
+				continue
+			}
+
+			grant, diagErr := parseResourceFromDataForHost(d, host)
+			if diagErr != nil {
+				return diagErr
+			}
+
+			stmtSQL := grant.SQLGrantStatement()
+			log.Println("[DEBUG] Executing statement:", stmtSQL)
+			if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+				return diag.Errorf("failed granting again to recreated account: %v", err)
+			}
 		}
 	}
+	d.Set("grantee_fingerprint", granteeFingerprintFromData(ctx, db, d))
 
 	return nil
 }
@@ -603,8 +1058,47 @@ func updatePrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, g
 		privsToRevoke = append(privsToRevoke, revokeIf.(string))
 	}
 	privsToRevoke = normalizePerms(privsToRevoke)
+	grantPrivs := len(grantIfs) > 0 || (newGrantOption && !oldGrantOption)
+
+	mariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return err
+	}
+
+	// Granting before revoking keeps the privileges that stay from missing
+	// even for a moment, e.g. when ALL PRIVILEGES is narrowed down.
+	revokeAfterGrant, ok := privilegesToRevokeAfterGrant(privsToRevoke, normalizePerms(setToArray(newPrivs)), allPrivilegesExpansion(grant, mariaDB))
+	if !ok {
+		log.Printf("[DEBUG] Revoking %v before granting, it can't be revoked privilege by privilege", privsToRevoke)
+		if err := revokePrivileges(ctx, db, grant, privsToRevoke, revokeGrantOption, mariaDB); err != nil {
+			return err
+		}
+		revokeAfterGrant = nil
+		revokeGrantOption = false
+	}
+
+	// Do a full grant if anything or the grant option has been added
+	if grantPrivs {
+		sqlCommand := grant.SQLGrantStatement()
+		log.Printf("[DEBUG] SQL to re-grant privileges: %s", sqlCommand)
+
+		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+			return err
+		}
+	}
 
 	// Do a partial revoke of anything that has been removed
+	if err := revokePrivileges(ctx, db, grant, revokeAfterGrant, revokeGrantOption, mariaDB); err != nil {
+		return err
+	}
+
+	if grantPrivs {
+		return verifyGrant(ctx, db, grant)
+	}
+	return nil
+}
+
+func revokePrivileges(ctx context.Context, db *sql.DB, grant MySQLGrant, privsToRevoke []string, revokeGrantOption bool, mariaDB bool) error {
 	if len(privsToRevoke) > 0 {
 		partialRevoker, ok := grant.(PrivilegesPartiallyRevocable)
 		if !ok {
@@ -616,19 +1110,55 @@ func updatePrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, g
 		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
 			return err
 		}
+	} else if revokeGrantOption {
+		// Only the grant option was removed, revoke just that
+		revoker, ok := grant.(GrantOptionRevocable)
+		if !ok {
+			return fmt.Errorf("grant does not support revoking the grant option")
+		}
+		stmts := revoker.SQLRevokeGrantOptionStatements(mariaDB)
+		if len(stmts) > 1 {
+			log.Printf("[WARN] Revoking and granting %s again to drop its admin option; the account lacks the roles in between", grant.GetId())
+		}
+		for i, sqlCommand := range stmts {
+			log.Printf("[DEBUG] SQL to revoke grant option: %s", sqlCommand)
+			if i == 0 {
+				if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+					return err
+				}
+				continue
+			}
+			// The statements after the first grant back what it revoked.
+			if err := execRegrant(ctx, db, sqlCommand); err != nil {
+				return fmt.Errorf("revoked %s to drop its admin option, but failed granting it again, so the account lacks it until the next apply: %v", grant.GetId(), err)
+			}
+		}
 	}
+	return nil
+}
 
-	// Do a full grant if anything has been added
-	if len(grantIfs) > 0 {
-		sqlCommand := grant.SQLGrantStatement()
-		log.Printf("[DEBUG] SQL to re-grant privileges: %s", sqlCommand)
+// regrantAttempts is how often a grant restoring revoked roles is tried.
+const regrantAttempts = 3
 
-		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+// execRegrant runs a grant restoring roles revoked a moment ago, retrying it,
+// as giving up leaves the account without them.
+func execRegrant(ctx context.Context, db *sql.DB, sqlCommand string) error {
+	var err error
+	for attempt := 1; attempt <= regrantAttempts; attempt++ {
+		if _, err = db.ExecContext(ctx, sqlCommand); err == nil {
+			return nil
+		}
+		log.Printf("[WARN] Failed granting again (attempt %d of %d): %v", attempt, regrantAttempts, err)
+		if attempt == regrantAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
 			return err
+		case <-time.After(time.Duration(attempt) * time.Second):
 		}
 	}
-
-	return nil
+	return err
 }
 
 func DeleteGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -637,19 +1167,36 @@ func DeleteGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
-	// Parse the grant from ResourceData
-	grant, diagErr := parseResourceFromData(d)
-	if err != nil {
-		return diagErr
+	if d.Get("update_strategy").(string) == grantUpdateRoleSwap {
+		if err := dropSwapRole(ctx, db, d.Get("user").(string), swapRoleHosts(d), d.Get("swap_role").(string)); err != nil {
+			return diag.FromErr(err)
+		}
+		return nil
 	}
 
+	for _, host := range grantHosts(d) {
+		// Parse the grant from ResourceData
+		grant, diagErr := parseResourceFromDataForHost(d, host)
+		if diagErr != nil {
+			return diagErr
+		}
+
+		if diagErr := revokeGrant(ctx, db, grant); diagErr != nil {
+			return diagErr
+		}
+	}
+
+	return nil
+}
+
+func revokeGrant(ctx context.Context, db *sql.DB, grant MySQLGrant) diag.Diagnostics {
 	// Acquire a lock for the user
 	grantCreateMutex.Lock(grant.GetUserOrRole().IDString())
 	defer grantCreateMutex.Unlock(grant.GetUserOrRole().IDString())
 
 	sqlStatement := grant.SQLRevokeStatement()
 	log.Printf("[DEBUG] SQL to delete grant: %s", sqlStatement)
-	_, err = db.ExecContext(ctx, sqlStatement)
+	_, err := db.ExecContext(ctx, sqlStatement)
 	if err != nil {
 		if !isNonExistingGrant(err) {
 			return diag.Errorf("error revoking %s: %s", sqlStatement, err)
@@ -711,7 +1258,11 @@ func ImportGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 	for _, foundGrant := range grants {
 		if foundGrant.ConflictsWithGrant(desiredGrant) {
 			res := resourceGrant().Data(nil)
-			setDataFromGrant(foundGrant, res)
+			setDataFromGrant(foundGrant, res, privilegeComparisonStrict)
+			res.Set("create_missing_database", false)
+			res.Set("validate_object_exists", false)
+			res.Set("refresh_mode", refreshAlways)
+			res.Set("update_strategy", grantUpdateInPlace)
 			if _, ok := desiredGrant.(*RoleGrant); ok {
 				/*
 					Import database and table for role grants literally for backwards compatibility.
@@ -733,7 +1284,7 @@ func ImportGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 // It is responsible for pulling any non-identifying properties (e.g. grant, tls_option) into the Terraform state
 // Identifying properties (database, table) are already set either as part of the import id or required properties
 // of the Terraform resource.
-func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData) *schema.ResourceData {
+func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData, comparison string) *schema.ResourceData {
 	if tableGrant, ok := grant.(*TablePrivilegeGrant); ok {
 		d.Set("grant", grant.GrantOption())
 		d.Set("tls_option", tableGrant.TLSOption)
@@ -757,7 +1308,7 @@ func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData) *schema.Resource
 			d.Set("privileges", grantWithPriv.GetPrivileges())
 		} else {
 			currentPrivs := setToArray(currentPriv.(*schema.Set))
-			if !arePrivilegesSetsEqual(currentPrivs, grantWithPriv.GetPrivileges()) {
+			if !privilegesMatch(currentPrivs, grantWithPriv.GetPrivileges(), grantLevel(grant), comparison) {
 				d.Set("privileges", grantWithPriv.GetPrivileges())
 			}
 		}
@@ -785,6 +1336,28 @@ func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData) *schema.Resource
 	return d
 }
 
+// setDataFromHostGrants sets the data from the grant of the first host that
+// differs from the state, so drift on any host shows up in the plan instead
+// of being hidden by the hosts read after it.
+func setDataFromHostGrants(grants []MySQLGrant, d *schema.ResourceData, comparison string) {
+	privileges := d.Get("privileges").(*schema.Set)
+	roles := d.Get("roles").(*schema.Set)
+	grantOption := d.Get("grant").(bool)
+	tlsOption := d.Get("tls_option").(string)
+
+	for _, grant := range grants {
+		setDataFromGrant(grant, d, comparison)
+		drifted := !d.Get("privileges").(*schema.Set).Equal(privileges) ||
+			!d.Get("roles").(*schema.Set).Equal(roles) ||
+			d.Get("grant").(bool) != grantOption ||
+			d.Get("tls_option").(string) != tlsOption
+		if drifted {
+			log.Printf("[INFO] GRANT for %s differs from the state", grant.GetUserOrRole().SQLString())
+			return
+		}
+	}
+}
+
 func combineGrants(grantA MySQLGrant, grantB MySQLGrant) (MySQLGrant, error) {
 	// Check if the grants cover the same user, table, database
 	// If not, throw an error because they are unmergeable
@@ -962,7 +1535,8 @@ func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
 		roles := make([]string, len(rolesStart))
 
 		for i, role := range rolesStart {
-			roles[i] = strings.Trim(role, "`@%\" ")
+			// TiDB quotes roles with ' instead of `
+			roles[i] = strings.Trim(role, "`'@%\" ")
 		}
 
 		userOrRole, err := parseUserOrRoleFromRow(roleMatches[2])
@@ -1142,6 +1716,9 @@ func normalizePerms(perms []string) []string {
 		if kReAllPrivileges.MatchString(strings.ToUpper(permNorm)) {
 			permNorm = "ALL PRIVILEGES"
 		}
+		if aurora := normalizeAuroraPrivilege(permNorm); aurora != "" {
+			permNorm = aurora
+		}
 		permSortedColumns := normalizeColumnOrder(permNorm)
 
 		ret = append(ret, permSortedColumns)
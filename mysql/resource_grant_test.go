@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"math/rand"
@@ -52,6 +53,174 @@ func TestAccGrant(t *testing.T) {
 	})
 }
 
+func TestAccGrant_createMissingDatabase(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-missing-%d", rand.Intn(100))
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if err := testAccGrantCheckDestroy(s); err != nil {
+				return err
+			}
+			// The created database isn't managed by the grant.
+			ctx := context.Background()
+			db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+			if err != nil {
+				return err
+			}
+			_, err = db.ExecContext(ctx, "DROP DATABASE IF EXISTS "+quoteIdentifier(dbName))
+			return err
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfigCreateMissingDatabase(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					func(s *terraform.State) error {
+						ctx := context.Background()
+						db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+						if err != nil {
+							return err
+						}
+						exists, err := queryHasRows(ctx, db, "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ? AND DEFAULT_CHARACTER_SET_NAME = 'latin1'", dbName)
+						if err != nil {
+							return err
+						}
+						if !exists {
+							return fmt.Errorf("database %s wasn't created", dbName)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccGrant_hosts(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfigHosts(dbName, `"10.0.1.%", "10.0.2.%"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					resource.TestCheckResourceAttr("mysql_user.test", "hosts.#", "2"),
+					resource.TestCheckResourceAttr("mysql_grant.test", "hosts.#", "2"),
+				),
+			},
+			{
+				Config: testAccGrantConfigHosts(dbName, `"10.0.1.%", "10.0.2.%", "10.0.3.%"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					resource.TestCheckResourceAttr("mysql_user.test", "hosts.#", "3"),
+					resource.TestCheckResourceAttr("mysql_grant.test", "hosts.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func testAccGrantConfigHosts(dbName string, hosts string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  hosts    = [%s]
+  password = "password"
+}
+
+resource "mysql_grant" "test" {
+  user       = mysql_user.test.user
+  hosts      = mysql_user.test.hosts
+  database   = mysql_database.test.name
+  privileges = ["SELECT"]
+}
+`, dbName, dbName, hosts)
+}
+
+func TestAccGrant_defaultDatabase(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "mysql" {
+  default_database = "%s"
+}
+
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  host     = "example.com"
+  password = "password"
+}
+
+resource "mysql_grant" "test" {
+  user       = mysql_user.test.user
+  host       = mysql_user.test.host
+  privileges = ["SELECT"]
+
+  depends_on = [mysql_database.test]
+}
+`, dbName, dbName, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					resource.TestCheckResourceAttr("mysql_grant.test", "database", dbName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGrant_bundle(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  host     = "example.com"
+  password = "password"
+}
+
+resource "mysql_grant" "test" {
+  user     = mysql_user.test.user
+  host     = mysql_user.test.host
+  database = mysql_database.test.name
+  bundle   = "read_only_analyst"
+}
+`, dbName, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					testAccPrivilege("mysql_grant.test", "SHOW VIEW", true, false),
+					resource.TestCheckResourceAttr("mysql_grant.test", "privileges.#", "2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRevokePrivRefresh(t *testing.T) {
 	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
 
@@ -902,6 +1071,24 @@ resource "mysql_grant" "test" {
 `, dbName, dbName)
 }
 
+func testAccGrantConfigCreateMissingDatabase(dbName string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  host     = "example.com"
+}
+
+resource "mysql_grant" "test" {
+  user                    = mysql_user.test.user
+  host                    = mysql_user.test.host
+  database                = "%s"
+  privileges              = ["SELECT"]
+  create_missing_database = true
+  default_character_set   = "latin1"
+}
+`, dbName, dbName)
+}
+
 func testAccGrantConfigExtraHost(dbName string, extraHost bool) string {
 	extra := ""
 	if extraHost {
@@ -1151,6 +1338,166 @@ WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = 'PROCEDURE'
 	}
 }
 
+func TestAccGrantOnMissingTable(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+
+	resource.Test(t, resource.TestCase{
+		// MariaDB and TiDB refuse grants on tables that don't exist.
+		PreCheck:          func() { testAccPreCheckSkipMariaDB(t); testAccPreCheckSkipTiDB(t); testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccGrantConfigMissingTable(dbName, true),
+				ExpectError: regexp.MustCompile("doesn't exist"),
+			},
+			{
+				Config: testAccGrantConfigMissingTable(dbName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					resource.TestCheckResourceAttr("mysql_grant.test", "table", "future_table"),
+				),
+			},
+			{
+				// Creating and dropping the table doesn't affect the grant.
+				PreConfig: func() {
+					db, err := connectToMySQL(context.Background(), testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec(fmt.Sprintf("CREATE TABLE `%s`.`future_table` (id INT)", dbName)); err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec(fmt.Sprintf("DROP TABLE `%s`.`future_table`", dbName)); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:   testAccGrantConfigMissingTable(dbName, false),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccGrant_recreatedUser(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	userName := fmt.Sprintf("jdoe-%s", dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipRds(t); testAccPreCheckSkipTiDB(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfigBasic(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					resource.TestCheckResourceAttrSet("mysql_grant.test", "grantee_fingerprint"),
+				),
+			},
+			{
+				// Dropping the user outside of Terraform drops its privileges;
+				// they are granted again once mysql_user recreates it.
+				PreConfig: func() {
+					db, err := connectToMySQL(context.Background(), testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec(fmt.Sprintf("DROP USER '%s'@'example.com'", userName)); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccGrantConfigBasic(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGrant_roleSwap(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	var firstRole string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipNotMySQL8(t); testAccPreCheckSkipTiDB(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfigRoleSwap(dbName, `"SELECT"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttrWith("mysql_grant.test", "swap_role", func(value string) error {
+						firstRole = value
+						return testAccRoleHasGrant(value, dbName, "SELECT")
+					}),
+				),
+			},
+			{
+				Config: testAccGrantConfigRoleSwap(dbName, `"SELECT", "UPDATE"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "privileges.#", "2"),
+					resource.TestCheckResourceAttrWith("mysql_grant.test", "swap_role", func(value string) error {
+						if value == firstRole {
+							return fmt.Errorf("swap_role %s didn't change", value)
+						}
+						if err := testAccRoleHasGrant(firstRole, dbName, "SELECT"); err == nil {
+							return fmt.Errorf("previous role %s still exists", firstRole)
+						}
+						return testAccRoleHasGrant(value, dbName, "UPDATE")
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccRoleHasGrant(role, dbName, privilege string) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SHOW GRANTS FOR '%s'", role))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return err
+		}
+		if strings.Contains(grant, privilege) && strings.Contains(grant, dbName) {
+			return nil
+		}
+	}
+	return fmt.Errorf("role %s has no %s on %s", role, privilege, dbName)
+}
+
+func testAccGrantConfigRoleSwap(dbName, privileges string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  host     = "example.com"
+}
+
+resource "mysql_grant" "test" {
+  user            = mysql_user.test.user
+  host            = mysql_user.test.host
+  database        = mysql_database.test.name
+  privileges      = [%s]
+  update_strategy = "role_swap"
+}
+`, dbName, dbName, privileges)
+}
+
 func TestAccGrantOnProcedure(t *testing.T) {
 	procedureName := "test_procedure"
 	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
@@ -1566,3 +1913,136 @@ func getAdditionalGrantSample(dbName string, privileges string) string {
     }
     `, dbName, privileges)
 }
+
+func TestSQLRevokeGrantOptionStatements(t *testing.T) {
+	user := UserOrRole{Name: "jdoe", Host: "%"}
+	cases := []struct {
+		grant    GrantOptionRevocable
+		mariaDB  bool
+		expected []string
+	}{
+		{
+			grant:    &TablePrivilegeGrant{Database: "db", Table: "tbl", Privileges: []string{"SELECT"}, UserOrRole: user},
+			expected: []string{"REVOKE GRANT OPTION ON `db`.`tbl` FROM 'jdoe'@'%'"},
+		},
+		{
+			grant:    &ProcedurePrivilegeGrant{Database: "db", ObjectT: ObjectT("PROCEDURE"), CallableName: "proc", Privileges: []string{"EXECUTE"}, UserOrRole: user},
+			expected: []string{"REVOKE GRANT OPTION ON PROCEDURE `db`.`proc` FROM 'jdoe'@'%'"},
+		},
+		{
+			grant:    &RoleGrant{Roles: []string{"r1", "r2"}, UserOrRole: user},
+			expected: []string{"REVOKE 'r1', 'r2' FROM 'jdoe'@'%'", "GRANT 'r1', 'r2' TO 'jdoe'@'%'"},
+		},
+		{
+			grant:    &RoleGrant{Roles: []string{"r1"}, UserOrRole: user},
+			mariaDB:  true,
+			expected: []string{"REVOKE ADMIN OPTION FOR 'r1' FROM 'jdoe'@'%'"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := c.grant.SQLRevokeGrantOptionStatements(c.mariaDB)
+		if strings.Join(actual, "; ") != strings.Join(c.expected, "; ") {
+			t.Errorf("expected %q, got %q", c.expected, actual)
+		}
+	}
+}
+
+func TestParseRoleGrantFromRow(t *testing.T) {
+	for _, row := range []string{
+		"GRANT `role1`@`%`,`role2`@`%` TO `jdoe`@`%`",
+		// TiDB
+		"GRANT 'role1'@'%', 'role2'@'%' TO 'jdoe'@'%'",
+	} {
+		grant, err := parseGrantFromRow(row)
+		if err != nil {
+			t.Fatalf("parseGrantFromRow(%q) failed: %v", row, err)
+		}
+		roleGrant, ok := grant.(*RoleGrant)
+		if !ok {
+			t.Fatalf("parseGrantFromRow(%q) returned %T", row, grant)
+		}
+		if strings.Join(roleGrant.Roles, ",") != "role1,role2" || roleGrant.UserOrRole.Name != "jdoe" {
+			t.Errorf("parseGrantFromRow(%q) = %+v", row, roleGrant)
+		}
+	}
+}
+
+func TestSetDataFromHostGrants(t *testing.T) {
+	grant := func(host string, privileges ...string) MySQLGrant {
+		return &TablePrivilegeGrant{Database: "db", Table: "*", Privileges: privileges, UserOrRole: UserOrRole{Name: "jdoe", Host: host}}
+	}
+	for _, grants := range [][]MySQLGrant{
+		{grant("a", "SELECT"), grant("b", "SELECT", "UPDATE")},
+		{grant("a", "SELECT", "UPDATE"), grant("b", "SELECT")},
+	} {
+		d := resourceGrant().TestResourceData()
+		d.Set("privileges", []string{"SELECT", "UPDATE"})
+		setDataFromHostGrants(grants, d, privilegeComparisonStrict)
+		if got := setToArray(d.Get("privileges")); len(got) != 1 || got[0] != "SELECT" {
+			t.Errorf("privileges = %q, want the ones of the host missing UPDATE", got)
+		}
+	}
+}
+
+func TestParseAuroraGrantFromRow(t *testing.T) {
+	grant, err := parseGrantFromRow("GRANT SELECT, LOAD FROM S3, SELECT INTO S3, INVOKE LAMBDA ON *.* TO `loader`@`%`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableGrant, ok := grant.(*TablePrivilegeGrant)
+	if !ok {
+		t.Fatalf("got %T", grant)
+	}
+	if !arePrivilegesSetsEqual(tableGrant.Privileges, []string{"load  from s3", "SELECT", "invoke lambda", "SELECT INTO S3"}) {
+		t.Errorf("got privileges %q", tableGrant.Privileges)
+	}
+}
+
+func TestNormalizeVerifiedName(t *testing.T) {
+	for _, tt := range []struct{ a, b string }{
+		{"select", "SELECT"},
+		{"REPLICATION CLIENT", "BINLOG MONITOR"},
+		{"role1", "`role1`@`%`"},
+		{"role1", "'role1'@'%'"},
+	} {
+		if normalizeVerifiedName(tt.a) != normalizeVerifiedName(tt.b) {
+			t.Errorf("expected %q and %q to match, got %q and %q", tt.a, tt.b, normalizeVerifiedName(tt.a), normalizeVerifiedName(tt.b))
+		}
+	}
+}
+
+func testAccGrantConfigMissingTable(dbName string, validate bool) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%[1]s"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe-%[1]s"
+  host = "example.com"
+}
+
+resource "mysql_grant" "test" {
+  user                   = mysql_user.test.user
+  host                   = mysql_user.test.host
+  database               = mysql_database.test.name
+  table                  = "future_table"
+  privileges             = ["SELECT"]
+  validate_object_exists = %[2]t
+}
+`, dbName, validate)
+}
+
+func TestExecRegrantRetries(t *testing.T) {
+	server := &fakeFailoverServer{failures: 1}
+	db := sql.OpenDB(server)
+	defer db.Close()
+
+	if err := execRegrant(context.Background(), db, "GRANT 'r1' TO 'jdoe'@'%'"); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.executed) != 1 || server.executed[0] != "GRANT 'r1' TO 'jdoe'@'%'" {
+		t.Errorf("expected the grant to run again, got %q", server.executed)
+	}
+}
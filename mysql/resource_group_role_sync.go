@@ -0,0 +1,314 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceGroupRoleSync() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateGroupRoleSync,
+		UpdateContext: UpdateGroupRoleSync,
+		ReadContext:   ReadGroupRoleSync,
+		DeleteContext: DeleteGroupRoleSync,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return checkTiDBRoleSupport(ctx, meta, false)
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_roles": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Maps directory groups to the role granted to their members",
+			},
+			"membership": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"user": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "%",
+						},
+					},
+				},
+			},
+			"skip_missing_users": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Skip members without a MySQL account instead of failing; they get the role once the account exists",
+			},
+		},
+	}
+}
+
+// groupRoleGrant is a role granted to an account because of a group
+// membership.
+type groupRoleGrant struct {
+	role string
+	user string
+	host string
+}
+
+func (g groupRoleGrant) account() string {
+	return formatUserIdentifier(g.user, g.host)
+}
+
+func (g groupRoleGrant) key() string {
+	return g.role + "\x00" + g.account()
+}
+
+// groupRoleGrants returns the role grants the memberships of mapped groups
+// require, by key. Memberships of unmapped groups are ignored.
+func groupRoleGrants(groupRoles map[string]interface{}, memberships []interface{}) map[string]groupRoleGrant {
+	grants := map[string]groupRoleGrant{}
+	for _, v := range memberships {
+		m := v.(map[string]interface{})
+		role, ok := groupRoles[m["group"].(string)]
+		if !ok || role.(string) == "" {
+			continue
+		}
+		g := groupRoleGrant{role: role.(string), user: m["user"].(string), host: m["host"].(string)}
+		grants[g.key()] = g
+	}
+	return grants
+}
+
+func groupRoleGrantsFromData(groupRoles, memberships interface{}) map[string]groupRoleGrant {
+	return groupRoleGrants(groupRoles.(map[string]interface{}), memberships.(*schema.Set).List())
+}
+
+// sortedGroupRoleGrants returns the grants of the keys, ordered by role and
+// account.
+func sortedGroupRoleGrants(grants map[string]groupRoleGrant, keys map[string]bool) []groupRoleGrant {
+	sorted := make([]groupRoleGrant, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, grants[key])
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].key() < sorted[j].key()
+	})
+	return sorted
+}
+
+// groupRoleStatements returns GRANT or REVOKE statements for the grants,
+// handling all accounts of a role in one statement.
+func groupRoleStatements(revoke bool, grants []groupRoleGrant) []string {
+	var roles []string
+	accounts := map[string][]string{}
+	for _, g := range grants {
+		if _, ok := accounts[g.role]; !ok {
+			roles = append(roles, g.role)
+		}
+		accounts[g.role] = append(accounts[g.role], g.account())
+	}
+
+	stmts := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if revoke {
+			stmts = append(stmts, fmt.Sprintf("REVOKE '%s' FROM %s", role, strings.Join(accounts[role], ", ")))
+		} else {
+			stmts = append(stmts, fmt.Sprintf("GRANT '%s' TO %s", role, strings.Join(accounts[role], ", ")))
+		}
+	}
+	return stmts
+}
+
+// readRoleHolders returns the keys of all role grants to accounts.
+func readRoleHolders(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	isMariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return nil, err
+	}
+	stmtSQL := "SELECT FROM_USER, TO_USER, TO_HOST FROM mysql.role_edges"
+	if isMariaDB {
+		stmtSQL = "SELECT Role, User, Host FROM mysql.roles_mapping"
+	}
+
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holders := map[string]bool{}
+	for rows.Next() {
+		var g groupRoleGrant
+		if err := rows.Scan(&g.role, &g.user, &g.host); err != nil {
+			return nil, err
+		}
+		holders[g.key()] = true
+	}
+	return holders, rows.Err()
+}
+
+func readAccounts(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	stmtSQL := "SELECT User, Host FROM mysql.user"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := map[string]bool{}
+	for rows.Next() {
+		var user, host string
+		if err := rows.Scan(&user, &host); err != nil {
+			return nil, err
+		}
+		accounts[formatUserIdentifier(user, host)] = true
+	}
+	return accounts, rows.Err()
+}
+
+// reconcileGroupRoles revokes the old grants the new ones don't have and
+// grants the new ones accounts are missing. Only grants held on the server
+// are revoked, so accounts dropped meanwhile don't fail the apply.
+func reconcileGroupRoles(ctx context.Context, db *sql.DB, old, new map[string]groupRoleGrant, skipMissingUsers bool) error {
+	holders, err := readRoleHolders(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed reading role grants: %v", err)
+	}
+	accounts, err := readAccounts(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed reading users: %v", err)
+	}
+
+	revoked := map[string]bool{}
+	for key := range old {
+		if _, ok := new[key]; !ok && holders[key] {
+			revoked[key] = true
+		}
+	}
+	granted := map[string]bool{}
+	for key, g := range new {
+		if holders[key] {
+			continue
+		}
+		if !accounts[g.account()] {
+			if skipMissingUsers {
+				log.Printf("[WARN] User %s doesn't exist, not granting it role %s", g.account(), g.role)
+				continue
+			}
+			return fmt.Errorf("user %s of role %s doesn't exist", g.account(), g.role)
+		}
+		granted[key] = true
+	}
+
+	stmts := groupRoleStatements(true, sortedGroupRoleGrants(old, revoked))
+	stmts = append(stmts, groupRoleStatements(false, sortedGroupRoleGrants(new, granted))...)
+	for _, stmtSQL := range stmts {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed executing %s: %v", stmtSQL, err)
+		}
+	}
+	return nil
+}
+
+func CreateGroupRoleSync(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grants := groupRoleGrantsFromData(d.Get("group_roles"), d.Get("membership"))
+	if err := reconcileGroupRoles(ctx, db, nil, grants, d.Get("skip_missing_users").(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id.UniqueId())
+	return ReadGroupRoleSync(ctx, d, meta)
+}
+
+func UpdateGroupRoleSync(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	oldGroupRoles, newGroupRoles := d.GetChange("group_roles")
+	oldMemberships, newMemberships := d.GetChange("membership")
+	old := groupRoleGrantsFromData(oldGroupRoles, oldMemberships)
+	new := groupRoleGrantsFromData(newGroupRoles, newMemberships)
+	if err := reconcileGroupRoles(ctx, db, old, new, d.Get("skip_missing_users").(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return ReadGroupRoleSync(ctx, d, meta)
+}
+
+// ReadGroupRoleSync removes memberships whose role grant is missing from the
+// state, so the next apply grants it again. Memberships of users without an
+// account are kept when skip_missing_users is set.
+func ReadGroupRoleSync(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	holders, err := readRoleHolders(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed reading role grants: %v", err)
+	}
+	accounts, err := readAccounts(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed reading users: %v", err)
+	}
+	skipMissingUsers := d.Get("skip_missing_users").(bool)
+
+	groupRoles := d.Get("group_roles").(map[string]interface{})
+	var memberships []interface{}
+	for _, v := range d.Get("membership").(*schema.Set).List() {
+		grants := groupRoleGrants(groupRoles, []interface{}{v})
+		for _, g := range grants {
+			if !holders[g.key()] && (accounts[g.account()] || !skipMissingUsers) {
+				log.Printf("[WARN] User %s is missing role %s, removing its membership from state", g.account(), g.role)
+				v = nil
+			}
+		}
+		if v != nil {
+			memberships = append(memberships, v)
+		}
+	}
+	if err := d.Set("membership", memberships); err != nil {
+		return diag.Errorf("failed setting membership field: %v", err)
+	}
+
+	return nil
+}
+
+func DeleteGroupRoleSync(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grants := groupRoleGrantsFromData(d.Get("group_roles"), d.Get("membership"))
+	if err := reconcileGroupRoles(ctx, db, grants, nil, true); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGroupRoleSync(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQL8(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupRoleSyncConfig(`"jdoe-sync1", "jdoe-sync2"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupRoleSyncHasRole("jdoe-sync1", true),
+					testAccGroupRoleSyncHasRole("jdoe-sync2", true),
+				),
+			},
+			{
+				Config: testAccGroupRoleSyncConfig(`"jdoe-sync2", "jdoe-missing"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupRoleSyncHasRole("jdoe-sync1", false),
+					testAccGroupRoleSyncHasRole("jdoe-sync2", true),
+					resource.TestCheckResourceAttr("mysql_group_role_sync.test", "membership.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestGroupRoleGrants(t *testing.T) {
+	groupRoles := map[string]interface{}{"dba": "admin", "dev": "developer"}
+	memberships := []interface{}{
+		map[string]interface{}{"group": "dba", "user": "alice", "host": "%"},
+		map[string]interface{}{"group": "dev", "user": "alice", "host": "%"},
+		map[string]interface{}{"group": "dev", "user": "bob", "host": "%"},
+		map[string]interface{}{"group": "sales", "user": "carol", "host": "%"},
+	}
+	grants := groupRoleGrants(groupRoles, memberships)
+	if len(grants) != 3 {
+		t.Fatalf("expected 3 grants, got %v", grants)
+	}
+
+	keys := map[string]bool{}
+	for key := range grants {
+		keys[key] = true
+	}
+	got := groupRoleStatements(false, sortedGroupRoleGrants(grants, keys))
+	expected := []string{
+		"GRANT 'admin' TO `alice`@`%`",
+		"GRANT 'developer' TO `alice`@`%`, `bob`@`%`",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected statements %q", got)
+	}
+}
+
+func testAccGroupRoleSyncHasRole(user string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+		holders, err := readRoleHolders(ctx, db)
+		if err != nil {
+			return err
+		}
+		g := groupRoleGrant{role: "tf-test-sync-role", user: user, host: "%"}
+		if holders[g.key()] != expected {
+			return fmt.Errorf("expected %s to have role %s: %v", g.account(), g.role, expected)
+		}
+		return nil
+	}
+}
+
+func testAccGroupRoleSyncConfig(members string) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = "tf-test-sync-role"
+}
+
+resource "mysql_user" "test" {
+  for_each = toset(["jdoe-sync1", "jdoe-sync2"])
+  user     = each.key
+  host     = "%%"
+}
+
+resource "mysql_group_role_sync" "test" {
+  group_roles = {
+    "cn=dba,ou=groups" = mysql_role.test.name
+  }
+
+  dynamic "membership" {
+    for_each = [%s]
+    content {
+      group = "cn=dba,ou=groups"
+      user  = membership.value
+    }
+  }
+
+  depends_on = [mysql_user.test]
+}
+`, members)
+}
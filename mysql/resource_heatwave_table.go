@@ -0,0 +1,228 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// rpd_tables reports AVAIL_RPDGSTABSTATE once a table is fully loaded.
+const heatwaveTableLoaded = "AVAIL_RPDGSTABSTATE"
+
+var kReSecondaryEngine = regexp.MustCompile(`(?i)SECONDARY_ENGINE="?([^" ]+)"?`)
+
+func resourceHeatwaveTable() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateHeatwaveTable,
+		UpdateContext: UpdateHeatwaveTable,
+		ReadContext:   ReadHeatwaveTable,
+		DeleteContext: DeleteHeatwaveTable,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportHeatwaveTable,
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return setDefaultDatabase(d, meta, "")
+		},
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"table": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"secondary_engine": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "RAPID",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z_]+$`), "must be an engine name"),
+			},
+			"loaded": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the table is loaded into the HeatWave cluster (SECONDARY_LOAD)",
+			},
+		},
+	}
+}
+
+// heatwaveAttached reports whether a HeatWave cluster is attached and ready.
+func heatwaveAttached(ctx context.Context, db *sql.DB) (bool, error) {
+	active, err := pluginActive(ctx, db, "RAPID")
+	if err != nil || !active {
+		return false, err
+	}
+	return queryHasRows(ctx, db, "SHOW GLOBAL STATUS WHERE Variable_name = 'rapid_cluster_status' AND Value = 'ON'")
+}
+
+func heatwaveTableIdentifier(d *schema.ResourceData) string {
+	return fmt.Sprintf("%s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("table").(string)))
+}
+
+func execHeatwaveStatements(ctx context.Context, db *sql.DB, stmts ...string) error {
+	for _, stmtSQL := range stmts {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed executing %s: %v", stmtSQL, err)
+		}
+	}
+	return nil
+}
+
+func CreateHeatwaveTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	attached, err := heatwaveAttached(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed checking HeatWave status: %v", err)
+	}
+	if !attached && d.Get("loaded").(bool) {
+		return diag.Errorf("no HeatWave cluster is attached to the DB system, so the table can't be loaded")
+	}
+
+	table := heatwaveTableIdentifier(d)
+	stmts := []string{fmt.Sprintf("ALTER TABLE %s SECONDARY_ENGINE = %s", table, d.Get("secondary_engine").(string))}
+	if d.Get("loaded").(bool) {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SECONDARY_LOAD", table))
+	}
+
+	if err := execHeatwaveStatements(ctx, db, stmts...); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", d.Get("database").(string), d.Get("table").(string)))
+
+	return ReadHeatwaveTable(ctx, d, meta)
+}
+
+func UpdateHeatwaveTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	table := heatwaveTableIdentifier(d)
+	oldLoaded, _ := d.GetChange("loaded")
+
+	var stmts []string
+	if d.HasChange("secondary_engine") {
+		// The secondary engine can't be changed while the table is loaded.
+		if oldLoaded.(bool) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SECONDARY_UNLOAD", table))
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SECONDARY_ENGINE = %s", table, d.Get("secondary_engine").(string)))
+		if d.Get("loaded").(bool) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SECONDARY_LOAD", table))
+		}
+	} else if d.HasChange("loaded") {
+		if d.Get("loaded").(bool) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SECONDARY_LOAD", table))
+		} else {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SECONDARY_UNLOAD", table))
+		}
+	}
+
+	if err := execHeatwaveStatements(ctx, db, stmts...); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return ReadHeatwaveTable(ctx, d, meta)
+}
+
+func ReadHeatwaveTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	var createOptions string
+	stmtSQL := "SELECT CREATE_OPTIONS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	err = db.QueryRowContext(ctx, stmtSQL, database, table).Scan(&createOptions)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] Table %s not found - removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading table options: %v", err)
+	}
+
+	m := kReSecondaryEngine.FindStringSubmatch(createOptions)
+	if m == nil {
+		log.Printf("[WARN] Table %s has no secondary engine - removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	d.Set("secondary_engine", strings.ToUpper(m[1]))
+
+	// rpd_* tables only exist when the RAPID plugin is installed.
+	attached, err := heatwaveAttached(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed checking HeatWave status: %v", err)
+	}
+	loaded := false
+	if attached {
+		loaded, err = queryHasRows(ctx, db,
+			"SELECT 1 FROM performance_schema.rpd_tables t JOIN performance_schema.rpd_table_id i ON t.ID = i.ID WHERE i.SCHEMA_NAME = ? AND i.TABLE_NAME = ? AND t.LOAD_STATUS = ?",
+			database, table, heatwaveTableLoaded)
+		if err != nil {
+			return diag.Errorf("failed reading HeatWave load status: %v", err)
+		}
+	}
+	d.Set("loaded", loaded)
+
+	return nil
+}
+
+func DeleteHeatwaveTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	table := heatwaveTableIdentifier(d)
+	if d.Get("loaded").(bool) {
+		if err := execHeatwaveStatements(ctx, db, fmt.Sprintf("ALTER TABLE %s SECONDARY_UNLOAD", table)); err != nil {
+			log.Printf("[WARN] %v", err)
+		}
+	}
+
+	if err := execHeatwaveStatements(ctx, db, fmt.Sprintf("ALTER TABLE %s SECONDARY_ENGINE = NULL", table)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportHeatwaveTable(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ".", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("wrong ID format %s (expected DATABASE.TABLE)", d.Id())
+	}
+
+	d.Set("database", parts[0])
+	d.Set("table", parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func testAccPreCheckRequireHeatwave(t *testing.T) {
+	testAccPreCheck(t)
+
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		t.Fatalf("Cannot connect to DB (RequireHeatwave): %v", err)
+	}
+
+	attached, err := heatwaveAttached(ctx, db)
+	if err != nil {
+		t.Fatalf("Cannot check HeatWave status: %v", err)
+	}
+	if !attached {
+		t.Skip("Skip on servers without HeatWave")
+	}
+}
+
+func TestAccHeatwaveTable_basic(t *testing.T) {
+	resourceName := "mysql_heatwave_table.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheckRequireHeatwave(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHeatwaveTableConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "secondary_engine", "RAPID"),
+					resource.TestCheckResourceAttr(resourceName, "loaded", "true"),
+					resource.TestCheckResourceAttr("data.mysql_heatwave.test", "attached", "true"),
+				),
+			},
+			{
+				Config: testAccHeatwaveTableConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "loaded", "false"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "tf_heatwave_test.orders",
+			},
+		},
+	})
+}
+
+func testAccHeatwaveTableConfig(loaded bool) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "tf_heatwave_test"
+}
+
+resource "mysql_sql" "table" {
+  name       = "heatwave_table"
+  create_sql = "CREATE TABLE ${mysql_database.test.name}.orders (id INT PRIMARY KEY, amount DECIMAL(10,2))"
+  delete_sql = "DROP TABLE ${mysql_database.test.name}.orders"
+}
+
+resource "mysql_heatwave_table" "test" {
+  database = mysql_database.test.name
+  table    = "orders"
+  loaded   = %t
+
+  depends_on = [mysql_sql.table]
+}
+
+data "mysql_heatwave" "test" {}
+`, loaded)
+}
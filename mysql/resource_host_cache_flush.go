@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceHostCacheFlush() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateHostCacheFlush,
+		ReadContext:   ReadHostCacheFlush,
+		DeleteContext: DeleteHostCacheFlush,
+
+		Schema: map[string]*schema.Schema{
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that flush the host cache again when changed",
+			},
+		},
+	}
+}
+
+// CreateHostCacheFlush empties the host cache, unblocking hosts that reached
+// max_connect_errors. FLUSH HOSTS is deprecated since MySQL 8.0.23, so the
+// performance_schema table is truncated instead when it's available.
+func CreateHostCacheFlush(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	isMariaDB, err := serverMariaDB(db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	flushed := false
+	if !isMariaDB {
+		stmtSQL := "TRUNCATE TABLE performance_schema.host_cache"
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			log.Printf("[WARN] Failed truncating host_cache, falling back to FLUSH HOSTS: %v", err)
+		} else {
+			flushed = true
+		}
+	}
+	if !flushed {
+		stmtSQL := "FLUSH HOSTS"
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed flushing host cache: %v", err)
+		}
+	}
+
+	d.SetId(id.UniqueId())
+	return nil
+}
+
+// ReadHostCacheFlush does nothing, the flush is a one-off action repeated
+// only when triggers change.
+func ReadHostCacheFlush(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func DeleteHostCacheFlush(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
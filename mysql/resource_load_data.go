@@ -0,0 +1,403 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// loadDataHandlers numbers the reader handlers of LOAD DATA LOCAL INFILE.
+var loadDataHandlers atomic.Uint64
+
+func resourceLoadData() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateLoadData,
+		UpdateContext: UpdateLoadData,
+		ReadContext:   ReadLoadData,
+		DeleteContext: DeleteLoadData,
+
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := setDefaultDatabase(d, meta, ""); err != nil {
+				return err
+			}
+			return diffSourceChecksum(ctx, d)
+		},
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"table": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Path or http(s)://, s3:// or gs:// URL of the CSV file",
+			},
+			"columns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Columns the CSV fields are loaded into, all columns in table order by default",
+			},
+			"field_separator": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ",",
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 1),
+			},
+			"header": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Skip the first line of the file",
+			},
+			"on_duplicate": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "error",
+				ValidateFunc: validation.StringInSlice([]string{"error", "replace", "ignore"}, false),
+			},
+			"chunk_rows": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10000,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"truncate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Empty the table before loading",
+			},
+			"truncate_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"checksum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 of the loaded file; the file is loaded again when it changes",
+			},
+			"source_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ETag or generation of the loaded remote file; plans read the file only when it changes",
+			},
+			"rows_loaded": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"method": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "LOAD DATA LOCAL INFILE, or INSERT when the server doesn't allow it",
+			},
+		},
+	}
+}
+
+// diffSourceChecksum loads the source again when its content changed. Remote
+// sources are only read when their ETag or generation changed, or when the
+// server doesn't report one.
+func diffSourceChecksum(ctx context.Context, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("source") {
+		if err := d.SetNewComputed("checksum"); err != nil {
+			return err
+		}
+		if d.Id() != "" {
+			return d.ForceNew("checksum")
+		}
+		return nil
+	}
+
+	source := d.Get("source").(string)
+	version, err := sourceVersion(ctx, source)
+	if err != nil {
+		return fmt.Errorf("failed reading source: %v", err)
+	}
+	if d.Id() != "" && version != "" && !d.HasChange("source") && version == d.Get("source_version").(string) {
+		return nil
+	}
+
+	content, _, err := fetchSource(ctx, source)
+	if err != nil {
+		return fmt.Errorf("failed reading source: %v", err)
+	}
+	checksum := hashSum(string(content))
+	if old, _ := d.GetChange("checksum"); old.(string) == checksum {
+		// The file was uploaded again with the same content.
+		if version != d.Get("source_version").(string) {
+			return d.SetNew("source_version", version)
+		}
+		return nil
+	}
+	if err := d.SetNew("checksum", checksum); err != nil {
+		return err
+	}
+	if d.Id() != "" {
+		return d.ForceNew("checksum")
+	}
+	return nil
+}
+
+func CreateLoadData(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	table := quoteIdentifier(database) + "." + quoteIdentifier(d.Get("table").(string))
+
+	content, version, err := fetchSource(ctx, d.Get("source").(string))
+	if err != nil {
+		return diag.Errorf("failed reading source: %v", err)
+	}
+	chunks, rows, err := csvChunks(content, d.Get("field_separator").(string), d.Get("header").(bool), d.Get("chunk_rows").(int))
+	if err != nil {
+		return diag.Errorf("failed parsing %s: %v", d.Get("source").(string), err)
+	}
+
+	if d.Get("truncate").(bool) {
+		stmtSQL := "TRUNCATE TABLE " + table
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed truncating %s: %v", table, err)
+		}
+	}
+
+	localInfile, err := localInfileAllowed(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed checking local_infile: %v", err)
+	}
+
+	// The chunks are loaded in one transaction, so a failed chunk doesn't
+	// leave the ones before it behind. TRUNCATE commits on its own, though.
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return diag.Errorf("failed starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	loader := &dataLoader{
+		db:          tx,
+		table:       table,
+		columns:     toStringList(d.Get("columns")),
+		onDuplicate: d.Get("on_duplicate").(string),
+	}
+	load := loader.insert
+	method := "INSERT"
+	if localInfile {
+		load = loader.loadDataLocal
+		method = "LOAD DATA LOCAL INFILE"
+	}
+
+	for i, chunk := range chunks {
+		if err := load(ctx, chunk); err != nil {
+			return diag.Errorf("failed loading chunk %d of %d into %s, rolled back all chunks: %v", i+1, len(chunks), table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return diag.Errorf("failed committing rows loaded into %s: %v", table, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", database, d.Get("table").(string)))
+	d.Set("checksum", hashSum(string(content)))
+	d.Set("source_version", version)
+	d.Set("rows_loaded", rows)
+	d.Set("method", method)
+
+	return ReadLoadData(ctx, d, meta)
+}
+
+// UpdateLoadData only stores settings that apply to the next load.
+func UpdateLoadData(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return ReadLoadData(ctx, d, meta)
+}
+
+func ReadLoadData(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	exists, err := queryHasRows(ctx, db, stmtSQL, d.Get("database").(string), d.Get("table").(string))
+	if err != nil {
+		return diag.Errorf("failed reading table: %v", err)
+	}
+	if !exists {
+		log.Printf("[WARN] Table %s doesn't exist - removing loaded data from state", d.Id())
+		d.SetId("")
+	}
+	return nil
+}
+
+func DeleteLoadData(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if !d.Get("truncate_on_destroy").(bool) {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "TRUNCATE TABLE " + quoteIdentifier(d.Get("database").(string)) + "." + quoteIdentifier(d.Get("table").(string))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil && mysqlErrorNumber(err) != noSuchTableErrCode {
+		return diag.Errorf("failed truncating table: %v", err)
+	}
+	return nil
+}
+
+// localInfileAllowed reports whether the server accepts LOAD DATA LOCAL
+// INFILE. Otherwise rows are inserted, which secure_file_priv and
+// local_infile don't restrict.
+func localInfileAllowed(ctx context.Context, db *sql.DB) (bool, error) {
+	var localInfile sql.NullString
+	err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.local_infile").Scan(&localInfile)
+	if mysqlErrorNumber(err) == unknownSystemVariableErrCode {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return localInfile.String == "1" || strings.EqualFold(localInfile.String, "ON"), nil
+}
+
+// csvChunks parses the CSV and returns its records, without the header, in
+// chunks of at most chunkRows records.
+func csvChunks(content []byte, separator string, header bool, chunkRows int) ([][][]string, int, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = rune(separator[0])
+	reader.FieldsPerRecord = -1
+
+	var chunks [][][]string
+	var chunk [][]string
+	rows := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if header {
+			header = false
+			continue
+		}
+		chunk = append(chunk, record)
+		rows++
+		if len(chunk) == chunkRows {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks, rows, nil
+}
+
+// sqlExecer runs statements on a *sql.DB or in a *sql.Tx.
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type dataLoader struct {
+	db          sqlExecer
+	table       string
+	columns     []string
+	onDuplicate string
+}
+
+func (l *dataLoader) columnList() string {
+	if len(l.columns) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(l.columns))
+	for i, column := range l.columns {
+		quoted[i] = quoteIdentifier(column)
+	}
+	return " (" + strings.Join(quoted, ", ") + ")"
+}
+
+// loadDataLocal streams a chunk re-encoded as CSV, so quoting and line
+// endings of the source don't matter.
+func (l *dataLoader) loadDataLocal(ctx context.Context, chunk [][]string) error {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(chunk); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("terraform_load_data_%d", loadDataHandlers.Add(1))
+	mysql.RegisterReaderHandler(name, func() io.Reader { return bytes.NewReader(buf.Bytes()) })
+	defer mysql.DeregisterReaderHandler(name)
+
+	duplicate := ""
+	switch l.onDuplicate {
+	case "replace":
+		duplicate = " REPLACE"
+	case "ignore":
+		duplicate = " IGNORE"
+	}
+	stmtSQL := fmt.Sprintf("LOAD DATA LOCAL INFILE 'Reader::%s'%s INTO TABLE %s CHARACTER SET utf8mb4 "+
+		`FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' ESCAPED BY '' LINES TERMINATED BY '\n'%s`,
+		name, duplicate, l.table, l.columnList())
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	_, err := l.db.ExecContext(ctx, stmtSQL)
+	return err
+}
+
+// insert inserts a chunk with a single multi-row INSERT.
+func (l *dataLoader) insert(ctx context.Context, chunk [][]string) error {
+	verb := "INSERT"
+	switch l.onDuplicate {
+	case "replace":
+		verb = "REPLACE"
+	case "ignore":
+		verb = "INSERT IGNORE"
+	}
+
+	var values []string
+	var args []interface{}
+	for _, record := range chunk {
+		placeholders := make([]string, len(record))
+		for i, field := range record {
+			placeholders[i] = "?"
+			args = append(args, field)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+	}
+	stmtSQL := fmt.Sprintf("%s INTO %s%s VALUES %s", verb, l.table, l.columnList(), strings.Join(values, ", "))
+	log.Printf("[DEBUG] Executing statement: %s INTO %s%s VALUES ... (%d rows)", verb, l.table, l.columnList(), len(chunk))
+	_, err := l.db.ExecContext(ctx, stmtSQL, args...)
+	return err
+}
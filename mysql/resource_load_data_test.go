@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestCSVChunks(t *testing.T) {
+	content := []byte("id;name\n1;\"a;b\"\n2;c\n3;\"multi\nline\"\n")
+	chunks, rows, err := csvChunks(content, ";", true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][][]string{
+		{{"1", "a;b"}, {"2", "c"}},
+		{{"3", "multi\nline"}},
+	}
+	if rows != 3 {
+		t.Errorf("got %d rows, want 3", rows)
+	}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("got %#v\nwant %#v", chunks, want)
+	}
+
+	if _, _, err := csvChunks([]byte("a,\"b\n"), ",", false, 10); err == nil {
+		t.Error("no error for an unterminated quote")
+	}
+}
+
+func TestDataLoaderColumnList(t *testing.T) {
+	loader := &dataLoader{columns: []string{"id", "na`me"}}
+	if got, want := loader.columnList(), " (`id`, `na``me`)"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got := (&dataLoader{}).columnList(); got != "" {
+		t.Errorf("got %s for no columns", got)
+	}
+}
+
+func TestFetchSourceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.csv")
+	if err := os.WriteFile(path, []byte("id\n1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, source := range []string{path, "file://" + path} {
+		content, version, err := fetchSource(context.Background(), source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "id\n1\n" || version != "" {
+			t.Errorf("got %q from %s", content, source)
+		}
+	}
+	if _, _, err := fetchSource(context.Background(), "ftp://example.com/seed.csv"); err == nil {
+		t.Error("no error for an unsupported scheme")
+	}
+}
+
+func TestFetchSourceS3(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/seeds/ref/countries.csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("request is not signed: %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Set("Content-Length", "5")
+		if r.Method == http.MethodHead {
+			return
+		}
+		fmt.Fprint(w, "id\n1\n")
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_REGION", "us-east-1")
+	defaultOptions := s3ClientOptions
+	s3ClientOptions = func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(server.URL)
+		o.UsePathStyle = true
+	}
+	defer func() { s3ClientOptions = defaultOptions }()
+
+	source := "s3://seeds/ref/countries.csv"
+	version, err := sourceVersion(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, fetchedVersion, err := fetchSource(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "id\n1\n" || version != `"abc123"` || fetchedVersion != version {
+		t.Errorf("got %q with versions %s and %s", content, version, fetchedVersion)
+	}
+}
@@ -0,0 +1,259 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// stable non-empty ID, validate_password settings are global
+const mysqlPasswordValidationId = "validate_password"
+
+// validatePasswordSettings maps resource attributes to validate_password
+// variable suffixes.
+var validatePasswordSettings = map[string]string{
+	"policy":             "policy",
+	"length":             "length",
+	"mixed_case_count":   "mixed_case_count",
+	"number_count":       "number_count",
+	"special_char_count": "special_char_count",
+	"dictionary_file":    "dictionary_file",
+	"check_user_name":    "check_user_name",
+}
+
+func resourcePasswordValidation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreatePasswordValidation,
+		UpdateContext: UpdatePasswordValidation,
+		ReadContext:   ReadPasswordValidation,
+		DeleteContext: DeletePasswordValidation,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"LOW", "MEDIUM", "STRONG"}, false),
+				Description:  "Password policy enforced by validate_password: LOW, MEDIUM or STRONG",
+			},
+			"length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Minimum number of characters in a password",
+			},
+			"mixed_case_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Minimum number of lowercase and uppercase characters",
+			},
+			"number_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Minimum number of numeric characters",
+			},
+			"special_char_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Minimum number of nonalphanumeric characters",
+			},
+			"dictionary_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Path of the dictionary file checked by the STRONG policy",
+			},
+			"check_user_name": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether to reject passwords matching the user name. MySQL 8.0.15+ only.",
+			},
+		},
+	}
+}
+
+// validatePasswordVariable returns the server variable name for a setting;
+// the component uses validate_password.X, the pre-8.0 plugin validate_password_X.
+func validatePasswordVariable(currentVersion *version.Version, setting string) string {
+	if validatePasswordIsComponent(currentVersion) {
+		return "validate_password." + setting
+	}
+	return "validate_password_" + setting
+}
+
+func validatePasswordValue(d *schema.ResourceData, attr string) string {
+	switch v := d.Get(attr).(type) {
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		if v {
+			return "ON"
+		}
+		return "OFF"
+	default:
+		return v.(string)
+	}
+}
+
+func setPasswordValidationVariables(ctx context.Context, db *sql.DB, currentVersion *version.Version, d *schema.ResourceData, attrs []string) error {
+	for _, attr := range attrs {
+		stmtSQL := setGlobalVariableSQL(validatePasswordVariable(currentVersion, validatePasswordSettings[attr]), validatePasswordValue(d, attr))
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed setting %s: %v", attr, err)
+		}
+	}
+	return nil
+}
+
+func CreatePasswordValidation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	currentVersion := getVersionFromMeta(ctx, meta)
+
+	if err := ensureValidatePassword(ctx, db, currentVersion); err != nil {
+		return diag.Errorf("failed installing validate_password: %v", err)
+	}
+
+	var attrs []string
+	rawConfig := d.GetRawConfig()
+	for attr := range validatePasswordSettings {
+		if !rawConfig.IsNull() && !rawConfig.GetAttr(attr).IsNull() {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if err := setPasswordValidationVariables(ctx, db, currentVersion, d, attrs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(mysqlPasswordValidationId)
+
+	return ReadPasswordValidation(ctx, d, meta)
+}
+
+func UpdatePasswordValidation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var attrs []string
+	for attr := range validatePasswordSettings {
+		if d.HasChange(attr) {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if err := setPasswordValidationVariables(ctx, db, getVersionFromMeta(ctx, meta), d, attrs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return ReadPasswordValidation(ctx, d, meta)
+}
+
+func ReadPasswordValidation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	variables, err := readValidatePasswordVariables(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed reading validate_password variables: %v", err)
+	}
+
+	if len(variables) == 0 {
+		log.Printf("[WARN] validate_password is not installed; removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	for attr, setting := range validatePasswordSettings {
+		value, ok := variables[setting]
+		if !ok {
+			continue
+		}
+		switch attr {
+		case "check_user_name":
+			d.Set(attr, value == "ON")
+		case "policy", "dictionary_file":
+			d.Set(attr, value)
+		default:
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return diag.Errorf("failed parsing %s value %q: %v", attr, value, err)
+			}
+			d.Set(attr, intValue)
+		}
+	}
+
+	return nil
+}
+
+func DeletePasswordValidation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	currentVersion := getVersionFromMeta(ctx, meta)
+
+	// We keep the component installed, as other resources may depend on it,
+	// and only restore the defaults. Not every variable exists on every
+	// version (e.g. check_user_name), so failures are only logged.
+	for attr, setting := range validatePasswordSettings {
+		stmtSQL := fmt.Sprintf("SET GLOBAL %s = DEFAULT", quoteIdentifier(validatePasswordVariable(currentVersion, setting)))
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			log.Printf("[WARN] failed resetting %s: %v", attr, err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// readValidatePasswordVariables returns validate_password settings keyed by
+// their suffix, e.g. "policy", regardless of component or plugin naming.
+func readValidatePasswordVariables(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	stmtSQL := "SHOW GLOBAL VARIABLES LIKE 'validate\\_password%'"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variables := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		name = strings.TrimPrefix(name, "validate_password")
+		variables[strings.TrimLeft(name, "._")] = value
+	}
+
+	return variables, rows.Err()
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPasswordValidation_basic(t *testing.T) {
+	resourceName := "mysql_password_validation.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPasswordValidationConfig("LOW", 10),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPasswordValidationVariable("policy", "LOW"),
+					testAccPasswordValidationVariable("length", "10"),
+					resource.TestCheckResourceAttr(resourceName, "policy", "LOW"),
+					resource.TestCheckResourceAttrSet(resourceName, "number_count"),
+				),
+			},
+			{
+				Config: testAccPasswordValidationConfig("MEDIUM", 12),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPasswordValidationVariable("policy", "MEDIUM"),
+					testAccPasswordValidationVariable("length", "12"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPasswordValidationVariable(setting, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		variables, err := readValidatePasswordVariables(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		if variables[setting] != expected {
+			return fmt.Errorf("validate_password %s is %q, expected %q", setting, variables[setting], expected)
+		}
+		return nil
+	}
+}
+
+func testAccPasswordValidationConfig(policy string, length int) string {
+	return fmt.Sprintf(`
+resource "mysql_password_validation" "test" {
+  policy = "%s"
+  length = %d
+}
+`, policy, length)
+}
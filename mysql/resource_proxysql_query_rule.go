@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceProxySQLQueryRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateProxySQLQueryRule,
+		UpdateContext: UpdateProxySQLQueryRule,
+		ReadContext:   ReadProxySQLQueryRule,
+		DeleteContext: DeleteProxySQLQueryRule,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportProxySQLQueryRule,
+		},
+		Schema: map[string]*schema.Schema{
+			"rule_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"username": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			"schemaname": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			"match_digest": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			"match_pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			"destination_hostgroup": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  -1,
+				// -1 leaves the destination unset
+			},
+			"apply": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+		},
+	}
+}
+
+func proxysqlQueryRuleValues(d *schema.ResourceData) string {
+	destinationHostgroup := "NULL"
+	if hg := d.Get("destination_hostgroup").(int); hg >= 0 {
+		destinationHostgroup = strconv.Itoa(hg)
+	}
+
+	return fmt.Sprintf("active = %d, username = %s, schemaname = %s, match_digest = %s, match_pattern = %s, destination_hostgroup = %s, apply = %d, comment = %s",
+		proxysqlBool(d.Get("active").(bool)),
+		proxysqlNullableQuote(d.Get("username").(string)),
+		proxysqlNullableQuote(d.Get("schemaname").(string)),
+		proxysqlNullableQuote(d.Get("match_digest").(string)),
+		proxysqlNullableQuote(d.Get("match_pattern").(string)),
+		destinationHostgroup,
+		proxysqlBool(d.Get("apply").(bool)),
+		proxysqlNullableQuote(d.Get("comment").(string)))
+}
+
+func CreateProxySQLQueryRule(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ruleID := d.Get("rule_id").(int)
+	stmtsSQL := []string{
+		fmt.Sprintf("INSERT INTO mysql_query_rules (rule_id) VALUES (%d)", ruleID),
+		fmt.Sprintf("UPDATE mysql_query_rules SET %s WHERE rule_id = %d", proxysqlQueryRuleValues(d), ruleID),
+	}
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "QUERY RULES", stmtsSQL...); err != nil {
+		return diag.Errorf("failed adding ProxySQL query rule: %v", err)
+	}
+
+	d.SetId(strconv.Itoa(ruleID))
+
+	return ReadProxySQLQueryRule(ctx, d, meta)
+}
+
+func UpdateProxySQLQueryRule(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("UPDATE mysql_query_rules SET %s WHERE rule_id = %d", proxysqlQueryRuleValues(d), d.Get("rule_id").(int))
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "QUERY RULES", stmtSQL); err != nil {
+		return diag.Errorf("failed updating ProxySQL query rule: %v", err)
+	}
+
+	return ReadProxySQLQueryRule(ctx, d, meta)
+}
+
+func ReadProxySQLQueryRule(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, _, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("SELECT active, IFNULL(username, ''), IFNULL(schemaname, ''), IFNULL(match_digest, ''), IFNULL(match_pattern, ''), IFNULL(destination_hostgroup, -1), apply, IFNULL(comment, '') FROM mysql_query_rules WHERE rule_id = %d",
+		d.Get("rule_id").(int))
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var username, schemaname, matchDigest, matchPattern, comment string
+	var active, destinationHostgroup, apply int
+	err = db.QueryRowContext(ctx, stmtSQL).Scan(&active, &username, &schemaname, &matchDigest, &matchPattern, &destinationHostgroup, &apply, &comment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] ProxySQL query rule (%s) not found; removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading ProxySQL query rule: %v", err)
+	}
+
+	d.Set("active", active != 0)
+	d.Set("username", username)
+	d.Set("schemaname", schemaname)
+	d.Set("match_digest", matchDigest)
+	d.Set("match_pattern", matchPattern)
+	d.Set("destination_hostgroup", destinationHostgroup)
+	d.Set("apply", apply != 0)
+	d.Set("comment", comment)
+
+	return nil
+}
+
+func DeleteProxySQLQueryRule(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DELETE FROM mysql_query_rules WHERE rule_id = %d", d.Get("rule_id").(int))
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "QUERY RULES", stmtSQL); err != nil {
+		return diag.Errorf("failed deleting ProxySQL query rule: %v", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportProxySQLQueryRule(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ruleID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule_id %s: %v", d.Id(), err)
+	}
+	d.Set("rule_id", ruleID)
+	return []*schema.ResourceData{d}, nil
+}
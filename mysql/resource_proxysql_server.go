@@ -0,0 +1,203 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceProxySQLServer() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateProxySQLServer,
+		UpdateContext: UpdateProxySQLServer,
+		ReadContext:   ReadProxySQLServer,
+		DeleteContext: DeleteProxySQLServer,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportProxySQLServer,
+		},
+		Schema: map[string]*schema.Schema{
+			"hostgroup_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"hostname": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+				Default:  3306,
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ONLINE",
+				ValidateFunc: validation.StringInSlice([]string{"ONLINE", "SHUNNED", "OFFLINE_SOFT", "OFFLINE_HARD"}, false),
+			},
+			"weight": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+			"max_connections": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1000,
+			},
+			"max_replication_lag": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"use_ssl": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+		},
+	}
+}
+
+func proxysqlServerWhere(hostgroupID int, hostname string, port int) string {
+	return fmt.Sprintf("hostgroup_id = %d AND hostname = %s AND port = %d", hostgroupID, quoteString(hostname), port)
+}
+
+func CreateProxySQLServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	hostgroupID := d.Get("hostgroup_id").(int)
+	hostname := d.Get("hostname").(string)
+	port := d.Get("port").(int)
+
+	stmtSQL := fmt.Sprintf("INSERT INTO mysql_servers (hostgroup_id, hostname, port, status, weight, max_connections, max_replication_lag, use_ssl, comment) VALUES (%d, %s, %d, %s, %d, %d, %d, %d, %s)",
+		hostgroupID,
+		quoteString(hostname),
+		port,
+		quoteString(d.Get("status").(string)),
+		d.Get("weight").(int),
+		d.Get("max_connections").(int),
+		d.Get("max_replication_lag").(int),
+		proxysqlBool(d.Get("use_ssl").(bool)),
+		quoteString(d.Get("comment").(string)))
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "SERVERS", stmtSQL); err != nil {
+		return diag.Errorf("failed adding ProxySQL server: %v", err)
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s:%d", hostgroupID, hostname, port))
+
+	return ReadProxySQLServer(ctx, d, meta)
+}
+
+func UpdateProxySQLServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("UPDATE mysql_servers SET status = %s, weight = %d, max_connections = %d, max_replication_lag = %d, use_ssl = %d, comment = %s WHERE %s",
+		quoteString(d.Get("status").(string)),
+		d.Get("weight").(int),
+		d.Get("max_connections").(int),
+		d.Get("max_replication_lag").(int),
+		proxysqlBool(d.Get("use_ssl").(bool)),
+		quoteString(d.Get("comment").(string)),
+		proxysqlServerWhere(d.Get("hostgroup_id").(int), d.Get("hostname").(string), d.Get("port").(int)))
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "SERVERS", stmtSQL); err != nil {
+		return diag.Errorf("failed updating ProxySQL server: %v", err)
+	}
+
+	return ReadProxySQLServer(ctx, d, meta)
+}
+
+func ReadProxySQLServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, _, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "SELECT status, weight, max_connections, max_replication_lag, use_ssl, comment FROM mysql_servers WHERE " +
+		proxysqlServerWhere(d.Get("hostgroup_id").(int), d.Get("hostname").(string), d.Get("port").(int))
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var status, comment string
+	var weight, maxConnections, maxReplicationLag, useSsl int
+	err = db.QueryRowContext(ctx, stmtSQL).Scan(&status, &weight, &maxConnections, &maxReplicationLag, &useSsl, &comment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] ProxySQL server (%s) not found; removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading ProxySQL server: %v", err)
+	}
+
+	d.Set("status", status)
+	d.Set("weight", weight)
+	d.Set("max_connections", maxConnections)
+	d.Set("max_replication_lag", maxReplicationLag)
+	d.Set("use_ssl", useSsl != 0)
+	d.Set("comment", comment)
+
+	return nil
+}
+
+func DeleteProxySQLServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "DELETE FROM mysql_servers WHERE " +
+		proxysqlServerWhere(d.Get("hostgroup_id").(int), d.Get("hostname").(string), d.Get("port").(int))
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "SERVERS", stmtSQL); err != nil {
+		return diag.Errorf("failed deleting ProxySQL server: %v", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportProxySQLServer(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("wrong ID format %s (expected HOSTGROUP_ID:HOSTNAME:PORT)", d.Id())
+	}
+
+	hostgroupID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hostgroup_id %s: %v", parts[0], err)
+	}
+	port, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %s: %v", parts[2], err)
+	}
+
+	d.Set("hostgroup_id", hostgroupID)
+	d.Set("hostname", parts[1])
+	d.Set("port", port)
+
+	return []*schema.ResourceData{d}, nil
+}
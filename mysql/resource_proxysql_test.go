@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func testAccPreCheckProxySQL(t *testing.T) {
+	if os.Getenv("PROXYSQL_ENDPOINT") == "" {
+		t.Skip("PROXYSQL_ENDPOINT must be set for ProxySQL acceptance tests")
+	}
+}
+
+func TestAccProxySQL_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheckProxySQL(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProxySQLConfig(10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_proxysql_server.test", "id", "10:db1.example.com:3306"),
+					resource.TestCheckResourceAttr("mysql_proxysql_server.test", "weight", "10"),
+					resource.TestCheckResourceAttr("mysql_proxysql_user.test", "default_hostgroup", "10"),
+					resource.TestCheckResourceAttr("mysql_proxysql_query_rule.test", "destination_hostgroup", "10"),
+				),
+			},
+			{
+				Config: testAccProxySQLConfig(20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_proxysql_server.test", "weight", "20"),
+				),
+			},
+			{
+				ResourceName:      "mysql_proxysql_query_rule.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccProxySQLConfig(weight int) string {
+	return fmt.Sprintf(`
+provider "mysql" {
+  endpoint = %q
+  username = %q
+  password = %q
+  proxysql = true
+
+  proxysql_save_to_disk = false
+}
+
+resource "mysql_proxysql_server" "test" {
+  hostgroup_id = 10
+  hostname     = "db1.example.com"
+  weight       = %d
+}
+
+resource "mysql_proxysql_user" "test" {
+  username          = "tf-proxysql-test"
+  password          = "secret"
+  default_hostgroup = mysql_proxysql_server.test.hostgroup_id
+}
+
+resource "mysql_proxysql_query_rule" "test" {
+  rule_id               = 100
+  match_digest          = "^SELECT .* FOR UPDATE$"
+  destination_hostgroup = mysql_proxysql_server.test.hostgroup_id
+  apply                 = true
+}
+`, os.Getenv("PROXYSQL_ENDPOINT"), os.Getenv("PROXYSQL_USERNAME"), os.Getenv("PROXYSQL_PASSWORD"), weight)
+}
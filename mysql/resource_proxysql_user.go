@@ -0,0 +1,178 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceProxySQLUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateProxySQLUser,
+		UpdateContext: UpdateProxySQLUser,
+		ReadContext:   ReadProxySQLUser,
+		DeleteContext: DeleteProxySQLUser,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportProxySQLUser,
+		},
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+				Default:   "",
+			},
+			"default_hostgroup": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"default_schema": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"use_ssl": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"transaction_persistent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"max_connections": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  10000,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+		},
+	}
+}
+
+func proxysqlUserValues(d *schema.ResourceData) string {
+	return fmt.Sprintf("password = %s, default_hostgroup = %d, default_schema = %s, active = %d, use_ssl = %d, transaction_persistent = %d, max_connections = %d, comment = %s",
+		quoteString(d.Get("password").(string)),
+		d.Get("default_hostgroup").(int),
+		proxysqlNullableQuote(d.Get("default_schema").(string)),
+		proxysqlBool(d.Get("active").(bool)),
+		proxysqlBool(d.Get("use_ssl").(bool)),
+		proxysqlBool(d.Get("transaction_persistent").(bool)),
+		d.Get("max_connections").(int),
+		quoteString(d.Get("comment").(string)))
+}
+
+func CreateProxySQLUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	username := d.Get("username").(string)
+	stmtsSQL := []string{
+		fmt.Sprintf("INSERT INTO mysql_users (username) VALUES (%s)", quoteString(username)),
+		fmt.Sprintf("UPDATE mysql_users SET %s WHERE username = %s", proxysqlUserValues(d), quoteString(username)),
+	}
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "USERS", stmtsSQL...); err != nil {
+		return diag.Errorf("failed adding ProxySQL user: %v", err)
+	}
+
+	d.SetId(username)
+
+	return ReadProxySQLUser(ctx, d, meta)
+}
+
+func UpdateProxySQLUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("UPDATE mysql_users SET %s WHERE username = %s", proxysqlUserValues(d), quoteString(d.Id()))
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "USERS", stmtSQL); err != nil {
+		return diag.Errorf("failed updating ProxySQL user: %v", err)
+	}
+
+	return ReadProxySQLUser(ctx, d, meta)
+}
+
+func ReadProxySQLUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, _, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// ProxySQL keeps separate frontend and backend rows for the same user;
+	// we manage both together, so reading one of them is enough.
+	stmtSQL := fmt.Sprintf("SELECT default_hostgroup, IFNULL(default_schema, ''), active, use_ssl, transaction_persistent, max_connections, comment FROM mysql_users WHERE username = %s LIMIT 1",
+		quoteString(d.Id()))
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var defaultSchema, comment string
+	var defaultHostgroup, active, useSsl, transactionPersistent, maxConnections int
+	err = db.QueryRowContext(ctx, stmtSQL).Scan(&defaultHostgroup, &defaultSchema, &active, &useSsl, &transactionPersistent, &maxConnections, &comment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] ProxySQL user (%s) not found; removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading ProxySQL user: %v", err)
+	}
+
+	d.Set("username", d.Id())
+	// ProxySQL may store the password hashed, so it's not read back.
+	d.Set("default_hostgroup", defaultHostgroup)
+	d.Set("default_schema", defaultSchema)
+	d.Set("active", active != 0)
+	d.Set("use_ssl", useSsl != 0)
+	d.Set("transaction_persistent", transactionPersistent != 0)
+	d.Set("max_connections", maxConnections)
+	d.Set("comment", comment)
+
+	return nil
+}
+
+func DeleteProxySQLUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, saveToDisk, err := getProxySQLFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DELETE FROM mysql_users WHERE username = %s", quoteString(d.Id()))
+
+	if err := proxysqlExecAndLoad(ctx, db, saveToDisk, "USERS", stmtSQL); err != nil {
+		return diag.Errorf("failed deleting ProxySQL user: %v", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportProxySQLUser(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("username", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// restoreProgressSteps is how often the progress of a restore is logged.
+const restoreProgressSteps = 10
+
+func resourceRestore() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateRestore,
+		ReadContext:   ReadRestore,
+		DeleteContext: DeleteRestore,
+
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			// Dumps may select their databases themselves, so database
+			// is only required with a default_database.
+			if defaultDatabaseFromMeta(meta) != "" {
+				if err := setDefaultDatabase(d, meta, ""); err != nil {
+					return err
+				}
+			}
+			return diffSourceChecksum(ctx, d)
+		},
+
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Path or http(s)://, s3:// or gs:// URL of the SQL dump; gzip-compressed dumps are decompressed",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Database the statements of the dump run in, unless they select another one with USE; defaults to the provider's default_database",
+			},
+			"checksum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 of the dump; the dump is restored again when it changes",
+			},
+			"statements": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of statements executed",
+			},
+		},
+	}
+}
+
+// CreateRestore runs the statements of a SQL dump, like the mysql client,
+// on a dedicated connection, so session settings of the dump don't leak
+// into other resources.
+func CreateRestore(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok {
+		return diag.Errorf("mysql_restore requires a MySQL connection")
+	}
+
+	source := d.Get("source").(string)
+	content, _, err := fetchSource(ctx, source)
+	if err != nil {
+		return diag.Errorf("failed reading source: %v", err)
+	}
+	script, err := decompressDump(content)
+	if err != nil {
+		return diag.Errorf("failed decompressing %s: %v", source, err)
+	}
+	statements, err := splitSQLStatements(script)
+	if err != nil {
+		return diag.Errorf("failed parsing %s: %v", source, err)
+	}
+
+	restoreConf := *conf
+	restoreConf.Config = conf.Config.Clone()
+	restoreConf.Config.DBName = d.Get("database").(string)
+	conn, err := createNewConnection(ctx, &restoreConf)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer conn.Db.Close()
+	session, err := conn.Db.Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer session.Close()
+
+	step := len(statements)/restoreProgressSteps + 1
+	for i, statement := range statements {
+		log.Printf("[DEBUG] Executing statement %d of %d (line %d)", i+1, len(statements), statement.Line)
+		if _, err := session.ExecContext(ctx, statement.SQL); err != nil {
+			return diag.Errorf("restoring %s failed at statement %d of %d (line %d): %v", source, i+1, len(statements), statement.Line, err)
+		}
+		if (i+1)%step == 0 {
+			log.Printf("[INFO] Restored %d of %d statements of %s (%d%%)", i+1, len(statements), source, (i+1)*100/len(statements))
+		}
+	}
+	log.Printf("[INFO] Restored %s", source)
+
+	d.SetId(id.UniqueId())
+	d.Set("database", restoreConf.Config.DBName)
+	d.Set("checksum", hashSum(string(content)))
+	d.Set("statements", len(statements))
+	return nil
+}
+
+// ReadRestore does nothing, the restore is a one-off action repeated only
+// when the dump changes.
+func ReadRestore(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// DeleteRestore keeps the restored data.
+func DeleteRestore(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// decompressDump returns the dump, decompressed when it's gzip-compressed.
+func decompressDump(content []byte) (string, error) {
+	if !bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		return string(content), nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	var script strings.Builder
+	if _, err := io.Copy(&script, r); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return script.String(), nil
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDecompressDump(t *testing.T) {
+	dump := "CREATE TABLE t (id INT);\n"
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	w.Write([]byte(dump))
+	w.Close()
+
+	for _, content := range [][]byte{[]byte(dump), compressed.Bytes()} {
+		got, err := decompressDump(content)
+		if err != nil {
+			t.Fatalf("decompressDump failed: %v", err)
+		}
+		if got != dump {
+			t.Errorf("decompressDump = %q, want %q", got, dump)
+		}
+	}
+}
+
+func TestAccRestore(t *testing.T) {
+	dbName := "tf_restore_test"
+	source := filepath.Join(t.TempDir(), "dump.sql")
+	if err := os.WriteFile(source, []byte(`
+SET FOREIGN_KEY_CHECKS=0;
+CREATE TABLE t (id INT PRIMARY KEY, name VARCHAR(20));
+INSERT INTO t VALUES (1, 'one'), (2, 'two;three');
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_restore" "test" {
+  source   = "%s"
+  database = mysql_database.test.name
+}
+`, dbName, source),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_restore.test", "statements", "3"),
+					resource.TestCheckResourceAttrSet("mysql_restore.test", "checksum"),
+					func(s *terraform.State) error {
+						db, err := connectToMySQL(context.Background(), testAccProvider.Meta().(*MySQLConfiguration))
+						if err != nil {
+							return err
+						}
+						var count int
+						if err := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s.t", dbName)).Scan(&count); err != nil {
+							return err
+						}
+						if count != 2 {
+							return fmt.Errorf("restored %d rows, want 2", count)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
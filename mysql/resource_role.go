@@ -2,18 +2,26 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
 
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// tidbMinRolesVersion is the first TiDB release with roles.
+var tidbMinRolesVersion = version.Must(version.NewVersion("3.0.0"))
+
 func resourceRole() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateRole,
 		ReadContext:   ReadRole,
 		DeleteContext: DeleteRole,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return checkTiDBRoleSupport(ctx, meta, false)
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -83,3 +91,32 @@ func DeleteRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 
 	return nil
 }
+
+// checkTiDBRoleSupport rejects role features TiDB lacks at plan time instead
+// of failing mid-apply. Other servers are not checked.
+func checkTiDBRoleSupport(ctx context.Context, meta interface{}, adminOption bool) error {
+	if _, ok := meta.(*MySQLConfiguration); !ok {
+		return nil
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		log.Printf("[WARN] Could not check role support: %v", err)
+		return nil
+	}
+	isTiDB, tidbVersion, _, err := serverTiDB(db)
+	if err != nil {
+		log.Printf("[WARN] Could not check role support: %v", err)
+		return nil
+	}
+	if !isTiDB {
+		return nil
+	}
+
+	if ver, err := version.NewVersion(tidbVersion); err == nil && ver.LessThan(tidbMinRolesVersion) {
+		return fmt.Errorf("roles require TiDB %s or later, server is %s", tidbMinRolesVersion, tidbVersion)
+	}
+	if adminOption {
+		return errors.New("TiDB doesn't support WITH ADMIN OPTION, set grant to false for role grants")
+	}
+	return nil
+}
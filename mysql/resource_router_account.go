@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// routerAccountGrants are the grants of Cluster.setupRouterAccount() in
+// MySQL Shell. The metadata schema only exists once MySQL Shell created the
+// cluster and its tables depend on the metadata version.
+var routerAccountGrants = []accountGrant{
+	{Privileges: []string{"SELECT", "EXECUTE"}, Database: "mysql_innodb_cluster_metadata", Table: "*"},
+	{Privileges: []string{"INSERT", "UPDATE", "DELETE"}, Database: "mysql_innodb_cluster_metadata", Table: "routers", RequireTable: true},
+	// Metadata 2.0, which came with MySQL Shell 8.0.19, moved routers to v2 views.
+	{Privileges: []string{"INSERT", "UPDATE", "DELETE"}, Database: "mysql_innodb_cluster_metadata", Table: "v2_routers", Flavor: accountFlavorMySQL, MinVersion: "8.0.19", RequireTable: true},
+	{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "global_variables"},
+	{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "replication_group_member_stats"},
+	{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "replication_group_members"},
+}
+
+func resourceRouterAccount() *schema.Resource {
+	return accountProfileResource(func(d interface{ Get(string) interface{} }) []accountGrant {
+		return routerAccountGrants
+	}, nil)
+}
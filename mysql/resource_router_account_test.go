@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRouterAccount_basic(t *testing.T) {
+	resourceName := "mysql_router_account.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRouterAccountCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				// The test server has no metadata schema, so only the grants
+				// on the whole schema and on performance_schema are made.
+				Config: testAccRouterAccountConfig("secret1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "grants.#", "4"),
+					resource.TestCheckResourceAttr(resourceName, "grants.0", "SELECT, EXECUTE ON mysql_innodb_cluster_metadata.*"),
+					testAccRouterAccountHasGrant("SELECT", "performance_schema", "replication_group_members"),
+				),
+			},
+			{
+				Config: testAccRouterAccountConfig("secret2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "grants.#", "4"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRouterAccountHasGrant(privilege, database, table string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+		grants, err := showUserGrants(ctx, db, UserOrRole{Name: "tf_router", Host: "%"})
+		if err != nil {
+			return err
+		}
+		want := &TablePrivilegeGrant{Database: database, Table: table, Privileges: []string{privilege}}
+		if !hasAccountGrant(grants, want) {
+			return fmt.Errorf("tf_router has no %s on %s.%s", privilege, database, table)
+		}
+		return nil
+	}
+}
+
+func testAccRouterAccountCheckDestroy(s *terraform.State) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+
+	found, err := queryHasRows(ctx, db, "SELECT 1 FROM mysql.user WHERE User = ? AND Host = ?", "tf_router", "%")
+	if err != nil {
+		return err
+	}
+	if found {
+		return fmt.Errorf("router account still exists after destroy")
+	}
+	return nil
+}
+
+func testAccRouterAccountConfig(password string) string {
+	return fmt.Sprintf(`
+resource "mysql_router_account" "test" {
+  user               = "tf_router"
+  plaintext_password = "%s"
+}
+`, password)
+}
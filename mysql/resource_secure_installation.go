@@ -0,0 +1,224 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// stable non-empty ID, there is only one secure installation per server
+const mysqlSecureInstallationId = "secure_installation"
+
+const validatePasswordComponentURN = "file://component_validate_password"
+
+func resourceSecureInstallation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateOrUpdateSecureInstallation,
+		UpdateContext: CreateOrUpdateSecureInstallation,
+		ReadContext:   ReadSecureInstallation,
+		DeleteContext: DeleteSecureInstallation,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"remove_anonymous_users": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Drop all accounts with an empty user name",
+			},
+			"remove_test_database": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Drop the test database and privileges on test and test_% databases",
+			},
+			"disallow_remote_root": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Drop root accounts that can connect from hosts other than localhost",
+			},
+			"enforce_validate_password": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Install the validate_password component (or plugin before MySQL 8.0)",
+			},
+		},
+	}
+}
+
+func CreateOrUpdateSecureInstallation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("remove_anonymous_users").(bool) {
+		if err := dropUsersMatching(ctx, db, "SELECT user, host FROM mysql.user WHERE user = ''"); err != nil {
+			return diag.Errorf("failed removing anonymous users: %v", err)
+		}
+	}
+
+	if d.Get("disallow_remote_root").(bool) {
+		if err := dropUsersMatching(ctx, db, "SELECT user, host FROM mysql.user WHERE user = 'root' AND host NOT IN ('localhost', '127.0.0.1', '::1')"); err != nil {
+			return diag.Errorf("failed removing remote root users: %v", err)
+		}
+	}
+
+	if d.Get("remove_test_database").(bool) {
+		stmtsSQL := []string{
+			"DROP DATABASE IF EXISTS `test`",
+			"DELETE FROM mysql.db WHERE Db = 'test' OR Db = 'test\\_%'",
+			"FLUSH PRIVILEGES",
+		}
+		for _, stmtSQL := range stmtsSQL {
+			log.Println("[DEBUG] Executing statement:", stmtSQL)
+			if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+				return diag.Errorf("failed removing test database: %v", err)
+			}
+		}
+	}
+
+	if d.Get("enforce_validate_password").(bool) {
+		if err := ensureValidatePassword(ctx, db, getVersionFromMeta(ctx, meta)); err != nil {
+			return diag.Errorf("failed enabling validate_password: %v", err)
+		}
+	}
+
+	d.SetId(mysqlSecureInstallationId)
+
+	return ReadSecureInstallation(ctx, d, meta)
+}
+
+func ReadSecureInstallation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Only settings we enforce are drift detected; a disabled setting means
+	// "don't care", so we never flip it to true.
+	if d.Get("remove_anonymous_users").(bool) {
+		found, err := queryHasRows(ctx, db, "SELECT 1 FROM mysql.user WHERE user = ''")
+		if err != nil {
+			return diag.Errorf("failed reading anonymous users: %v", err)
+		}
+		d.Set("remove_anonymous_users", !found)
+	}
+
+	if d.Get("disallow_remote_root").(bool) {
+		found, err := queryHasRows(ctx, db, "SELECT 1 FROM mysql.user WHERE user = 'root' AND host NOT IN ('localhost', '127.0.0.1', '::1')")
+		if err != nil {
+			return diag.Errorf("failed reading remote root users: %v", err)
+		}
+		d.Set("disallow_remote_root", !found)
+	}
+
+	if d.Get("remove_test_database").(bool) {
+		found, err := queryHasRows(ctx, db, "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = 'test'")
+		if err != nil {
+			return diag.Errorf("failed reading test database: %v", err)
+		}
+		d.Set("remove_test_database", !found)
+	}
+
+	if d.Get("enforce_validate_password").(bool) {
+		installed, err := validatePasswordInstalled(ctx, db, getVersionFromMeta(ctx, meta))
+		if err != nil {
+			return diag.Errorf("failed reading validate_password status: %v", err)
+		}
+		d.Set("enforce_validate_password", installed)
+	}
+
+	return nil
+}
+
+func DeleteSecureInstallation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Dropped accounts and databases can't be restored, so we only
+	// remove the resource from the state.
+	d.SetId("")
+	return nil
+}
+
+func dropUsersMatching(ctx context.Context, db *sql.DB, query string) error {
+	log.Println("[DEBUG] Executing query:", query)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	var users []UserOrRole
+	for rows.Next() {
+		var user UserOrRole
+		if err := rows.Scan(&user.Name, &user.Host); err != nil {
+			rows.Close()
+			return err
+		}
+		users = append(users, user)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	for _, user := range users {
+		stmtSQL := fmt.Sprintf("DROP USER %s", formatUserIdentifier(user.Name, user.Host))
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func queryHasRows(ctx context.Context, db *sql.DB, query string, args ...interface{}) (bool, error) {
+	log.Println("[DEBUG] Executing query:", query)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	found := rows.Next()
+	return found, rows.Err()
+}
+
+// validate_password is a component since MySQL 8.0 and a plugin before that.
+func validatePasswordIsComponent(currentVersion *version.Version) bool {
+	ver, _ := version.NewVersion("8.0.0")
+	return currentVersion.GreaterThanOrEqual(ver)
+}
+
+func validatePasswordInstalled(ctx context.Context, db *sql.DB, currentVersion *version.Version) (bool, error) {
+	if validatePasswordIsComponent(currentVersion) {
+		return queryHasRows(ctx, db, "SELECT 1 FROM mysql.component WHERE component_urn = ?", validatePasswordComponentURN)
+	}
+	return pluginActive(ctx, db, "validate_password")
+}
+
+func ensureValidatePassword(ctx context.Context, db *sql.DB, currentVersion *version.Version) error {
+	installed, err := validatePasswordInstalled(ctx, db, currentVersion)
+	if err != nil {
+		return err
+	}
+	if installed {
+		return nil
+	}
+
+	stmtSQL := "INSTALL PLUGIN validate_password SONAME 'validate_password.so'"
+	if validatePasswordIsComponent(currentVersion) {
+		stmtSQL = fmt.Sprintf("INSTALL COMPONENT %s", quoteString(validatePasswordComponentURN))
+	}
+
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	_, err = db.ExecContext(ctx, stmtSQL)
+	return err
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSecureInstallation_basic(t *testing.T) {
+	resourceName := "mysql_secure_installation.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSecureInstallationConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccSecureInstallationApplied(),
+					resource.TestCheckResourceAttr(resourceName, "remove_anonymous_users", "true"),
+					resource.TestCheckResourceAttr(resourceName, "remove_test_database", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSecureInstallationApplied() resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		found, err := queryHasRows(ctx, db, "SELECT 1 FROM mysql.user WHERE user = ''")
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("anonymous users still exist")
+		}
+
+		found, err = queryHasRows(ctx, db, "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = 'test'")
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("test database still exists")
+		}
+
+		return nil
+	}
+}
+
+const testAccSecureInstallationConfigBasic = `
+resource "mysql_secure_installation" "test" {
+  disallow_remote_root = false
+}
+`
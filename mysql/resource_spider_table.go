@@ -0,0 +1,304 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// spiderServerRegex finds the server of a Spider table or partition comment.
+var spiderServerRegex = regexp.MustCompile(`\b(?:srv|server)\s+"([^"]*)"`)
+
+// validateSpiderParameter refuses values that would end the double quoted
+// parameters of a Spider comment.
+var validateSpiderParameter = validation.StringDoesNotContainAny(`"'\`)
+
+func resourceSpiderTable() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateSpiderTable,
+		ReadContext:   ReadSpiderTable,
+		DeleteContext: DeleteSpiderTable,
+
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := setDefaultDatabase(d, meta, ""); err != nil {
+				return err
+			}
+			shards := d.Get("shard").([]interface{})
+			if len(shards) > 1 && d.Get("partition_by").(string) == "" {
+				return errors.New("partition_by is required with more than one shard")
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"definition": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Column and index definitions, matching the tables on the shards",
+			},
+			"remote_database": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSpiderParameter,
+				Description:  "Database of the tables on the shards, the database of the server by default",
+			},
+			"remote_table": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSpiderParameter,
+				Description:  "Name of the tables on the shards, the name of this table by default",
+			},
+			"partition_by": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Partitioning of the rows to the shards, e.g. HASH (id) or RANGE (id)",
+			},
+			"shard": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"server": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validateSpiderParameter,
+							Description:  "Server created with CREATE SERVER, e.g. by mysql_foreign_server",
+						},
+						"partition": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							ForceNew:    true,
+							Description: "Partition name, p<index> by default",
+						},
+						"values": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "Partition values, e.g. LESS THAN (1000) or IN (1, 2)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// spiderComment returns the Spider connection parameters of a table or
+// partition.
+func spiderComment(server, remoteDatabase, remoteTable string) string {
+	params := []string{`wrapper "mysql"`}
+	if server != "" {
+		params = append(params, fmt.Sprintf(`srv "%s"`, server))
+	}
+	if remoteDatabase != "" {
+		params = append(params, fmt.Sprintf(`database "%s"`, remoteDatabase))
+	}
+	if remoteTable != "" {
+		params = append(params, fmt.Sprintf(`table "%s"`, remoteTable))
+	}
+	return strings.Join(params, ", ")
+}
+
+// spiderTableSQL builds the CREATE TABLE statement of a Spider table. With
+// partition_by, every shard is a partition linked to its server.
+func spiderTableSQL(database, name, definition, remoteDatabase, remoteTable, partitionBy string, shards []map[string]interface{}) string {
+	table := quoteIdentifier(database) + "." + quoteIdentifier(name)
+	if partitionBy == "" {
+		return fmt.Sprintf("CREATE TABLE %s (%s) ENGINE=SPIDER COMMENT=%s",
+			table, definition, spiderQuote(spiderComment(shards[0]["server"].(string), remoteDatabase, remoteTable)))
+	}
+
+	partitions := make([]string, len(shards))
+	for i, shard := range shards {
+		partition := fmt.Sprintf("PARTITION %s", quoteIdentifier(spiderPartitionName(shard, i)))
+		if values := shard["values"].(string); values != "" {
+			partition += " VALUES " + values
+		}
+		partition += " COMMENT=" + spiderQuote(fmt.Sprintf(`srv "%s"`, shard["server"].(string)))
+		partitions[i] = partition
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s) ENGINE=SPIDER COMMENT=%s PARTITION BY %s (%s)",
+		table, definition, spiderQuote(spiderComment("", remoteDatabase, remoteTable)), partitionBy, strings.Join(partitions, ", "))
+}
+
+// spiderQuote quotes a Spider comment. The parameters can't contain quotes
+// or backslashes, so the double quotes are kept readable for SHOW CREATE
+// TABLE.
+func spiderQuote(comment string) string {
+	return "'" + comment + "'"
+}
+
+func spiderPartitionName(shard map[string]interface{}, i int) string {
+	if partition, _ := shard["partition"].(string); partition != "" {
+		return partition
+	}
+	return fmt.Sprintf("p%d", i)
+}
+
+func spiderShards(d *schema.ResourceData) []map[string]interface{} {
+	var shards []map[string]interface{}
+	for _, shard := range d.Get("shard").([]interface{}) {
+		shards = append(shards, shard.(map[string]interface{}))
+	}
+	return shards
+}
+
+func CreateSpiderTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if isMariaDB, err := serverMariaDB(db); err != nil {
+		return diag.Errorf("failed checking server flavor: %v", err)
+	} else if !isMariaDB {
+		return diag.Errorf("the Spider engine is only available in MariaDB")
+	}
+	spiderInstalled, err := queryHasRows(ctx, db, "SELECT 1 FROM information_schema.ENGINES WHERE ENGINE = 'SPIDER' AND SUPPORT IN ('YES', 'DEFAULT')")
+	if err != nil {
+		return diag.Errorf("failed checking storage engines: %v", err)
+	}
+	if !spiderInstalled {
+		return diag.Errorf("the Spider engine isn't installed, install it with INSTALL SONAME 'ha_spider'")
+	}
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	stmtSQL := spiderTableSQL(database, name,
+		d.Get("definition").(string),
+		d.Get("remote_database").(string),
+		d.Get("remote_table").(string),
+		d.Get("partition_by").(string),
+		spiderShards(d))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed creating Spider table %s.%s: %v", database, name, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	return ReadSpiderTable(ctx, d, meta)
+}
+
+func ReadSpiderTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+
+	stmtSQL := "SELECT ENGINE, TABLE_COMMENT FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var engine, comment sql.NullString
+	err = db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&engine, &comment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] Spider table (%s) not found; removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading Spider table: %v", err)
+	}
+	if !strings.EqualFold(engine.String, "SPIDER") {
+		log.Printf("[WARN] Table (%s) uses engine %s instead of SPIDER; removing from state", d.Id(), engine.String)
+		d.SetId("")
+		return nil
+	}
+
+	stmtSQL = "SELECT PARTITION_NAME, PARTITION_COMMENT FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL ORDER BY PARTITION_ORDINAL_POSITION"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, database, name)
+	if err != nil {
+		return diag.Errorf("failed reading Spider partitions: %v", err)
+	}
+	defer rows.Close()
+
+	// Keep values, which information_schema formats differently, from the
+	// configuration.
+	configured := spiderShards(d)
+	var shards []map[string]interface{}
+	for rows.Next() {
+		var partition, partitionComment string
+		if err := rows.Scan(&partition, &partitionComment); err != nil {
+			return diag.Errorf("failed reading Spider partitions: %v", err)
+		}
+		shard := map[string]interface{}{"partition": partition, "server": spiderServer(partitionComment)}
+		if i := len(shards); i < len(configured) {
+			shard["values"] = configured[i]["values"]
+		}
+		shards = append(shards, shard)
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed reading Spider partitions: %v", err)
+	}
+	if len(shards) == 0 {
+		shard := map[string]interface{}{"server": spiderServer(comment.String)}
+		if len(configured) > 0 {
+			shard["partition"] = configured[0]["partition"]
+			shard["values"] = configured[0]["values"]
+		}
+		shards = append(shards, shard)
+	}
+
+	if err := d.Set("shard", shards); err != nil {
+		return diag.Errorf("failed setting shards: %v", err)
+	}
+
+	return nil
+}
+
+func spiderServer(comment string) string {
+	if m := spiderServerRegex.FindStringSubmatch(comment); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// DeleteSpiderTable drops the Spider table only; the tables on the shards
+// and their rows are kept.
+func DeleteSpiderTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "DROP TABLE IF EXISTS " + quoteIdentifier(d.Get("database").(string)) + "." + quoteIdentifier(d.Get("name").(string))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed dropping Spider table: %v", err)
+	}
+	return nil
+}
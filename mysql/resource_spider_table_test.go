@@ -0,0 +1,35 @@
+package mysql
+
+import "testing"
+
+func TestSpiderTableSQL(t *testing.T) {
+	shards := []map[string]interface{}{
+		{"server": "backend1", "partition": "", "values": ""},
+		{"server": "backend2", "partition": "odd", "values": ""},
+	}
+
+	got := spiderTableSQL("app", "orders", "id INT PRIMARY KEY", "", "orders", "", shards[:1])
+	want := "CREATE TABLE `app`.`orders` (id INT PRIMARY KEY) ENGINE=SPIDER COMMENT='wrapper \"mysql\", srv \"backend1\", table \"orders\"'"
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	got = spiderTableSQL("app", "orders", "id INT PRIMARY KEY", "shard_db", "", "HASH (id)", shards)
+	want = "CREATE TABLE `app`.`orders` (id INT PRIMARY KEY) ENGINE=SPIDER COMMENT='wrapper \"mysql\", database \"shard_db\"' " +
+		"PARTITION BY HASH (id) (PARTITION `p0` COMMENT='srv \"backend1\"', PARTITION `odd` COMMENT='srv \"backend2\"')"
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestSpiderServer(t *testing.T) {
+	for comment, want := range map[string]string{
+		`wrapper "mysql", srv "backend1", table "t"`: "backend1",
+		`server "backend2"`:                          "backend2",
+		`wrapper "mysql", host "10.0.0.1"`:           "",
+	} {
+		if got := spiderServer(comment); got != want {
+			t.Errorf("spiderServer(%q) = %q, want %q", comment, got, want)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package mysql
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"strings"
+	"text/template"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -11,9 +14,12 @@ import (
 func resourceSql() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateSql,
+		UpdateContext: UpdateSql,
 		ReadContext:   ReadSql,
 		DeleteContext: DeleteSql,
 
+		CustomizeDiff: diffSqlChecksum,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -21,15 +27,46 @@ func resourceSql() *schema.Resource {
 				ForceNew: true,
 			},
 			"create_sql": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "SQL run on create; it runs again whenever its rendered text changes",
 			},
 			"delete_sql": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
+			"variables": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Values available in create_sql and delete_sql as {{ .name }}, {{ quote .name }} or {{ ident .name }}",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that run the SQL again when they change",
+			},
+			"checksum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 of the rendered create_sql",
+			},
+			"ignore_body_whitespace": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Ignore comments and whitespace in create_sql, so reformatting it doesn't run it again",
+			},
+			"multi_statements": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Split create_sql and delete_sql into statements and run them on a dedicated connection",
+			},
+			"wait_for_replicas": waitForReplicasSchema(),
 		},
 	}
 }
@@ -40,17 +77,31 @@ func CreateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
-	createSql := d.Get("create_sql").(string)
+	createSql, err := renderSql(d.Get("create_sql").(string), d.Get("variables").(map[string]interface{}))
+	if err != nil {
+		return diag.Errorf("failed rendering create_sql: %v", err)
+	}
 
 	log.Println("[DEBUG] Executing SQL", createSql)
 
-	_, err = db.ExecContext(ctx, createSql)
+	err = execSQLScript(ctx, db, d, meta, createSql)
 	if err != nil {
 		return diag.Errorf("couldn't exec SQL: %v", err)
 	}
 
 	d.SetId(name)
+	d.Set("checksum", sqlChecksum(createSql, d.Get("ignore_body_whitespace").(bool)))
+
+	if err := waitForReplicas(ctx, d, meta); err != nil {
+		return diag.Errorf("failed waiting for replicas: %v", err)
+	}
+
+	return nil
+}
 
+// UpdateSql only stores changes that don't change the rendered create_sql,
+// e.g. moving a value into variables. Other changes force a new resource.
+func UpdateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	return nil
 }
 
@@ -63,15 +114,100 @@ func DeleteSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	deleteSql := d.Get("delete_sql").(string)
+	deleteSql, err := renderSql(d.Get("delete_sql").(string), d.Get("variables").(map[string]interface{}))
+	if err != nil {
+		return diag.Errorf("failed rendering delete_sql: %v", err)
+	}
 
 	log.Println("[DEBUG] Executing SQL:", deleteSql)
 
-	_, err = db.ExecContext(ctx, deleteSql)
+	err = execSQLScript(ctx, db, d, meta, deleteSql)
 	if err != nil {
 		return diag.Errorf("failed to run delete SQL: %v", err)
 	}
 
+	if err := waitForReplicas(ctx, d, meta); err != nil {
+		return diag.Errorf("failed waiting for replicas: %v", err)
+	}
+
 	d.SetId("")
 	return nil
 }
+
+// renderSql renders create_sql or delete_sql as a text/template with the
+// variables. quote and ident escape values as string literals and
+// identifiers.
+func renderSql(text string, variables map[string]interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("sql").Option("missingkey=error").Funcs(template.FuncMap{
+		"quote": quoteString,
+		"ident": quoteIdentifier,
+	}).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, variables); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// sqlChecksum returns the checksum of the rendered create_sql, ignoring
+// comments and whitespace with ignore_body_whitespace.
+func sqlChecksum(createSql string, ignoreWhitespace bool) string {
+	if ignoreWhitespace {
+		return hashSum(normalizeSQLBody(createSql))
+	}
+	return hashSum(createSql)
+}
+
+// diffSqlChecksum plans the checksum of the rendered create_sql and runs the
+// SQL again when it changes. States from releases without checksum only
+// record it.
+func diffSqlChecksum(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("create_sql") || !d.NewValueKnown("variables") {
+		if err := d.SetNewComputed("checksum"); err != nil {
+			return err
+		}
+		if d.Id() != "" {
+			return d.ForceNew("checksum")
+		}
+		return nil
+	}
+
+	createSql, err := renderSql(d.Get("create_sql").(string), d.Get("variables").(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("failed rendering create_sql: %v", err)
+	}
+	checksum := sqlChecksum(createSql, d.Get("ignore_body_whitespace").(bool))
+
+	old, _ := d.GetChange("checksum")
+	if old.(string) == checksum {
+		return nil
+	}
+	if err := d.SetNew("checksum", checksum); err != nil {
+		return err
+	}
+	// Toggling ignore_body_whitespace alone only changes how the checksum is
+	// computed.
+	unchanged := d.HasChange("ignore_body_whitespace") &&
+		(old.(string) == hashSum(createSql) || old.(string) == hashSum(normalizeSQLBody(createSql)))
+	if d.Id() == "" || unchanged {
+		return nil
+	}
+	if old.(string) == "" {
+		// State of releases without checksum: compare the rendered SQL
+		// instead.
+		oldSql, _ := d.GetChange("create_sql")
+		oldVariables, _ := d.GetChange("variables")
+		oldCreateSql, err := renderSql(oldSql.(string), oldVariables.(map[string]interface{}))
+		if err != nil || sqlChecksum(oldCreateSql, d.Get("ignore_body_whitespace").(bool)) != checksum {
+			return d.ForceNew("checksum")
+		}
+		return nil
+	}
+	return d.ForceNew("checksum")
+}
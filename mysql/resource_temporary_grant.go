@@ -0,0 +1,251 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceTemporaryGrant() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateTemporaryGrant,
+		UpdateContext: UpdateTemporaryGrant,
+		ReadContext:   ReadTemporaryGrant,
+		DeleteContext: DeleteTemporaryGrant,
+
+		CustomizeDiff: diffTemporaryGrant,
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNotReservedAccount,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"table": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "*",
+			},
+			"privileges": {
+				Type:     schema.TypeSet,
+				Required: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"duration": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTemporaryGrantDuration,
+				Description:  "How long the grant lasts, e.g. 30m or 4h",
+			},
+			"reason": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Why the access is needed, logged when it's granted and revoked",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that grant the access again, for a new duration, when changed",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the grant is revoked, in RFC 3339",
+			},
+			"expired": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the grant was revoked",
+			},
+		},
+	}
+}
+
+func validateTemporaryGrantDuration(v interface{}, k string) ([]string, []error) {
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s must be a duration like 30m or 4h: %v", k, err)}
+	}
+	if duration <= 0 {
+		return nil, []error{fmt.Errorf("%s must be positive", k)}
+	}
+	return nil, nil
+}
+
+// diffTemporaryGrant plans the revocation of grants whose expires_at passed,
+// which the next apply runs.
+func diffTemporaryGrant(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := setDefaultDatabase(d, meta, ""); err != nil {
+		return err
+	}
+	if err := checkSystemSchemaGrant(d); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		if d.Get("host").(string) == "" {
+			if err := d.SetNew("host", defaultUserHostFromMeta(meta)); err != nil {
+				return err
+			}
+		}
+		if err := d.SetNewComputed("expires_at"); err != nil {
+			return err
+		}
+		return d.SetNew("expired", false)
+	}
+	if d.Get("expired").(bool) {
+		return nil
+	}
+	expired, err := userExpired(d.Get("expires_at").(string), time.Now())
+	if err != nil || !expired {
+		return err
+	}
+	return d.SetNew("expired", true)
+}
+
+func temporaryGrantFromData(d *schema.ResourceData) *TablePrivilegeGrant {
+	return &TablePrivilegeGrant{
+		Database:   d.Get("database").(string),
+		Table:      d.Get("table").(string),
+		Privileges: normalizePerms(setToArray(d.Get("privileges"))),
+		UserOrRole: UserOrRole{
+			Name: d.Get("user").(string),
+			Host: d.Get("host").(string),
+		},
+	}
+}
+
+func CreateTemporaryGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	duration, _ := time.ParseDuration(d.Get("duration").(string))
+	grant := temporaryGrantFromData(d)
+	if err := checkTemporaryGrantOverlap(ctx, db, grant); err != nil {
+		return diag.FromErr(err)
+	}
+	if diagErr := createGrant(ctx, db, grant); diagErr != nil {
+		return diagErr
+	}
+	expiresAt := time.Now().Add(duration).UTC().Format(time.RFC3339)
+	log.Printf("[INFO] Granted %s until %s: %s", grant.SQLGrantStatement(), expiresAt, d.Get("reason").(string))
+
+	d.SetId(grant.GetId())
+	d.Set("expires_at", expiresAt)
+	d.Set("expired", false)
+	return ReadTemporaryGrant(ctx, d, meta)
+}
+
+// checkTemporaryGrantOverlap refuses privileges the account already holds,
+// e.g. from a mysql_grant: the REVOKE at expiry would remove them too.
+func checkTemporaryGrantOverlap(ctx context.Context, db *sql.DB, grant *TablePrivilegeGrant) error {
+	matching, err := getMatchingGrant(ctx, db, grant)
+	if err != nil {
+		return fmt.Errorf("failed reading grants of %s: %v", grant.GetUserOrRole().SQLString(), err)
+	}
+	existing, ok := matching.(MySQLGrantWithPrivileges)
+	if !ok {
+		return nil
+	}
+
+	held := map[string]bool{}
+	for _, privilege := range existing.GetPrivileges() {
+		held[privilege] = true
+	}
+	var overlap []string
+	for _, privilege := range grant.Privileges {
+		if held[privilege] || held["ALL PRIVILEGES"] {
+			overlap = append(overlap, privilege)
+		}
+	}
+	if len(overlap) > 0 {
+		return fmt.Errorf("%s already has %s on %s, which revoking the temporary grant would remove as well",
+			grant.GetUserOrRole().SQLString(), strings.Join(overlap, ", "), grant.GetDatabase()+"."+grant.GetTable())
+	}
+	return nil
+}
+
+// ReadTemporaryGrant doesn't revoke expired grants: diffTemporaryGrant plans
+// expired, and UpdateTemporaryGrant revokes. The resource stays in state, so
+// the next apply doesn't grant the access again; changing duration or
+// triggers does. A grant revoked by hand counts as expired.
+func ReadTemporaryGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("expired").(bool) {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grant, err := getMatchingGrant(ctx, db, temporaryGrantFromData(d))
+	if err != nil {
+		return diag.Errorf("failed reading temporary grant: %v", err)
+	}
+	if grant == nil {
+		log.Printf("[WARN] Temporary grant %s was revoked before %s", d.Id(), d.Get("expires_at").(string))
+		d.Set("expired", true)
+	}
+	return nil
+}
+
+func UpdateTemporaryGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("expired") && d.Get("expired").(bool) {
+		return revokeTemporaryGrant(ctx, d, meta)
+	}
+	return nil
+}
+
+func DeleteTemporaryGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("expired").(bool) {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return revokeGrant(ctx, db, temporaryGrantFromData(d))
+}
+
+func revokeTemporaryGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grant := temporaryGrantFromData(d)
+	if diagErr := revokeGrant(ctx, db, grant); diagErr != nil {
+		return diagErr
+	}
+	log.Printf("[INFO] Revoked %s, expired at %s: %s", grant.SQLRevokeStatement(), d.Get("expires_at").(string), d.Get("reason").(string))
+	d.Set("expired", true)
+	return nil
+}
@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestValidateTemporaryGrantDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		valid    bool
+	}{
+		{"30m", true},
+		{"4h", true},
+		{"1h30m", true},
+		{"0s", false},
+		{"-1h", false},
+		{"1 day", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		_, errs := validateTemporaryGrantDuration(tt.duration, "duration")
+		if (len(errs) == 0) != tt.valid {
+			t.Errorf("validateTemporaryGrantDuration(%q) errors = %v, want valid %v", tt.duration, errs, tt.valid)
+		}
+	}
+}
+
+func TestAccTemporaryGrant_expires(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	config := fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  host     = "example.com"
+  password = "password"
+}
+
+resource "mysql_temporary_grant" "test" {
+  user       = mysql_user.test.user
+  host       = mysql_user.test.host
+  database   = mysql_database.test.name
+  privileges = ["SELECT"]
+  duration   = "10s"
+  reason     = "incident 42"
+}
+`, dbName, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_temporary_grant.test", "SELECT", true, false),
+					resource.TestCheckResourceAttr("mysql_temporary_grant.test", "expired", "false"),
+					resource.TestCheckResourceAttrSet("mysql_temporary_grant.test", "expires_at"),
+				),
+			},
+			{
+				PreConfig: func() { time.Sleep(11 * time.Second) },
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_temporary_grant.test", "SELECT", false, false),
+					resource.TestCheckResourceAttr("mysql_temporary_grant.test", "expired", "true"),
+				),
+			},
+		},
+	})
+}
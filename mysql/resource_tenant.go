@@ -0,0 +1,548 @@
+package mysql
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// tenantMaxUserLength is the longest user name of MySQL 5.7.8 and later.
+	tenantMaxUserLength  = 32
+	tenantAppSuffix      = "_app"
+	tenantReadOnlySuffix = "_ro"
+	// unknownThreadErrCode is ER_NO_SUCH_THREAD of KILL.
+	unknownThreadErrCode = 1094
+)
+
+// tenantReadOnlyPrivileges are granted to the read-only user of a tenant.
+var tenantReadOnlyPrivileges = []string{"SELECT", "SHOW VIEW"}
+
+// tenantPasswordClasses are the characters of generated passwords. A
+// password has one of each class at least, to pass validate_password
+// policies. The symbols need no escaping in DSNs and shells.
+var tenantPasswordClasses = []string{
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"abcdefghijklmnopqrstuvwxyz",
+	"0123456789",
+	"-_.~",
+}
+
+func resourceTenant() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateTenant,
+		UpdateContext: UpdateTenant,
+		ReadContext:   ReadTenant,
+		DeleteContext: DeleteTenant,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := diffTenant(ctx, d, meta); err != nil {
+				return err
+			}
+			if err := checkTenantConnectionLimits(ctx, d, meta); err != nil {
+				return err
+			}
+			return checkDatabaseCollation(ctx, d, meta)
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`), "must be at most 64 letters, digits or underscores"),
+				Description:  "Name of the tenant and its database",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "%",
+				Description: "Host of the users of the tenant",
+			},
+			"app_user": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, tenantMaxUserLength),
+				Description:  "Application user with all privileges on the database, the name followed by _app by default",
+			},
+			"readonly_user": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, tenantMaxUserLength),
+				Description:  "Read-only user of the database, the name followed by _ro by default",
+			},
+			"create_readonly_user": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"password_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      32,
+				ValidateFunc: validation.IntBetween(16, 128),
+				Description:  "Length of the generated passwords",
+			},
+			"password_version": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Change to generate new passwords for the users",
+			},
+			"default_character_set": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "utf8mb4",
+			},
+			"default_collation": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "utf8mb4_general_ci",
+			},
+			"connection_limits": tenantConnectionLimitsSchema(),
+			"connection_usage":  tenantConnectionUsageSchema(),
+			"keep_database_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only drop the users when the tenant is destroyed, keeping the database and its data",
+			},
+			"app_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"readonly_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// tenantUserNames returns the app and read-only users of a tenant, by
+// default derived from its name.
+func tenantUserNames(d interface{ Get(string) interface{} }) (string, string) {
+	name := d.Get("name").(string)
+	appUser, readOnlyUser := d.Get("app_user").(string), d.Get("readonly_user").(string)
+	if appUser == "" {
+		appUser = name + tenantAppSuffix
+	}
+	if readOnlyUser == "" {
+		readOnlyUser = name + tenantReadOnlySuffix
+	}
+	return appUser, readOnlyUser
+}
+
+// diffTenant sets the default user names and marks the passwords to be
+// generated again when password_version changes or a user went missing.
+func diffTenant(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("name") || !d.NewValueKnown("app_user") || !d.NewValueKnown("readonly_user") {
+		return nil
+	}
+	appUser, readOnlyUser := tenantUserNames(d)
+	if appUser == readOnlyUser {
+		return fmt.Errorf("app_user and readonly_user must differ, both are %s", appUser)
+	}
+	for _, u := range []struct{ key, user string }{{"app_user", appUser}, {"readonly_user", readOnlyUser}} {
+		key, user := u.key, u.user
+		if len(user) > tenantMaxUserLength {
+			return fmt.Errorf("%s %s is longer than %d characters, set a shorter one", key, user, tenantMaxUserLength)
+		}
+		if d.Get(key).(string) == "" {
+			if err := d.SetNew(key, user); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChange("password_version") || d.Get("app_password").(string) == "" {
+		if err := d.SetNewComputed("app_password"); err != nil {
+			return err
+		}
+	}
+	readOnly := d.Get("create_readonly_user").(bool)
+	if d.HasChange("create_readonly_user") || (readOnly && (d.HasChange("password_version") || d.Get("readonly_password").(string) == "")) {
+		return d.SetNewComputed("readonly_password")
+	}
+	return nil
+}
+
+// generateTenantPassword returns a random password of length characters,
+// with one of each of tenantPasswordClasses at least.
+func generateTenantPassword(length int) (string, error) {
+	alphabet := strings.Join(tenantPasswordClasses, "")
+	for {
+		password := make([]byte, length)
+		for i := range password {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+			if err != nil {
+				return "", err
+			}
+			password[i] = alphabet[n.Int64()]
+		}
+		complete := true
+		for _, class := range tenantPasswordClasses {
+			complete = complete && strings.ContainsAny(string(password), class)
+		}
+		if complete {
+			return string(password), nil
+		}
+	}
+}
+
+// tenantUsers returns the users of a tenant, with new passwords and their
+// connection limits.
+func tenantUsers(d *schema.ResourceData) (app *databaseOwner, readOnly *databaseOwner, err error) {
+	appUser, readOnlyUser := tenantUserNames(d)
+	host := d.Get("host").(string)
+	length := d.Get("password_length").(int)
+	appLimit, readOnlyLimit := tenantConnectionLimits(d)
+
+	appPassword, err := generateTenantPassword(length)
+	if err != nil {
+		return nil, nil, err
+	}
+	app = &databaseOwner{databaseGrantee: databaseGrantee{User: appUser, Host: host}, Password: appPassword, MaxUserConnections: appLimit}
+	if d.Get("create_readonly_user").(bool) {
+		readOnlyPassword, err := generateTenantPassword(length)
+		if err != nil {
+			return nil, nil, err
+		}
+		readOnly = &databaseOwner{databaseGrantee: databaseGrantee{User: readOnlyUser, Host: host}, Password: readOnlyPassword, MaxUserConnections: readOnlyLimit}
+	}
+	return app, readOnly, nil
+}
+
+func tenantReadOnlyGrant(readOnly *databaseOwner) databaseGrant {
+	return databaseGrant{databaseGrantee: readOnly.databaseGrantee, Privileges: tenantReadOnlyPrivileges}
+}
+
+func createTenantReadOnlyUser(ctx context.Context, s *databaseStatements, database string, readOnly *databaseOwner) error {
+	if err := s.exec(ctx, readOnly.createSQL(), "CREATE USER "+readOnly.sqlString(), readOnly.dropSQL()); err != nil {
+		return fmt.Errorf("failed creating read-only %s: %v", readOnly, err)
+	}
+	grantSQL := tenantReadOnlyGrant(readOnly).grantSQL(database)
+	if err := s.exec(ctx, grantSQL, grantSQL, ""); err != nil {
+		return fmt.Errorf("failed granting read-only %s: %v", readOnly, err)
+	}
+	return nil
+}
+
+// CreateTenant creates the database and the users of a tenant. When a step
+// fails, the ones before it are undone, so no half-provisioned tenant is
+// left behind.
+func CreateTenant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Get("name").(string)
+	app, readOnly, err := tenantUsers(d)
+	if err != nil {
+		return diag.Errorf("failed generating passwords: %v", err)
+	}
+
+	s := &databaseStatements{db: db}
+	err = func() error {
+		stmtSQL := tenantDatabaseSQL("CREATE", d)
+		if err := s.exec(ctx, stmtSQL, stmtSQL, "DROP DATABASE "+quoteIdentifier(name)); err != nil {
+			return fmt.Errorf("failed creating database %s: %v", name, err)
+		}
+		if err := createDatabaseOwner(ctx, s, name, app); err != nil {
+			return err
+		}
+		if readOnly != nil {
+			return createTenantReadOnlyUser(ctx, s, name, readOnly)
+		}
+		return nil
+	}()
+	if err != nil {
+		s.rollback(ctx)
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	d.Set("app_user", app.User)
+	d.Set("app_password", app.Password)
+	if readOnly != nil {
+		d.Set("readonly_user", readOnly.User)
+		d.Set("readonly_password", readOnly.Password)
+	}
+	return ReadTenant(ctx, d, meta)
+}
+
+func tenantDatabaseSQL(verb string, d *schema.ResourceData) string {
+	return fmt.Sprintf("%s DATABASE %s %s%s %s%s", verb, quoteIdentifier(d.Get("name").(string)),
+		defaultCharacterSetKeyword, quoteIdentifier(d.Get("default_character_set").(string)),
+		defaultCollateKeyword, quoteIdentifier(d.Get("default_collation").(string)))
+}
+
+// UpdateTenant alters the database, sets new passwords and creates the
+// users that went missing or the read-only user once enabled.
+func UpdateTenant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Id()
+
+	if d.HasChange("default_character_set") || d.HasChange("default_collation") {
+		stmtSQL := tenantDatabaseSQL("ALTER", d)
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed altering database %s: %v", name, err)
+		}
+	}
+
+	app, readOnly, err := tenantUsers(d)
+	if err != nil {
+		return diag.Errorf("failed generating passwords: %v", err)
+	}
+
+	if d.HasChange("password_version") || d.Get("app_password").(string) == "" {
+		if err := setTenantUser(ctx, db, meta, name, app, func(s *databaseStatements) error {
+			return createDatabaseOwner(ctx, s, name, app)
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("app_password", app.Password)
+	}
+
+	switch {
+	case readOnly == nil && d.HasChange("create_readonly_user"):
+		_, readOnlyUser := tenantUserNames(d)
+		dropped := &databaseOwner{databaseGrantee: databaseGrantee{User: readOnlyUser, Host: d.Get("host").(string)}}
+		log.Println("[DEBUG] Executing statement:", dropped.dropSQL())
+		if _, err := db.ExecContext(ctx, dropped.dropSQL()); err != nil {
+			return diag.Errorf("failed dropping read-only %s: %v", dropped, err)
+		}
+		d.Set("readonly_password", "")
+	case readOnly != nil && (d.HasChange("create_readonly_user") || d.HasChange("password_version") || d.Get("readonly_password").(string) == ""):
+		if err := setTenantUser(ctx, db, meta, name, readOnly, func(s *databaseStatements) error {
+			return createTenantReadOnlyUser(ctx, s, name, readOnly)
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("readonly_password", readOnly.Password)
+	}
+
+	// Limits of users just created are set again, which changes nothing.
+	if d.HasChange("connection_limits") {
+		for _, user := range []*databaseOwner{app, readOnly} {
+			if user == nil {
+				continue
+			}
+			if err := alterUserConnectionLimit(ctx, db, user); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return ReadTenant(ctx, d, meta)
+}
+
+// setTenantUser sets the password of a user of a tenant, or creates it with
+// create when it doesn't exist.
+func setTenantUser(ctx context.Context, db *sql.DB, meta interface{}, database string, user *databaseOwner, create func(*databaseStatements) error) error {
+	found, err := userExists(ctx, db, meta, user.User, user.Host)
+	if err != nil {
+		return fmt.Errorf("failed reading %s: %v", user, err)
+	}
+	if !found {
+		log.Printf("[INFO] Creating missing %s of tenant %s", user, database)
+		s := &databaseStatements{db: db}
+		if err := create(s); err != nil {
+			s.rollback(ctx)
+			return err
+		}
+		return nil
+	}
+
+	stmtSQL := fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", user.sqlString(), quoteString(user.Password))
+	log.Println("[DEBUG] Executing statement: ALTER USER", user.sqlString())
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed changing the password of %s: %v", user, err)
+	}
+	return nil
+}
+
+// ReadTenant removes the tenant from the state when its database is gone. A
+// missing user clears its password, so the next apply creates it again.
+// Connection limits are read when they're managed, and the usage of the
+// users always.
+func ReadTenant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Id()
+
+	var charset, collation string
+	err = db.QueryRowContext(ctx, "SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?", name).Scan(&charset, &collation)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Printf("[WARN] Database of tenant %s not found, removing it from state", name)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("failed reading database of tenant %s: %v", name, err)
+	}
+	d.Set("name", name)
+	d.Set("default_character_set", charset)
+	d.Set("default_collation", collation)
+
+	host := d.Get("host").(string)
+	appUser, readOnlyUser := tenantUserNames(d)
+	type tenantUser struct {
+		name, passwordKey, limitKey string
+		limit                       int
+	}
+	users := []tenantUser{{name: appUser, passwordKey: "app_password", limitKey: "app_max_user_connections"}}
+	if d.Get("create_readonly_user").(bool) {
+		users = append(users, tenantUser{name: readOnlyUser, passwordKey: "readonly_password", limitKey: "readonly_max_user_connections"})
+	}
+	_, limitsManaged := d.GetOk("connection_limits")
+	// Limits of users that aren't read are kept as configured.
+	appLimit, readOnlyLimit := tenantConnectionLimits(d)
+	limits := map[string]interface{}{"app_max_user_connections": appLimit, "readonly_max_user_connections": readOnlyLimit}
+	var userNames []string
+	for i, user := range users {
+		userNames = append(userNames, user.name)
+		found, err := userExists(ctx, db, meta, user.name, host)
+		if err != nil {
+			return diag.Errorf("failed reading user %s@%s: %v", user.name, host, err)
+		}
+		if !found {
+			log.Printf("[WARN] User %s@%s of tenant %s not found, it will be created again", user.name, host, name)
+			d.Set(user.passwordKey, "")
+			continue
+		}
+		limit, err := readUserConnectionLimit(ctx, db, user.name, host)
+		if err != nil {
+			if limitsManaged {
+				return diag.Errorf("failed reading the connection limit of %s@%s: %v", user.name, host, err)
+			}
+			log.Printf("[WARN] Could not read the connection limit of %s@%s: %v", user.name, host, err)
+		}
+		users[i].limit = limit
+		limits[user.limitKey] = limit
+	}
+	if limitsManaged {
+		if err := d.Set("connection_limits", []interface{}{limits}); err != nil {
+			return diag.Errorf("failed setting connection_limits: %v", err)
+		}
+	}
+
+	usage, err := readTenantConnectionUsage(ctx, db, userNames)
+	if err != nil {
+		// performance_schema may be off, which doesn't make the tenant fail.
+		log.Printf("[WARN] Could not read the connections of tenant %s: %v", name, err)
+	}
+	report := make([]interface{}, len(users))
+	for i, user := range users {
+		report[i] = map[string]interface{}{
+			"user":                 user.name,
+			"current_connections":  int(usage[user.name][0]),
+			"total_connections":    int(usage[user.name][1]),
+			"max_user_connections": user.limit,
+		}
+	}
+	if err := d.Set("connection_usage", report); err != nil {
+		return diag.Errorf("failed setting connection_usage: %v", err)
+	}
+	return nil
+}
+
+// DeleteTenant drops the users first, so they can't connect anymore, then
+// kills their sessions and drops the database.
+func DeleteTenant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Id()
+	host := d.Get("host").(string)
+	appUser, readOnlyUser := tenantUserNames(d)
+
+	users := []string{appUser}
+	if d.Get("create_readonly_user").(bool) {
+		users = append(users, readOnlyUser)
+	}
+	for _, user := range users {
+		dropped := &databaseOwner{databaseGrantee: databaseGrantee{User: user, Host: host}}
+		log.Println("[DEBUG] Executing statement:", dropped.dropSQL())
+		if _, err := db.ExecContext(ctx, dropped.dropSQL()); err != nil {
+			return diag.Errorf("failed dropping %s of tenant %s: %v", dropped, name, err)
+		}
+	}
+	if err := killTenantSessions(ctx, db, users); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("keep_database_on_destroy").(bool) {
+		log.Printf("[INFO] Keeping database %s of tenant %s", name, name)
+		return nil
+	}
+	stmtSQL := "DROP DATABASE IF EXISTS " + quoteIdentifier(name)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed dropping database of tenant %s: %v", name, err)
+	}
+	return nil
+}
+
+// killTenantSessions kills the sessions of users, which dropping them
+// keeps, so they don't hold locks on the database being dropped.
+func killTenantSessions(ctx context.Context, db *sql.DB, users []string) error {
+	args := make([]interface{}, len(users))
+	for i, user := range users {
+		args[i] = user
+	}
+	query := fmt.Sprintf("SELECT ID FROM information_schema.PROCESSLIST WHERE USER IN (?%s) AND ID <> CONNECTION_ID()", strings.Repeat(", ?", len(users)-1))
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed reading sessions of the users: %v", err)
+	}
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		stmtSQL := fmt.Sprintf("KILL CONNECTION %d", id)
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		// The session may have ended in the meantime.
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil && mysqlErrorNumber(err) != unknownThreadErrCode {
+			return fmt.Errorf("failed killing session %d: %v", id, err)
+		}
+	}
+	return nil
+}
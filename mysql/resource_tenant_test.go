@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGenerateTenantPassword(t *testing.T) {
+	alphabet := strings.Join(tenantPasswordClasses, "")
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		password, err := generateTenantPassword(16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(password) != 16 {
+			t.Fatalf("password %q has %d characters, want 16", password, len(password))
+		}
+		for _, class := range tenantPasswordClasses {
+			if !strings.ContainsAny(password, class) {
+				t.Errorf("password %q has none of %q", password, class)
+			}
+		}
+		if strings.Trim(password, alphabet) != "" {
+			t.Errorf("password %q has characters outside of %q", password, alphabet)
+		}
+		if seen[password] {
+			t.Errorf("password %q generated twice", password)
+		}
+		seen[password] = true
+	}
+}
+
+func TestTenantUserNames(t *testing.T) {
+	tests := []struct {
+		config                map[string]interface{}
+		appUser, readOnlyUser string
+	}{
+		{map[string]interface{}{"name": "acme"}, "acme_app", "acme_ro"},
+		{map[string]interface{}{"name": "acme", "app_user": "acme", "readonly_user": "acme_reports"}, "acme", "acme_reports"},
+	}
+	for _, tt := range tests {
+		d := schema.TestResourceDataRaw(t, resourceTenant().Schema, tt.config)
+		appUser, readOnlyUser := tenantUserNames(d)
+		if appUser != tt.appUser || readOnlyUser != tt.readOnlyUser {
+			t.Errorf("tenantUserNames(%v) = %s, %s, want %s, %s", tt.config, appUser, readOnlyUser, tt.appUser, tt.readOnlyUser)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceTenant().Schema, map[string]interface{}{"name": "acme", "readonly_user": "acme_reports"})
+	app, readOnly, err := tenantUsers(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tenantReadOnlyGrant(readOnly).grantSQL("acme"), "GRANT SELECT, SHOW VIEW ON `acme`.* TO `acme_reports`@`%`"; got != want {
+		t.Errorf("read-only grant is %q, want %q", got, want)
+	}
+	if app.Password == readOnly.Password || len(app.Password) != 32 {
+		t.Errorf("generated passwords %q and %q, want two different ones of 32 characters", app.Password, readOnly.Password)
+	}
+}
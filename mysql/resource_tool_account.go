@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// toolAccountGrants are the grants monitoring and management tools document
+// for their accounts.
+var toolAccountGrants = map[string][]accountGrant{
+	"orchestrator": {
+		{Privileges: []string{"SUPER", "PROCESS", "REPLICATION SLAVE", "REPLICATION CLIENT", "RELOAD"}, Database: "*", Table: "*"},
+		// MariaDB 10.5.9 split SHOW SLAVE STATUS off REPLICATION CLIENT.
+		{Privileges: []string{"SLAVE MONITOR"}, Database: "*", Table: "*", Flavor: accountFlavorMariaDB, MinVersion: "10.5.9"},
+		{Privileges: []string{"SELECT"}, Database: "meta", Table: "*"},
+		{Privileges: []string{"SELECT"}, Database: "mysql", Table: "slave_master_info", Flavor: accountFlavorMySQL, RequireTable: true},
+		{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "replication_group_members", Flavor: accountFlavorMySQL, RequireTable: true},
+		{Privileges: []string{"SELECT"}, Database: "ndbinfo", Table: "processes", Flavor: accountFlavorMySQL, RequireTable: true},
+	},
+	"pt-heartbeat": {
+		{Privileges: []string{"REPLICATION CLIENT"}, Database: "*", Table: "*"},
+		{Privileges: []string{"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE"}, Database: "percona", Table: "*"},
+	},
+	"datadog": {
+		{Privileges: []string{"REPLICATION CLIENT", "PROCESS"}, Database: "*", Table: "*"},
+		{Privileges: []string{"SELECT"}, Database: "performance_schema", Table: "*"},
+	},
+	"pmm": {
+		{Privileges: []string{"SELECT", "PROCESS", "REPLICATION CLIENT", "RELOAD"}, Database: "*", Table: "*"},
+		{Privileges: []string{"BACKUP_ADMIN"}, Database: "*", Table: "*", Flavor: accountFlavorMySQL, MinVersion: "8.0.0"},
+		{Privileges: []string{"SLAVE MONITOR"}, Database: "*", Table: "*", Flavor: accountFlavorMariaDB, MinVersion: "10.5.9"},
+	},
+}
+
+func toolAccountNames() []string {
+	names := make([]string, 0, len(toolAccountGrants))
+	for name := range toolAccountGrants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func resourceToolAccount() *schema.Resource {
+	return accountProfileResource(func(d interface{ Get(string) interface{} }) []accountGrant {
+		return toolAccountGrants[d.Get("tool").(string)]
+	}, map[string]*schema.Schema{
+		"tool": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice(toolAccountNames(), false),
+			Description:  "Tool the account is for: datadog, orchestrator, pmm or pt-heartbeat",
+		},
+	})
+}
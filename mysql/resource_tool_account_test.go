@@ -0,0 +1,41 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccToolAccount_datadog(t *testing.T) {
+	resourceName := "mysql_tool_account.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccToolAccountConfig("datadog"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "grants.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "grants.0", "REPLICATION CLIENT, PROCESS ON *.*"),
+					resource.TestCheckResourceAttr(resourceName, "grants.1", "SELECT ON performance_schema.*"),
+				),
+			},
+		},
+	})
+}
+
+func testAccToolAccountConfig(tool string) string {
+	return fmt.Sprintf(`
+resource "mysql_tool_account" "test" {
+  user               = "tf_%s"
+  tool               = "%s"
+  plaintext_password = "secret"
+}
+`, tool, tool)
+}
@@ -1,11 +1,15 @@
 package mysql
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -21,22 +25,53 @@ func formatUserIdentifier(user, host string) string {
 	return fmt.Sprintf("%s@%s", quoteIdentifier(user), quoteIdentifier(host))
 }
 
+// MySQL string literals need to escape: backslash, single quote, double quote, null, newline, carriage return
+var stringLiteralReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	`"`, `\"`,
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
+
+// escapeString escapes a string literal for MySQL, without quoting it
+func escapeString(s string) string {
+	return stringLiteralReplacer.Replace(s)
+}
+
 // quoteString escapes and quotes a string literal for MySQL
 func quoteString(s string) string {
-	// MySQL string literals need to escape: backslash, single quote, double quote, null, newline, carriage return
-	replacer := strings.NewReplacer(
-		`\`, `\\`,
-		`'`, `\'`,
-		`"`, `\"`,
-		"\x00", `\0`,
-		"\n", `\n`,
-		"\r", `\r`,
-	)
-	return fmt.Sprintf("'%s'", replacer.Replace(s))
+	return fmt.Sprintf("'%s'", escapeString(s))
+}
+
+// reservedAccounts are the system accounts of MySQL, MariaDB and Percona
+// XtraDB Cluster. Changing them breaks the server, so they can't be managed.
+var reservedAccounts = map[string]bool{
+	"mysql.infoschema":           true,
+	"mysql.session":              true,
+	"mysql.sys":                  true,
+	"mariadb.sys":                true,
+	"mysql.pxc.internal.session": true,
+	"mysql.pxc.sst.role":         true,
+}
+
+func checkNotReservedAccount(user string) error {
+	if reservedAccounts[user] {
+		return fmt.Errorf("%s is a reserved system account and can't be managed", user)
+	}
+	return nil
+}
+
+func validateNotReservedAccount(v interface{}, k string) (ws []string, es []error) {
+	if err := checkNotReservedAccount(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s: %v", k, err))
+	}
+	return
 }
 
 func resourceUser() *schema.Resource {
-	return &schema.Resource{
+	return withIDStateUpgrader(&schema.Resource{
 		CreateContext: CreateUser,
 		UpdateContext: UpdateUser,
 		ReadContext:   ReadUser,
@@ -46,6 +81,20 @@ func resourceUser() *schema.Resource {
 		},
 
 		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := setDefaultUserHost(d, meta); err != nil {
+				return err
+			}
+
+			// Switching between host and hosts changes the account identity.
+			if d.Id() != "" && d.HasChange("hosts") {
+				o, n := d.GetChange("hosts")
+				if o.(*schema.Set).Len() == 0 || n.(*schema.Set).Len() == 0 {
+					if err := d.ForceNew("hosts"); err != nil {
+						return err
+					}
+				}
+			}
+
 			// Validate max_user_connections is not set on TiDB
 			if _, ok := d.GetOk("max_user_connections"); ok {
 				if err := checkMaxUserConnectionsSupport(ctx, meta); err != nil {
@@ -60,21 +109,31 @@ func resourceUser() *schema.Resource {
 				}
 			}
 
-			return nil
+			return diffUserExpiry(d)
 		},
 
 		Schema: map[string]*schema.Schema{
 			"user": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNotReservedAccount,
 			},
 
 			"host": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
-				Default:  "localhost",
+			},
+
+			"hosts": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"host"},
+				Description:   "Create the same account for each of these hosts, sharing password and settings",
 			},
 
 			"plaintext_password": {
@@ -84,6 +143,13 @@ func resourceUser() *schema.Resource {
 				StateFunc: hashSum,
 			},
 
+			"client_side_hashing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Hash the password in the provider and send only the auth string, so it never reaches the server in plaintext. Requires auth_plugin mysql_native_password or caching_sha2_password.",
+			},
+
 			"password": {
 				Type:          schema.TypeString,
 				Optional:      true,
@@ -146,7 +212,8 @@ func resourceUser() *schema.Resource {
 				Type:             schema.TypeString,
 				Optional:         true,
 				Sensitive:        true,
-				DiffSuppressFunc: NewEmptyStringSuppressFunc,
+				DiffSuppressFunc: suppressEncryptedDiff(NewEmptyStringSuppressFunc),
+				ValidateFunc:     validateAuthStringEncoding,
 				ConflictsWith:    []string{"plaintext_password", "password", "password_wo"},
 			},
 			"auth_string_hex": {
@@ -154,13 +221,15 @@ func resourceUser() *schema.Resource {
 				Optional:         true,
 				Sensitive:        true,
 				StateFunc:        NormalizeHexStringStateFunc,
-				DiffSuppressFunc: SuppressHexStringDiff,
+				DiffSuppressFunc: suppressEncryptedDiff(SuppressHexStringDiff),
 				ConflictsWith:    []string{"plaintext_password", "password", "password_wo", "auth_string_hashed"},
 			},
 			"tls_option": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "NONE",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "NONE",
+				ValidateFunc:     validateTLSOption,
+				DiffSuppressFunc: SuppressTLSOptionDiff,
 			},
 
 			"retain_old_password": {
@@ -181,14 +250,66 @@ func resourceUser() *schema.Resource {
 				Description:  "Maximum number of simultaneous connections for the user (0 = unlimited). Supported on MySQL 5.0+ and MariaDB.",
 			},
 
+			"create_user_statement": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Output of SHOW CREATE USER with the authentication string elided",
+			},
+
 			"max_statement_time": {
 				Type:         schema.TypeFloat,
 				Optional:     true,
 				ValidateFunc: validation.FloatAtLeast(0),
 				Description:  "Maximum execution time for statements in seconds (0 = unlimited). Supports fractional values (e.g., 0.01 for 10ms, 30.5 for 30.5s). Only supported on MariaDB 10.1.1+, not MySQL.",
 			},
+
+			"profile": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateUserProfile,
+				Description:  "definition of a mysql_user_profile data source to apply to the user",
+			},
+
+			"schema_owner": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"plaintext_password", "password", "password_wo", "auth_string_hashed", "auth_string_hex", "aad_identity"},
+				Description:   "Create a locked account without password, which owns objects (e.g. as DEFINER of views and routines) but can't log in",
+			},
+
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Take over the account if it already exists instead of failing, then apply the configured settings to it",
+			},
+
+			"expires_at": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  "RFC 3339 timestamp after which the account is locked or dropped, enforced on refresh and apply",
+			},
+
+			"expiry_action": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      userExpiryLock,
+				ValidateFunc: validation.StringInSlice([]string{userExpiryLock, userExpiryDrop}, false),
+				Description:  "What happens to the account once expires_at passed: lock or drop",
+			},
+
+			"expired": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether expires_at passed and the account was locked or dropped",
+			},
+
+			"pre_sql":  sqlHookSchema("before"),
+			"post_sql": sqlHookSchema("after"),
 		},
-	}
+	}, upgradeUserStateV0)
 }
 
 func checkRetainCurrentPasswordSupport(ctx context.Context, meta interface{}) error {
@@ -251,12 +372,73 @@ func checkMaxStatementTimeSupport(ctx context.Context, meta interface{}) error {
 	return nil
 }
 
+// userHosts returns the hosts the account is created for: either hosts or
+// the single host.
+func userHosts(d *schema.ResourceData, meta interface{}) []string {
+	if hosts := setToArray(d.Get("hosts")); len(hosts) > 0 {
+		sort.Strings(hosts)
+		return hosts
+	}
+	if host := d.Get("host").(string); host != "" {
+		return []string{host}
+	}
+	return []string{defaultUserHostFromMeta(meta)}
+}
+
+func userResourceId(d *schema.ResourceData, meta interface{}) string {
+	return fmt.Sprintf("%s@%s", d.Get("user").(string), strings.Join(userHosts(d, meta), ","))
+}
+
 func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	hosts := userHosts(d, meta)
+	if len(setToArray(d.Get("hosts"))) == 0 {
+		d.Set("host", hosts[0])
+	}
+
+	defer resetSQLHookSession(db, d)
+	if err := runSQLHooks(ctx, db, d, "pre_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var created []string
+	for _, host := range hosts {
+		if diags := createUser(ctx, db, d, meta, host); diags.HasError() {
+			if len(setToArray(d.Get("hosts"))) > 0 {
+				// Only the hosts created so far go to the state, so
+				// destroying the tainted resource drops them.
+				if found, err := userExists(ctx, db, meta, d.Get("user").(string), host); err == nil && found {
+					created = append(created, host)
+				}
+				setCreatedUserHosts(d, meta, created)
+			}
+			return diags
+		}
+		created = append(created, host)
+	}
+	d.Set("expired", false)
+
+	if err := runSQLHooks(ctx, db, d, "post_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func setCreatedUserHosts(d *schema.ResourceData, meta interface{}, created []string) {
+	if len(created) == 0 {
+		d.SetId("")
+		return
+	}
+	d.Set("hosts", created)
+	d.SetId(userResourceId(d, meta))
+}
+
+func createUser(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, host string) diag.Diagnostics {
 	var authStm string
 	var auth string
 	var createObj = "USER"
@@ -280,38 +462,41 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
-	var hashed string
-	if v, ok := d.GetOk("auth_string_hashed"); ok {
-		hashed = v.(string)
-		if hashed != "" {
-			if authStm == "" {
-				return diag.Errorf("auth_string_hashed is not supported for auth plugin %s", auth)
-			}
-			authStm = fmt.Sprintf("%s AS ?", authStm)
+	hashed, err := getDecryptedString(ctx, d, meta, "auth_string_hashed")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var hashedLiteral string
+	if hashed != "" {
+		if authStm == "" {
+			return diag.Errorf("auth_string_hashed is not supported for auth plugin %s", auth)
 		}
+		hashedLiteral, err = hashedAuthStringLiteral(auth, hashed)
+		if err != nil {
+			return diag.Errorf("invalid auth_string_hashed: %v", err)
+		}
+		authStm = fmt.Sprintf("%s AS ?", authStm)
 	}
-	var hashedHex string
-	if v, ok := d.GetOk("auth_string_hex"); ok {
-		hashedHex = v.(string)
-		if hashedHex != "" {
-			if hashed != "" {
-				return diag.Errorf("can not specify both auth_string_hashed and auth_string_hex")
-			}
-			if authStm == "" {
-				return diag.Errorf("auth_string_hex is not supported for auth plugin %s", auth)
-			}
-			normalizedHex := normalizeHexString(hashedHex)
-			hexDigits := normalizedHex[2:] // Remove the "0x" prefix for validation
-
-			if err := validateHexString(hexDigits); err != nil {
-				return diag.Errorf("invalid hex string for auth_string_hex: %v", err)
-			}
-			authStm = fmt.Sprintf("%s AS 0x%s", authStm, hexDigits)
+	hashedHex, err := getDecryptedString(ctx, d, meta, "auth_string_hex")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if hashedHex != "" {
+		if hashed != "" {
+			return diag.Errorf("can not specify both auth_string_hashed and auth_string_hex")
 		}
+		if authStm == "" {
+			return diag.Errorf("auth_string_hex is not supported for auth plugin %s", auth)
+		}
+		normalizedHex := normalizeHexString(hashedHex)
+		hexDigits := normalizedHex[2:] // Remove the "0x" prefix for validation
 
+		if err := validateHexString(hexDigits); err != nil {
+			return diag.Errorf("invalid hex string for auth_string_hex: %v", err)
+		}
+		authStm = fmt.Sprintf("%s AS 0x%s", authStm, hexDigits)
 	}
 	user := d.Get("user").(string)
-	host := d.Get("host").(string)
 
 	var stmtSQL string
 
@@ -345,11 +530,19 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.Errorf("cannot use IAM auth against localhost")
 	}
 
+	if d.Get("client_side_hashing").(bool) && password != "" {
+		authStm, err = clientSideAuthClause(auth, password)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		password = ""
+	}
+
 	if authStm != "" {
 		// Handle auth_string_hashed case
 		if hashed != "" {
 			// authStm already contains " AS ?" from line 197
-			stmtSQL += strings.Replace(authStm, " AS ?", fmt.Sprintf(" AS %s", quoteString(hashed)), 1)
+			stmtSQL += strings.Replace(authStm, " AS ?", " AS "+hashedLiteral, 1)
 		} else {
 			stmtSQL += authStm
 		}
@@ -400,10 +593,14 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		if len(resourceLimits) > 0 && getVersionFromMeta(ctx, meta).GreaterThanOrEqual(createUserWithVersion) {
 			stmtSQL += " WITH " + strings.Join(resourceLimits, " ")
 		}
+
+		if d.Get("schema_owner").(bool) {
+			stmtSQL += " ACCOUNT LOCK"
+		}
 	}
 
 	// Log statement with sensitive values redacted
-	logStmt := stmtSQL
+	logStmt := sanitizeCreateUserStatement(stmtSQL)
 	if password != "" {
 		logStmt = strings.Replace(logStmt, quoteString(password), "<SENSITIVE>", -1)
 	}
@@ -413,6 +610,9 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	log.Println("[DEBUG] Executing statement:", logStmt)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
+	if mysqlErrorNumber(err) == unknownUserErrCode && d.Get("adopt_existing").(bool) {
+		return adoptUser(ctx, db, d, meta, user, host)
+	}
 	if err != nil {
 		return diag.Errorf("failed executing SQL: %v", err)
 	}
@@ -431,8 +631,7 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
-	userId := fmt.Sprintf("%s@%s", user, host)
-	d.SetId(userId)
+	d.SetId(userResourceId(d, meta))
 
 	if updateStmtSql != "" {
 		log.Println("[DEBUG] Executing statement:", updateStmtSql, "args:", updateArgs)
@@ -443,6 +642,39 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
+	profile, err := parseUserProfile(d.Get("profile").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if profile != nil {
+		if err := applyUserProfile(ctx, db, d, user, host, nil, profile); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// adoptUser takes over an account that already exists and makes it match the
+// configuration. Create sees every configured attribute as changed, so
+// updateUser applies all of them.
+func adoptUser(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, user, host string) diag.Diagnostics {
+	log.Printf("[INFO] Adopting existing user %s", formatUserIdentifier(user, host))
+	d.SetId(userResourceId(d, meta))
+
+	if diags := updateUser(ctx, db, d, meta, host); diags.HasError() {
+		return diags
+	}
+
+	profile, err := parseUserProfile(d.Get("profile").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if profile != nil {
+		if err := applyUserProfile(ctx, db, d, user, host, nil, profile); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	return nil
 }
 
@@ -466,6 +698,83 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.FromErr(err)
 	}
 
+	if d.HasChange("expired") {
+		if d.Get("expired").(bool) {
+			if err := enforceUserExpiry(ctx, db, d, meta); err != nil {
+				return diag.FromErr(err)
+			}
+		} else if err := unlockUser(ctx, db, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	// Dropped accounts have nothing left to update.
+	if d.Get("expired").(bool) && d.Get("expiry_action").(string) == userExpiryDrop {
+		return nil
+	}
+
+	defer resetSQLHookSession(db, d)
+	if err := runSQLHooks(ctx, db, d, "pre_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	added := schema.NewSet(schema.HashString, nil)
+	if d.HasChange("hosts") {
+		o, n := d.GetChange("hosts")
+		added = n.(*schema.Set).Difference(o.(*schema.Set))
+
+		for _, host := range setToArray(o.(*schema.Set).Difference(n.(*schema.Set))) {
+			stmtSQL := fmt.Sprintf("DROP USER %s", formatUserIdentifier(d.Get("user").(string), host))
+			log.Println("[DEBUG] Executing statement:", stmtSQL)
+			if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+				return diag.Errorf("failed dropping user for removed host %s: %v", host, err)
+			}
+		}
+
+		for _, host := range setToArray(added) {
+			if diags := createUser(ctx, db, d, meta, host); diags.HasError() {
+				return diags
+			}
+		}
+		d.SetId(userResourceId(d, meta))
+	}
+
+	for _, host := range userHosts(d, meta) {
+		if added.Contains(host) {
+			continue
+		}
+		if diags := updateUser(ctx, db, d, meta, host); diags.HasError() {
+			return diags
+		}
+	}
+
+	if d.HasChange("profile") {
+		o, n := d.GetChange("profile")
+		oldProfile, err := parseUserProfile(o.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		newProfile, err := parseUserProfile(n.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, host := range userHosts(d, meta) {
+			if added.Contains(host) {
+				continue
+			}
+			if err := applyUserProfile(ctx, db, d, d.Get("user").(string), host, oldProfile, newProfile); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if err := runSQLHooks(ctx, db, d, "post_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func updateUser(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, host string) diag.Diagnostics {
 	var auth string
 	if v, ok := d.GetOk("auth_plugin"); ok {
 		auth = v.(string)
@@ -474,11 +783,23 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		if d.HasChange("tls_option") || d.HasChange("auth_plugin") || d.HasChange("auth_string_hashed") || d.HasChange("auth_string_hex") {
 			var stmtSQL string
 
+			hashed, err := getDecryptedString(ctx, d, meta, "auth_string_hashed")
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			authStringHex, err := getDecryptedString(ctx, d, meta, "auth_string_hex")
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
 			authString := ""
-			if d.Get("auth_string_hashed").(string) != "" {
-				authString = fmt.Sprintf("IDENTIFIED WITH %s AS '%s'", d.Get("auth_plugin"), d.Get("auth_string_hashed"))
-			} else if d.Get("auth_string_hex").(string) != "" {
-				authStringHex := d.Get("auth_string_hex").(string)
+			if hashed != "" {
+				hashedLiteral, err := hashedAuthStringLiteral(auth, hashed)
+				if err != nil {
+					return diag.Errorf("invalid auth_string_hashed: %v", err)
+				}
+				authString = fmt.Sprintf("IDENTIFIED WITH %s AS %s", auth, hashedLiteral)
+			} else if authStringHex != "" {
 				normalizedHex := normalizeHexString(authStringHex)
 
 				hexDigits := normalizedHex[2:]
@@ -488,12 +809,12 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 				authString = fmt.Sprintf("IDENTIFIED WITH %s AS 0x%s", d.Get("auth_plugin"), hexDigits)
 			}
 			stmtSQL = fmt.Sprintf("ALTER USER %s %s  REQUIRE %s",
-				formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)),
+				formatUserIdentifier(d.Get("user").(string), host),
 				authString,
 				d.Get("tls_option").(string))
 
 			log.Println("[DEBUG] Executing query:", stmtSQL)
-			_, err := db.ExecContext(ctx, stmtSQL)
+			_, err = db.ExecContext(ctx, stmtSQL)
 			if err != nil {
 				return diag.Errorf("failed running query: %v", err)
 			}
@@ -508,7 +829,7 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		} else {
 			var stmtSQL string
 			stmtSQL = fmt.Sprintf("ALTER USER %s DISCARD OLD PASSWORD",
-				formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)))
+				formatUserIdentifier(d.Get("user").(string), host))
 
 			log.Println("[DEBUG] Executing query:", stmtSQL)
 			_, err := db.ExecContext(ctx, stmtSQL)
@@ -543,8 +864,23 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
-	if newpw != nil {
-		stmtSQL, err := getSetPasswordStatement(ctx, meta, d.Get("user").(string), d.Get("host").(string), newpw.(string), retainPassword)
+	if newpw != nil && d.Get("client_side_hashing").(bool) {
+		authClause, err := clientSideAuthClause(d.Get("auth_plugin").(string), newpw.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		stmtSQL := fmt.Sprintf("ALTER USER %s%s", formatUserIdentifier(d.Get("user").(string), host), authClause)
+		if retainPassword {
+			stmtSQL += " RETAIN CURRENT PASSWORD"
+		}
+
+		log.Println("[DEBUG] Executing query:", sanitizeCreateUserStatement(stmtSQL))
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return diag.Errorf("failed changing password: %v", err)
+		}
+	} else if newpw != nil {
+		stmtSQL, err := getSetPasswordStatement(ctx, meta, d.Get("user").(string), host, newpw.(string), retainPassword)
 		if err != nil {
 			return diag.Errorf("failed getting change password statement: %v", err)
 		}
@@ -562,9 +898,13 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	if d.HasChange("tls_option") && getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
 		var stmtSQL string
 
+		tlsOption := d.Get("tls_option").(string)
+		if tlsOption == "" {
+			tlsOption = "NONE"
+		}
 		stmtSQL = fmt.Sprintf("ALTER USER %s REQUIRE %s",
-			formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)),
-			d.Get("tls_option").(string))
+			formatUserIdentifier(d.Get("user").(string), host),
+			tlsOption)
 
 		log.Println("[DEBUG] Executing query:", stmtSQL)
 		_, err := db.ExecContext(ctx, stmtSQL)
@@ -573,6 +913,19 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
+	if d.HasChange("schema_owner") {
+		lockOption := "ACCOUNT UNLOCK"
+		if d.Get("schema_owner").(bool) {
+			lockOption = "ACCOUNT LOCK"
+		}
+		stmtSQL := fmt.Sprintf("ALTER USER %s %s", formatUserIdentifier(d.Get("user").(string), host), lockOption)
+
+		log.Println("[DEBUG] Executing query:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed changing account lock: %v", err)
+		}
+	}
+
 	// Handle resource limits changes (Option B: field removal resets to 0)
 	// MySQL 5.6: ALTER USER only supports PASSWORD EXPIRE, use GRANT USAGE for resource limits
 	// MySQL 5.7.6+: ALTER USER supports WITH clause for resource limits
@@ -628,12 +981,12 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 			if getVersionFromMeta(ctx, meta).LessThan(alterUserVersion) {
 				// MySQL 5.6 and earlier: use GRANT USAGE
 				stmtSQL = fmt.Sprintf("GRANT USAGE ON *.* TO %s WITH %s",
-					formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)),
+					formatUserIdentifier(d.Get("user").(string), host),
 					strings.Join(resourceLimits, " "))
 			} else {
 				// MySQL 5.7.6+: use ALTER USER
 				stmtSQL = fmt.Sprintf("ALTER USER %s WITH %s",
-					formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)),
+					formatUserIdentifier(d.Get("user").(string), host),
 					strings.Join(resourceLimits, " "))
 			}
 
@@ -676,6 +1029,129 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 	if err != nil {
 		return diag.FromErr(err)
 	}
+
+	// Expiry is enforced by UpdateUser once diffUserExpiry planned it, so a
+	// refresh doesn't change the account. Dropped accounts stay in the state,
+	// so they aren't created again.
+	if d.Get("expired").(bool) && d.Get("expiry_action").(string) == userExpiryDrop {
+		return nil
+	}
+
+	hosts := setToArray(d.Get("hosts"))
+	if len(hosts) == 0 {
+		return readUser(ctx, db, d, meta, d.Get("host").(string))
+	}
+
+	// Each host is checked separately, so a missing one is recreated.
+	var existing []string
+	for _, host := range hosts {
+		found, err := userExists(ctx, db, meta, d.Get("user").(string), host)
+		if err != nil {
+			return diag.Errorf("failed getting user: %v", err)
+		}
+		if found {
+			existing = append(existing, host)
+		}
+	}
+
+	if len(existing) == 0 {
+		log.Printf("[WARN] User %s not found for any host - removing from state", d.Get("user").(string))
+		d.SetId("")
+		return nil
+	}
+	d.Set("hosts", existing)
+
+	sort.Strings(existing)
+	return readUserHosts(ctx, db, d, meta, existing)
+}
+
+// userHostAttributes differ between the hosts of a user without being drift.
+var userHostAttributes = map[string]bool{
+	"id":                    true,
+	"user":                  true,
+	"host":                  true,
+	"hosts":                 true,
+	"create_user_statement": true,
+}
+
+// userAuthStringAttributes differ between hosts when the server salts the
+// authentication string, e.g. with caching_sha2_password.
+var userAuthStringAttributes = map[string]bool{
+	"auth_string_hashed": true,
+	"auth_string_hex":    true,
+}
+
+// readUserHosts reads each host and keeps the state of the first one that
+// differs from it, so drift on any host shows up in the plan. Hosts whose
+// authentication string alone differs are only kept when no other host
+// drifted.
+func readUserHosts(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, hosts []string) diag.Diagnostics {
+	prior := userStateAttributes(d)
+	authStringHost := ""
+	for _, host := range hosts {
+		if diags := readUser(ctx, db, d, meta, host); diags.HasError() || d.Id() == "" {
+			return diags
+		}
+		drifted, authStringDrifted := userStateDrift(prior, userStateAttributes(d))
+		if drifted {
+			log.Printf("[INFO] User %s differs from the state", formatUserIdentifier(d.Get("user").(string), host))
+			return nil
+		}
+		if authStringDrifted && authStringHost == "" {
+			authStringHost = host
+		}
+	}
+	if authStringHost != "" && authStringHost != hosts[len(hosts)-1] {
+		return readUser(ctx, db, d, meta, authStringHost)
+	}
+	return nil
+}
+
+func userStateAttributes(d *schema.ResourceData) map[string]string {
+	if state := d.State(); state != nil {
+		return state.Attributes
+	}
+	return nil
+}
+
+// userStateDrift compares the flattened attributes of two states of a user.
+func userStateDrift(prior, current map[string]string) (drifted, authStringDrifted bool) {
+	keys := map[string]bool{}
+	for k := range prior {
+		keys[k] = true
+	}
+	for k := range current {
+		keys[k] = true
+	}
+	for k := range keys {
+		name := strings.SplitN(k, ".", 2)[0]
+		if userHostAttributes[name] || prior[k] == current[k] {
+			continue
+		}
+		if userAuthStringAttributes[name] {
+			authStringDrifted = true
+		} else {
+			drifted = true
+		}
+	}
+	return drifted, authStringDrifted
+}
+
+func readUser(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, host string) diag.Diagnostics {
+	// Settings coming from the profile aren't drift of the user's own
+	// attributes.
+	if profile, _ := parseUserProfile(d.Get("profile").(string)); profile != nil {
+		tlsOption, maxUserConnections := d.Get("tls_option"), d.Get("max_user_connections")
+		defer func() {
+			if profile.TLSOption != "" && normalizeTLSOption(d.Get("tls_option").(string)) == normalizeTLSOption(profile.TLSOption) {
+				d.Set("tls_option", tlsOption)
+			}
+			if profile.MaxUserConnections != nil && d.Get("max_user_connections").(int) == *profile.MaxUserConnections {
+				d.Set("max_user_connections", maxUserConnections)
+			}
+		}()
+	}
+
 	requiredVersion, _ := version.NewVersion("5.7.0")
 	if getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
 		// Skip setting print_identified_with_as_hex if auth_plugin is aad_auth
@@ -686,9 +1162,9 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 				log.Printf("[DEBUG] Could not set print_identified_with_as_hex: %v", err)
 			}
 		}
-		stmt := fmt.Sprintf("SHOW CREATE USER %s", formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)))
+		stmt := fmt.Sprintf("SHOW CREATE USER %s", formatUserIdentifier(d.Get("user").(string), host))
 		var createUserStmt string
-		err = db.QueryRowContext(ctx, stmt).Scan(&createUserStmt)
+		err := db.QueryRowContext(ctx, stmt).Scan(&createUserStmt)
 		if err != nil {
 			errorNumber := mysqlErrorNumber(err)
 			if errorNumber == unknownUserErrCode || errorNumber == userNotFoundErrCode {
@@ -697,18 +1173,30 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 			}
 			return diag.Errorf("failed getting user: %v", err)
 		}
+		d.Set("create_user_statement", sanitizeCreateUserStatement(createUserStmt))
+		if err := readUserProfileDrift(ctx, db, d, meta, d.Get("user").(string), host, createUserStmt); err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Accounts locked outside of Terraform aren't turned into schema
+		// owners, only unlocked schema owners are drift.
+		if d.Get("schema_owner").(bool) && !strings.Contains(createUserStmt, " ACCOUNT LOCK") {
+			d.Set("schema_owner", false)
+		}
+
 		// Examples of create user:
 		// CREATE USER 'some_app'@'%' IDENTIFIED WITH 'mysql_native_password' AS '*0something' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK
 		// CREATE USER `jdoe-tf-test-47`@`example.com` IDENTIFIED WITH 'caching_sha2_password' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT
 		// CREATE USER `jdoe`@`example.com` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$i`xay#fG/\' TrbkNA82' REQUIRE NONE PASSWORD
 		// CREATE USER `hashed_hex`@`localhost` IDENTIFIED WITH 'caching_sha2_password' AS 0x244124303035242522434C16580334755221766C29210D2C415E033550367655494F314864686775414E735A742E6F474857504B623172525066574D524F30506B7A79646F30 REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT
 
-		re := regexp.MustCompile("^CREATE USER ['`]([^'`]*)['`]@['`]([^'`]*)['`] IDENTIFIED WITH ['`]([^'`]*)['`] (?:AS (?:'((?:.*?[^\\\\])?)'|(0x[0-9A-Fa-f]+)) )?REQUIRE ([^ ]*)")
-		if m := re.FindStringSubmatch(createUserStmt); len(m) == 7 {
+		if m := kReCreateUser.FindStringSubmatch(createUserStmt); len(m) == 7 {
 			d.Set("user", m[1])
-			d.Set("host", m[2])
+			if len(setToArray(d.Get("hosts"))) == 0 {
+				d.Set("host", m[2])
+			}
 			d.Set("auth_plugin", m[3])
-			d.Set("tls_option", m[6])
+			d.Set("tls_option", readTLSOption(createUserStmt))
 
 			if m[3] == "aad_auth" {
 				// AADGroup:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:Doe_Family_Group
@@ -748,12 +1236,24 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 				quotedAuthString := m[4]
 				authStringHex := m[5]
 
+				serverAuthString := []byte(unescapeMySQLString(quotedAuthString))
 				if authStringHex != "" {
+					serverAuthString, _ = decodeAuthString(authStringHex)
+				}
+
+				if len(serverAuthString) > 0 && hashedAuthStringMatches(ctx, d, meta, serverAuthString) {
+					// Keep auth_string_hashed in its configured encoding
+					d.Set("auth_string_hex", "")
+				} else if authStringHex != "" {
 					normalizedHex := normalizeHexString(authStringHex)
-					d.Set("auth_string_hex", normalizedHex)
+					if err := setEncryptedStateValue(ctx, d, meta, "auth_string_hex", normalizedHex); err != nil {
+						return diag.Errorf("failed setting auth_string_hex: %v", err)
+					}
 					d.Set("auth_string_hashed", "")
 				} else if quotedAuthString != "" {
-					d.Set("auth_string_hashed", quotedAuthString)
+					if err := setEncryptedStateValue(ctx, d, meta, "auth_string_hashed", quotedAuthString); err != nil {
+						return diag.Errorf("failed setting auth_string_hashed: %v", err)
+					}
 					d.Set("auth_string_hex", "")
 				} else {
 					d.Set("auth_string_hashed", "")
@@ -781,6 +1281,8 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 		re2 := regexp.MustCompile("^CREATE USER")
 		if m := re2.FindStringSubmatch(createUserStmt); m != nil {
 			// Ok, we have at least something - it's probably in MariaDB.
+			d.Set("tls_option", readTLSOption(createUserStmt))
+
 			// Parse resource limits from WITH clause if present (MariaDB format)
 			withRe := regexp.MustCompile(`WITH\s+(.*)$`)
 			if withMatch := withRe.FindStringSubmatch(createUserStmt); len(withMatch) > 1 {
@@ -817,22 +1319,104 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 	return nil
 }
 
+// kReCreateUser matches SHOW CREATE USER output; submatches are user, host,
+// auth plugin, quoted auth string, hex auth string and TLS option.
+var kReCreateUser = regexp.MustCompile("^CREATE USER ['`]([^'`]*)['`]@['`]([^'`]*)['`] IDENTIFIED WITH ['`]([^'`]*)['`] (?:AS (?:'((?:.*?[^\\\\])?)'|(0x[0-9A-Fa-f]+)) )?REQUIRE ([^ ]*)")
+
+var kReCreateUserAuthString = regexp.MustCompile(`( AS )(?:'(?:[^'\\]|\\.)*'|0x[0-9A-Fa-f]+)`)
+
+// sanitizeCreateUserStatement elides the authentication string (password
+// hash) from SHOW CREATE USER output.
+func sanitizeCreateUserStatement(stmt string) string {
+	return kReCreateUserAuthString.ReplaceAllString(stmt, "${1}'<redacted>'")
+}
+
+// kReRequireClause matches REQUIRE clauses of SHOW CREATE USER output. It also
+// matches MySQL's PASSWORD REQUIRE CURRENT, which readTLSOption skips.
+var kReRequireClause = regexp.MustCompile(` REQUIRE (.+?)(?: PASSWORD | ACCOUNT | WITH |$)`)
+
+// readTLSOption returns the TLS requirement of SHOW CREATE USER output.
+// MariaDB omits the clause for users without one.
+func readTLSOption(createUserStmt string) string {
+	for _, m := range kReRequireClause.FindAllStringSubmatch(sanitizeCreateUserStatement(createUserStmt), -1) {
+		if !strings.HasPrefix(m[1], "CURRENT") {
+			return m[1]
+		}
+	}
+	return "NONE"
+}
+
+// normalizeTLSOption makes TLS requirements comparable; an empty one means NONE.
+func normalizeTLSOption(tlsOption string) string {
+	tlsOption = strings.Join(strings.Fields(tlsOption), " ")
+	if tlsOption == "" {
+		return "NONE"
+	}
+	return strings.ToUpper(tlsOption)
+}
+
+// kReTLSOption matches the TLS requirements REQUIRE accepts: NONE, SSL, X509
+// or CIPHER, ISSUER and SUBJECT strings, optionally joined by AND.
+var kReTLSOption = regexp.MustCompile(`(?i)^(?:NONE|SSL|X509|(?:CIPHER|ISSUER|SUBJECT)\s+'(?:[^'\\]|\\.|'')*'(?:\s+(?:AND\s+)?(?:CIPHER|ISSUER|SUBJECT)\s+'(?:[^'\\]|\\.|'')*')*)$`)
+
+// validateTLSOption checks TLS requirements, which are put into REQUIRE
+// clauses as they are. An empty one means NONE.
+func validateTLSOption(v interface{}, k string) (ws []string, es []error) {
+	tlsOption := strings.TrimSpace(v.(string))
+	if tlsOption != "" && !kReTLSOption.MatchString(tlsOption) {
+		es = append(es, fmt.Errorf("%s must be NONE, SSL, X509 or CIPHER, ISSUER and SUBJECT strings, got %q", k, tlsOption))
+	}
+	return
+}
+
+func SuppressTLSOptionDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeTLSOption(old) == normalizeTLSOption(new)
+}
+
+// hashedAuthStringMatches reports whether auth_string_hashed in the state
+// decodes to authString.
+func hashedAuthStringMatches(ctx context.Context, d *schema.ResourceData, meta interface{}, authString []byte) bool {
+	hashed, err := getDecryptedString(ctx, d, meta, "auth_string_hashed")
+	if err != nil || hashed == "" {
+		return false
+	}
+	decoded, err := decodeAuthString(hashed)
+	return err == nil && bytes.Equal(decoded, authString)
+}
+
 func DeleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	stmtSQL := fmt.Sprintf("DROP USER %s", formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)))
+	if d.Get("expired").(bool) && d.Get("expiry_action").(string) == userExpiryDrop {
+		d.SetId("")
+		return nil
+	}
 
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	defer resetSQLHookSession(db, d)
+	if err := runSQLHooks(ctx, db, d, "pre_sql"); err != nil {
+		return diag.FromErr(err)
+	}
 
-	_, err = db.ExecContext(ctx, stmtSQL)
+	for _, host := range userHosts(d, meta) {
+		stmtSQL := fmt.Sprintf("DROP USER %s", formatUserIdentifier(d.Get("user").(string), host))
 
-	if err == nil {
-		d.SetId("")
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
-	return diag.FromErr(err)
+
+	if err := runSQLHooks(ctx, db, d, "post_sql"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
 }
 
 func ImportUser(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -842,10 +1426,24 @@ func ImportUser(ctx context.Context, d *schema.ResourceData, meta interface{}) (
 		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST)", d.Id())
 	}
 
+	// IDs of users with several hosts list them separated by commas, see
+	// userResourceId.
 	user := userHost[0]
-	host := userHost[1]
+	hosts := strings.Split(userHost[1], ",")
+	if err := checkNotReservedAccount(user); err != nil {
+		return nil, err
+	}
 	d.Set("user", user)
-	d.Set("host", host)
+	if len(hosts) > 1 {
+		if slices.Contains(hosts, "") {
+			return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST or USER@HOST1,HOST2)", d.Id())
+		}
+		d.Set("hosts", hosts)
+		d.SetId(userResourceId(d, meta))
+	} else {
+		d.Set("host", hosts[0])
+	}
+	d.Set("adopt_existing", false)
 	err := ReadUser(ctx, d, meta)
 	var ferror error
 	if err.HasError() {
@@ -924,6 +1522,9 @@ func NormalizeHexStringStateFunc(val interface{}) string {
 	}
 
 	hexStr := val.(string)
+	if isEncryptedStateValue(hexStr) {
+		return hexStr
+	}
 	return normalizeHexString(hexStr) // Always store normalized format
 }
 
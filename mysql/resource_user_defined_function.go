@@ -0,0 +1,167 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// 1126 = ER_CANT_OPEN_LIBRARY
+const cantOpenLibraryErrCode = 1126
+
+// udfReturnTypes maps mysql.func.ret values to RETURNS types.
+var udfReturnTypes = map[int]string{
+	0: "STRING",
+	1: "REAL",
+	2: "INTEGER",
+	4: "DECIMAL",
+}
+
+func resourceUserDefinedFunction() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateUserDefinedFunction,
+		ReadContext:   ReadUserDefinedFunction,
+		DeleteContext: DeleteUserDefinedFunction,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"soname": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringDoesNotContainAny("/\\"),
+				Description:  "Shared library file name; the library must be located in plugin_dir",
+			},
+			"returns": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"STRING", "INTEGER", "REAL", "DECIMAL"}, true),
+				StateFunc: func(v interface{}) string {
+					return strings.ToUpper(v.(string))
+				},
+			},
+			"aggregate": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"plugin_dir": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func CreateUserDefinedFunction(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	name := d.Get("name").(string)
+	soname := d.Get("soname").(string)
+
+	pluginDir, err := readPluginDir(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed reading plugin_dir: %v", err)
+	}
+
+	aggregate := ""
+	if d.Get("aggregate").(bool) {
+		aggregate = "AGGREGATE "
+	}
+
+	stmtSQL := fmt.Sprintf("CREATE %sFUNCTION %s RETURNS %s SONAME %s",
+		aggregate,
+		quoteIdentifier(name),
+		strings.ToUpper(d.Get("returns").(string)),
+		quoteString(soname))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		if mysqlErrorNumber(err) == cantOpenLibraryErrCode {
+			return diag.Errorf("library %s can't be loaded, make sure it exists in plugin_dir %s: %v", soname, pluginDir, err)
+		}
+		return diag.Errorf("failed creating function: %v", err)
+	}
+
+	d.SetId(name)
+
+	return ReadUserDefinedFunction(ctx, d, meta)
+}
+
+func ReadUserDefinedFunction(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "SELECT name, ret, dl, type FROM mysql.func WHERE name = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var name, soname, funcType string
+	var ret int
+	err = db.QueryRowContext(ctx, stmtSQL, d.Id()).Scan(&name, &ret, &soname, &funcType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] Function (%s) not found; removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading function: %v", err)
+	}
+
+	pluginDir, err := readPluginDir(ctx, db)
+	if err != nil {
+		return diag.Errorf("failed reading plugin_dir: %v", err)
+	}
+
+	d.Set("name", name)
+	d.Set("soname", soname)
+	d.Set("returns", udfReturnTypes[ret])
+	d.Set("aggregate", funcType == "aggregate")
+	d.Set("plugin_dir", pluginDir)
+
+	return nil
+}
+
+func DeleteUserDefinedFunction(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP FUNCTION IF EXISTS %s", quoteIdentifier(d.Id()))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed dropping function: %v", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func readPluginDir(ctx context.Context, db *sql.DB) (string, error) {
+	var name, pluginDir string
+	err := db.QueryRowContext(ctx, "SHOW VARIABLES WHERE VARIABLE_NAME = 'plugin_dir'").Scan(&name, &pluginDir)
+	return pluginDir, err
+}
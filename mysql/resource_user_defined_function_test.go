@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccUserDefinedFunction_missingLibrary(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserDefinedFunctionCheckDestroy("tf_test_udf"),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserDefinedFunctionConfig("tf_test_udf", "tf_does_not_exist.so"),
+				ExpectError: regexp.MustCompile("make sure it exists in plugin_dir"),
+			},
+			{
+				Config:      testAccUserDefinedFunctionConfig("tf_test_udf", "../escape.so"),
+				ExpectError: regexp.MustCompile("soname"),
+			},
+		},
+	})
+}
+
+func testAccUserDefinedFunctionCheckDestroy(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		found, err := queryHasRows(ctx, db, "SELECT 1 FROM mysql.func WHERE name = ?", name)
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("function %s still exists", name)
+		}
+		return nil
+	}
+}
+
+func testAccUserDefinedFunctionConfig(name, soname string) string {
+	return fmt.Sprintf(`
+resource "mysql_user_defined_function" "test" {
+  name    = "%s"
+  soname  = "%s"
+  returns = "INTEGER"
+}
+`, name, soname)
+}
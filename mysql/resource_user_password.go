@@ -21,9 +21,10 @@ func resourceUserPassword() *schema.Resource {
 		DeleteContext: DeleteUserPassword,
 		Schema: map[string]*schema.Schema{
 			"user": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNotReservedAccount,
 			},
 			"host": {
 				Type:     schema.TypeString,
@@ -0,0 +1,386 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceUserReplica recreates a user read by the mysql_user_definition
+// data source (usually from another provider alias) with the same
+// authentication string and grants.
+func resourceUserReplica() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateUserReplica,
+		UpdateContext: UpdateUserReplica,
+		ReadContext:   ReadUserReplica,
+		DeleteContext: DeleteUserReplica,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportUserReplica,
+		},
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "localhost",
+			},
+			"auth_plugin": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"auth_string_hex": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+				StateFunc: NormalizeHexStringStateFunc,
+				DiffSuppressFunc: suppressEncryptedDiff(func(k, old, new string, d *schema.ResourceData) bool {
+					return false
+				}),
+				Description: "Authentication string (password hash) of the source user, hex encoded",
+			},
+			"grants": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateReplicaGrant},
+				Set:         schema.HashString,
+				Description: "GRANT statements as returned by SHOW GRANTS on the source server, applied to the replicated account",
+			},
+		},
+	}
+}
+
+func userReplicaAuthClause(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, error) {
+	stmt := fmt.Sprintf("IDENTIFIED WITH %s", quoteIdentifier(d.Get("auth_plugin").(string)))
+	authStringHex, err := getDecryptedString(ctx, d, meta, "auth_string_hex")
+	if err != nil {
+		return "", err
+	}
+	if authStringHex != "" {
+		hexDigits := normalizeHexString(authStringHex)[2:]
+		if err := validateHexString(hexDigits); err != nil {
+			return "", fmt.Errorf("invalid auth_string_hex: %v", err)
+		}
+		stmt += " AS 0x" + hexDigits
+	}
+	return stmt, nil
+}
+
+func CreateUserReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	authClause, err := userReplicaAuthClause(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	stmtSQL := fmt.Sprintf("CREATE USER %s %s", formatUserIdentifier(user, host), authClause)
+	log.Println("[DEBUG] Executing statement: CREATE USER", formatUserIdentifier(user, host))
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed creating user replica: %v", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s@%s", user, host))
+
+	for _, grant := range setToArray(d.Get("grants")) {
+		if err := replicateGrant(ctx, db, grant, user, host); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadUserReplica(ctx, d, meta)
+}
+
+// replicateGrant applies a SHOW GRANTS line of the source user to the
+// account user@host.
+func replicateGrant(ctx context.Context, db *sql.DB, grant, user, host string) error {
+	parsed, err := parseReplicaGrant(grant)
+	if err != nil {
+		return err
+	}
+	stmtSQL := parsed.grantSQL(user, host)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed replicating grant %q: %v", grant, err)
+	}
+	return nil
+}
+
+func UpdateUserReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	if d.HasChange("auth_plugin") || d.HasChange("auth_string_hex") {
+		authClause, err := userReplicaAuthClause(ctx, d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		stmtSQL := fmt.Sprintf("ALTER USER %s %s", formatUserIdentifier(user, host), authClause)
+		log.Println("[DEBUG] Executing statement: ALTER USER", formatUserIdentifier(user, host))
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("failed updating user replica authentication: %v", err)
+		}
+	}
+
+	if d.HasChange("grants") {
+		o, n := d.GetChange("grants")
+		oldGrants := o.(*schema.Set)
+		newGrants := n.(*schema.Set)
+
+		// Revoke first, so a grant losing only WITH GRANT OPTION is re-added.
+		for _, grant := range setToArray(oldGrants.Difference(newGrants)) {
+			parsed, err := parseReplicaGrant(grant)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			stmtSQL := parsed.revokeSQL(user, host)
+			log.Println("[DEBUG] Executing statement:", stmtSQL)
+			if _, err := db.ExecContext(ctx, stmtSQL); err != nil && !isNonExistingGrant(err) {
+				return diag.Errorf("failed revoking %q: %v", grant, err)
+			}
+		}
+
+		for _, grant := range setToArray(newGrants.Difference(oldGrants)) {
+			if err := replicateGrant(ctx, db, grant, user, host); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return ReadUserReplica(ctx, d, meta)
+}
+
+func ReadUserReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	authPlugin, authStringHex, err := readUserAuthString(ctx, db, user, host)
+	if err != nil {
+		errorNumber := mysqlErrorNumber(err)
+		if errorNumber == unknownUserErrCode || errorNumber == userNotFoundErrCode {
+			log.Printf("[WARN] User replica %s not found - removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading user replica: %v", err)
+	}
+
+	grants, err := showGrantStatements(ctx, db, user, host)
+	if err != nil {
+		return diag.Errorf("failed reading user replica grants: %v", err)
+	}
+
+	d.Set("auth_plugin", authPlugin)
+	if err := setEncryptedStateValue(ctx, d, meta, "auth_string_hex", authStringHex); err != nil {
+		return diag.Errorf("failed setting auth_string_hex: %v", err)
+	}
+	d.Set("grants", grants)
+
+	return nil
+}
+
+func DeleteUserReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP USER %s", formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed dropping user replica: %v", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportUserReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	userHost := strings.SplitN(d.Id(), "@", 2)
+	if len(userHost) != 2 {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST)", d.Id())
+	}
+
+	d.Set("user", userHost[0])
+	d.Set("host", userHost[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// readUserAuthString returns the auth plugin and hex encoded authentication
+// string of a user as shown by SHOW CREATE USER.
+func readUserAuthString(ctx context.Context, db *sql.DB, user, host string) (string, string, error) {
+	if _, err := db.ExecContext(ctx, "SET print_identified_with_as_hex = ON"); err != nil {
+		log.Printf("[DEBUG] Could not set print_identified_with_as_hex: %v", err)
+	}
+
+	stmtSQL := fmt.Sprintf("SHOW CREATE USER %s", formatUserIdentifier(user, host))
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var createUserStmt string
+	if err := db.QueryRowContext(ctx, stmtSQL).Scan(&createUserStmt); err != nil {
+		return "", "", err
+	}
+
+	m := kReCreateUser.FindStringSubmatch(createUserStmt)
+	if len(m) != 7 {
+		return "", "", fmt.Errorf("create user couldn't be parsed - it is %s", sanitizeCreateUserStatement(createUserStmt))
+	}
+
+	switch {
+	case m[5] != "":
+		return m[3], normalizeHexString(m[5]), nil
+	case m[4] != "":
+		return m[3], normalizeHexString(hex.EncodeToString([]byte(unescapeMySQLString(m[4])))), nil
+	default:
+		return m[3], "", nil
+	}
+}
+
+var mysqlStringUnescaper = strings.NewReplacer(
+	`\0`, "\x00",
+	`\'`, `'`,
+	`\"`, `"`,
+	`\b`, "\b",
+	`\n`, "\n",
+	`\r`, "\r",
+	`\t`, "\t",
+	`\Z`, "\x1a",
+	`\\`, `\`,
+)
+
+// unescapeMySQLString reverses the escaping of a quoted MySQL string literal.
+func unescapeMySQLString(s string) string {
+	return mysqlStringUnescaper.Replace(s)
+}
+
+// showGrantStatements returns SHOW GRANTS output for a user, without the
+// GRANT USAGE line every account has.
+func showGrantStatements(ctx context.Context, db *sql.DB, user, host string) ([]string, error) {
+	stmtSQL := fmt.Sprintf("SHOW GRANTS FOR %s", formatUserIdentifier(user, host))
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []string{}
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(grant, "GRANT USAGE ON *.* TO ") {
+			continue
+		}
+		grants = append(grants, grant)
+	}
+
+	return grants, rows.Err()
+}
+
+const (
+	kGrantIdent   = "(?:`(?:[^`]|``)*`|'(?:[^'\\\\]|\\\\.|'')*'|[A-Za-z0-9_$]+)"
+	kGrantAccount = kGrantIdent + "(?:@" + kGrantIdent + ")?"
+	kGrantPriv    = `[A-Z][A-Z_ ]*?(?:\s*\(` + kGrantIdent + `(?:\s*,\s*` + kGrantIdent + `)*\))?`
+)
+
+var (
+	kReGrantPrivileges = regexp.MustCompile(`^GRANT (.+) ON (.+) TO (.+?)( WITH GRANT OPTION)?$`)
+	kReGrantRoles      = regexp.MustCompile(`^GRANT (.+) TO (.+?)( WITH ADMIN OPTION)?$`)
+
+	kReGrantPrivilegeList = regexp.MustCompile(`^` + kGrantPriv + `(?:\s*,\s*` + kGrantPriv + `)*$`)
+	kReGrantObject        = regexp.MustCompile(`^(?:(?:TABLE|FUNCTION|PROCEDURE)\s+)?(?:\*|` + kGrantIdent + `)(?:\.(?:\*|` + kGrantIdent + `))?$|^` + kGrantAccount + `$`)
+	kReGrantRoleList      = regexp.MustCompile(`^` + kGrantAccount + `(?:\s*,\s*` + kGrantAccount + `)*$`)
+	kReGrantGrantee       = regexp.MustCompile(`^` + kGrantAccount + `$`)
+)
+
+// replicaGrant is a GRANT statement as shown by SHOW GRANTS, split so it can
+// be applied to the managed account only.
+type replicaGrant struct {
+	// privileges is the privilege list, or the roles of a role grant.
+	privileges string
+	// object is empty for role grants.
+	object string
+	// option is WITH GRANT OPTION, or WITH ADMIN OPTION for role grants.
+	option bool
+}
+
+// parseReplicaGrant parses a SHOW GRANTS line. Anything else than a single
+// GRANT of privileges or roles to one account is rejected, so grants can't
+// run other statements or reach other accounts.
+func parseReplicaGrant(grant string) (*replicaGrant, error) {
+	if m := kReGrantPrivileges.FindStringSubmatch(grant); m != nil &&
+		kReGrantPrivilegeList.MatchString(m[1]) && kReGrantObject.MatchString(m[2]) && kReGrantGrantee.MatchString(m[3]) {
+		return &replicaGrant{privileges: m[1], object: m[2], option: m[4] != ""}, nil
+	}
+	if m := kReGrantRoles.FindStringSubmatch(grant); m != nil &&
+		kReGrantRoleList.MatchString(m[1]) && kReGrantGrantee.MatchString(m[2]) {
+		return &replicaGrant{privileges: m[1], option: m[3] != ""}, nil
+	}
+	return nil, fmt.Errorf("%q is not a GRANT of privileges or roles to a single account, as shown by SHOW GRANTS", grant)
+}
+
+func validateReplicaGrant(val interface{}, key string) ([]string, []error) {
+	if _, err := parseReplicaGrant(val.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %v", key, err)}
+	}
+	return nil, nil
+}
+
+// grantSQL returns the GRANT for the account user@host.
+func (g *replicaGrant) grantSQL(user, host string) string {
+	if g.object == "" {
+		stmtSQL := fmt.Sprintf("GRANT %s TO %s", g.privileges, formatUserIdentifier(user, host))
+		if g.option {
+			stmtSQL += " WITH ADMIN OPTION"
+		}
+		return stmtSQL
+	}
+	stmtSQL := fmt.Sprintf("GRANT %s ON %s TO %s", g.privileges, g.object, formatUserIdentifier(user, host))
+	if g.option {
+		stmtSQL += " WITH GRANT OPTION"
+	}
+	return stmtSQL
+}
+
+// revokeSQL returns the REVOKE undoing the grant for the account user@host.
+func (g *replicaGrant) revokeSQL(user, host string) string {
+	if g.object == "" {
+		return fmt.Sprintf("REVOKE %s FROM %s", g.privileges, formatUserIdentifier(user, host))
+	}
+	privileges := g.privileges
+	if g.option {
+		privileges += ", GRANT OPTION"
+	}
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", privileges, g.object, formatUserIdentifier(user, host))
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestReplicaGrant(t *testing.T) {
+	tests := []struct {
+		grant, grantSQL, revokeSQL string
+	}{
+		{"GRANT SELECT, INSERT ON `app`.* TO `jdoe`@`%`", "GRANT SELECT, INSERT ON `app`.* TO `jdoe`@`10.0.0.1`", "REVOKE SELECT, INSERT ON `app`.* FROM `jdoe`@`10.0.0.1`"},
+		{"GRANT SELECT ON `app`.`t` TO `jdoe`@`%` WITH GRANT OPTION", "GRANT SELECT ON `app`.`t` TO `jdoe`@`10.0.0.1` WITH GRANT OPTION", "REVOKE SELECT, GRANT OPTION ON `app`.`t` FROM `jdoe`@`10.0.0.1`"},
+		{"GRANT SELECT (`id`, `name`) ON `app`.`t` TO 'jdoe'@'%'", "GRANT SELECT (`id`, `name`) ON `app`.`t` TO `jdoe`@`10.0.0.1`", "REVOKE SELECT (`id`, `name`) ON `app`.`t` FROM `jdoe`@`10.0.0.1`"},
+		{"GRANT EXECUTE ON PROCEDURE `app`.`p` TO `jdoe`@`%`", "GRANT EXECUTE ON PROCEDURE `app`.`p` TO `jdoe`@`10.0.0.1`", "REVOKE EXECUTE ON PROCEDURE `app`.`p` FROM `jdoe`@`10.0.0.1`"},
+		{"GRANT `reader`@`%`,`writer`@`%` TO `jdoe`@`%`", "GRANT `reader`@`%`,`writer`@`%` TO `jdoe`@`10.0.0.1`", "REVOKE `reader`@`%`,`writer`@`%` FROM `jdoe`@`10.0.0.1`"},
+		{"GRANT `reader`@`%` TO `jdoe`@`%` WITH ADMIN OPTION", "GRANT `reader`@`%` TO `jdoe`@`10.0.0.1` WITH ADMIN OPTION", "REVOKE `reader`@`%` FROM `jdoe`@`10.0.0.1`"},
+		{"GRANT BACKUP_ADMIN,REPLICATION_APPLIER ON *.* TO `jdoe`@`localhost`", "GRANT BACKUP_ADMIN,REPLICATION_APPLIER ON *.* TO `jdoe`@`10.0.0.1`", "REVOKE BACKUP_ADMIN,REPLICATION_APPLIER ON *.* FROM `jdoe`@`10.0.0.1`"},
+	}
+	for _, tt := range tests {
+		grant, err := parseReplicaGrant(tt.grant)
+		if err != nil {
+			t.Errorf("parseReplicaGrant(%q) failed: %v", tt.grant, err)
+			continue
+		}
+		if got := grant.grantSQL("jdoe", "10.0.0.1"); got != tt.grantSQL {
+			t.Errorf("grant of %q is %q, expected %q", tt.grant, got, tt.grantSQL)
+		}
+		if got := grant.revokeSQL("jdoe", "10.0.0.1"); got != tt.revokeSQL {
+			t.Errorf("revoke of %q is %q, expected %q", tt.grant, got, tt.revokeSQL)
+		}
+	}
+
+	for _, grant := range []string{
+		"DROP USER `root`@`%`",
+		"GRANT ALL ON *.* TO `jdoe`@`%`, `mallory`@`%`",
+		"GRANT ALL ON *.* TO `jdoe`@`%` IDENTIFIED BY 'secret'",
+		"GRANT SELECT ON `app`.* TO `jdoe`@`%`; DROP DATABASE `app`",
+		"REVOKE SELECT ON `app`.* FROM `jdoe`@`%`",
+	} {
+		if _, err := parseReplicaGrant(grant); err == nil {
+			t.Errorf("parseReplicaGrant(%q) succeeded, expected an error", grant)
+		}
+	}
+}
+
+func TestUnescapeMySQLString(t *testing.T) {
+	if got := unescapeMySQLString(`$A$005$i\'x\\y\0`); got != "$A$005$i'x\\y\x00" {
+		t.Errorf("unexpected unescaped string %q", got)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"log"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -53,6 +55,44 @@ func TestAccUser_basic(t *testing.T) {
 	})
 }
 
+func TestAccUser_tlsOptionDrift(t *testing.T) {
+	ctx := context.Background()
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+				),
+			},
+			{
+				PreConfig: func() {
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatalf("Could not connect to MySQL instance: %v", err)
+					}
+					if _, err := db.ExecContext(ctx, "ALTER USER 'jdoe'@'%' REQUIRE SSL"); err != nil {
+						t.Fatalf("Failed to require SSL: %v", err)
+					}
+				},
+				Config:             testAccUserConfig_basic,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccUser_auth(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheckSkipTiDB(t); testAccPreCheckSkipMariaDB(t); testAccPreCheckSkipRds(t) },
@@ -201,6 +241,46 @@ func TestAccUser_auth_mysql8_validation(t *testing.T) {
 	})
 }
 
+func TestAccUser_clientSideHashing(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_clientSideHashing("caching_sha2_password", "password"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthExists("mysql_user.test"),
+					testAccUserAuthValid("jdoe", "password"),
+				),
+			},
+			{
+				Config: testAccUserConfig_clientSideHashing("caching_sha2_password", "password2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserConfig_clientSideHashing(plugin, password string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user                = "jdoe"
+    host                = "%%"
+    auth_plugin         = "%s"
+    plaintext_password  = "%s"
+    client_side_hashing = true
+}
+`, plugin, password)
+}
+
 func TestAccUser_authConnect(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -447,6 +527,155 @@ func TestAccUser_passwordWO(t *testing.T) {
 	})
 }
 
+func TestAccUser_defaultUserHost(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "mysql" {
+  default_user_host = "10.0.0.0/255.255.0.0"
+}
+
+resource "mysql_user" "test" {
+  user               = "jdoe"
+  plaintext_password = "password"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "10.0.0.0/255.255.0.0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_schemaOwner(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipNotMySQL8(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_schemaOwner(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "schema_owner", "true"),
+					resource.TestMatchResourceAttr("mysql_user.test", "create_user_statement", regexp.MustCompile(" ACCOUNT LOCK")),
+				),
+			},
+			{
+				Config: testAccUserConfig_schemaOwner(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "schema_owner", "false"),
+					resource.TestMatchResourceAttr("mysql_user.test", "create_user_statement", regexp.MustCompile(" ACCOUNT UNLOCK")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_expiresAt(t *testing.T) {
+	expiresAt := time.Now().Add(10 * time.Second).UTC().Format(time.RFC3339)
+	config := fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = "jdoe-expiring"
+  host               = "localhost"
+  plaintext_password = "password"
+  expires_at         = "%s"
+}
+`, expiresAt)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipNotMySQL8(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "expired", "false"),
+				),
+			},
+			{
+				PreConfig: func() { time.Sleep(11 * time.Second) },
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "expired", "true"),
+					resource.TestMatchResourceAttr("mysql_user.test", "create_user_statement", regexp.MustCompile(" ACCOUNT LOCK")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_adoptExisting(t *testing.T) {
+	ctx := context.Background()
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatalf("Could not connect to MySQL instance: %v", err)
+					}
+					if _, err := db.ExecContext(ctx, "CREATE USER 'jdoe-adopt'@'localhost' IDENTIFIED BY 'old-password'"); err != nil {
+						t.Fatalf("Failed to create user: %v", err)
+					}
+				},
+				Config: `
+resource "mysql_user" "test" {
+  user               = "jdoe-adopt"
+  host               = "localhost"
+  plaintext_password = "new-password"
+  adopt_existing     = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "adopt_existing", "true"),
+					testAccUserAuthValid("jdoe-adopt", "new-password"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_reservedAccount(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_user" "test" {
+  user = "mysql.sys"
+  host = "localhost"
+}
+`,
+				ExpectError: regexp.MustCompile("reserved system account"),
+			},
+		},
+	})
+}
+
+func testAccUserConfig_schemaOwner(schemaOwner bool) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user         = "jdoe-owner"
+  host         = "localhost"
+  schema_owner = %t
+}
+`, schemaOwner)
+}
+
 func testAccUserExists(rn string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -1155,3 +1384,96 @@ resource "mysql_user" "test" {
     max_user_connections = 10
 }
 `
+
+func TestSanitizeCreateUserStatement(t *testing.T) {
+	tests := map[string]string{
+		"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$i`xay#fG/\\' TrbkNA82' REQUIRE NONE": "CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS '<redacted>' REQUIRE NONE",
+		"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS 0x2441243030 REQUIRE NONE":                   "CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS '<redacted>' REQUIRE NONE",
+		"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'mysql_no_login' REQUIRE NONE":                                          "CREATE USER `jdoe`@`%` IDENTIFIED WITH 'mysql_no_login' REQUIRE NONE",
+	}
+	for in, expected := range tests {
+		if got := sanitizeCreateUserStatement(in); got != expected {
+			t.Errorf("sanitizeCreateUserStatement(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
+
+func TestReadTLSOption(t *testing.T) {
+	tests := map[string]string{
+		"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD REQUIRE CURRENT DEFAULT": "NONE",
+		"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS 'x REQUIRE y ' REQUIRE SSL PASSWORD EXPIRE DEFAULT":                                "SSL",
+		"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'mysql_native_password' REQUIRE SUBJECT '/CN=jdoe' AND ISSUER '/CN=ca' PASSWORD EXPIRE DEFAULT":               "SUBJECT '/CN=jdoe' AND ISSUER '/CN=ca'",
+		"CREATE USER `jdoe`@`%` IDENTIFIED BY PASSWORD '*ABC' REQUIRE X509 WITH MAX_USER_CONNECTIONS 10":                                                      "X509",
+		"CREATE USER `jdoe`@`%` IDENTIFIED BY PASSWORD '*ABC'":                                                                                                "NONE",
+	}
+	for in, expected := range tests {
+		if got := readTLSOption(in); got != expected {
+			t.Errorf("readTLSOption(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
+
+func TestValidateTLSOption(t *testing.T) {
+	for _, valid := range []string{"", "NONE", "ssl", "X509", "SUBJECT '/CN=jdoe'", "SUBJECT '/CN=jdoe' AND ISSUER '/CN=ca'", "CIPHER 'EDH-RSA-DES-CBC3-SHA' ISSUER '/CN=it''s'"} {
+		if _, errs := validateTLSOption(valid, "tls_option"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", valid, errs)
+		}
+	}
+	for _, invalid := range []string{"SSL; DROP USER root", "SUBJECT /CN=jdoe", "SUBJECT '/CN=jdoe' OR ISSUER '/CN=ca'", "NONE WITH MAX_USER_CONNECTIONS 1"} {
+		if _, errs := validateTLSOption(invalid, "tls_option"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestUserStateDrift(t *testing.T) {
+	prior := map[string]string{"id": "jdoe@a,b", "hosts.#": "2", "tls_option": "NONE", "auth_string_hashed": "x", "create_user_statement": "CREATE USER `jdoe`@`a`"}
+	tests := []struct {
+		current                    map[string]string
+		drifted, authStringDrifted bool
+	}{
+		{map[string]string{"id": "jdoe@a,b", "hosts.#": "1", "tls_option": "NONE", "auth_string_hashed": "x", "create_user_statement": "CREATE USER `jdoe`@`b`"}, false, false},
+		{map[string]string{"id": "jdoe@a,b", "hosts.#": "2", "tls_option": "SSL", "auth_string_hashed": "x"}, true, false},
+		{map[string]string{"id": "jdoe@a,b", "hosts.#": "2", "tls_option": "NONE", "auth_string_hashed": "y"}, false, true},
+		{map[string]string{"id": "jdoe@a,b", "hosts.#": "2", "tls_option": "NONE", "auth_string_hashed": "x", "max_user_connections": "5"}, true, false},
+	}
+	for _, tt := range tests {
+		drifted, authStringDrifted := userStateDrift(prior, tt.current)
+		if drifted != tt.drifted || authStringDrifted != tt.authStringDrifted {
+			t.Errorf("userStateDrift(%v) = %v, %v, expected %v, %v", tt.current, drifted, authStringDrifted, tt.drifted, tt.authStringDrifted)
+		}
+	}
+}
+
+func TestUserHostDefaultWithoutHost(t *testing.T) {
+	r := resourceUser()
+	values := map[string]cty.Value{}
+	for name, ty := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		values[name] = cty.NullVal(ty)
+	}
+	values["user"] = cty.StringVal("jdoe")
+	config := cty.ObjectVal(values)
+
+	tests := []struct {
+		host, defaultUserHost string
+		replace               bool
+	}{
+		{"%", "", true},
+		{"localhost", "", false},
+		{"%", "%", false},
+	}
+	for _, tt := range tests {
+		state := &terraform.InstanceState{
+			ID:         "jdoe@" + tt.host,
+			Attributes: map[string]string{"id": "jdoe@" + tt.host, "user": "jdoe", "host": tt.host},
+			RawConfig:  config,
+		}
+		diff, err := r.SimpleDiff(context.Background(), state, terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema()), &MySQLConfiguration{DefaultUserHost: tt.defaultUserHost})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if replace := diff != nil && diff.RequiresNew(); replace != tt.replace {
+			t.Errorf("host %q without host in the config and default_user_host %q: expected replace %v, got %v", tt.host, tt.defaultUserHost, tt.replace, replace)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// usersBatchSize limits the accounts changed by a single statement.
+const usersBatchSize = 100
+
+func resourceUsers() *schema.Resource {
+	return withRefreshMode(&schema.Resource{
+		CreateContext: CreateUsers,
+		UpdateContext: UpdateUsers,
+		ReadContext:   ReadUsers,
+		DeleteContext: DeleteUsers,
+
+		Schema: map[string]*schema.Schema{
+			"users": {
+				Type:             schema.TypeMap,
+				Required:         true,
+				Sensitive:        true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateDiagFunc: validateBulkUsers,
+				DiffSuppressFunc: suppressBulkUserDiff,
+				Description:      "Map of USER@HOST to a JSON definition of the user",
+			},
+		},
+	})
+}
+
+// bulkUserDefinition is the JSON definition of a user of mysql_users.
+type bulkUserDefinition struct {
+	PlaintextPassword string `json:"plaintext_password,omitempty"`
+	AuthPlugin        string `json:"auth_plugin,omitempty"`
+	AuthStringHashed  string `json:"auth_string_hashed,omitempty"`
+	TLSOption         string `json:"tls_option,omitempty"`
+}
+
+// bulkUser is an element of the users map of mysql_users.
+type bulkUser struct {
+	name             string
+	host             string
+	password         string
+	authPlugin       string
+	authStringHashed string
+	tlsOption        string
+}
+
+func (u bulkUser) account() string {
+	return formatUserIdentifier(u.name, u.host)
+}
+
+// key returns the key of the user in the users map.
+func (u bulkUser) key() string {
+	return u.name + "@" + u.host
+}
+
+// definition returns the JSON definition of the user, as stored in the
+// state.
+func (u bulkUser) definition() string {
+	data, _ := json.Marshal(bulkUserDefinition{
+		PlaintextPassword: u.password,
+		AuthPlugin:        u.authPlugin,
+		AuthStringHashed:  u.authStringHashed,
+		TLSOption:         u.tlsOption,
+	})
+	return string(data)
+}
+
+// authClause returns the IDENTIFIED clause of the user.
+func (u bulkUser) authClause() (string, error) {
+	switch {
+	case u.authPlugin != "" && u.authStringHashed != "":
+		literal, err := hashedAuthStringLiteral(u.authPlugin, u.authStringHashed)
+		if err != nil {
+			return "", fmt.Errorf("user %s: %v", u.account(), err)
+		}
+		return fmt.Sprintf(" IDENTIFIED WITH %s AS %s", u.authPlugin, literal), nil
+	case u.authPlugin != "" && u.password != "":
+		return fmt.Sprintf(" IDENTIFIED WITH %s BY %s", u.authPlugin, quoteString(u.password)), nil
+	case u.authPlugin != "":
+		return " IDENTIFIED WITH " + u.authPlugin, nil
+	case u.password != "":
+		return " IDENTIFIED BY " + quoteString(u.password), nil
+	}
+	return "", nil
+}
+
+// parseBulkUser parses an element of the users map. The user name is
+// everything before the last @, so names may be e-mail addresses. An empty
+// definition creates the user without password.
+func parseBulkUser(key, definition string) (bulkUser, error) {
+	at := strings.LastIndex(key, "@")
+	if at <= 0 {
+		return bulkUser{}, fmt.Errorf("wrong key format %s (expected USER@HOST)", key)
+	}
+	u := bulkUser{name: key[:at], host: key[at+1:]}
+	if err := checkNotReservedAccount(u.name); err != nil {
+		return bulkUser{}, err
+	}
+
+	var def bulkUserDefinition
+	if strings.TrimSpace(definition) != "" {
+		decoder := json.NewDecoder(strings.NewReader(definition))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&def); err != nil {
+			return bulkUser{}, fmt.Errorf("user %s: invalid definition: %v", key, err)
+		}
+	}
+	u.password = def.PlaintextPassword
+	u.authPlugin = def.AuthPlugin
+	u.authStringHashed = def.AuthStringHashed
+	if _, errs := validateTLSOption(def.TLSOption, "tls_option"); len(errs) > 0 {
+		return bulkUser{}, fmt.Errorf("user %s: %v", key, errs[0])
+	}
+	u.tlsOption = normalizeTLSOption(def.TLSOption)
+	if u.authStringHashed != "" {
+		if u.authPlugin == "" {
+			return bulkUser{}, fmt.Errorf("user %s: auth_string_hashed requires auth_plugin", key)
+		}
+		if _, errs := validateAuthStringEncoding(u.authStringHashed, "auth_string_hashed"); len(errs) > 0 {
+			return bulkUser{}, fmt.Errorf("user %s: %v", key, errs[0])
+		}
+	}
+	return u, nil
+}
+
+func bulkUsersFromMap(v interface{}) (map[string]bulkUser, error) {
+	users := map[string]bulkUser{}
+	for key, definition := range v.(map[string]interface{}) {
+		u, err := parseBulkUser(key, definition.(string))
+		if err != nil {
+			return nil, err
+		}
+		users[u.account()] = u
+	}
+	return users, nil
+}
+
+func validateBulkUsers(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for key, definition := range v.(map[string]interface{}) {
+		if _, err := parseBulkUser(key, definition.(string)); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       err.Error(),
+				AttributePath: append(path, cty.IndexStep{Key: cty.StringVal(key)}),
+			})
+		}
+	}
+	return diags
+}
+
+// suppressBulkUserDiff compares definitions by their values, so formatting
+// JSON differently doesn't change users.
+func suppressBulkUserDiff(k, old, new string, d *schema.ResourceData) bool {
+	key := strings.TrimPrefix(k, "users.")
+	if key == "%" || old == "" || new == "" {
+		return false
+	}
+	oldUser, err := parseBulkUser(key, old)
+	if err != nil {
+		return false
+	}
+	newUser, err := parseBulkUser(key, new)
+	if err != nil {
+		return false
+	}
+	return oldUser == newUser
+}
+
+func sortedBulkUsers(users map[string]bulkUser) []bulkUser {
+	sorted := make([]bulkUser, 0, len(users))
+	for _, u := range users {
+		sorted = append(sorted, u)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].account() < sorted[j].account()
+	})
+	return sorted
+}
+
+// bulkUserBatch is a statement changing a batch of users.
+type bulkUserBatch struct {
+	stmtSQL string
+	users   []bulkUser
+}
+
+// bulkUserBatches returns statements running verb (CREATE USER or ALTER
+// USER) for the users, batching users sharing a TLS option.
+func bulkUserBatches(verb string, users []bulkUser) ([]bulkUserBatch, error) {
+	byTLSOption := map[string][]bulkUser{}
+	var tlsOptions []string
+	for _, u := range users {
+		tlsOption := normalizeTLSOption(u.tlsOption)
+		if _, ok := byTLSOption[tlsOption]; !ok {
+			tlsOptions = append(tlsOptions, tlsOption)
+		}
+		byTLSOption[tlsOption] = append(byTLSOption[tlsOption], u)
+	}
+
+	var batches []bulkUserBatch
+	for _, tlsOption := range tlsOptions {
+		users := byTLSOption[tlsOption]
+		for start := 0; start < len(users); start += usersBatchSize {
+			end := start + usersBatchSize
+			if end > len(users) {
+				end = len(users)
+			}
+			specs := make([]string, 0, end-start)
+			for _, u := range users[start:end] {
+				authClause, err := u.authClause()
+				if err != nil {
+					return nil, err
+				}
+				specs = append(specs, u.account()+authClause)
+			}
+			batches = append(batches, bulkUserBatch{
+				stmtSQL: fmt.Sprintf("%s %s REQUIRE %s", verb, strings.Join(specs, ", "), tlsOption),
+				users:   users[start:end],
+			})
+		}
+	}
+	return batches, nil
+}
+
+// execBulkUserStatements runs verb for the users, calling done with the
+// users of each batch that succeeded, so the state can follow partial
+// changes. dropBulkUsers does the same for DROP USER, done may be nil there.
+func execBulkUserStatements(ctx context.Context, db *sql.DB, verb string, users []bulkUser, done func([]bulkUser)) error {
+	batches, err := bulkUserBatches(verb, users)
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches {
+		// Statements carry passwords, so only the verb is logged.
+		log.Printf("[DEBUG] Executing %s statement for %d users", verb, len(batch.users))
+		if _, err := db.ExecContext(ctx, batch.stmtSQL); err != nil {
+			return fmt.Errorf("failed executing %s: %v", verb, err)
+		}
+		done(batch.users)
+	}
+	return nil
+}
+
+func dropBulkUsers(ctx context.Context, db *sql.DB, users []bulkUser, done func([]bulkUser)) error {
+	for start := 0; start < len(users); start += usersBatchSize {
+		end := start + usersBatchSize
+		if end > len(users) {
+			end = len(users)
+		}
+		accounts := make([]string, 0, end-start)
+		for _, u := range users[start:end] {
+			accounts = append(accounts, u.account())
+		}
+		stmtSQL := "DROP USER IF EXISTS " + strings.Join(accounts, ", ")
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed dropping users: %v", err)
+		}
+		if done != nil {
+			done(users[start:end])
+		}
+	}
+	return nil
+}
+
+// bulkUsersState tracks the users that exist while batches run. When a batch
+// fails, the users changed by earlier batches are kept in the state, so they
+// aren't orphaned.
+type bulkUsersState map[string]interface{}
+
+func (s bulkUsersState) set(users []bulkUser) {
+	for _, u := range users {
+		s[u.key()] = u.definition()
+	}
+}
+
+func (s bulkUsersState) remove(users []bulkUser) {
+	for _, u := range users {
+		delete(s, u.key())
+	}
+}
+
+// failed saves the state of the users and returns err.
+func (s bulkUsersState) failed(d *schema.ResourceData, err error) diag.Diagnostics {
+	if setErr := d.Set("users", map[string]interface{}(s)); setErr != nil {
+		log.Printf("[WARN] Failed saving users changed before the error: %v", setErr)
+	}
+	return diag.FromErr(err)
+}
+
+func CreateUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	users, err := bulkUsersFromMap(d.Get("users"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The ID is set before the first batch, so the users of batches that
+	// succeeded before an error stay in the state and get dropped with it.
+	d.SetId(id.UniqueId())
+	state := bulkUsersState{}
+	if err := execBulkUserStatements(ctx, db, "CREATE USER", sortedBulkUsers(users), state.set); err != nil {
+		if len(state) == 0 {
+			d.SetId("")
+			return diag.FromErr(err)
+		}
+		return state.failed(d, err)
+	}
+
+	return ReadUsers(ctx, d, meta)
+}
+
+func UpdateUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	o, n := d.GetChange("users")
+	oldUsers, err := bulkUsersFromMap(o)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	newUsers, err := bulkUsersFromMap(n)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Users are matched by account, so changing a password alters the user
+	// instead of recreating it and losing its grants.
+	var dropped, created, altered []bulkUser
+	for _, u := range sortedBulkUsers(oldUsers) {
+		if _, ok := newUsers[u.account()]; !ok {
+			dropped = append(dropped, u)
+		}
+	}
+	for _, u := range sortedBulkUsers(newUsers) {
+		old, ok := oldUsers[u.account()]
+		if !ok {
+			created = append(created, u)
+		} else if old != u {
+			altered = append(altered, u)
+		}
+	}
+
+	state := bulkUsersState{}
+	for key, definition := range o.(map[string]interface{}) {
+		state[key] = definition
+	}
+	if err := dropBulkUsers(ctx, db, dropped, state.remove); err != nil {
+		return state.failed(d, err)
+	}
+	if err := execBulkUserStatements(ctx, db, "CREATE USER", created, state.set); err != nil {
+		return state.failed(d, err)
+	}
+	if err := execBulkUserStatements(ctx, db, "ALTER USER", altered, state.set); err != nil {
+		return state.failed(d, err)
+	}
+
+	return ReadUsers(ctx, d, meta)
+}
+
+// ReadUsers checks all users with a single query. Users missing on the
+// server are removed from the state, so they get created again.
+func ReadUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "SELECT User, Host, plugin FROM mysql.user"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed reading users: %v", err)
+	}
+	defer rows.Close()
+
+	plugins := map[string]string{}
+	for rows.Next() {
+		var user, host string
+		var plugin sql.NullString
+		if err := rows.Scan(&user, &host, &plugin); err != nil {
+			return diag.Errorf("failed scanning MySQL rows: %v", err)
+		}
+		plugins[formatUserIdentifier(user, host)] = plugin.String
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed reading users: %v", err)
+	}
+
+	existing := map[string]interface{}{}
+	for key, definition := range d.Get("users").(map[string]interface{}) {
+		u, err := parseBulkUser(key, definition.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		plugin, ok := plugins[u.account()]
+		if !ok {
+			log.Printf("[WARN] User %s not found, removing it from state", u.account())
+			continue
+		}
+		if u.authPlugin != "" && u.authPlugin != plugin {
+			u.authPlugin = plugin
+			existing[key] = u.definition()
+			continue
+		}
+		existing[key] = definition
+	}
+
+	if len(existing) == 0 {
+		d.SetId("")
+		return nil
+	}
+	if err := d.Set("users", existing); err != nil {
+		return diag.Errorf("failed setting users field: %v", err)
+	}
+
+	return nil
+}
+
+func DeleteUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	users, err := bulkUsersFromMap(d.Get("users"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := dropBulkUsers(ctx, db, sortedBulkUsers(users), nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
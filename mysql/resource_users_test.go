@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUsers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUsersConfig([]string{"jdoe-bulk1", "jdoe-bulk2", "jdoe-bulk3"}, "password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_users.test", "users.%", "3"),
+				),
+			},
+			{
+				Config: testAccUsersConfig([]string{"jdoe-bulk1", "jdoe-bulk3", "jdoe-bulk4"}, "password2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_users.test", "users.%", "3"),
+					resource.TestCheckResourceAttrSet("mysql_users.test", "users.jdoe-bulk4@example.com"),
+				),
+			},
+		},
+	})
+}
+
+func TestBulkUserStatements(t *testing.T) {
+	users := []bulkUser{
+		{name: "a", host: "%", password: "pw"},
+		{name: "b", host: "%", authPlugin: "mysql_native_password", authStringHashed: "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19", tlsOption: "NONE"},
+		{name: "c", host: "%", tlsOption: "SSL"},
+	}
+	batches, err := bulkUserBatches("CREATE USER", users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, batch := range batches {
+		got = append(got, batch.stmtSQL)
+	}
+	expected := []string{
+		"CREATE USER `a`@`%` IDENTIFIED BY 'pw', `b`@`%` IDENTIFIED WITH mysql_native_password AS '*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19' REQUIRE NONE",
+		"CREATE USER `c`@`%` REQUIRE SSL",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected statements %q", got)
+	}
+
+	many := make([]bulkUser, usersBatchSize+1)
+	for i := range many {
+		many[i] = bulkUser{name: fmt.Sprintf("u%d", i), host: "%"}
+	}
+	if got, _ := bulkUserBatches("ALTER USER", many); len(got) != 2 || len(got[1].users) != 1 {
+		t.Errorf("expected 2 batches, got %d", len(got))
+	}
+}
+
+func TestParseBulkUser(t *testing.T) {
+	u, err := parseBulkUser("jdoe@corp.com@%", `{"plaintext_password": "pw", "tls_option": "ssl"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := bulkUser{name: "jdoe@corp.com", host: "%", password: "pw", tlsOption: "SSL"}
+	if u != expected {
+		t.Errorf("got %+v, expected %+v", u, expected)
+	}
+	if u, err := parseBulkUser("jdoe@%", ""); err != nil || u.tlsOption != "NONE" {
+		t.Errorf("expected user without options, got %+v, %v", u, err)
+	}
+
+	for key, definition := range map[string]string{
+		"jdoe":                "",
+		"mysql.sys@localhost": "",
+		"jdoe@%":              `{"password": "pw"}`,
+		"jdoe@host":           `{"auth_string_hashed": "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19"}`,
+		"jdoe@other":          `not json`,
+	} {
+		if _, err := parseBulkUser(key, definition); err == nil {
+			t.Errorf("expected an error for %s = %q", key, definition)
+		}
+	}
+
+	if !suppressBulkUserDiff("users.jdoe@%", `{"plaintext_password":"pw"}`, `{ "plaintext_password" : "pw", "tls_option": "NONE" }`, nil) {
+		t.Error("expected formatting differences to be suppressed")
+	}
+	if suppressBulkUserDiff("users.jdoe@%", `{"plaintext_password":"pw"}`, `{"plaintext_password":"pw2"}`, nil) {
+		t.Error("expected a password change not to be suppressed")
+	}
+}
+
+func TestBulkUsersState(t *testing.T) {
+	state := bulkUsersState{"a@%": `{"plaintext_password":"old"}`, "b@%": ""}
+	state.remove([]bulkUser{{name: "b", host: "%"}})
+	state.set([]bulkUser{{name: "a", host: "%", password: "new"}, {name: "c", host: "%"}})
+	expected := bulkUsersState{"a@%": `{"plaintext_password":"new"}`, "c@%": "{}"}
+	if !reflect.DeepEqual(state, expected) {
+		t.Errorf("got %v, expected %v", state, expected)
+	}
+}
+
+func testAccUsersConfig(names []string, password string) string {
+	config := `
+resource "mysql_users" "test" {
+  users = {`
+	for _, name := range names {
+		config += fmt.Sprintf(`
+    "%s@example.com" = jsonencode({ plaintext_password = "%s" })`, name, password)
+	}
+	return config + `
+  }
+}
+`
+}
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// checkExpectedServer refuses a connection to another server than the one
+// the configuration was written for, e.g. when MYSQL_ENDPOINT points to the
+// wrong environment.
+func checkExpectedServer(ctx context.Context, conf *MySQLConfiguration, db *sql.DB) error {
+	if conf.ExpectedServerUUID == "" && conf.ExpectedVersionPrefix == "" {
+		return nil
+	}
+
+	var serverUUID string
+	if conf.ExpectedServerUUID != "" {
+		err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.server_uuid").Scan(&serverUUID)
+		if mysqlErrorNumber(err) == unknownSystemVariableErrCode {
+			return fmt.Errorf("expected_server_uuid is set, but the server has no server_uuid")
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading server_uuid: %v", err)
+		}
+	}
+
+	versionString, err := serverVersionString(db)
+	if err != nil {
+		return fmt.Errorf("failed reading server version: %v", err)
+	}
+
+	return matchExpectedServer(conf.ExpectedServerUUID, conf.ExpectedVersionPrefix, serverUUID, versionString)
+}
+
+func matchExpectedServer(expectedUUID, expectedVersionPrefix, serverUUID, versionString string) error {
+	if expectedUUID != "" && !strings.EqualFold(expectedUUID, serverUUID) {
+		return fmt.Errorf("connected to server %s, but expected_server_uuid is %s", serverUUID, expectedUUID)
+	}
+	if expectedVersionPrefix != "" && !strings.HasPrefix(versionString, expectedVersionPrefix) {
+		return fmt.Errorf("connected to server version %s, but expected_version_prefix is %s", versionString, expectedVersionPrefix)
+	}
+	return nil
+}
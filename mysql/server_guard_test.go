@@ -0,0 +1,26 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestMatchExpectedServer(t *testing.T) {
+	const uuid = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+	tests := []struct {
+		expectedUUID, expectedPrefix, version string
+		ok                                    bool
+	}{
+		{"", "", "8.0.36", true},
+		{uuid, "", "8.0.36", true},
+		{"3E11FA47-71CA-11E1-9E33-C80AA9429562", "8.0.", "8.0.36", true},
+		{"00000000-0000-0000-0000-000000000000", "", "8.0.36", false},
+		{"", "8.4.", "8.0.36", false},
+		{"", "10.11", "10.11.6-MariaDB", true},
+	}
+	for _, tt := range tests {
+		err := matchExpectedServer(tt.expectedUUID, tt.expectedPrefix, uuid, tt.version)
+		if (err == nil) != tt.ok {
+			t.Errorf("matchExpectedServer(%q, %q, %q) = %v", tt.expectedUUID, tt.expectedPrefix, tt.version, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// readinessPollInterval is how often the readiness of the server is checked.
+// Tests shorten it.
+var readinessPollInterval = 5 * time.Second
+
+// waitForReadyConfig is the wait_for_ready block of the provider.
+type waitForReadyConfig struct {
+	Timeout     time.Duration
+	Writable    bool
+	ClusterSize int
+}
+
+func waitForReadySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Wait until the server is ready before resources use it, e.g. while an operator brings up the cluster",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"timeout_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      600,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"writable": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Wait until read_only and super_read_only are off",
+				},
+				"cluster_size": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Wait until the Galera or Group Replication cluster has this many members online, 0 not to wait",
+				},
+			},
+		},
+	}
+}
+
+func buildWaitForReadyConfig(block []interface{}) *waitForReadyConfig {
+	if len(block) == 0 || block[0] == nil {
+		return nil
+	}
+	m := block[0].(map[string]interface{})
+	return &waitForReadyConfig{
+		Timeout:     time.Duration(m["timeout_sec"].(int)) * time.Second,
+		Writable:    m["writable"].(bool),
+		ClusterSize: m["cluster_size"].(int),
+	}
+}
+
+// waitForServerReady polls the server until it's ready or the timeout
+// passes.
+func waitForServerReady(ctx context.Context, cfg *waitForReadyConfig, db *sql.DB) error {
+	deadline := time.Now().Add(cfg.Timeout)
+	for {
+		reason, err := serverNotReadyReason(ctx, cfg, db)
+		if err != nil {
+			return fmt.Errorf("failed checking whether the server is ready: %v", err)
+		}
+		if reason == "" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("server not ready after %s: %s", cfg.Timeout, reason)
+		}
+		log.Printf("[INFO] Waiting for the server to be ready: %s", reason)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("server not ready: %s", reason)
+		case <-time.After(readinessPollInterval):
+		}
+	}
+}
+
+// serverNotReadyReason returns why the server isn't ready, or an empty
+// string when it is.
+func serverNotReadyReason(ctx context.Context, cfg *waitForReadyConfig, db *sql.DB) (string, error) {
+	wsrep, err := readWsrepStatus(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	// Galera nodes refuse queries until they joined the cluster.
+	if len(wsrep) > 0 {
+		if wsrep["wsrep_ready"] != "ON" {
+			return "wsrep_ready is " + wsrep["wsrep_ready"], nil
+		}
+		if state := wsrep["wsrep_local_state_comment"]; state != "Synced" {
+			return "the Galera node is " + state, nil
+		}
+	}
+
+	if cfg.Writable {
+		readOnly, err := serverReadOnly(ctx, db)
+		if err != nil {
+			return "", err
+		}
+		if readOnly {
+			return "the server is read-only", nil
+		}
+	}
+
+	if cfg.ClusterSize > 0 {
+		var members int
+		if size, ok := wsrep["wsrep_cluster_size"]; ok {
+			members, _ = strconv.Atoi(size)
+		} else {
+			stmtSQL := "SELECT COUNT(*) FROM performance_schema.replication_group_members WHERE MEMBER_STATE = 'ONLINE'"
+			log.Printf("[DEBUG] SQL: %s", stmtSQL)
+			err := db.QueryRowContext(ctx, stmtSQL).Scan(&members)
+			if err != nil && mysqlErrorNumber(err) != noSuchTableErrCode {
+				return "", err
+			}
+		}
+		if members < cfg.ClusterSize {
+			return fmt.Sprintf("%d of %d cluster members are online", members, cfg.ClusterSize), nil
+		}
+	}
+	return "", nil
+}
+
+func readWsrepStatus(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	stmtSQL := "SHOW GLOBAL STATUS WHERE Variable_name IN ('wsrep_ready', 'wsrep_local_state_comment', 'wsrep_cluster_size')"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	status := map[string]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		status[name] = value
+	}
+	return status, rows.Err()
+}
+
+// serverReadOnly reports whether read_only or super_read_only is on. MariaDB
+// has no super_read_only.
+func serverReadOnly(ctx context.Context, db *sql.DB) (bool, error) {
+	var readOnly, superReadOnly bool
+	err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.read_only, @@GLOBAL.super_read_only").Scan(&readOnly, &superReadOnly)
+	if mysqlErrorNumber(err) == unknownSystemVariableErrCode {
+		err = db.QueryRowContext(ctx, "SELECT @@GLOBAL.read_only").Scan(&readOnly)
+	}
+	return readOnly || superReadOnly, err
+}
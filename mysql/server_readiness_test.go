@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildWaitForReadyConfig(t *testing.T) {
+	if cfg := buildWaitForReadyConfig(nil); cfg != nil {
+		t.Errorf("buildWaitForReadyConfig(nil) = %+v, want nil", cfg)
+	}
+
+	cfg := buildWaitForReadyConfig([]interface{}{map[string]interface{}{
+		"timeout_sec":  120,
+		"writable":     true,
+		"cluster_size": 3,
+	}})
+	want := waitForReadyConfig{Timeout: 2 * time.Minute, Writable: true, ClusterSize: 3}
+	if cfg == nil || *cfg != want {
+		t.Errorf("buildWaitForReadyConfig = %+v, want %+v", cfg, want)
+	}
+}
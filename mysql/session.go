@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// sessionSetting is a statement configuring the sessions of the provider.
+type sessionSetting struct {
+	name    string
+	stmtSQL string
+}
+
+// sessionSettings holds the settings every connection of a pool runs. They
+// depend on the server version, so they're known only once the first
+// connection is open.
+type sessionSettings struct {
+	mu       sync.Mutex
+	settings []sessionSetting
+}
+
+func (s *sessionSettings) set(settings []sessionSetting) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings = settings
+}
+
+func (s *sessionSettings) get() []sessionSetting {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settings
+}
+
+// sessionConnector runs the session settings on each new connection, so
+// the connections the pool opens again, e.g. after max_conn_lifetime_sec or
+// a dropped connection, keep them.
+type sessionConnector struct {
+	connector driver.Connector
+	settings  *sessionSettings
+}
+
+func (c sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := applySessionSettings(ctx, conn, c.settings.get()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c sessionConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+func applySessionSettings(ctx context.Context, conn driver.Conn, settings []sessionSetting) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		return fmt.Errorf("connection of type %T can't run session settings", conn)
+	}
+	for _, setting := range settings {
+		log.Println("[DEBUG] Executing statement:", setting.stmtSQL)
+		if _, err := execer.ExecContext(ctx, setting.stmtSQL, nil); err != nil {
+			return fmt.Errorf("failed setting %s: %v", setting.name, err)
+		}
+	}
+	return nil
+}
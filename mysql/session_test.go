@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+func TestSessionConnectorAppliesSettings(t *testing.T) {
+	server := &fakeFailoverServer{}
+	settings := &sessionSettings{}
+	db := sql.OpenDB(sessionConnector{connector: server, settings: settings})
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "SET SESSION sql_mode=''"); err != nil {
+		t.Fatal(err)
+	}
+	settings.set([]sessionSetting{{"SQL mode", "SET SESSION sql_mode=''"}})
+
+	// Without idle connections, the next statement runs on a new one, which
+	// runs the settings too.
+	db.SetMaxIdleConns(0)
+	if _, err := db.ExecContext(ctx, "CREATE USER 'jdoe'"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"SET SESSION sql_mode=''", "SET SESSION sql_mode=''", "CREATE USER 'jdoe'"}
+	if server.dials != 2 || fmt.Sprint(server.executed) != fmt.Sprint(expected) {
+		t.Errorf("%d connections ran %q, want 2 running %q", server.dials, server.executed, expected)
+	}
+}
@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// sqlHookSchema is the pre_sql or post_sql list of a resource.
+func sqlHookSchema(when string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: fmt.Sprintf("Statements run %s the resource's own statements on create, update and delete", when),
+	}
+}
+
+// runSQLHooks executes the statements of pre_sql or post_sql in order. The
+// provider uses a single connection, so they usually share the session of
+// the resource's own statements. They aren't isolated, though: resources are
+// changed in parallel, so statements of other resources may run on the
+// session in between, and a reconnect drops its changes. Their session
+// changes aren't restored after failovers, and resetSQLHookSession discards
+// them once the resource is done.
+func runSQLHooks(ctx context.Context, db *sql.DB, d *schema.ResourceData, key string) error {
+	ctx = withoutSettingReplay(ctx)
+	for _, stmtSQL := range toStringList(d.Get(key)) {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		result, err := db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return fmt.Errorf("failed executing %s statement %q: %v", key, stmtSQL, err)
+		}
+		if affected, err := result.RowsAffected(); err == nil {
+			log.Printf("[INFO] %s statement %q affected %d rows", key, stmtSQL, affected)
+		}
+	}
+	return nil
+}
+
+// resetSQLHookSession closes the connection after a resource ran pre_sql or
+// post_sql, so session variables they set don't leak into the statements of
+// other resources. The pool opens a new connection with the provider's
+// session settings.
+func resetSQLHookSession(db *sql.DB, d *schema.ResourceData) {
+	if len(toStringList(d.Get("pre_sql"))) == 0 && len(toStringList(d.Get("post_sql"))) == 0 {
+		return
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		log.Printf("[WARN] Failed resetting the session after pre_sql and post_sql: %v", err)
+		return
+	}
+	discardConn(conn)
+}
+
+// discardConn closes conn and its driver connection instead of returning it
+// to the pool, dropping the session changes made on it.
+func discardConn(conn *sql.Conn) {
+	conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+	conn.Close()
+}
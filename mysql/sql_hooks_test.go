@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSQLHooksDontLeakSession(t *testing.T) {
+	server := &fakeFailoverServer{}
+	connector := newFailoverConnector(server, 1, 0)
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceDatabase().Schema, map[string]interface{}{
+		"name":    "app",
+		"pre_sql": []interface{}{"SET SESSION foreign_key_checks = 0"},
+	})
+	ctx := context.Background()
+	if err := runSQLHooks(ctx, db, d, "pre_sql"); err != nil {
+		t.Fatal(err)
+	}
+	resetSQLHookSession(db, d)
+	if _, err := db.ExecContext(ctx, "CREATE USER 'jdoe'"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The hook isn't replayed on the new connection.
+	expected := []string{"SET SESSION foreign_key_checks = 0", "CREATE USER 'jdoe'"}
+	if server.dials != 2 || fmt.Sprint(server.executed) != fmt.Sprint(expected) {
+		t.Errorf("%d connections ran %q, want 2 running %q", server.dials, server.executed, expected)
+	}
+}
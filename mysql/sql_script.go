@@ -0,0 +1,250 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// sqlStatement is a statement of a script and the line it starts on.
+type sqlStatement struct {
+	SQL  string
+	Line int
+}
+
+// splitSQLStatements splits a script into statements the way the mysql
+// client does: on the current delimiter outside of strings, quoted
+// identifiers and comments, with DELIMITER lines changing the delimiter.
+// Statements consisting only of comments are dropped.
+func splitSQLStatements(script string) ([]sqlStatement, error) {
+	var statements []sqlStatement
+	delimiter := ";"
+
+	var current strings.Builder
+	hasCode := false
+	startLine := 1
+	line := 1
+	atLineStart := true
+
+	flush := func() {
+		if hasCode {
+			statements = append(statements, sqlStatement{SQL: strings.TrimSpace(current.String()), Line: startLine})
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+
+		if atLineStart && !hasCode {
+			rest := script[i:]
+			trimmed := strings.TrimLeft(rest, " \t")
+			if len(trimmed) > len("DELIMITER") && strings.EqualFold(trimmed[:len("DELIMITER")], "DELIMITER") && (trimmed[len("DELIMITER")] == ' ' || trimmed[len("DELIMITER")] == '\t') {
+				end := strings.IndexByte(trimmed, '\n')
+				if end < 0 {
+					end = len(trimmed)
+				}
+				newDelimiter := strings.TrimSpace(trimmed[len("DELIMITER"):end])
+				if newDelimiter == "" {
+					return nil, fmt.Errorf("line %d: DELIMITER without a delimiter", line)
+				}
+				delimiter = newDelimiter
+				i += len(rest) - len(trimmed) + end
+				current.Reset()
+				continue
+			}
+		}
+		atLineStart = false
+
+		switch {
+		case c == '\n':
+			line++
+			atLineStart = true
+			current.WriteByte(c)
+			i++
+
+		case c == '\'' || c == '"' || c == '`':
+			end, lines, err := skipQuoted(script, i, line)
+			if err != nil {
+				return nil, err
+			}
+			if !hasCode {
+				startLine = line
+			}
+			hasCode = true
+			current.WriteString(script[i:end])
+			line += lines
+			i = end
+
+		case c == '#' || (c == '-' && strings.HasPrefix(script[i:], "--") && (i+2 == len(script) || unicode.IsSpace(rune(script[i+2])))):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			current.WriteString(script[i : i+end])
+			i += end
+
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			comment := script[i : i+2+end+2]
+			// Executable comments like /*!50100 ... */ are code.
+			if strings.HasPrefix(comment, "/*!") || strings.HasPrefix(comment, "/*M!") {
+				if !hasCode {
+					startLine = line
+				}
+				hasCode = true
+			}
+			current.WriteString(comment)
+			line += strings.Count(comment, "\n")
+			i += len(comment)
+
+		case strings.HasPrefix(script[i:], delimiter):
+			flush()
+			i += len(delimiter)
+
+		default:
+			if !unicode.IsSpace(rune(c)) {
+				if !hasCode {
+					startLine = line
+				}
+				hasCode = true
+			}
+			current.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+
+	return statements, nil
+}
+
+// skipQuoted returns the end of the string or quoted identifier starting at
+// start and the newlines in it. Quotes are escaped by doubling them and, in
+// strings, with a backslash.
+func skipQuoted(script string, start, line int) (int, int, error) {
+	quote := script[start]
+	lines := 0
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case '\n':
+			lines++
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, lines, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("line %d: unterminated %c", line, quote)
+}
+
+// normalizeSQLBody drops comments and collapses whitespace outside of
+// strings and quoted identifiers, so SQL bodies that only differ in
+// formatting compare equal. Executable comments like /*!50100 ... */ are
+// kept.
+func normalizeSQLBody(body string) string {
+	var normalized strings.Builder
+	space := false
+	for i := 0; i < len(body); {
+		c := body[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end, _, err := skipQuoted(body, i, 0)
+			if err != nil {
+				end = len(body)
+			}
+			if space && normalized.Len() > 0 {
+				normalized.WriteByte(' ')
+			}
+			space = false
+			normalized.WriteString(body[i:end])
+			i = end
+
+		case c == '#' || (c == '-' && strings.HasPrefix(body[i:], "--") && (i+2 == len(body) || unicode.IsSpace(rune(body[i+2])))):
+			end := strings.IndexByte(body[i:], '\n')
+			if end < 0 {
+				end = len(body) - i
+			}
+			space = true
+			i += end
+
+		case c == '/' && strings.HasPrefix(body[i:], "/*") && !strings.HasPrefix(body[i:], "/*!") && !strings.HasPrefix(body[i:], "/*M!"):
+			end := strings.Index(body[i+2:], "*/")
+			if end < 0 {
+				end = len(body) - i - 4
+			}
+			space = true
+			i += end + 4
+
+		case unicode.IsSpace(rune(c)):
+			space = true
+			i++
+
+		default:
+			if space && normalized.Len() > 0 {
+				normalized.WriteByte(' ')
+			}
+			space = false
+			normalized.WriteByte(c)
+			i++
+		}
+	}
+	return normalized.String()
+}
+
+// runSQLScript splits the script and executes its statements in order,
+// reporting the statement that failed.
+func runSQLScript(ctx context.Context, db *sql.DB, script string) error {
+	statements, err := splitSQLStatements(script)
+	if err != nil {
+		return fmt.Errorf("failed parsing SQL: %v", err)
+	}
+	for i, statement := range statements {
+		log.Printf("[DEBUG] Executing statement %d of %d (line %d): %s", i+1, len(statements), statement.Line, statement.SQL)
+		if _, err := db.ExecContext(ctx, statement.SQL); err != nil {
+			return fmt.Errorf("statement %d of %d (line %d) failed: %v\n%s", i+1, len(statements), statement.Line, err, statement.SQL)
+		}
+	}
+	return nil
+}
+
+// execSQLScript runs a script of mysql_sql. Multi-statement scripts run on a
+// dedicated connection with multiStatements enabled, so their session
+// changes (e.g. USE or SET) don't leak into other resources.
+func execSQLScript(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, script string) error {
+	if !d.Get("multi_statements").(bool) {
+		_, err := db.ExecContext(ctx, script)
+		return err
+	}
+
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok {
+		return errors.New("multi_statements requires a MySQL connection")
+	}
+	scriptConf := *conf
+	scriptConf.Config = conf.Config.Clone()
+	scriptConf.Config.MultiStatements = true
+
+	conn, err := createNewConnection(ctx, &scriptConf)
+	if err != nil {
+		return err
+	}
+	defer conn.Db.Close()
+
+	return runSQLScript(ctx, conn.Db, script)
+}
@@ -0,0 +1,153 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	cases := []struct {
+		name   string
+		script string
+		want   []sqlStatement
+	}{
+		{
+			name:   "plain",
+			script: "CREATE TABLE t (id INT);\nINSERT INTO t VALUES (1);",
+			want:   []sqlStatement{{"CREATE TABLE t (id INT)", 1}, {"INSERT INTO t VALUES (1)", 2}},
+		},
+		{
+			name:   "delimiters in strings and identifiers",
+			script: "INSERT INTO `a;b` VALUES ('x;y', \"it''s;\", 'back\\';slash');",
+			want:   []sqlStatement{{"INSERT INTO `a;b` VALUES ('x;y', \"it''s;\", 'back\\';slash')", 1}},
+		},
+		{
+			name:   "comments",
+			script: "-- create; the table\nCREATE TABLE t (id INT); # trailing;\n/* block; */\n/*!50100 SET @a = 1 */;",
+			want: []sqlStatement{
+				{"-- create; the table\nCREATE TABLE t (id INT)", 2},
+				{"# trailing;\n/* block; */\n/*!50100 SET @a = 1 */", 4},
+			},
+		},
+		{
+			name: "DELIMITER",
+			script: `DELIMITER //
+CREATE PROCEDURE p()
+BEGIN
+  SELECT 1;
+  SELECT 2;
+END //
+delimiter ;
+CALL p();`,
+			want: []sqlStatement{
+				{"CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND", 2},
+				{"CALL p()", 8},
+			},
+		},
+		{
+			name:   "double dash without space isn't a comment",
+			script: "SELECT 1--1;",
+			want:   []sqlStatement{{"SELECT 1--1", 1}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := splitSQLStatements(c.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %#v\nwant %#v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSplitSQLStatementsErrors(t *testing.T) {
+	for _, script := range []string{
+		"SELECT 'unterminated;",
+		"SELECT 1; /* unterminated",
+		"DELIMITER \nSELECT 1;",
+	} {
+		if _, err := splitSQLStatements(script); err == nil {
+			t.Errorf("no error for %q", script)
+		}
+	}
+}
+
+func TestRenderSql(t *testing.T) {
+	variables := map[string]interface{}{"db": "app`db", "name": "O'Brien", "limit": "10"}
+	got, err := renderSql("INSERT INTO {{ ident .db }}.t VALUES ({{ quote .name }}) LIMIT {{ .limit }}", variables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "INSERT INTO `app``db`.t VALUES ('O\\'Brien') LIMIT 10"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if _, err := renderSql("SELECT {{ .missing }}", variables); err == nil {
+		t.Error("no error for a missing variable")
+	}
+	if got, _ := renderSql("SELECT '{'", nil); got != "SELECT '{'" {
+		t.Errorf("SQL without template changed: %s", got)
+	}
+}
+
+func TestNormalizeSQLBody(t *testing.T) {
+	a := "CREATE PROCEDURE p()\nBEGIN\n  -- say hi\n  SELECT   'a  b', `c  d`; /* done */\nEND"
+	b := "CREATE PROCEDURE p() BEGIN\tSELECT 'a  b', `c  d`;\n# comment\nEND\n"
+	want := "CREATE PROCEDURE p() BEGIN SELECT 'a  b', `c  d`; END"
+	for _, body := range []string{a, b} {
+		if got := normalizeSQLBody(body); got != want {
+			t.Errorf("normalizeSQLBody(%q) = %q, want %q", body, got, want)
+		}
+	}
+	if got := normalizeSQLBody("SELECT /*!50100 1 */ -- x"); got != "SELECT /*!50100 1 */" {
+		t.Errorf("executable comment dropped: %q", got)
+	}
+	if normalizeSQLBody("SELECT 'a b'") == normalizeSQLBody("SELECT 'a  b'") {
+		t.Error("whitespace in strings ignored")
+	}
+}
+
+func TestDiffSqlChecksumWithoutOldChecksum(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "seed",
+		Attributes: map[string]string{
+			"id":                     "seed",
+			"name":                   "seed",
+			"create_sql":             "INSERT INTO t VALUES (1)",
+			"delete_sql":             "DELETE FROM t",
+			"ignore_body_whitespace": "false",
+			"multi_statements":       "false",
+		},
+	}
+	tests := []struct {
+		createSql string
+		forcesNew bool
+	}{
+		{"INSERT INTO t VALUES (1)", false},
+		{"INSERT INTO t VALUES (2)", true},
+	}
+	for _, tt := range tests {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":       "seed",
+			"create_sql": tt.createSql,
+			"delete_sql": "DELETE FROM t",
+		})
+		diff, err := resourceSql().Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff == nil {
+			t.Fatalf("create_sql %q has no diff, expected the checksum to be added", tt.createSql)
+		}
+		if diff.RequiresNew() != tt.forcesNew {
+			t.Errorf("create_sql %q requires new: %v, expected %v", tt.createSql, diff.RequiresNew(), tt.forcesNew)
+		}
+	}
+}
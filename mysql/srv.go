@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+const srvEndpointPrefix = "srv://"
+
+// srvNet is the driver network of srv:// endpoints.
+const srvNet = "srv"
+
+type srvLookupFunc func(ctx context.Context, name string) ([]*net.SRV, error)
+
+func lookupSRV(ctx context.Context, name string) ([]*net.SRV, error) {
+	_, addrs, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	return addrs, err
+}
+
+// dialSRV resolves the SRV record name and connects to the first target
+// accepting the connection. Targets come ordered by priority and randomized
+// by weight. The record is resolved again for every new connection, so
+// reconnects follow changes in service discovery.
+func dialSRV(ctx context.Context, dialer proxy.Dialer, lookup srvLookupFunc, name string) (net.Conn, error) {
+	targets, err := lookup(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving SRV record %s: %v", name, err)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("SRV record %s has no targets", name)
+	}
+
+	var errs []string
+	for _, target := range targets {
+		addr := net.JoinHostPort(strings.TrimSuffix(target.Target, "."), strconv.Itoa(int(target.Port)))
+		log.Printf("[DEBUG] Connecting to %s from SRV record %s", addr, name)
+		conn, err := dialer.Dial("tcp", addr)
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", addr, err))
+	}
+	return nil, fmt.Errorf("no target of SRV record %s is reachable: %s", name, strings.Join(errs, "; "))
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+)
+
+type fakeDialer struct {
+	reachable map[string]bool
+	dialed    []string
+}
+
+func (f *fakeDialer) Dial(network, addr string) (net.Conn, error) {
+	f.dialed = append(f.dialed, addr)
+	if !f.reachable[addr] {
+		return nil, fmt.Errorf("connection refused")
+	}
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
+
+func TestDialSRV(t *testing.T) {
+	lookup := func(ctx context.Context, name string) ([]*net.SRV, error) {
+		if name != "_mysql._tcp.db.internal" {
+			return nil, fmt.Errorf("no such host")
+		}
+		return []*net.SRV{
+			{Target: "db1.internal.", Port: 3306, Priority: 10},
+			{Target: "db2.internal.", Port: 3307, Priority: 20},
+		}, nil
+	}
+
+	dialer := &fakeDialer{reachable: map[string]bool{"db2.internal:3307": true}}
+	conn, err := dialSRV(context.Background(), dialer, lookup, "_mysql._tcp.db.internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if want := []string{"db1.internal:3306", "db2.internal:3307"}; fmt.Sprint(dialer.dialed) != fmt.Sprint(want) {
+		t.Errorf("dialed %v, want %v", dialer.dialed, want)
+	}
+
+	if _, err := dialSRV(context.Background(), &fakeDialer{}, lookup, "_mysql._tcp.db.internal"); err == nil {
+		t.Error("no error without reachable targets")
+	}
+	if _, err := dialSRV(context.Background(), &fakeDialer{}, lookup, "_mysql._tcp.other"); err == nil {
+		t.Error("no error for a missing record")
+	}
+}
@@ -0,0 +1,316 @@
+package mysql
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Encrypted state values look like enc:v1:<wrapped data key>:<nonce and
+// ciphertext>, both base64 encoded.
+const stateEncryptionPrefix = "enc:v1:"
+
+// stateKeyProvider wraps and unwraps the data keys used to encrypt state.
+type stateKeyProvider interface {
+	GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, err error)
+	Decrypt(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// stateEncryptor envelope encrypts password-derived values before they are
+// stored in the state. One data key is generated per provider run; keys of
+// older values are unwrapped on demand and cached.
+type stateEncryptor struct {
+	keys stateKeyProvider
+
+	mu      sync.Mutex
+	wrapped []byte
+}
+
+// stateDataKeys caches the data keys unwrapped by the encryptors of all
+// provider instances, by wrapped key. Diff suppress functions don't get the
+// provider meta, so they decrypt with these, or with any encryptor in
+// stateEncryptors when the key isn't cached yet. The wrapped key in each
+// value selects the KMS key, so provider aliases using other keys don't
+// interfere.
+var (
+	stateEncryptionMtx sync.Mutex
+	stateDataKeys      = map[string][]byte{}
+	stateEncryptors    []*stateEncryptor
+)
+
+func registerStateEncryptor(e *stateEncryptor) {
+	stateEncryptionMtx.Lock()
+	defer stateEncryptionMtx.Unlock()
+	stateEncryptors = append(stateEncryptors, e)
+}
+
+func cachedStateDataKey(wrapped []byte) ([]byte, bool) {
+	stateEncryptionMtx.Lock()
+	defer stateEncryptionMtx.Unlock()
+	dataKey, ok := stateDataKeys[string(wrapped)]
+	return dataKey, ok
+}
+
+func cacheStateDataKey(wrapped, dataKey []byte) {
+	stateEncryptionMtx.Lock()
+	defer stateEncryptionMtx.Unlock()
+	stateDataKeys[string(wrapped)] = dataKey
+}
+
+// stateEncryptionFromMeta returns the encryptor of the provider, nil
+// without state_encryption.
+func stateEncryptionFromMeta(meta interface{}) *stateEncryptor {
+	switch conf := meta.(type) {
+	case *MySQLConfiguration:
+		return conf.StateEncryption
+	case *RDSDataAPIConfiguration:
+		return conf.StateEncryption
+	}
+	return nil
+}
+
+func newStateEncryptor(keys stateKeyProvider) *stateEncryptor {
+	return &stateEncryptor{keys: keys}
+}
+
+func isEncryptedStateValue(value string) bool {
+	return strings.HasPrefix(value, stateEncryptionPrefix)
+}
+
+func (e *stateEncryptor) encrypt(ctx context.Context, value string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.wrapped == nil {
+		dataKey, wrapped, err := e.keys.GenerateDataKey(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed generating data key: %v", err)
+		}
+		e.wrapped = wrapped
+		cacheStateDataKey(wrapped, dataKey)
+	}
+
+	dataKey, _ := cachedStateDataKey(e.wrapped)
+	gcm, err := newStateCipher(dataKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+
+	return stateEncryptionPrefix + base64.StdEncoding.EncodeToString(e.wrapped) + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt returns values without the encryption prefix unchanged, so state
+// written before encryption was enabled keeps working.
+func (e *stateEncryptor) decrypt(ctx context.Context, value string) (string, error) {
+	return decryptStateValueWith(ctx, value, func(ctx context.Context, wrapped []byte) ([]byte, error) {
+		return e.keys.Decrypt(ctx, wrapped)
+	})
+}
+
+// decryptStateValueWith decrypts value, unwrapping its data key with unwrap
+// unless it's cached.
+func decryptStateValueWith(ctx context.Context, value string, unwrap func(context.Context, []byte) ([]byte, error)) (string, error) {
+	if !isEncryptedStateValue(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, stateEncryptionPrefix), ":")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("malformed encrypted state value")
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted state value: %v", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted state value: %v", err)
+	}
+
+	dataKey, ok := cachedStateDataKey(wrapped)
+	if !ok {
+		dataKey, err = unwrap(ctx, wrapped)
+		if err != nil {
+			return "", fmt.Errorf("failed decrypting data key: %v", err)
+		}
+		cacheStateDataKey(wrapped, dataKey)
+	}
+
+	gcm, err := newStateCipher(dataKey)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted state value")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed decrypting state value: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+func newStateCipher(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptStateValue encrypts a value for the state when state_encryption is
+// configured.
+func encryptStateValue(ctx context.Context, meta interface{}, value string) (string, error) {
+	e := stateEncryptionFromMeta(meta)
+	if e == nil || value == "" {
+		return value, nil
+	}
+	return e.encrypt(ctx, value)
+}
+
+// decryptStateValue reverses encryptStateValue. Without state_encryption,
+// encrypted values can't be read back.
+func decryptStateValue(ctx context.Context, meta interface{}, value string) (string, error) {
+	if !isEncryptedStateValue(value) {
+		return value, nil
+	}
+	e := stateEncryptionFromMeta(meta)
+	if e == nil {
+		return "", fmt.Errorf("state contains encrypted values, but state_encryption is not configured")
+	}
+	return e.decrypt(ctx, value)
+}
+
+// setEncryptedStateValue stores value in d, encrypted when state_encryption
+// is configured. The current state value is kept when it decrypts to value,
+// so refreshes don't rewrite the state.
+func setEncryptedStateValue(ctx context.Context, d *schema.ResourceData, meta interface{}, key, value string) error {
+	if stateEncryptionFromMeta(meta) == nil || value == "" {
+		return d.Set(key, value)
+	}
+	current := d.Get(key).(string)
+	if isEncryptedStateValue(current) {
+		if old, err := decryptStateValue(ctx, meta, current); err == nil && old == value {
+			return nil
+		}
+	}
+	encrypted, err := encryptStateValue(ctx, meta, value)
+	if err != nil {
+		return err
+	}
+	return d.Set(key, encrypted)
+}
+
+// getDecryptedString returns a string attribute that may hold an encrypted
+// value, e.g. copied from a mysql_user_definition data source.
+func getDecryptedString(ctx context.Context, d *schema.ResourceData, meta interface{}, key string) (string, error) {
+	value, err := decryptStateValue(ctx, meta, d.Get(key).(string))
+	if err != nil {
+		return "", fmt.Errorf("failed decrypting %s: %v", key, err)
+	}
+	return value, nil
+}
+
+// unwrapWithAnyStateEncryptor unwraps a data key with the encryptors of the
+// provider instances in turn, for diff suppress functions.
+func unwrapWithAnyStateEncryptor(ctx context.Context, wrapped []byte) ([]byte, error) {
+	stateEncryptionMtx.Lock()
+	encryptors := append([]*stateEncryptor(nil), stateEncryptors...)
+	stateEncryptionMtx.Unlock()
+
+	err := fmt.Errorf("state_encryption is not configured")
+	for _, e := range encryptors {
+		var dataKey []byte
+		if dataKey, err = e.keys.Decrypt(ctx, wrapped); err == nil {
+			return dataKey, nil
+		}
+	}
+	return nil, err
+}
+
+// suppressEncryptedDiff compares the decrypted state value instead of the
+// ciphertext before calling suppress.
+func suppressEncryptedDiff(suppress schema.SchemaDiffSuppressFunc) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if isEncryptedStateValue(old) {
+			decrypted, err := decryptStateValueWith(context.Background(), old, unwrapWithAnyStateEncryptor)
+			if err != nil {
+				return false
+			}
+			if decrypted == new {
+				return true
+			}
+			old = decrypted
+		}
+		return suppress(k, old, new, d)
+	}
+}
+
+func buildStateEncryptor(ctx context.Context, awsConfigBlock []interface{}, block map[string]interface{}) (*stateEncryptor, error) {
+	awsConfigObj, err := buildAwsConfig(ctx, awsConfigBlock)
+	if err != nil {
+		return nil, err
+	}
+	if region := block["region"].(string); region != "" {
+		awsConfigObj.Region = region
+	}
+	if awsConfigObj.Region == "" {
+		return nil, fmt.Errorf("region must be set in state_encryption or aws_config")
+	}
+
+	keyId := block["kms_key_id"].(string)
+	endpoint := block["endpoint"].(string)
+	client := kms.NewFromConfig(awsConfigObj, func(o *kms.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+
+	e := newStateEncryptor(&awsKMS{client: client, keyId: keyId})
+	registerStateEncryptor(e)
+	return e, nil
+}
+
+// awsKMS wraps data keys with an AWS KMS key.
+type awsKMS struct {
+	client *kms.Client
+	keyId  string
+}
+
+func (k *awsKMS) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	out, err := k.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(k.keyId),
+		KeySpec: kmstypes.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out.Plaintext, out.CiphertextBlob, nil
+}
+
+func (k *awsKMS) Decrypt(ctx context.Context, wrapped []byte) ([]byte, error) {
+	out, err := k.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(k.keyId),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
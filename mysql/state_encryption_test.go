@@ -0,0 +1,144 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestStateEncryptionAwsKMS(t *testing.T) {
+	dataKey := bytes.Repeat([]byte{7}, 32)
+	wrapped := []byte("wrapped-data-key")
+	decrypts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+			t.Errorf("request is not signed: %q", r.Header.Get("Authorization"))
+		}
+		var in struct {
+			KeyId          string
+			KeySpec        string
+			CiphertextBlob []byte
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Fatal(err)
+		}
+		if in.KeyId != "alias/terraform" {
+			t.Errorf("unexpected key id %q", in.KeyId)
+		}
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GenerateDataKey":
+			json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": dataKey, "CiphertextBlob": wrapped})
+		case "TrentService.Decrypt":
+			decrypts++
+			if !bytes.Equal(in.CiphertextBlob, wrapped) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": dataKey})
+		default:
+			t.Errorf("unexpected target %q", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	keys := &awsKMS{
+		client: kms.NewFromConfig(aws.Config{
+			Region:      "us-east-1",
+			Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		}, func(o *kms.Options) {
+			o.BaseEndpoint = aws.String(server.URL)
+		}),
+		keyId: "alias/terraform",
+	}
+	ctx := context.Background()
+
+	encrypted, err := newStateEncryptor(keys).encrypt(ctx, "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncryptedStateValue(encrypted) || strings.Contains(encrypted, "2470C0C0") {
+		t.Fatalf("value is not encrypted: %s", encrypted)
+	}
+
+	// A new provider run has to unwrap the data key again, once.
+	stateEncryptionMtx.Lock()
+	stateDataKeys = map[string][]byte{}
+	stateEncryptionMtx.Unlock()
+	encryptor := newStateEncryptor(keys)
+	for i := 0; i < 2; i++ {
+		decrypted, err := encryptor.decrypt(ctx, encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted != "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19" {
+			t.Errorf("unexpected decrypted value %q", decrypted)
+		}
+	}
+	if decrypts != 1 {
+		t.Errorf("expected 1 Decrypt call, got %d", decrypts)
+	}
+
+	if decrypted, err := encryptor.decrypt(ctx, "0x1234"); err != nil || decrypted != "0x1234" {
+		t.Errorf("plain values must be returned unchanged, got %q, %v", decrypted, err)
+	}
+	if _, err := encryptor.decrypt(ctx, encrypted[:len(encrypted)-4]+"AAAA"); err == nil {
+		t.Errorf("expected an error for a tampered value")
+	}
+}
+
+// fakeStateKeys wraps data keys by prefixing them with its name.
+type fakeStateKeys string
+
+func (k fakeStateKeys) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	dataKey := bytes.Repeat([]byte(k[:1]), 32)
+	return dataKey, append([]byte(k), dataKey...), nil
+}
+
+func (k fakeStateKeys) Decrypt(ctx context.Context, wrapped []byte) ([]byte, error) {
+	if !bytes.HasPrefix(wrapped, []byte(k)) {
+		return nil, fmt.Errorf("data key isn't wrapped by %s", k)
+	}
+	return wrapped[len(k):], nil
+}
+
+func TestStateEncryptionPerProvider(t *testing.T) {
+	ctx := context.Background()
+	primary := &MySQLConfiguration{StateEncryption: newStateEncryptor(fakeStateKeys("primary"))}
+	replica := &MySQLConfiguration{StateEncryption: newStateEncryptor(fakeStateKeys("replica"))}
+	registerStateEncryptor(primary.StateEncryption)
+	registerStateEncryptor(replica.StateEncryption)
+
+	encrypted, err := encryptStateValue(ctx, primary, "0x1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, err := encryptStateValue(ctx, replica, "0x1234"); err != nil || strings.Split(other, ":")[2] == strings.Split(encrypted, ":")[2] {
+		t.Errorf("aliases must use their own keys, got %q and %q, %v", encrypted, other, err)
+	}
+	if _, err := encryptStateValue(ctx, &MySQLConfiguration{}, "0x1234"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decryptStateValue(ctx, &MySQLConfiguration{}, encrypted); err == nil {
+		t.Errorf("expected an error decrypting without state_encryption")
+	}
+
+	stateEncryptionMtx.Lock()
+	stateDataKeys = map[string][]byte{}
+	stateEncryptionMtx.Unlock()
+	suppress := suppressEncryptedDiff(func(k, old, new string, d *schema.ResourceData) bool { return false })
+	if !suppress("auth_string_hex", encrypted, "0x1234", nil) {
+		t.Errorf("diff of an unchanged encrypted value isn't suppressed")
+	}
+}
@@ -0,0 +1,132 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withIDStateUpgrader bumps the schema version of a resource and rewrites
+// the IDs of older states to the current format. The upgrade runs on states
+// written by any earlier release, so no state surgery is needed when the ID
+// format changes.
+func withIDStateUpgrader(r *schema.Resource, upgrade schema.StateUpgradeFunc) *schema.Resource {
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    r.CoreConfigSchema().ImpliedType(),
+			Upgrade: upgrade,
+		},
+	}
+	return r
+}
+
+func rawStateString(rawState map[string]interface{}, key string) string {
+	s, _ := rawState[key].(string)
+	return s
+}
+
+// rawStateHosts returns the sorted hosts of a raw state.
+func rawStateHosts(rawState map[string]interface{}) []string {
+	var hosts []string
+	if values, ok := rawState["hosts"].([]interface{}); ok {
+		for _, value := range values {
+			if host, ok := value.(string); ok {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// upgradeUserStateV0 sets the ID to USER@HOSTS. Older releases used the
+// single host, kept the hosts unsorted or only stored the user.
+func upgradeUserStateV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	user := rawStateString(rawState, "user")
+	if user == "" {
+		return rawState, nil
+	}
+
+	hosts := rawStateHosts(rawState)
+	if len(hosts) == 0 {
+		host := rawStateString(rawState, "host")
+		if host == "" {
+			host = defaultUserHostFromMeta(meta)
+		}
+		hosts = []string{host}
+	}
+	rawState["id"] = fmt.Sprintf("%s@%s", user, strings.Join(hosts, ","))
+	return rawState, nil
+}
+
+// upgradeGrantStateV0 sets the ID to the one of the grant's first host, as
+// CreateGrant does. Older releases used USER@HOST:DATABASE for table grants.
+// Procedure grants keep their ID.
+func upgradeGrantStateV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	database := rawStateString(rawState, "database")
+	if kReProcedureWithDatabase.MatchString(database) || kReProcedureWithoutDatabase.MatchString(database) {
+		return rawState, nil
+	}
+
+	userOrRole := UserOrRole{Name: rawStateString(rawState, "role")}
+	if userOrRole.Name == "" {
+		userOrRole.Name = rawStateString(rawState, "user")
+		userOrRole.Host = rawStateString(rawState, "host")
+		if hosts := rawStateHosts(rawState); len(hosts) > 0 {
+			userOrRole.Host = hosts[0]
+		}
+	}
+	if userOrRole.Name == "" {
+		return rawState, nil
+	}
+
+	var grant MySQLGrant
+	if roles, ok := rawState["roles"].([]interface{}); ok && len(roles) > 0 {
+		grant = &RoleGrant{UserOrRole: userOrRole}
+	} else {
+		table := rawStateString(rawState, "table")
+		if table == "" {
+			table = "*"
+		}
+		if database == "" {
+			database = "*"
+		}
+		grant = &TablePrivilegeGrant{Database: database, Table: table, UserOrRole: userOrRole}
+	}
+	rawState["id"] = grant.GetId()
+	return rawState, nil
+}
+
+// MoveResourceState adopts resources of the same type from other providers,
+// e.g. mysql_user of an older fork in a moved block. The source state is
+// upgraded as a version 0 state of this provider.
+func (s *providerServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	if req.SourceTypeName != req.TargetTypeName || req.SourceState == nil {
+		return &tfprotov5.MoveResourceStateResponse{
+			Diagnostics: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Move Resource State Not Supported",
+				Detail:   fmt.Sprintf("Only resources of the same type can be moved to %s, not %s.", req.TargetTypeName, req.SourceTypeName),
+			}},
+		}, nil
+	}
+
+	upgraded, err := s.ProviderServer.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: req.TargetTypeName,
+		Version:  0,
+		RawState: req.SourceState,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov5.MoveResourceStateResponse{
+		TargetState: upgraded.UpgradedState,
+		Diagnostics: upgraded.Diagnostics,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+func TestUpgradeUserStateV0(t *testing.T) {
+	cases := []struct {
+		state map[string]interface{}
+		id    string
+	}{
+		{map[string]interface{}{"id": "app", "user": "app", "host": "10.0.0.1"}, "app@10.0.0.1"},
+		{map[string]interface{}{"id": "app@b,a", "user": "app", "host": "", "hosts": []interface{}{"b", "a"}}, "app@a,b"},
+		{map[string]interface{}{"id": "app", "user": "app"}, "app@localhost"},
+	}
+	for _, c := range cases {
+		state, err := upgradeUserStateV0(context.Background(), c.state, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state["id"] != c.id {
+			t.Errorf("got ID %v, want %s", state["id"], c.id)
+		}
+	}
+}
+
+func TestUpgradeGrantStateV0(t *testing.T) {
+	cases := []struct {
+		state map[string]interface{}
+		id    string
+	}{
+		{map[string]interface{}{"id": "app@%:shop", "user": "app", "host": "%", "database": "shop", "table": "*"}, "app@%:`shop`:*"},
+		{map[string]interface{}{"id": "old", "user": "app", "hosts": []interface{}{"b", "a"}, "database": "shop", "table": "orders"}, "app@a:`shop`:`orders`"},
+		{map[string]interface{}{"id": "old", "user": "app", "host": "%", "database": "*", "roles": []interface{}{"reader"}}, "app@%"},
+		{map[string]interface{}{"id": "old", "role": "reader", "database": "shop", "table": "*"}, "reader:`shop`:*"},
+		{map[string]interface{}{"id": "kept", "user": "app", "host": "%", "database": "PROCEDURE shop.checkout"}, "kept"},
+	}
+	for _, c := range cases {
+		state, err := upgradeGrantStateV0(context.Background(), c.state, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state["id"] != c.id {
+			t.Errorf("got ID %v, want %s", state["id"], c.id)
+		}
+	}
+}
+
+func TestMoveResourceState(t *testing.T) {
+	server := ProviderServer()
+	ctx := context.Background()
+
+	resp, err := server.MoveResourceState(ctx, &tfprotov5.MoveResourceStateRequest{
+		SourceProviderAddress: "registry.terraform.io/hashicorp/mysql",
+		SourceTypeName:        "mysql_user",
+		TargetTypeName:        "mysql_user",
+		SourceState:           &tfprotov5.RawState{JSON: []byte(`{"id":"app","user":"app","host":"10.0.0.1","unknown_attribute":true}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %s: %s", resp.Diagnostics[0].Summary, resp.Diagnostics[0].Detail)
+	}
+	ty := Provider().ResourcesMap["mysql_user"].CoreConfigSchema().ImpliedType()
+	state, err := msgpack.Unmarshal(resp.TargetState.MsgPack, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := state.GetAttr("id"); !id.RawEquals(cty.StringVal("app@10.0.0.1")) {
+		t.Errorf("got ID %#v, want app@10.0.0.1", id)
+	}
+
+	resp, err = server.MoveResourceState(ctx, &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "mysql_role",
+		TargetTypeName: "mysql_user",
+		SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id":"app","name":"app"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Errorf("moving a role to a user isn't refused")
+	}
+}
@@ -0,0 +1,236 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	statementClassDDL      = "ddl"
+	statementClassDML      = "dml"
+	statementClassMetadata = "metadata"
+)
+
+// statementTimeoutGrace is how much longer the provider waits for a SELECT
+// with a MAX_EXECUTION_TIME hint, so the server aborts it first and the
+// connection is kept.
+const statementTimeoutGrace = 2 * time.Second
+
+var (
+	kReLeadingComments = regexp.MustCompile(`^(\s+|/\*[^+!](?s:.*?)\*/|/\*\*/|(--|#)[^\n]*\n?)*`)
+	kReLeadingSelect   = regexp.MustCompile(`(?i)^SELECT\b`)
+	kReFirstKeyword    = regexp.MustCompile(`^\(*\s*([A-Za-z]+)`)
+)
+
+// statementClasses maps the first keyword of a statement to its class.
+// Statements of other kinds, like SET or KILL, have no timeout.
+var statementClasses = map[string]string{
+	"ALTER":    statementClassDDL,
+	"ANALYZE":  statementClassDDL,
+	"CREATE":   statementClassDDL,
+	"DROP":     statementClassDDL,
+	"GRANT":    statementClassDDL,
+	"OPTIMIZE": statementClassDDL,
+	"RENAME":   statementClassDDL,
+	"REPAIR":   statementClassDDL,
+	"REVOKE":   statementClassDDL,
+	"TRUNCATE": statementClassDDL,
+	"CALL":     statementClassDML,
+	"DELETE":   statementClassDML,
+	"INSERT":   statementClassDML,
+	"LOAD":     statementClassDML,
+	"REPLACE":  statementClassDML,
+	"UPDATE":   statementClassDML,
+	"DESC":     statementClassMetadata,
+	"DESCRIBE": statementClassMetadata,
+	"EXPLAIN":  statementClassMetadata,
+	"SELECT":   statementClassMetadata,
+	"SHOW":     statementClassMetadata,
+	"WITH":     statementClassMetadata,
+}
+
+// statementTimeoutsConfig is the statement_timeouts block of the provider.
+// A zero timeout disables the timeout of its class.
+type statementTimeoutsConfig struct {
+	DDL      time.Duration
+	DML      time.Duration
+	Metadata time.Duration
+}
+
+func statementTimeoutsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Maximum execution time of statements by class, so a slow SHOW can't hang a refresh while index builds keep a long timeout",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ddl_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Timeout of CREATE, ALTER, DROP, GRANT, REVOKE and similar statements, 0 for none",
+				},
+				"dml_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Timeout of INSERT, UPDATE, DELETE, LOAD DATA and CALL statements, 0 for none",
+				},
+				"metadata_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Timeout of SELECT, SHOW and DESCRIBE statements, 0 for none",
+				},
+			},
+		},
+	}
+}
+
+func buildStatementTimeoutsConfig(block []interface{}) *statementTimeoutsConfig {
+	if len(block) == 0 || block[0] == nil {
+		return nil
+	}
+	m := block[0].(map[string]interface{})
+	cfg := &statementTimeoutsConfig{
+		DDL:      time.Duration(m["ddl_sec"].(int)) * time.Second,
+		DML:      time.Duration(m["dml_sec"].(int)) * time.Second,
+		Metadata: time.Duration(m["metadata_sec"].(int)) * time.Second,
+	}
+	if *cfg == (statementTimeoutsConfig{}) {
+		return nil
+	}
+	return cfg
+}
+
+// statementClass returns the class of query, or an empty string for
+// statements without a timeout.
+func statementClass(query string) string {
+	match := kReFirstKeyword.FindStringSubmatch(kReLeadingComments.ReplaceAllString(query, ""))
+	if match == nil {
+		return ""
+	}
+	return statementClasses[strings.ToUpper(match[1])]
+}
+
+func (cfg *statementTimeoutsConfig) timeout(class string) time.Duration {
+	switch class {
+	case statementClassDDL:
+		return cfg.DDL
+	case statementClassDML:
+		return cfg.DML
+	case statementClassMetadata:
+		return cfg.Metadata
+	}
+	return 0
+}
+
+// withMaxExecutionTime adds a MAX_EXECUTION_TIME hint to a SELECT, so MySQL
+// and TiDB abort it on the server. Other servers ignore the hint as a
+// comment.
+func withMaxExecutionTime(query string, timeout time.Duration) (string, bool) {
+	trimmed := kReLeadingComments.ReplaceAllString(query, "")
+	if !kReLeadingSelect.MatchString(trimmed) || strings.Contains(strings.ToUpper(trimmed), "MAX_EXECUTION_TIME") {
+		return query, false
+	}
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */%s", timeout.Milliseconds(), trimmed[len("SELECT"):]), true
+}
+
+// statementTimeoutConnector limits how long each statement of its
+// connections runs, by the timeout of its class. Statements exceeding it are
+// cancelled, which closes the connection unless the server aborted them.
+type statementTimeoutConnector struct {
+	connector driver.Connector
+	timeouts  *statementTimeoutsConfig
+}
+
+func (c statementTimeoutConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &statementTimeoutConn{wrappedConn: wrappedConn{conn}, timeouts: c.timeouts}, nil
+}
+
+func (c statementTimeoutConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// statementTimeoutConn limits its statements. Prepared statements aren't
+// limited; the provider interpolates arguments, so it doesn't prepare them.
+type statementTimeoutConn struct {
+	wrappedConn
+	timeouts *statementTimeoutsConfig
+}
+
+// limit returns the query to run and its context, limited by the timeout of
+// the class of the query.
+func (c *statementTimeoutConn) limit(ctx context.Context, query string) (string, context.Context, context.CancelFunc, time.Duration) {
+	timeout := c.timeouts.timeout(statementClass(query))
+	if timeout <= 0 {
+		return query, ctx, func() {}, 0
+	}
+	deadline := timeout
+	if hinted, ok := withMaxExecutionTime(query, timeout); ok {
+		query = hinted
+		deadline += statementTimeoutGrace
+	}
+	limited, cancel := context.WithTimeout(ctx, deadline)
+	return query, limited, cancel, timeout
+}
+
+func timeoutError(ctx, limited context.Context, query string, timeout time.Duration, err error) error {
+	if err != nil && ctx.Err() == nil && errors.Is(limited.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s statement exceeded its timeout of %s: %w", statementClass(query), timeout, err)
+	}
+	return err
+}
+
+func (c *statementTimeoutConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	limitedQuery, limited, cancel, timeout := c.limit(ctx, query)
+	defer cancel()
+	result, err := execer.ExecContext(limited, limitedQuery, args)
+	return result, timeoutError(ctx, limited, query, timeout, err)
+}
+
+func (c *statementTimeoutConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	limitedQuery, limited, cancel, timeout := c.limit(ctx, query)
+	rows, err := queryer.QueryContext(limited, limitedQuery, args)
+	if err != nil {
+		cancel()
+		return nil, timeoutError(ctx, limited, query, timeout, err)
+	}
+	// The rows are read after QueryContext returns, within the timeout.
+	return &statementTimeoutRows{wrappedRows: wrappedRows{rows}, cancel: cancel}, nil
+}
+
+// statementTimeoutRows releases the timeout of its query once closed.
+type statementTimeoutRows struct {
+	wrappedRows
+	cancel context.CancelFunc
+}
+
+func (r *statementTimeoutRows) Close() error {
+	defer r.cancel()
+	return r.Rows.Close()
+}
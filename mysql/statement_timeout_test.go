@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatementClass(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"CREATE DATABASE `a`", statementClassDDL},
+		{"alter table t add index (a)", statementClassDDL},
+		{"GRANT SELECT ON *.* TO 'a'@'%'", statementClassDDL},
+		{"INSERT INTO t VALUES (1)", statementClassDML},
+		{"LOAD DATA LOCAL INFILE 'x' INTO TABLE t", statementClassDML},
+		{"SHOW GRANTS FOR 'a'@'%'", statementClassMetadata},
+		{"SELECT\n1", statementClassMetadata},
+		{"(SELECT 1) UNION (SELECT 2)", statementClassMetadata},
+		{"/* comment */ SELECT 1", statementClassMetadata},
+		{"-- comment\nDROP USER 'a'@'%'", statementClassDDL},
+		{"SET SESSION sql_mode=''", ""},
+		{"KILL QUERY 12", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := statementClass(tt.query); got != tt.want {
+			t.Errorf("statementClass(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestWithMaxExecutionTime(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+		ok    bool
+	}{
+		{"SELECT 1", "SELECT /*+ MAX_EXECUTION_TIME(1500) */ 1", true},
+		{"  select a FROM t", "SELECT /*+ MAX_EXECUTION_TIME(1500) */ a FROM t", true},
+		{"SELECT /*+ MAX_EXECUTION_TIME(10) */ 1", "SELECT /*+ MAX_EXECUTION_TIME(10) */ 1", false},
+		{"SHOW DATABASES", "SHOW DATABASES", false},
+		{"SELECTED", "SELECTED", false},
+	}
+	for _, tt := range tests {
+		got, ok := withMaxExecutionTime(tt.query, 1500*time.Millisecond)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("withMaxExecutionTime(%q) = %q, %v, want %q, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBuildStatementTimeoutsConfig(t *testing.T) {
+	if cfg := buildStatementTimeoutsConfig(nil); cfg != nil {
+		t.Errorf("buildStatementTimeoutsConfig(nil) = %+v, want nil", cfg)
+	}
+	if cfg := buildStatementTimeoutsConfig([]interface{}{map[string]interface{}{"ddl_sec": 0, "dml_sec": 0, "metadata_sec": 0}}); cfg != nil {
+		t.Errorf("buildStatementTimeoutsConfig without timeouts = %+v, want nil", cfg)
+	}
+
+	cfg := buildStatementTimeoutsConfig([]interface{}{map[string]interface{}{
+		"ddl_sec":      3600,
+		"dml_sec":      0,
+		"metadata_sec": 30,
+	}})
+	want := statementTimeoutsConfig{DDL: time.Hour, Metadata: 30 * time.Second}
+	if cfg == nil || *cfg != want {
+		t.Errorf("buildStatementTimeoutsConfig = %+v, want %+v", cfg, want)
+	}
+	if got := cfg.timeout(statementClassDML); got != 0 {
+		t.Errorf("timeout(dml) = %s, want 0", got)
+	}
+}
@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Sweepers drop what interrupted acceptance tests left behind. Run them
+// against a test server only, with make sweep or
+//
+//	go test ./mysql -v -sweep=local
+//
+// The region argument isn't used; the server comes from MYSQL_ENDPOINT.
+
+// sweepAccountPrefixes and sweepDatabasePrefixes are LIKE patterns of the
+// names the acceptance tests use.
+var (
+	sweepAccountPrefixes  = []string{"jdoe%", "tf-test%", `tf\_%`, "TFRole-%"}
+	sweepDatabasePrefixes = []string{"tf-test%", `tf\_test%`, `tf\_ci\_%`}
+)
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("mysql_grant", &resource.Sweeper{
+		Name: "mysql_grant",
+		F:    sweepGrants,
+	})
+	resource.AddTestSweepers("mysql_role", &resource.Sweeper{
+		Name:         "mysql_role",
+		F:            sweepRoles,
+		Dependencies: []string{"mysql_grant"},
+	})
+	resource.AddTestSweepers("mysql_user", &resource.Sweeper{
+		Name:         "mysql_user",
+		F:            sweepUsers,
+		Dependencies: []string{"mysql_grant", "mysql_role"},
+	})
+	resource.AddTestSweepers("mysql_database", &resource.Sweeper{
+		Name:         "mysql_database",
+		F:            sweepDatabases,
+		Dependencies: []string{"mysql_grant"},
+	})
+}
+
+// sweeperDB connects like the acceptance tests do.
+func sweeperDB() (*sql.DB, error) {
+	ctx := context.Background()
+	for _, name := range []string{"MYSQL_ENDPOINT", "MYSQL_USERNAME"} {
+		if os.Getenv(name) == "" {
+			return nil, fmt.Errorf("MYSQL_ENDPOINT, MYSQL_USERNAME and optionally MYSQL_PASSWORD must be set for sweepers")
+		}
+	}
+	raw := map[string]interface{}{
+		"conn_params": map[string]interface{}{},
+	}
+	if diags := testAccProvider.Configure(ctx, terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+		return nil, fmt.Errorf("failed configuring provider: %v", diags)
+	}
+	return connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+}
+
+// sweepLikeClause returns a condition matching column against any of the
+// patterns, and its arguments.
+func sweepLikeClause(column string, patterns []string) (string, []interface{}) {
+	conditions := make([]string, len(patterns))
+	args := make([]interface{}, len(patterns))
+	for i, p := range patterns {
+		conditions[i] = column + " LIKE ?"
+		args[i] = p
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+func sweepQueryPairs(ctx context.Context, db *sql.DB, stmtSQL string, args ...interface{}) ([][2]string, error) {
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs [][2]string
+	for rows.Next() {
+		var pair [2]string
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, rows.Err()
+}
+
+func sweepExec(ctx context.Context, db *sql.DB, stmtSQL string) {
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		log.Printf("[WARN] Sweeper failed running %s: %v", stmtSQL, err)
+	}
+}
+
+// sweepGrants revokes privileges on test databases from accounts that
+// aren't swept themselves, as dropping a database keeps its grants.
+func sweepGrants(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB()
+	if err != nil {
+		return err
+	}
+
+	where, args := sweepLikeClause("TABLE_SCHEMA", sweepDatabasePrefixes)
+	grants, err := sweepQueryPairs(ctx, db, "SELECT DISTINCT GRANTEE, TABLE_SCHEMA FROM information_schema.SCHEMA_PRIVILEGES WHERE "+where, args...)
+	if err != nil {
+		return fmt.Errorf("failed listing grants: %v", err)
+	}
+	for _, g := range grants {
+		sweepExec(ctx, db, fmt.Sprintf("REVOKE ALL PRIVILEGES ON %s.* FROM %s", quoteIdentifier(g[1]), g[0]))
+	}
+	return nil
+}
+
+// sweepRoles drops MariaDB roles. MySQL keeps roles as accounts, which
+// sweepUsers drops.
+func sweepRoles(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB()
+	if err != nil {
+		return err
+	}
+	if isMariaDB, err := serverMariaDB(db); err != nil || !isMariaDB {
+		return err
+	}
+
+	where, args := sweepLikeClause("User", sweepAccountPrefixes)
+	roles, err := sweepQueryPairs(ctx, db, "SELECT User, Host FROM mysql.user WHERE is_role = 'Y' AND "+where, args...)
+	if err != nil {
+		return fmt.Errorf("failed listing roles: %v", err)
+	}
+	for _, r := range roles {
+		sweepExec(ctx, db, "DROP ROLE IF EXISTS "+quoteIdentifier(r[0]))
+	}
+	return nil
+}
+
+func sweepUsers(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB()
+	if err != nil {
+		return err
+	}
+
+	where, args := sweepLikeClause("User", sweepAccountPrefixes)
+	users, err := sweepQueryPairs(ctx, db, "SELECT User, Host FROM mysql.user WHERE "+where, args...)
+	if err != nil {
+		return fmt.Errorf("failed listing users: %v", err)
+	}
+	for _, u := range users {
+		sweepExec(ctx, db, "DROP USER IF EXISTS "+formatUserIdentifier(u[0], u[1]))
+	}
+	return nil
+}
+
+// sweepDatabases drops test databases and expired ephemeral databases.
+func sweepDatabases(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB()
+	if err != nil {
+		return err
+	}
+
+	where, args := sweepLikeClause("SCHEMA_NAME", sweepDatabasePrefixes)
+	databases, err := queryStrings(ctx, db, "SELECT SCHEMA_NAME FROM information_schema.SCHEMATA WHERE "+where, args...)
+	if err != nil {
+		return fmt.Errorf("failed listing databases: %v", err)
+	}
+	for _, name := range databases {
+		sweepExec(ctx, db, "DROP DATABASE IF EXISTS "+quoteIdentifier(name))
+	}
+
+	if _, err := reapEphemeralDatabases(ctx, db, time.Now()); err != nil {
+		return fmt.Errorf("failed reaping ephemeral databases: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// probeSystemTables checks whether the provider user can read the grant
+// tables in the mysql schema. Hardened and managed servers often deny it,
+// while they allow SHOW GRANTS and SHOW CREATE USER.
+func probeSystemTables(ctx context.Context, db *sql.DB) bool {
+	stmtSQL := "SELECT 1 FROM mysql.user LIMIT 1"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	var one int
+	err := db.QueryRowContext(ctx, stmtSQL).Scan(&one)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("[WARN] Can't read mysql.user, reading accounts with SHOW statements: %v", err)
+		return false
+	}
+	return true
+}
+
+// systemTablesReadable reports whether reads may query the mysql schema, or
+// have to use SHOW statements, because show_statements_only is set or the
+// probe of the connection failed.
+func systemTablesReadable(ctx context.Context, meta interface{}) bool {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok {
+		return true
+	}
+	if conf.ShowStatementsOnly {
+		return false
+	}
+	oneConnection, err := connectToMySQLInternal(ctx, conf)
+	if err != nil {
+		return true
+	}
+	return oneConnection.SystemTablesReadable
+}
+
+// userExists reports whether the account exists. SHOW GRANTS fails with
+// ER_NONEXISTING_GRANT for unknown accounts.
+func userExists(ctx context.Context, db *sql.DB, meta interface{}, user, host string) (bool, error) {
+	if systemTablesReadable(ctx, meta) {
+		return queryHasRows(ctx, db, "SELECT 1 FROM mysql.user WHERE User = ? AND Host = ?", user, host)
+	}
+
+	stmtSQL := "SHOW GRANTS FOR " + formatUserIdentifier(user, host)
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if isNonExistingGrant(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
+
+var (
+	// kShowCreateUserDefaultRoles matches the DEFAULT ROLE clause of SHOW
+	// CREATE USER on MySQL 8.
+	kShowCreateUserDefaultRoles = regexp.MustCompile("DEFAULT ROLE ((?:`[^`]*`@`[^`]*`,?)+)")
+	kQuotedAccountName          = regexp.MustCompile("`([^`]*)`@`[^`]*`")
+	// kShowGrantsDefaultRole matches the line of SHOW GRANTS on MariaDB
+	// setting the default role.
+	kShowGrantsDefaultRole = regexp.MustCompile("^SET DEFAULT ROLE ['`]?([^'`]+)['`]? FOR ")
+)
+
+// parseShowCreateUserDefaultRoles returns the names of the default roles in
+// the output of SHOW CREATE USER.
+func parseShowCreateUserDefaultRoles(createUser string) []string {
+	roles := make([]string, 0)
+	m := kShowCreateUserDefaultRoles.FindStringSubmatch(createUser)
+	if m == nil {
+		return roles
+	}
+	for _, account := range kQuotedAccountName.FindAllStringSubmatch(m[1], -1) {
+		roles = append(roles, account[1])
+	}
+	return roles
+}
+
+// showDefaultRoles reads the default roles of an account without the mysql
+// schema: from SHOW CREATE USER on MySQL and from SHOW GRANTS on MariaDB,
+// which has a single default role.
+func showDefaultRoles(ctx context.Context, db *sql.DB, user, host string, mariaDB bool) ([]string, error) {
+	account := formatUserIdentifier(user, host)
+	if !mariaDB {
+		var createUser string
+		stmtSQL := "SHOW CREATE USER " + account
+		log.Printf("[DEBUG] SQL: %s", stmtSQL)
+		if err := db.QueryRowContext(ctx, stmtSQL).Scan(&createUser); err != nil {
+			return nil, fmt.Errorf("failed reading %s: %w", account, err)
+		}
+		return parseShowCreateUserDefaultRoles(createUser), nil
+	}
+
+	stmtSQL := "SHOW GRANTS FOR " + account
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading grants of %s: %w", account, err)
+	}
+	defer rows.Close()
+
+	roles := make([]string, 0)
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return nil, err
+		}
+		if m := kShowGrantsDefaultRole.FindStringSubmatch(strings.TrimSpace(grant)); m != nil {
+			roles = append(roles, m[1])
+		}
+	}
+	return roles, rows.Err()
+}
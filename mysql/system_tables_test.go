@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseShowCreateUserDefaultRoles(t *testing.T) {
+	tests := []struct {
+		createUser string
+		want       []string
+	}{
+		{
+			"CREATE USER `app`@`%` IDENTIFIED WITH 'caching_sha2_password' AS '<redacted>' DEFAULT ROLE `reader`@`%`,`writer`@`%` REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
+			[]string{"reader", "writer"},
+		},
+		{
+			"CREATE USER `app`@`localhost` IDENTIFIED WITH 'caching_sha2_password' DEFAULT ROLE `admin`@`localhost` REQUIRE NONE",
+			[]string{"admin"},
+		},
+		{
+			"CREATE USER `app`@`%` IDENTIFIED WITH 'caching_sha2_password' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
+			[]string{},
+		},
+	}
+	for _, tt := range tests {
+		if got := parseShowCreateUserDefaultRoles(tt.createUser); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseShowCreateUserDefaultRoles(%q) = %v, want %v", tt.createUser, got, tt.want)
+		}
+	}
+}
+
+func TestShowGrantsDefaultRole(t *testing.T) {
+	for grant, want := range map[string]string{
+		"SET DEFAULT ROLE `reader` FOR `app`@`%`": "reader",
+		"SET DEFAULT ROLE 'reader' FOR 'app'@'%'": "reader",
+		"GRANT `reader` TO `app`@`%`":             "",
+		"GRANT USAGE ON *.* TO `app`@`%`":         "",
+	} {
+		got := ""
+		if m := kShowGrantsDefaultRole.FindStringSubmatch(grant); m != nil {
+			got = m[1]
+		}
+		if got != want {
+			t.Errorf("default role of %q = %q, want %q", grant, got, want)
+		}
+	}
+}
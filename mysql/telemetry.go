@@ -0,0 +1,288 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const telemetryServiceName = "terraform-provider-mysql"
+
+// maxTracedStatementLength truncates statements recorded in spans.
+const maxTracedStatementLength = 2048
+
+// kReStringLiteral matches quoted strings, which may hold passwords, and hex
+// literals, e.g. authentication strings written as 0x....
+var kReStringLiteral = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"|\b0[xX][0-9A-Fa-f]+\b`)
+
+var (
+	telemetryOnce  sync.Once
+	tracerProvider *sdktrace.TracerProvider
+	tracer         trace.Tracer = noop.NewTracerProvider().Tracer("")
+)
+
+// telemetryEnabled sets up tracing the first time it's called. Spans are
+// exported with OTLP over HTTP when the standard
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT variable
+// is set; the exporter reads the other OTEL_EXPORTER_OTLP_* variables, e.g.
+// the headers.
+func telemetryEnabled() bool {
+	telemetryOnce.Do(func() {
+		if os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+			return
+		}
+		exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithTimeout(10*time.Second))
+		if err != nil {
+			log.Printf("[WARN] Failed setting up the export of traces: %v", err)
+			return
+		}
+		serviceName := os.Getenv("OTEL_SERVICE_NAME")
+		if serviceName == "" {
+			serviceName = telemetryServiceName
+		}
+		tracerProvider = newTracerProvider(exporter, serviceName)
+		tracer = tracerProvider.Tracer(telemetryServiceName)
+	})
+	return tracerProvider != nil
+}
+
+func newTracerProvider(exporter sdktrace.SpanExporter, serviceName string) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+}
+
+// flushTraces exports the spans recorded so far. The provider process may be
+// killed once Terraform is done with it, so spans are flushed after each
+// operation instead of being buffered until exit.
+func flushTraces(ctx context.Context) {
+	if tracerProvider == nil {
+		return
+	}
+	if err := tracerProvider.ForceFlush(ctx); err != nil {
+		log.Printf("[WARN] Failed exporting traces: %v", err)
+	}
+}
+
+// instrumentConnections reports whether connections need to be wrapped for
+// traces or metrics.
+func instrumentConnections(conf *MySQLConfiguration) bool {
+	return telemetryEnabled() || conf.Metrics != nil
+}
+
+// sanitizeStatement replaces string and hex literals, so passwords and
+// authentication strings in statements don't end up in traces.
+func sanitizeStatement(query string) string {
+	query = kReStringLiteral.ReplaceAllString(query, "?")
+	if len(query) > maxTracedStatementLength {
+		query = query[:maxTracedStatementLength] + "..."
+	}
+	return query
+}
+
+// instrumentResource adds a span around each operation of the resource or
+// data source, and records the operations in the session metrics.
+func instrumentResource(typeName string, r *schema.Resource) {
+	if r.CreateContext != nil {
+		r.CreateContext = traceOperation(typeName, "create", r.CreateContext)
+	}
+	if r.ReadContext != nil {
+		r.ReadContext = traceOperation(typeName, "read", r.ReadContext)
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = traceOperation(typeName, "update", r.UpdateContext)
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = traceOperation(typeName, "delete", r.DeleteContext)
+	}
+}
+
+func traceOperation(typeName, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		ctx, span := tracer.Start(ctx, typeName+"."+operation, trace.WithAttributes(
+			attribute.String("terraform.resource.type", typeName),
+			attribute.String("terraform.operation", operation),
+			attribute.String("terraform.resource.id", d.Id()),
+		))
+		defer flushTraces(ctx)
+		defer span.End()
+
+		start := time.Now()
+		diags := f(ctx, d, meta)
+		for _, diagnostic := range diags {
+			if diagnostic.Severity == diag.Error {
+				span.SetStatus(codes.Error, diagnostic.Summary)
+				break
+			}
+		}
+		metricsFromMeta(meta).operation(typeName, operation, d.Id(), time.Since(start), diags.HasError())
+		return diags
+	}
+}
+
+// openConnector returns the connector sql.Open would use.
+func openConnector(driverName, dsn string) (driver.Connector, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	db.Close()
+	if driverContext, ok := drv.(driver.DriverContext); ok {
+		return driverContext.OpenConnector(dsn)
+	}
+	return dsnConnector{driver: drv, dsn: dsn}, nil
+}
+
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// tracingConnector adds a span for each statement run on its connections and
+// counts statements and connections in the session metrics.
+type tracingConnector struct {
+	connector driver.Connector
+	metrics   *providerMetrics
+}
+
+func (c tracingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.metrics.connectionOpened()
+	return &tracingConn{wrappedConn: wrappedConn{conn}, metrics: c.metrics}, nil
+}
+
+func (c tracingConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+func traceStatement(ctx context.Context, metrics *providerMetrics, query string, f func() error) error {
+	_, span := tracer.Start(ctx, "SQL", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("db.system", "mysql"),
+		attribute.String("db.statement", sanitizeStatement(query)),
+	))
+	defer span.End()
+
+	err := f()
+	if err == driver.ErrSkip {
+		return err
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	metrics.statement(query, err)
+	return err
+}
+
+type tracingConn struct {
+	wrappedConn
+	metrics *providerMetrics
+}
+
+func (c *tracingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var result driver.Result
+	err := traceStatement(ctx, c.metrics, query, func() error {
+		var err error
+		result, err = execer.ExecContext(ctx, query, args)
+		return err
+	})
+	return result, err
+}
+
+func (c *tracingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var rows driver.Rows
+	err := traceStatement(ctx, c.metrics, query, func() error {
+		var err error
+		rows, err = queryer.QueryContext(ctx, query, args)
+		return err
+	})
+	return rows, err
+}
+
+func (c *tracingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.wrappedConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingStmt{Stmt: stmt, query: query, metrics: c.metrics}, nil
+}
+
+type tracingStmt struct {
+	driver.Stmt
+	query   string
+	metrics *providerMetrics
+}
+
+func (s *tracingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.Stmt.(driver.StmtExecContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var result driver.Result
+	err := traceStatement(ctx, s.metrics, s.query, func() error {
+		var err error
+		result, err = execer.ExecContext(ctx, args)
+		return err
+	})
+	return result, err
+}
+
+func (s *tracingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.Stmt.(driver.StmtQueryContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var rows driver.Rows
+	err := traceStatement(ctx, s.metrics, s.query, func() error {
+		var err error
+		rows, err = queryer.QueryContext(ctx, args)
+		return err
+	})
+	return rows, err
+}
+
+func (s *tracingStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
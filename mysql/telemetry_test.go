@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSanitizeStatement(t *testing.T) {
+	tests := map[string]string{
+		"CREATE USER 'jdoe'@'%' IDENTIFIED BY 'pa''ss\\'word'": "CREATE USER ?@? IDENTIFIED BY ?",
+		"SELECT 1": "SELECT 1",
+		"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS 0x244124303035": "CREATE USER `jdoe`@`%` IDENTIFIED WITH ? AS ?",
+		"SELECT col0x1 FROM t":               "SELECT col0x1 FROM t",
+		`SET SESSION sql_mode="ANSI_QUOTES"`: "SET SESSION sql_mode=?",
+	}
+	for query, expected := range tests {
+		if got := sanitizeStatement(query); got != expected {
+			t.Errorf("sanitizeStatement(%q) = %q, expected %q", query, got, expected)
+		}
+	}
+}
+
+func TestTracerProviderExport(t *testing.T) {
+	var requests []*collectortrace.ExportTraceServiceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing header, got %v", r.Header)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		request := &collectortrace.ExportTraceServiceRequest{}
+		if err := proto.Unmarshal(body, request); err != nil {
+			t.Error(err)
+		}
+		requests = append(requests, request)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(server.URL+"/v1/traces"),
+		otlptracehttp.WithHeaders(map[string]string{"Authorization": "Bearer token"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := newTracerProvider(exporter, "test")
+	defer provider.Shutdown(ctx)
+
+	tr := provider.Tracer("test")
+	spanCtx, root := tr.Start(ctx, "mysql_user.create")
+	_, child := tr.Start(spanCtx, "SQL", trace.WithSpanKind(trace.SpanKindClient))
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	root.End()
+	if err := provider.ForceFlush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+
+	spans := requests[0].ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %v", spans)
+	}
+	exportedChild, exportedRoot := spans[0], spans[1]
+	if string(exportedChild.ParentSpanId) != string(exportedRoot.SpanId) || string(exportedChild.TraceId) != string(exportedRoot.TraceId) {
+		t.Errorf("child isn't linked to its root: %v", spans)
+	}
+	if exportedChild.Kind != tracepb.Span_SPAN_KIND_CLIENT || exportedChild.Status.Code != tracepb.Status_STATUS_CODE_ERROR {
+		t.Errorf("unexpected child %v", exportedChild)
+	}
+}
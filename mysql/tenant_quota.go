@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// kReMaxUserConnections matches the connection limit in SHOW CREATE USER.
+var kReMaxUserConnections = regexp.MustCompile(`MAX_USER_CONNECTIONS\s+(\d+)`)
+
+func tenantConnectionLimitsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Limits of the simultaneous connections of the users of the tenant, so a noisy tenant can't exhaust max_connections",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"app_max_user_connections": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Maximum connections of the application user, 0 for no limit",
+				},
+				"readonly_max_user_connections": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Maximum connections of the read-only user, 0 for no limit",
+				},
+			},
+		},
+	}
+}
+
+func tenantConnectionUsageSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Connections of the users of the tenant from performance_schema.accounts, as of the last refresh",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"current_connections": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"total_connections": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"max_user_connections": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// tenantConnectionLimits returns the connection limits of the app and the
+// read-only user, 0 for no limit.
+func tenantConnectionLimits(d interface{ Get(string) interface{} }) (int, int) {
+	block, _ := d.Get("connection_limits").([]interface{})
+	if len(block) == 0 || block[0] == nil {
+		return 0, 0
+	}
+	m := block[0].(map[string]interface{})
+	return m["app_max_user_connections"].(int), m["readonly_max_user_connections"].(int)
+}
+
+// checkTenantConnectionLimits fails the plan of connection limits on TiDB,
+// which has no MAX_USER_CONNECTIONS.
+func checkTenantConnectionLimits(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("connection_limits") {
+		return nil
+	}
+	if app, readOnly := tenantConnectionLimits(d); app == 0 && readOnly == 0 {
+		return nil
+	}
+	if _, ok := meta.(*MySQLConfiguration); !ok {
+		return nil
+	}
+	return checkMaxUserConnectionsSupport(ctx, meta)
+}
+
+func alterUserConnectionLimit(ctx context.Context, db *sql.DB, user *databaseOwner) error {
+	stmtSQL := fmt.Sprintf("ALTER USER %s WITH MAX_USER_CONNECTIONS %d", user.sqlString(), user.MaxUserConnections)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed limiting the connections of %s: %v", user, err)
+	}
+	return nil
+}
+
+// readUserConnectionLimit returns the MAX_USER_CONNECTIONS of a user, 0 for
+// no limit.
+func readUserConnectionLimit(ctx context.Context, db *sql.DB, user, host string) (int, error) {
+	var createUserStmt string
+	stmtSQL := "SHOW CREATE USER " + formatUserIdentifier(user, host)
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	if err := db.QueryRowContext(ctx, stmtSQL).Scan(&createUserStmt); err != nil {
+		return 0, err
+	}
+	match := kReMaxUserConnections.FindStringSubmatch(createUserStmt)
+	if match == nil {
+		return 0, nil
+	}
+	return strconv.Atoi(match[1])
+}
+
+// readTenantConnectionUsage returns the connections of users over all of
+// their hosts. Users that haven't connected since the server started have
+// none.
+func readTenantConnectionUsage(ctx context.Context, db *sql.DB, users []string) (map[string][2]int64, error) {
+	args := make([]interface{}, len(users))
+	for i, user := range users {
+		args[i] = user
+	}
+	query := fmt.Sprintf("SELECT USER, SUM(CURRENT_CONNECTIONS), SUM(TOTAL_CONNECTIONS) FROM performance_schema.accounts WHERE USER IN (?%s) GROUP BY USER", strings.Repeat(", ?", len(users)-1))
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := map[string][2]int64{}
+	for rows.Next() {
+		var user string
+		var current, total int64
+		if err := rows.Scan(&user, &current, &total); err != nil {
+			return nil, err
+		}
+		usage[user] = [2]int64{current, total}
+	}
+	return usage, rows.Err()
+}
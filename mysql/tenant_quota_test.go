@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestTenantConnectionLimits(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTenant().Schema, map[string]interface{}{
+		"name": "acme",
+		"connection_limits": []interface{}{map[string]interface{}{
+			"app_max_user_connections": 20,
+		}},
+	})
+	app, readOnly, err := tenantUsers(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.MaxUserConnections != 20 || readOnly.MaxUserConnections != 0 {
+		t.Errorf("limits are %d and %d, want 20 and 0", app.MaxUserConnections, readOnly.MaxUserConnections)
+	}
+
+	app.Password = "secret"
+	if got, want := app.createSQL(), "CREATE USER `acme_app`@`%` IDENTIFIED BY 'secret' WITH MAX_USER_CONNECTIONS 20"; got != want {
+		t.Errorf("createSQL() = %q, want %q", got, want)
+	}
+	readOnly.Password = "secret"
+	if got, want := readOnly.createSQL(), "CREATE USER `acme_ro`@`%` IDENTIFIED BY 'secret'"; got != want {
+		t.Errorf("createSQL() = %q, want %q", got, want)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceTenant().Schema, map[string]interface{}{"name": "acme"})
+	if appLimit, readOnlyLimit := tenantConnectionLimits(d); appLimit != 0 || readOnlyLimit != 0 {
+		t.Errorf("limits without connection_limits are %d and %d, want none", appLimit, readOnlyLimit)
+	}
+}
+
+func TestMaxUserConnectionsPattern(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want string
+	}{
+		{"CREATE USER `acme_app`@`%` IDENTIFIED WITH 'caching_sha2_password' AS '...' REQUIRE NONE WITH MAX_USER_CONNECTIONS 20 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK", "20"},
+		{"CREATE USER `acme_app`@`%` IDENTIFIED BY PASSWORD '*A4B6157319038724E3560894F7F932C8886EBFCF' WITH MAX_USER_CONNECTIONS 5", "5"},
+		{"CREATE USER `acme_app`@`%` IDENTIFIED BY PASSWORD '*A4B6157319038724E3560894F7F932C8886EBFCF'", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if match := kReMaxUserConnections.FindStringSubmatch(tt.stmt); match != nil {
+			got = match[1]
+		}
+		if got != tt.want {
+			t.Errorf("limit of %q is %q, want %q", tt.stmt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	userExpiryLock = "lock"
+	userExpiryDrop = "drop"
+)
+
+// userExpired reports whether expiresAt, an RFC 3339 timestamp, passed. An
+// empty expiresAt never expires.
+func userExpired(expiresAt string, now time.Time) (bool, error) {
+	if expiresAt == "" {
+		return false, nil
+	}
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("invalid expires_at %q: %v", expiresAt, err)
+	}
+	return !now.Before(t), nil
+}
+
+// diffUserExpiry plans expired from expires_at, so an account expiring
+// between applies shows as a change. Dropped accounts are created again when
+// expires_at is extended.
+func diffUserExpiry(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("expires_at") {
+		return d.SetNewComputed("expired")
+	}
+	expired, err := userExpired(d.Get("expires_at").(string), time.Now())
+	if err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		if expired {
+			return fmt.Errorf("expires_at %s is in the past", d.Get("expires_at").(string))
+		}
+		return d.SetNew("expired", false)
+	}
+
+	wasExpired, _ := d.GetChange("expired")
+	if wasExpired.(bool) == expired {
+		return nil
+	}
+	if err := d.SetNew("expired", expired); err != nil {
+		return err
+	}
+	if !expired && d.Get("expiry_action").(string) == userExpiryDrop {
+		return d.ForceNew("expires_at")
+	}
+	return nil
+}
+
+// enforceUserExpiry locks or drops the account at all of its hosts, when
+// an apply changes expired to true.
+func enforceUserExpiry(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}) error {
+	user := d.Get("user").(string)
+	for _, host := range userHosts(d, meta) {
+		stmtSQL := fmt.Sprintf("ALTER USER %s ACCOUNT LOCK", formatUserIdentifier(user, host))
+		if d.Get("expiry_action").(string) == userExpiryDrop {
+			stmtSQL = "DROP USER IF EXISTS " + formatUserIdentifier(user, host)
+		}
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			if mysqlErrorNumber(err) == unknownUserErrCode || mysqlErrorNumber(err) == userNotFoundErrCode {
+				continue
+			}
+			return fmt.Errorf("failed expiring user %s: %v", formatUserIdentifier(user, host), err)
+		}
+	}
+	log.Printf("[INFO] User %s expired at %s", user, d.Get("expires_at").(string))
+	return d.Set("expired", true)
+}
+
+// unlockUser unlocks an account locked by expiry after expires_at was
+// extended. Schema owners stay locked.
+func unlockUser(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}) error {
+	if d.Get("schema_owner").(bool) {
+		return nil
+	}
+	for _, host := range userHosts(d, meta) {
+		stmtSQL := fmt.Sprintf("ALTER USER %s ACCOUNT UNLOCK", formatUserIdentifier(d.Get("user").(string), host))
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed unlocking user: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expiresAt string
+		want      bool
+	}{
+		{"", false},
+		{"2026-03-01T13:00:00Z", false},
+		{"2026-03-01T12:00:00Z", true},
+		{"2026-03-01T12:30:00+01:00", true},
+	}
+	for _, tt := range tests {
+		got, err := userExpired(tt.expiresAt, now)
+		if err != nil {
+			t.Fatalf("userExpired(%q) failed: %v", tt.expiresAt, err)
+		}
+		if got != tt.want {
+			t.Errorf("userExpired(%q) = %t, want %t", tt.expiresAt, got, tt.want)
+		}
+	}
+
+	if _, err := userExpired("tomorrow", now); err == nil {
+		t.Errorf("userExpired(\"tomorrow\") should fail")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 
 	"github.com/go-sql-driver/mysql"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	rds "github.com/krotscheck/go-rds-driver"
 )
 
@@ -58,6 +60,9 @@ func hashSum(contents interface{}) string {
 func getDatabaseFromMeta(ctx context.Context, meta interface{}) (*sql.DB, error) {
 	switch conf := meta.(type) {
 	case *MySQLConfiguration:
+		if conf.ProxySQL {
+			return nil, fmt.Errorf("only mysql_proxysql_* resources are supported when proxysql is enabled")
+		}
 		oneConnection, err := connectToMySQLInternal(ctx, conf)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to MySQL: %v", err)
@@ -72,6 +77,39 @@ func getDatabaseFromMeta(ctx context.Context, meta interface{}) (*sql.DB, error)
 	}
 }
 
+// getReadDatabaseFromMeta returns the connection to read_endpoint for data
+// sources, or the one of getDatabaseFromMeta when it's not configured.
+func getReadDatabaseFromMeta(ctx context.Context, meta interface{}) (*sql.DB, error) {
+	conf, ok := meta.(*MySQLConfiguration)
+	if !ok || conf.ProxySQL || conf.ReadEndpoint == "" {
+		return getDatabaseFromMeta(ctx, meta)
+	}
+
+	readConf := *conf
+	readConf.Config = conf.Config.Clone()
+	readConf.Config.Addr = conf.ReadEndpoint
+	if conf.Config.Net != "cloudsql" {
+		readConf.Config.Net = "tcp"
+		if strings.HasPrefix(conf.ReadEndpoint, "/") {
+			readConf.Config.Net = "unix"
+		} else if strings.HasPrefix(conf.ReadEndpoint, srvEndpointPrefix) {
+			readConf.Config.Net = srvNet
+			readConf.Config.Addr = strings.TrimPrefix(conf.ReadEndpoint, srvEndpointPrefix)
+		}
+	}
+	if conf.ReadPassword != "" {
+		readConf.Config.Passwd = conf.ReadPassword
+	}
+	// Replicas have their own server_uuid.
+	readConf.ExpectedServerUUID = ""
+
+	oneConnection, err := connectToMySQLInternal(ctx, &readConf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to read_endpoint: %v", err)
+	}
+	return oneConnection.Db, nil
+}
+
 func getVersionFromMeta(ctx context.Context, meta interface{}) *version.Version {
 	switch conf := meta.(type) {
 	case *MySQLConfiguration:
@@ -100,6 +138,66 @@ func getVersionFromMeta(ctx context.Context, meta interface{}) *version.Version
 	}
 }
 
+// defaultUserHostFromMeta returns the host used when a resource omits it.
+func defaultUserHostFromMeta(meta interface{}) string {
+	if conf, ok := meta.(*MySQLConfiguration); ok && conf.DefaultUserHost != "" {
+		return conf.DefaultUserHost
+	}
+	return "localhost"
+}
+
+// setDefaultUserHost plans the provider's default_user_host for resources
+// that configure neither host nor hosts, nor a role. Existing resources with
+// another host are replaced, as when their host is changed.
+func setDefaultUserHost(d *schema.ResourceDiff, meta interface{}) error {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.GetAttr("host").IsNull() || !rawConfig.GetAttr("hosts").IsNull() {
+		return nil
+	}
+	if rawConfig.Type().HasAttribute("role") && !rawConfig.GetAttr("role").IsNull() {
+		return nil
+	}
+	host := defaultUserHostFromMeta(meta)
+	if d.Id() == "" {
+		return d.SetNew("host", host)
+	}
+	if d.Get("host").(string) == host {
+		return nil
+	}
+	if err := d.SetNew("host", host); err != nil {
+		return err
+	}
+	return d.ForceNew("host")
+}
+
+// defaultDatabaseFromMeta returns the provider's default_database, if any.
+func defaultDatabaseFromMeta(meta interface{}) string {
+	if conf, ok := meta.(*MySQLConfiguration); ok {
+		return conf.DefaultDatabase
+	}
+	return ""
+}
+
+// setDefaultDatabase plans the provider's default_database, or fallback
+// without one, for new resources that don't configure database.
+func setDefaultDatabase(d *schema.ResourceDiff, meta interface{}, fallback string) error {
+	if d.Id() != "" {
+		return nil
+	}
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.GetAttr("database").IsNull() {
+		return nil
+	}
+	database := defaultDatabaseFromMeta(meta)
+	if database == "" {
+		database = fallback
+	}
+	if database == "" {
+		return fmt.Errorf("database must be set when the provider has no default_database")
+	}
+	return d.SetNew("database", database)
+}
+
 // 0 == not mysql error or not error at all.
 func mysqlErrorNumber(err error) uint16 {
 	if err == nil {
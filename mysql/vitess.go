@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"strings"
+)
+
+// vitessUnsupportedResources lists resources whose statements vtgate can't
+// run. Vitess manages accounts outside of MySQL (vttablet ACLs or the
+// PlanetScale API), doesn't grant SUPER and doesn't allow SET GLOBAL or
+// plugin management, so these fail at plan time rather than on apply.
+var vitessUnsupportedResources = []string{
+	"mysql_user",
+	"mysql_user_password",
+	"mysql_user_replica",
+	"mysql_grant",
+	"mysql_temporary_grant",
+	"mysql_role",
+	"mysql_default_roles",
+	"mysql_global_variable",
+	"mysql_global_variables",
+	"mysql_ti_config",
+	"mysql_rds_config",
+	"mysql_secure_installation",
+	"mysql_password_validation",
+	"mysql_connection_control",
+	"mysql_user_defined_function",
+	"mysql_heatwave_table",
+	"mysql_router_account",
+	"mysql_tool_account",
+	"mysql_backup_account",
+	"mysql_tenant",
+}
+
+// vitessUnsupportedHint tells users of Vitess where to manage what the
+// provider can't.
+const vitessUnsupportedHint = "manage it through vttablet ACLs or the PlanetScale API instead"
+
+// serverVitess reports whether we are connected to vtgate, which appends
+// -Vitess (or -PlanetScale) to the MySQL version it emulates.
+func serverVitess(versionString string) bool {
+	return strings.Contains(versionString, "Vitess") || strings.Contains(versionString, "PlanetScale")
+}
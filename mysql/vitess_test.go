@@ -0,0 +1,43 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVitessUnsupportedResources(t *testing.T) {
+	provider := Provider()
+	meta := &MySQLConfiguration{Vitess: true}
+
+	for _, name := range vitessUnsupportedResources {
+		r, ok := provider.ResourcesMap[name]
+		if !ok {
+			t.Fatalf("resource %s is not registered", name)
+		}
+		if r.CustomizeDiff == nil {
+			t.Fatalf("resource %s has no CustomizeDiff", name)
+		}
+		err := r.CustomizeDiff(context.Background(), nil, meta)
+		if err == nil || !strings.Contains(err.Error(), "not supported by Vitess") || !strings.Contains(err.Error(), "PlanetScale API") {
+			t.Errorf("expected Vitess error for %s, got %v", name, err)
+		}
+	}
+}
+
+func TestServerVitess(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"8.0.30-Vitess", true},
+		{"8.0.23-PlanetScale", true},
+		{"8.0.36", false},
+		{"10.11.6-MariaDB", false},
+	}
+	for _, tt := range tests {
+		if got := serverVitess(tt.version); got != tt.want {
+			t.Errorf("serverVitess(%q) = %t, want %t", tt.version, got, tt.want)
+		}
+	}
+}